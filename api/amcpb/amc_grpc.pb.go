@@ -0,0 +1,290 @@
+// The read API of AMC for automation, served over gRPC when AMC is built with the grpc tag
+// and grpc_bind is set. The stats are the same as in the REST API, formatted as strings.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: amc.proto
+
+package amcpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	AMC_ListClusters_FullMethodName      = "/amc.v1.AMC/ListClusters"
+	AMC_GetNodeStats_FullMethodName      = "/amc.v1.AMC/GetNodeStats"
+	AMC_GetNamespaceStats_FullMethodName = "/amc.v1.AMC/GetNamespaceStats"
+	AMC_ListAlerts_FullMethodName        = "/amc.v1.AMC/ListAlerts"
+	AMC_WatchCluster_FullMethodName      = "/amc.v1.AMC/WatchCluster"
+)
+
+// AMCClient is the client API for AMC service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AMCClient interface {
+	// the monitored clusters
+	ListClusters(ctx context.Context, in *ListClustersRequest, opts ...grpc.CallOption) (*ListClustersResponse, error)
+	// the stats of the nodes of a cluster
+	GetNodeStats(ctx context.Context, in *NodeStatsRequest, opts ...grpc.CallOption) (*NodeStatsResponse, error)
+	// the stats of the namespaces of a cluster, aggregated over the nodes
+	GetNamespaceStats(ctx context.Context, in *NamespaceStatsRequest, opts ...grpc.CallOption) (*NamespaceStatsResponse, error)
+	// the alerts of a cluster after an alert id
+	ListAlerts(ctx context.Context, in *ListAlertsRequest, opts ...grpc.CallOption) (*ListAlertsResponse, error)
+	// the snapshot of a cluster after each of its updates
+	WatchCluster(ctx context.Context, in *WatchClusterRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ClusterSnapshot], error)
+}
+
+type aMCClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAMCClient(cc grpc.ClientConnInterface) AMCClient {
+	return &aMCClient{cc}
+}
+
+func (c *aMCClient) ListClusters(ctx context.Context, in *ListClustersRequest, opts ...grpc.CallOption) (*ListClustersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListClustersResponse)
+	err := c.cc.Invoke(ctx, AMC_ListClusters_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aMCClient) GetNodeStats(ctx context.Context, in *NodeStatsRequest, opts ...grpc.CallOption) (*NodeStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NodeStatsResponse)
+	err := c.cc.Invoke(ctx, AMC_GetNodeStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aMCClient) GetNamespaceStats(ctx context.Context, in *NamespaceStatsRequest, opts ...grpc.CallOption) (*NamespaceStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NamespaceStatsResponse)
+	err := c.cc.Invoke(ctx, AMC_GetNamespaceStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aMCClient) ListAlerts(ctx context.Context, in *ListAlertsRequest, opts ...grpc.CallOption) (*ListAlertsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAlertsResponse)
+	err := c.cc.Invoke(ctx, AMC_ListAlerts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aMCClient) WatchCluster(ctx context.Context, in *WatchClusterRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ClusterSnapshot], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AMC_ServiceDesc.Streams[0], AMC_WatchCluster_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchClusterRequest, ClusterSnapshot]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AMC_WatchClusterClient = grpc.ServerStreamingClient[ClusterSnapshot]
+
+// AMCServer is the server API for AMC service.
+// All implementations must embed UnimplementedAMCServer
+// for forward compatibility.
+type AMCServer interface {
+	// the monitored clusters
+	ListClusters(context.Context, *ListClustersRequest) (*ListClustersResponse, error)
+	// the stats of the nodes of a cluster
+	GetNodeStats(context.Context, *NodeStatsRequest) (*NodeStatsResponse, error)
+	// the stats of the namespaces of a cluster, aggregated over the nodes
+	GetNamespaceStats(context.Context, *NamespaceStatsRequest) (*NamespaceStatsResponse, error)
+	// the alerts of a cluster after an alert id
+	ListAlerts(context.Context, *ListAlertsRequest) (*ListAlertsResponse, error)
+	// the snapshot of a cluster after each of its updates
+	WatchCluster(*WatchClusterRequest, grpc.ServerStreamingServer[ClusterSnapshot]) error
+	mustEmbedUnimplementedAMCServer()
+}
+
+// UnimplementedAMCServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAMCServer struct{}
+
+func (UnimplementedAMCServer) ListClusters(context.Context, *ListClustersRequest) (*ListClustersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListClusters not implemented")
+}
+func (UnimplementedAMCServer) GetNodeStats(context.Context, *NodeStatsRequest) (*NodeStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNodeStats not implemented")
+}
+func (UnimplementedAMCServer) GetNamespaceStats(context.Context, *NamespaceStatsRequest) (*NamespaceStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNamespaceStats not implemented")
+}
+func (UnimplementedAMCServer) ListAlerts(context.Context, *ListAlertsRequest) (*ListAlertsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAlerts not implemented")
+}
+func (UnimplementedAMCServer) WatchCluster(*WatchClusterRequest, grpc.ServerStreamingServer[ClusterSnapshot]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchCluster not implemented")
+}
+func (UnimplementedAMCServer) mustEmbedUnimplementedAMCServer() {}
+func (UnimplementedAMCServer) testEmbeddedByValue()             {}
+
+// UnsafeAMCServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AMCServer will
+// result in compilation errors.
+type UnsafeAMCServer interface {
+	mustEmbedUnimplementedAMCServer()
+}
+
+func RegisterAMCServer(s grpc.ServiceRegistrar, srv AMCServer) {
+	// If the following call pancis, it indicates UnimplementedAMCServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AMC_ServiceDesc, srv)
+}
+
+func _AMC_ListClusters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListClustersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AMCServer).ListClusters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AMC_ListClusters_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AMCServer).ListClusters(ctx, req.(*ListClustersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AMC_GetNodeStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AMCServer).GetNodeStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AMC_GetNodeStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AMCServer).GetNodeStats(ctx, req.(*NodeStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AMC_GetNamespaceStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NamespaceStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AMCServer).GetNamespaceStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AMC_GetNamespaceStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AMCServer).GetNamespaceStats(ctx, req.(*NamespaceStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AMC_ListAlerts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAlertsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AMCServer).ListAlerts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AMC_ListAlerts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AMCServer).ListAlerts(ctx, req.(*ListAlertsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AMC_WatchCluster_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchClusterRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AMCServer).WatchCluster(m, &grpc.GenericServerStream[WatchClusterRequest, ClusterSnapshot]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AMC_WatchClusterServer = grpc.ServerStreamingServer[ClusterSnapshot]
+
+// AMC_ServiceDesc is the grpc.ServiceDesc for AMC service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AMC_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "amc.v1.AMC",
+	HandlerType: (*AMCServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListClusters",
+			Handler:    _AMC_ListClusters_Handler,
+		},
+		{
+			MethodName: "GetNodeStats",
+			Handler:    _AMC_GetNodeStats_Handler,
+		},
+		{
+			MethodName: "GetNamespaceStats",
+			Handler:    _AMC_GetNamespaceStats_Handler,
+		},
+		{
+			MethodName: "ListAlerts",
+			Handler:    _AMC_ListAlerts_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchCluster",
+			Handler:       _AMC_WatchCluster_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "amc.proto",
+}
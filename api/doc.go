@@ -0,0 +1,6 @@
+// Package api holds the protobuf definitions of the gRPC API of AMC.
+// The Go code is generated in the amcpb package, which is only used by AMC built with the grpc tag;
+// run go generate after changing the definitions.
+package api
+
+//go:generate protoc --go_out=. --go_opt=module=github.com/aerospike-community/amc/api --go-grpc_out=. --go-grpc_opt=module=github.com/aerospike-community/amc/api amc.proto
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+const usage = `Usage: amc [command] [flags]
+
+Commands:
+  serve            run the AMC server (the default)
+  validate-config  check the config file and exit
+  version          print the version of AMC
+  list-clusters    list the clusters in the config file and the clusters persisted in the database
+
+Run 'amc <command> -h' for the flags of a command.
+`
+
+func main() {
+	defer func() {
+		if err := recover(); err != nil {
+			log.Fatal(string(debug.Stack()))
+		}
+	}()
+
+	runtime.GOMAXPROCS(runtime.NumCPU())
+
+	// amc -config-file=... is the same as amc serve -config-file=...
+	cmd, args := "serve", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		cmd, args = args[0], args[1:]
+	}
+
+	switch cmd {
+	case "serve":
+		serve(args)
+	case "validate-config":
+		os.Exit(validateConfig(args))
+	case "version":
+		printVersion()
+	case "list-clusters":
+		os.Exit(listClusters(args))
+	case "help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command %s\n\n%s", cmd, usage)
+		os.Exit(2)
+	}
+}
+
+// configFlags are the flags of the commands reading the config file
+type configFlags struct {
+	configFile *string
+	configDir  *string
+}
+
+func newConfigFlags(fs *flag.FlagSet) *configFlags {
+	return &configFlags{
+		configFile: fs.String("config-file", "/etc/amc/amc.conf", "Configuration file."),
+		configDir:  fs.String("config-dir", "/etc/amc/", "Configuration dir."),
+	}
+}
+
+// serveFlags are the flags of the serve command; the flags which are set override the config file
+type serveFlags struct {
+	*configFlags
+	profileMode *bool
+
+	bind           *string
+	logLevel       *string
+	logFormat      *string
+	staticDir      *string
+	database       *string
+	updateInterval *int
+}
+
+func newServeFlags(fs *flag.FlagSet) *serveFlags {
+	return &serveFlags{
+		configFlags: newConfigFlags(fs),
+		profileMode: fs.Bool("profile", false, "Run benchmarks with profiler active on port 6060."),
+
+		bind:           fs.String("bind", "", "The address AMC listens on; overrides bind."),
+		logLevel:       fs.String("loglevel", "", "One of debug, info, warn or error; overrides loglevel."),
+		logFormat:      fs.String("log-format", "", "text or json; overrides log_format."),
+		staticDir:      fs.String("static-dir", "", "The directory of the UI files; overrides static_dir."),
+		database:       fs.String("database", "", "The database file; overrides database."),
+		updateInterval: fs.Int("update-interval", 0, "Seconds between the updates of the clusters; overrides update_interval."),
+	}
+}
+
+// override - apply the flags which are set to the config
+func (f *serveFlags) override(config *common.Config) {
+	if *f.bind != "" {
+		config.AMC.Bind = *f.bind
+	}
+	if *f.logLevel != "" {
+		config.AMC.LogLevel = *f.logLevel
+	}
+	if *f.logFormat != "" {
+		config.AMC.LogFormat = *f.logFormat
+	}
+	if *f.staticDir != "" {
+		config.AMC.StaticPath = *f.staticDir
+	}
+	if *f.database != "" {
+		config.AMC.Database = *f.database
+	}
+	if *f.updateInterval > 0 {
+		config.AMC.UpdateInterval = *f.updateInterval
+	}
+}
+
+// startProfiler - launch the profiler if in profile mode
+func (f *serveFlags) startProfiler() {
+	if *f.profileMode {
+		go func() {
+			log.Println(http.ListenAndServe(":6060", nil))
+		}()
+	}
+}
+
+// validateConfig - report the errors and the unknown keys of the config file; the exit code is 1 if there are any
+func validateConfig(args []string) int {
+	fs := flag.NewFlagSet("validate-config", flag.ExitOnError)
+	flags := newConfigFlags(fs)
+	fs.Parse(args)
+
+	config := common.Config{}
+	if err := common.LoadConfig(*flags.configFile, &config); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	// unknown keys only are warnings when AMC starts, but are most likely misspelled settings
+	errs := config.Validate()
+	for _, err := range errs {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	if len(errs) > 0 {
+		return 1
+	}
+
+	fmt.Printf("%s is valid\n", *flags.configFile)
+	return 0
+}
+
+func printVersion() {
+	fmt.Printf("AMC %s %s (build %s, %s)\n", common.AMCVersion, common.AMCEdition, common.AMCBuild, runtime.Version())
+}
+
+// listClusters - list the clusters in the config file and the clusters registered from the UI
+// which are persisted in the database
+func listClusters(args []string) int {
+	fs := flag.NewFlagSet("list-clusters", flag.ExitOnError)
+	flags := newConfigFlags(fs)
+	database := fs.String("database", "", "The database file; overrides database.")
+	fs.Parse(args)
+
+	config := common.Config{}
+	if err := common.LoadConfig(*flags.configFile, &config); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if *database != "" {
+		config.AMC.Database = *database
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "SOURCE\tNAME\tSEEDS\tUSER\tALIAS")
+
+	names := make([]string, 0, len(config.AMC.Clusters))
+	for name := range config.AMC.Clusters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		server := config.AMC.Clusters[name]
+		seeds := net.JoinHostPort(server.Host, strconv.Itoa(int(server.Port)))
+		if server.SRVRecord != "" {
+			seeds = "srv:" + server.SRVRecord
+		} else if server.KubernetesService != "" {
+			seeds = "k8s:" + server.KubernetesService
+		}
+		fmt.Fprintf(w, "config\t%s\t%s\t%s\t%s\n", name, seeds, server.User, server.Alias)
+	}
+
+	if config.AMC.Database == "" {
+		w.Flush()
+		return 0
+	}
+
+	// the database path is relative to chdir, like when AMC runs
+	if config.AMC.Chdir != "" {
+		if err := os.Chdir(config.AMC.Chdir); err != nil {
+			w.Flush()
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+
+	log.SetLevel(log.WarnLevel)
+	common.SetupDatabase(config.AMC.Database)
+	clusters, err := common.MonitoredClusters()
+	if err != nil {
+		w.Flush()
+		fmt.Fprintln(os.Stderr, "Error reading the persisted clusters:", err)
+		return 1
+	}
+	for _, mc := range clusters {
+		fmt.Fprintf(w, "database\t%s\t%s\t%s\t%s\n", mc.Id, strings.Join(mc.Seeds, ","), mc.Username, mc.Alias)
+	}
+
+	w.Flush()
+	return 0
+}
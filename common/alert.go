@@ -4,7 +4,7 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"fmt"
-	"sync"
+	"sort"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -32,6 +32,10 @@ const (
 	AlertTypeNamespaceDiskPctStopWrites      AlertType = 8
 	AlertTypeNamespaceMemoryPctHighWatermark AlertType = 9
 	AlertTypeNamespaceMemoryPctStopWrites    AlertType = 10
+
+	AlertTypeNodeFeatureKeyExpiry AlertType = 11
+
+	AlertTypeClusterSlowPolling AlertType = 12
 )
 
 // AlertStatus - type
@@ -59,7 +63,7 @@ type Alert struct {
 	Status      AlertStatus
 }
 
-var _dbGlobalMutex sync.RWMutex
+var _dbGlobalMutex RWMutex
 
 // AlertBucket structure
 type AlertBucket struct {
@@ -69,7 +73,7 @@ type AlertBucket struct {
 	// Alerts which should be sent for notification system
 	newAlerts []*Alert
 
-	mutex sync.RWMutex
+	mutex RWMutex
 }
 
 // AlertsByID implements sort.Interface for []*Alert based on
@@ -269,6 +273,30 @@ func (ad *AlertBucket) AlertsFrom(nodeAddress string, id int64) []*Alert {
 	return res
 }
 
+// AlertsCreatedSince - get the alerts of the nodes raised since the time, oldest first
+func AlertsCreatedSince(nodeAddresses []string, since time.Time) ([]*Alert, error) {
+	_dbGlobalMutex.Lock()
+	defer _dbGlobalMutex.Unlock()
+
+	res := []*Alert{}
+	for _, nodeAddress := range nodeAddresses {
+		rows, err := db.Query(fmt.Sprintf("SELECT %s FROM alerts where NodeAddress = ?1 AND Created >= ?2 ORDER BY Id", _alertFields), nodeAddress, since)
+		if err != nil {
+			return res, err
+		}
+
+		alerts, err := fromSQLRows(rows)
+		rows.Close()
+		if err != nil {
+			return res, err
+		}
+		res = append(res, alerts...)
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Created.Before(res[j].Created) })
+	return res, nil
+}
+
 // RedAlertsFrom - get red alerts
 func (ad *AlertBucket) RedAlertsFrom(nodeAddress string, ID int64) int {
 	_dbGlobalMutex.Lock()
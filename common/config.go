@@ -4,12 +4,15 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"database/sql"
+	"errors"
+	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 
-	"github.com/BurntSushi/toml"
 	aslog "github.com/aerospike/aerospike-client-go/v5/logger"
 	log "github.com/sirupsen/logrus"
 
@@ -48,36 +51,98 @@ type Config struct {
 	AMC struct {
 		UpdateInterval           int    `toml:"update_interval"`
 		InactiveDurBeforeRemoval int    `toml:"cluster_inactive_before_removal"`
+		PollConcurrency          int    `toml:"poll_concurrency"`
+		InfoCacheTTL             int    `toml:"info_cache_ttl"`
 		CertFile                 string `toml:"certfile"`
 		KeyFile                  string `toml:"keyfile"`
 		ForceTLS12               bool   `toml:"force_tls12"`
 		MaxTLSSecurity           bool   `toml:"max_tls_security"`
 		StaticPath               string `toml:"static_dir"`
 
+		// the minimum TLS version (1.0 to 1.3) and the cipher suites of TLS 1.2 and below, by their Go names;
+		// they take precedence over force_tls12 and max_tls_security
+		TLSMinVersion   string   `toml:"tls_min_version"`
+		TLSCipherSuites []string `toml:"tls_cipher_suites"`
+
+		// get the certificates of these domains from Let's Encrypt instead of certfile and keyfile;
+		// the http-01 challenges are answered on acme_http_bind if set, otherwise tls-alpn-01 is used on bind
+		ACMEDomains  []string `toml:"acme_domains"`
+		ACMECacheDir string   `toml:"acme_cache_dir"`
+		ACMEEmail    string   `toml:"acme_email"`
+		ACMEHTTPBind string   `toml:"acme_http_bind"`
+
+		// seconds the browsers may cache the static files other than the pages; 0 to revalidate them on every load
+		StaticMaxAge int `toml:"static_max_age"`
+
+		// serve the .br and .gz files next to the static files to the browsers accepting them
+		StaticPrecompressed bool `toml:"static_precompressed"`
+
+		// memory budgets for the history in MB
+		HistoryMemoryLimit           int `toml:"history_memory_limit"`
+		HistoryMemoryLimitPerCluster int `toml:"history_memory_limit_per_cluster"`
+
+		// seconds a cluster may be idle before its connections are closed
+		InactiveDurBeforeDisconnect int `toml:"cluster_inactive_before_disconnect"`
+
+		// the IP version tried first for host names resolving to both; 4, 6 or 0 for no preference
+		PreferIPVersion int `toml:"prefer_ip_version"`
+
+		// seconds between re-resolving the DNS names and SRV records used as seeds
+		DNSRefreshInterval int `toml:"dns_refresh_interval"`
+
+		// the maximum number of clusters monitored at once; 0 for no limit
+		MaxClusters int `toml:"max_clusters"`
+
+		// serve the names and the up/down state of the clusters in the config file at /status, without authentication
+		PublicStatus bool `toml:"public_status"`
+
+		// the address of the gRPC API; only served if AMC is built with the grpc tag
+		GRPCBind string `toml:"grpc_bind"`
+
+		// the path of a unix socket the API is served on in addition to bind, e.g. for a local reverse proxy;
+		// the mode of the socket file is given in octal, e.g. 0660
+		UnixSocket     string `toml:"unix_socket"`
+		UnixSocketMode string `toml:"unix_socket_mode"`
+
+		// AMC is served through a reverse proxy on its own host, so the requests over the loopback are only
+		// from the AMC host if the proxy did not forward them for another address
+		ReverseProxy bool `toml:"reverse_proxy"`
+
+		// the destination of the access log: stdout, stderr or a file; the requests are not logged if unset
+		AccessLog       string `toml:"access_log"`
+		AccessLogFormat string `toml:"access_log_format"` // text or json
+
+		// the maximum size of the request bodies in MB, e.g. of the UDF modules; larger requests are rejected with 413
+		MaxBodySize int `toml:"max_body_size"`
+
+		// seconds a request may take before it is answered with 504; route_timeouts overrides it for the routes
+		// by their path as registered, e.g. "/aerospike/service/clusters/:clusterUUID/initiate_restore"
+		RequestTimeout int            `toml:"request_timeout"`
+		RouteTimeouts  map[string]int `toml:"route_timeouts"`
+
+		// the requests taking at least this many milliseconds are logged as slow; 0 to log none
+		SlowRequestThreshold int `toml:"slow_request_threshold"`
+
+		// the cluster-names of the non-production clusters the data generator and the benchmarks may write to
+		TestClusters []string `toml:"test_clusters"`
+
 		// BackupHost         string `toml:"backup_host"`
 		// BackupHostUser     string `toml:"backup_host_user"`
 		// BackupHostPassword string `toml:"backup_host_password"`
 		BackupHostKeyFile string `toml:"backup_host_public_key_file"`
 
-		Database string `toml:"database"`
-
-		Clusters map[string]struct {
-			Host                 string `toml:"host"`
-			TLSName              string `toml:"tls_name"`
-			Port                 uint16 `toml:"port"`
-			User                 string `toml:"user"`
-			Password             string `toml:"password"`
-			Alias                string `toml:"alias"`
-			UseServicesAlternate bool   `toml:"use_services_alternate"`
-			ShowInUI             bool   `toml:"show_in_ui"`
-		} `toml:"clusters"`
-
-		Bind     string `toml:"bind"`
-		LogLevel string `toml:"loglevel"`
-		ErrorLog string `toml:"errorlog"`
-		Chdir    string `toml:"chdir"`
-		Timeout  int    `toml:"timeout"`
-		PIDFile  string `toml:"pidfile"`
+		Database      string `toml:"database"`
+		SecretKeyFile string `toml:"secret_key_file"`
+
+		Clusters map[string]ClusterConfig `toml:"clusters"`
+
+		Bind      string `toml:"bind"`
+		LogLevel  string `toml:"loglevel"`
+		LogFormat string `toml:"log_format"` // text or json
+		ErrorLog  string `toml:"errorlog"`
+		Chdir     string `toml:"chdir"`
+		Timeout   int    `toml:"timeout"`
+		PIDFile   string `toml:"pidfile"`
 	}
 
 	Mailer struct {
@@ -91,13 +156,30 @@ type Config struct {
 		FromAddress       string   `toml:"from_address"`
 		SendTo            []string `toml:"send_to"`
 		AcceptInvalidCert bool     `toml:"accept_invalid_cert"`
+
+		// the locale of the notifications, e.g. ja, and of the recipients who need another one, by their address;
+		// the translations and the templates of a locale are in a directory of template_path named after it
+		Locale           string            `toml:"locale"`
+		RecipientLocales map[string]string `toml:"recipient_locales"`
+
+		// the summary reports of the clusters are sent daily or weekly at report_time (HH:MM, local time),
+		// the weekly ones on report_weekday, to report_send_to, or to send_to if unset; not sent if unset
+		ReportSchedule string   `toml:"report_schedule"`
+		ReportTime     string   `toml:"report_time"`
+		ReportWeekday  string   `toml:"report_weekday"`
+		ReportSendTo   []string `toml:"report_send_to"`
 	} `toml:"mailer"`
 
 	BasicAuth struct {
+		mutex sync.RWMutex
+
 		User     string `toml:"user"`
 		Password string `toml:"password"`
 	} `toml:"basic_auth"`
 
+	FireCmd    FireCmdConfig    `toml:"fire_cmd"`
+	ServerLogs ServerLogsConfig `toml:"server_logs"`
+
 	TLS struct {
 		ServerPool []string `toml:"server_cert_pool"`
 		ClientPool map[string]struct {
@@ -108,10 +190,66 @@ type Config struct {
 
 	serverPool *x509.CertPool
 	clientPool []tls.Certificate
+	poolMutex  sync.RWMutex
+
+	// guards the settings changed by Reload, but those of the mailer and basic auth, which have their own
+	mutex sync.RWMutex
+
+	// the file the config was read from, used to reload it
+	file string
+
+	// applied again to the config file on reload
+	overrides []func(*Config)
 
 	LogFile *os.File
 }
 
+// ClusterConfig - a cluster of the config file, monitored from the start
+type ClusterConfig struct {
+	Host                 string `toml:"host"`
+	TLSName              string `toml:"tls_name"`
+	Port                 uint16 `toml:"port"`
+	User                 string `toml:"user"`
+	Password             string `toml:"password"`
+	Alias                string `toml:"alias"`
+	UseServicesAlternate bool   `toml:"use_services_alternate"`
+	ShowInUI             bool   `toml:"show_in_ui"`
+
+	// client connection policy; timeouts are in seconds
+	ConnectionQueueSize int `toml:"connection_queue_size"`
+	Timeout             int `toml:"timeout"`
+	IdleTimeout         int `toml:"idle_timeout"`
+	LoginTimeout        int `toml:"login_timeout"`
+
+	// addresses advertised by the nodes mapped to the addresses AMC reaches them at
+	AlternateAddresses map[string]string `toml:"alternate_addresses"`
+
+	// SRV record listing the nodes; host and port are ignored if set
+	SRVRecord string `toml:"srv_record"`
+
+	// Kubernetes service in the namespace/service form whose endpoints are the nodes; host is ignored if set
+	KubernetesService string `toml:"kubernetes_service"`
+
+	// the optional stat groups collected: full, basic, minimal or a comma delimited list of groups
+	StatProfile string `toml:"stat_profile"`
+
+	// the schedule of the summary reports of the cluster: daily, weekly or off; report_schedule of the mailer if unset
+	ReportSchedule string `toml:"report_schedule"`
+}
+
+// FireCmdConfig - the info commands which may be sent to the clusters
+type FireCmdConfig struct {
+	Allow []string `toml:"allow"`
+	Deny  []string `toml:"deny"`
+}
+
+// ServerLogsConfig - the endpoint serving the logs of the nodes
+type ServerLogsConfig struct {
+	Endpoint string `toml:"endpoint"`
+	Timeout  int    `toml:"timeout"`
+	MaxLines int    `toml:"max_lines"`
+}
+
 // AppendAlertEmails - send email
 func (c *Config) AppendAlertEmails(emails []string) error {
 	c.Mailer.mutex.Lock()
@@ -180,18 +318,140 @@ func (c *Config) FromAddress() string {
 	return fromUser
 }
 
+// TLSEnabled - check whether AMC is served over https, with the certificate files or the ACME certificates
+func (c *Config) TLSEnabled() bool {
+	return c.AMC.CertFile != "" || len(c.AMC.ACMEDomains) > 0
+}
+
+// HistoryMemoryLimit - get the memory budget for the history of all clusters in bytes; 0 means unlimited
+func (c *Config) HistoryMemoryLimit() int64 {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return int64(c.AMC.HistoryMemoryLimit) * 1024 * 1024
+}
+
+// SlowRequestThreshold - the response time from which the requests are logged as slow; 0 if they are not logged
+func (c *Config) SlowRequestThreshold() time.Duration {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return time.Duration(c.AMC.SlowRequestThreshold) * time.Millisecond
+}
+
+// HistoryMemoryLimitPerCluster - get the memory budget for the history of each cluster in bytes; 0 means unlimited
+func (c *Config) HistoryMemoryLimitPerCluster() int64 {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return int64(c.AMC.HistoryMemoryLimitPerCluster) * 1024 * 1024
+}
+
+// ClusterConfigs - the clusters of the config file; Reload replaces the map rather than changing it
+func (c *Config) ClusterConfigs() map[string]ClusterConfig {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.AMC.Clusters
+}
+
+// InactiveDurBeforeRemoval - the time a cluster may be idle before it is removed; 0 if never
+func (c *Config) InactiveDurBeforeRemoval() time.Duration {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	if c.AMC.InactiveDurBeforeRemoval <= 0 {
+		return 0
+	}
+	return time.Duration(c.AMC.InactiveDurBeforeRemoval) * time.Second
+}
+
+// InactiveDurBeforeDisconnect - the time a cluster may be idle before its connections are closed; 0 if never
+func (c *Config) InactiveDurBeforeDisconnect() time.Duration {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	if c.AMC.InactiveDurBeforeDisconnect <= 0 {
+		return 0
+	}
+	return time.Duration(c.AMC.InactiveDurBeforeDisconnect) * time.Second
+}
+
+// InfoCacheTTL - info_cache_ttl in milliseconds, as configured
+func (c *Config) InfoCacheTTL() int {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.AMC.InfoCacheTTL
+}
+
+// PreferIPVersion - the IP version tried first; 0 for no preference
+func (c *Config) PreferIPVersion() int {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.AMC.PreferIPVersion
+}
+
+// DNSRefreshInterval - dns_refresh_interval in seconds, as configured
+func (c *Config) DNSRefreshInterval() int {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.AMC.DNSRefreshInterval
+}
+
+// MaxClusters - the maximum number of clusters monitored at once; 0 for no limit
+func (c *Config) MaxClusters() int {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.AMC.MaxClusters
+}
+
+// PublicStatus - whether /status is served without authentication
+func (c *Config) PublicStatus() bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.AMC.PublicStatus
+}
+
+// ReverseProxy - whether AMC is served through a reverse proxy on its own host
+func (c *Config) ReverseProxy() bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.AMC.ReverseProxy
+}
+
+// TestClusters - the cluster-names of the test clusters
+func (c *Config) TestClusters() []string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.AMC.TestClusters
+}
+
+// FireCmdRules - the allowed and the denied info commands
+func (c *Config) FireCmdRules() FireCmdConfig {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.FireCmd
+}
+
+// ServerLogSettings - the endpoint of the logs of the nodes
+func (c *Config) ServerLogSettings() ServerLogsConfig {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.ServerLogs
+}
+
 // ServerPool - return serverPool
 func (c *Config) ServerPool() *x509.CertPool {
+	c.poolMutex.RLock()
+	defer c.poolMutex.RUnlock()
 	return c.serverPool
 }
 
 // ClientPool - return clientPool
 func (c *Config) ClientPool() []tls.Certificate {
+	c.poolMutex.RLock()
+	defer c.poolMutex.RUnlock()
 	return c.clientPool
 }
 
 // LogLevel - return log.Level
 func (c *Config) LogLevel() log.Level {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
 	switch strings.ToLower(c.AMC.LogLevel) {
 	case "debug":
 		return log.DebugLevel
@@ -208,6 +468,8 @@ func (c *Config) LogLevel() log.Level {
 
 // AeroLogLevel - return aslog.LogPriority
 func (c *Config) AeroLogLevel() aslog.LogPriority {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
 	switch strings.ToLower(c.AMC.LogLevel) {
 	case "debug":
 		return aslog.DEBUG
@@ -222,37 +484,8 @@ func (c *Config) AeroLogLevel() aslog.LogPriority {
 	}
 }
 
-// InitConfig - init the config struct
-func InitConfig(configFile, configDir string, config *Config) {
-	// to print everything out regarding reading the config in app init
-	log.SetLevel(log.DebugLevel)
-
-	log.Info("Reading config file...")
-	blob, err := ioutil.ReadFile(configFile)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	if _, err := toml.Decode(string(blob), &config); err != nil {
-		log.Fatal(err)
-	}
-
-	if config.AMC.Chdir != "" {
-		if err := os.Chdir(config.AMC.Chdir); err != nil {
-			log.Fatal("Error while trying to chdir to the specified directory in "+configFile+":", err)
-		}
-	}
-
-	if AMCIsProd() {
-		config.LogFile = setLogFile(config.AMC.ErrorLog)
-	}
-
-	if config.AMC.UpdateInterval < 1 {
-		config.AMC.UpdateInterval = 5
-	} else if config.AMC.UpdateInterval > 10 {
-		config.AMC.UpdateInterval = 10
-	}
-
+// loadCertPools - load the server and client certificate pools from the TLS config
+func (c *Config) loadCertPools() {
 	// Try to load system CA certs, otherwise just make an empty pool
 	serverPool, err := x509.SystemCertPool()
 	if serverPool == nil || err != nil {
@@ -260,8 +493,12 @@ func InitConfig(configFile, configDir string, config *Config) {
 		serverPool = x509.NewCertPool()
 	}
 
+	c.mutex.RLock()
+	settings := c.TLS
+	c.mutex.RUnlock()
+
 	// Try to load system CA certs and add them to the system cert pool
-	for _, caFile := range config.TLS.ServerPool {
+	for _, caFile := range settings.ServerPool {
 		caCert, err := ioutil.ReadFile(caFile)
 		if err != nil {
 			log.Errorf("FAILED: Adding server certificate %s to the pool failed: %s", caFile, err)
@@ -272,10 +509,10 @@ func InitConfig(configFile, configDir string, config *Config) {
 		serverPool.AppendCertsFromPEM(caCert)
 	}
 
-	config.serverPool = serverPool
+	var clientPool []tls.Certificate
 
 	// Try to load system CA certs and add them to the system cert pool
-	for _, cFiles := range config.TLS.ClientPool {
+	for _, cFiles := range settings.ClientPool {
 		cert, err := tls.LoadX509KeyPair(cFiles.CertFile, cFiles.KeyFile)
 		if err != nil {
 			log.Errorf("FAILED: Adding client certificate %s to the pool failed: %s", cFiles.CertFile, err)
@@ -283,12 +520,94 @@ func InitConfig(configFile, configDir string, config *Config) {
 		}
 
 		log.Debugf("Adding client certificate %s to the pool...", cFiles.CertFile)
-		config.clientPool = append(config.clientPool, cert)
+		clientPool = append(clientPool, cert)
 	}
 
+	c.poolMutex.Lock()
+	c.serverPool = serverPool
+	c.clientPool = clientPool
+	c.poolMutex.Unlock()
+}
+
+// InitConfig - init the config struct; the overrides, e.g. the command line flags,
+// are applied to the values read from the config file
+func InitConfig(configFile, configDir string, config *Config, overrides ...func(*Config)) {
+	// to print everything out regarding reading the config in app init
+	log.SetLevel(log.DebugLevel)
+
+	log.Info("Reading config file...")
+	if err := LoadConfig(configFile, config); err != nil {
+		log.Fatal(err)
+	}
+
+	config.overrides = overrides
+	for _, override := range overrides {
+		override(config)
+	}
+
+	// fail now rather than when the invalid settings are used
+	invalid := false
+	for _, err := range config.Validate() {
+		if cerr, ok := err.(*ConfigError); ok && cerr.Warning {
+			log.Warn(err)
+			continue
+		}
+		log.Error(err)
+		invalid = true
+	}
+	if invalid {
+		log.Fatalf("Invalid config file %s; run amc validate-config to check it", configFile)
+	}
+
+	if config.AMC.Chdir != "" {
+		if err := os.Chdir(config.AMC.Chdir); err != nil {
+			log.Fatal("Error while trying to chdir to the specified directory in "+configFile+":", err)
+		}
+	}
+
+	if AMCIsProd() {
+		config.LogFile = setLogFile(config.AMC.ErrorLog)
+	}
+
+	if config.AMC.UpdateInterval < 1 {
+		config.AMC.UpdateInterval = 5
+	} else if config.AMC.UpdateInterval > 10 {
+		config.AMC.UpdateInterval = 10
+	}
+
+	config.loadCertPools()
+
 	aslog.Logger.SetLogger(log.StandardLogger())
 
 	setLogLevel(config.AMC.LogLevel)
+	setLogFormat(config.AMC.LogFormat)
+}
+
+// LoadConfig - read the config file, in TOML, YAML or JSON by its extension, and the AMC_* environment
+// variables overriding it, without applying them
+func LoadConfig(configFile string, config *Config) error {
+	if _, err := decodeConfigFile(configFile, config); err != nil {
+		// the syntax errors have their line, but the type errors have neither their key nor their line
+		if errs := configTypeErrors(configFile); len(errs) > 0 {
+			msgs := make([]string, len(errs))
+			for i := range errs {
+				msgs[i] = errs[i].Error()
+			}
+			return errors.New(strings.Join(msgs, "\n"))
+		}
+		return fmt.Errorf("%s: %s", configFile, err.Error())
+	}
+
+	if err := applyEnv(config); err != nil {
+		return err
+	}
+
+	// keep the absolute path, since the working directory may change
+	config.file = configFile
+	if abs, err := filepath.Abs(configFile); err == nil {
+		config.file = abs
+	}
+	return nil
 }
 
 // SetupDatabase - create memsql tables
@@ -337,6 +656,52 @@ func SetupDatabase(filepath string) {
 			ALTER TABLE backups ADD ModifiedBefore string;
 			ALTER TABLE backups ADD ModifiedAfter string;
 		COMMIT;`,
+		`CREATE TABLE IF NOT EXISTS config_changes (
+			Id          string,
+			ClusterId   string,
+			NodeAddress string,
+			Context     string,
+			Namespace   string,
+			Parameter   string,
+			OldValue    string,
+			NewValue    string,
+			Username    string,
+			Created     time,
+			RolledBack  bool
+		);`,
+		`CREATE INDEX IF NOT EXISTS idxConfigChangesClusterId ON config_changes (ClusterId);`,
+		`CREATE TABLE IF NOT EXISTS role_templates (
+			Name        string,
+			Privileges  string,
+			Whitelist   string,
+			Created     time,
+			Updated     time
+		);`,
+		`CREATE TABLE IF NOT EXISTS monitored_clusters (
+			Id                   string,
+			Seeds                string,
+			TLSName              string,
+			Alias                string,
+			Username             string,
+			Password             string,
+			EncryptOnly          bool,
+			UseServicesAlternate bool,
+			Created              time
+		);`,
+		`BEGIN TRANSACTION;
+			ALTER TABLE monitored_clusters ADD ConnectionQueueSize int64;
+			ALTER TABLE monitored_clusters ADD Timeout int64;
+			ALTER TABLE monitored_clusters ADD IdleTimeout int64;
+			ALTER TABLE monitored_clusters ADD LoginTimeout int64;
+			ALTER TABLE monitored_clusters ADD AlternateAddresses string;
+			ALTER TABLE monitored_clusters ADD SRVRecord string;
+		COMMIT;`,
+		`BEGIN TRANSACTION;
+			ALTER TABLE monitored_clusters ADD AuthMode string;
+			ALTER TABLE monitored_clusters ADD TLSCACert string;
+			ALTER TABLE monitored_clusters ADD TLSClientCert string;
+			ALTER TABLE monitored_clusters ADD TLSClientKey string;
+		COMMIT;`,
 	}
 
 	log.Infof("Database path is: %s", filepath)
@@ -367,6 +732,14 @@ func SetupDatabase(filepath string) {
 	}
 }
 
+// PingDatabase - check that the database has been set up and can be used
+func PingDatabase() error {
+	if db == nil {
+		return errors.New("The database has not been set up")
+	}
+	return db.Ping()
+}
+
 func setLogFile(filepath string) *os.File {
 	out, err := os.OpenFile(filepath, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
 	if err != nil {
@@ -0,0 +1,168 @@
+package common
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	_configChangeFields = [...]string{
+		"Id",
+		"ClusterId",
+		"NodeAddress",
+		"Context",
+		"Namespace",
+		"Parameter",
+		"OldValue",
+		"NewValue",
+		"Username",
+		"Created",
+		"RolledBack",
+	}
+)
+
+// ConfigChange struct records a single dynamic configuration
+// parameter change applied to a node
+type ConfigChange struct {
+	ID          string
+	ClusterID   string
+	NodeAddress string
+	Context     string
+	Namespace   sql.NullString
+	Parameter   string
+	OldValue    string
+	NewValue    string
+	User        string
+	Created     time.Time
+	RolledBack  bool
+}
+
+// NewConfigChange - create new config change record
+func NewConfigChange(clusterID, nodeAddress, context, namespace, parameter, oldValue, newValue, user string) *ConfigChange {
+	return &ConfigChange{
+		ID:          uuid.NewV4().String(),
+		ClusterID:   clusterID,
+		NodeAddress: nodeAddress,
+		Context:     context,
+		Namespace:   ToNullString(namespace),
+		Parameter:   parameter,
+		OldValue:    oldValue,
+		NewValue:    newValue,
+		User:        user,
+		Created:     time.Now(),
+	}
+}
+
+// Save - save config change
+func (cc *ConfigChange) Save() error {
+	_dbGlobalMutex.Lock()
+	defer _dbGlobalMutex.Unlock()
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	if _, err := tx.Exec(
+		fmt.Sprintf("INSERT INTO config_changes (%s) VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9, ?10, ?11)", strings.Join(_configChangeFields[:], ", ")),
+		cc.ID, cc.ClusterID, cc.NodeAddress, cc.Context, cc.Namespace, cc.Parameter, cc.OldValue, cc.NewValue, cc.User, cc.Created, cc.RolledBack,
+	); err != nil {
+		log.Errorf("Error registering the config change in the DB: %s", err.Error())
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// MarkRolledBack - mark the config change as rolled back
+func (cc *ConfigChange) MarkRolledBack() error {
+	_dbGlobalMutex.Lock()
+	defer _dbGlobalMutex.Unlock()
+
+	cc.RolledBack = true
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	if _, err := tx.Exec("UPDATE config_changes SET RolledBack = ?1 WHERE Id = ?2", true, cc.ID); err != nil {
+		log.Errorf("Error updating the config change in the DB: %s", err.Error())
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// ConfigChanges - return the config changes for a cluster, latest first
+func ConfigChanges(clusterID string, limit int) ([]*ConfigChange, error) {
+	_dbGlobalMutex.Lock()
+	defer _dbGlobalMutex.Unlock()
+
+	if limit <= 0 {
+		limit = 100
+	}
+
+	rows, err := db.Query(fmt.Sprintf("SELECT %s FROM config_changes where ClusterId = ?1 ORDER BY Created DESC LIMIT %d", strings.Join(_configChangeFields[:], ", "), limit), clusterID)
+	if err != nil {
+		log.Errorf("Error querying config changes in the DB: %s", err.Error())
+		return nil, err
+	}
+
+	defer rows.Close()
+	return configChangesFromSQLRows(rows)
+}
+
+// ConfigChangeByID - return a config change by its id
+func ConfigChangeByID(id string) (*ConfigChange, error) {
+	_dbGlobalMutex.Lock()
+	defer _dbGlobalMutex.Unlock()
+
+	rows, err := db.Query(fmt.Sprintf("SELECT %s FROM config_changes where Id = ?1", strings.Join(_configChangeFields[:], ", ")), id)
+	if err != nil {
+		log.Errorf("Error querying config changes in the DB: %s", err.Error())
+		return nil, err
+	}
+
+	defer rows.Close()
+	res, err := configChangesFromSQLRows(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res) == 0 {
+		return nil, nil
+	}
+
+	return res[0], nil
+}
+
+func configChangesFromSQLRows(rows *sql.Rows) ([]*ConfigChange, error) {
+	res := []*ConfigChange{}
+	for rows.Next() {
+		cc := ConfigChange{}
+		if err := rows.Scan(&cc.ID, &cc.ClusterID, &cc.NodeAddress, &cc.Context, &cc.Namespace, &cc.Parameter, &cc.OldValue, &cc.NewValue, &cc.User, &cc.Created, &cc.RolledBack); err != nil {
+			return res, err
+		}
+		res = append(res, &cc)
+	}
+
+	return res, nil
+}
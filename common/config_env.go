@@ -0,0 +1,195 @@
+package common
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// applyEnv - override the settings of the config file with the AMC_* environment variables, for container
+// deployments. The settings of [amc] are AMC_<KEY>, e.g. AMC_BIND and AMC_UPDATE_INTERVAL; the settings of the
+// other sections are AMC_<SECTION>_<KEY>, e.g. AMC_MAILER_HOST, and the settings of the tables of a section are
+// AMC_<SECTION>_<NAME>_<KEY>, e.g. AMC_CLUSTERS_LOCAL_HOST, which adds the table if it is not in the config file.
+// Arrays are comma separated, e.g. AMC_MAILER_SEND_TO=a@example.com,b@example.com, and tables of strings
+// or numbers are comma separated key=value pairs.
+func applyEnv(config *Config) error {
+	env := map[string]string{}
+	for _, kv := range os.Environ() {
+		if i := strings.Index(kv, "="); i > 0 && strings.HasPrefix(kv, "AMC_") {
+			env[kv[:i]] = kv[i+1:]
+		}
+	}
+	if len(env) == 0 {
+		return nil
+	}
+
+	rv := reflect.ValueOf(config).Elem()
+	for i := 0; i < rv.NumField(); i++ {
+		name, ok := envFieldName(rv.Type().Field(i))
+		if !ok {
+			continue
+		}
+
+		prefix := "AMC_" + name + "_"
+		if name == "AMC" {
+			prefix = "AMC_"
+		}
+		if err := applyEnvValue(rv.Field(i), strings.TrimSuffix(prefix, "_"), prefix, env); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// envFieldName - the upper case toml key of an exported field
+func envFieldName(f reflect.StructField) (string, bool) {
+	if f.PkgPath != "" {
+		return "", false
+	}
+	name := f.Tag.Get("toml")
+	if name == "" {
+		name = f.Name
+	}
+	return strings.ToUpper(name), true
+}
+
+// applyEnvValue - set the value from the variable name, or the fields of the value from the variables
+// starting with prefix
+func applyEnvValue(v reflect.Value, name, prefix string, env map[string]string) error {
+	switch {
+	case v.Kind() == reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			fieldName, ok := envFieldName(v.Type().Field(i))
+			if !ok {
+				continue
+			}
+			if err := applyEnvValue(v.Field(i), prefix+fieldName, prefix+fieldName+"_", env); err != nil {
+				return err
+			}
+		}
+		return nil
+	case v.Kind() == reflect.Map && v.Type().Elem().Kind() == reflect.Struct:
+		return applyEnvTables(v, prefix, env)
+	}
+
+	value, exists := env[name]
+	if !exists {
+		return nil
+	}
+
+	if err := setEnvValue(v, value); err != nil {
+		return fmt.Errorf("Invalid value of %s: %s", name, err.Error())
+	}
+	return nil
+}
+
+// applyEnvTables - set the fields of the tables of a section, e.g. AMC_CLUSTERS_<NAME>_HOST; the longest key
+// matching the end of the variable wins, so that <NAME>_IDLE_TIMEOUT is not read as <NAME>_IDLE's timeout
+func applyEnvTables(m reflect.Value, prefix string, env map[string]string) error {
+	elemType := m.Type().Elem()
+	var keys []string
+	for i := 0; i < elemType.NumField(); i++ {
+		if key, ok := envFieldName(elemType.Field(i)); ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return len(keys[i]) > len(keys[j]) })
+
+	names := map[string]bool{}
+	for variable := range env {
+		if !strings.HasPrefix(variable, prefix) {
+			continue
+		}
+		rest := variable[len(prefix):]
+		for _, key := range keys {
+			if len(rest) > len(key)+1 && strings.HasSuffix(rest, "_"+key) {
+				names[rest[:len(rest)-len(key)-1]] = true
+				break
+			}
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	if m.IsNil() {
+		m.Set(reflect.MakeMap(m.Type()))
+	}
+
+	for name := range names {
+		// the table of the config file, or a new one named in lower case
+		mapKey := reflect.ValueOf(strings.ToLower(name))
+		for _, k := range m.MapKeys() {
+			if strings.EqualFold(k.String(), name) {
+				mapKey = k
+				break
+			}
+		}
+
+		elem := reflect.New(elemType).Elem()
+		if existing := m.MapIndex(mapKey); existing.IsValid() {
+			elem.Set(existing)
+		}
+		if err := applyEnvValue(elem, prefix+name, prefix+name+"_", env); err != nil {
+			return err
+		}
+		m.SetMapIndex(mapKey, elem)
+	}
+	return nil
+}
+
+func setEnvValue(v reflect.Value, value string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint16:
+		i, err := strconv.ParseUint(value, 10, 16)
+		if err != nil {
+			return err
+		}
+		v.SetUint(i)
+	case reflect.Slice:
+		values := DeleteEmpty(strings.Split(value, ","))
+		s := reflect.MakeSlice(v.Type(), 0, len(values))
+		for _, value := range values {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := setEnvValue(elem, strings.TrimSpace(value)); err != nil {
+				return err
+			}
+			s = reflect.Append(s, elem)
+		}
+		v.Set(s)
+	case reflect.Map:
+		m := reflect.MakeMap(v.Type())
+		for _, pair := range DeleteEmpty(strings.Split(value, ",")) {
+			kv := strings.SplitN(pair, "=", 2)
+			if len(kv) != 2 {
+				return fmt.Errorf("%s is not a key=value pair", pair)
+			}
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := setEnvValue(elem, strings.TrimSpace(kv[1])); err != nil {
+				return err
+			}
+			m.SetMapIndex(reflect.ValueOf(strings.TrimSpace(kv[0])), elem)
+		}
+		v.Set(m)
+	default:
+		return fmt.Errorf("%s settings cannot be set from the environment", v.Kind())
+	}
+	return nil
+}
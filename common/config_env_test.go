@@ -0,0 +1,147 @@
+package common
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Config environment variables", func() {
+
+	var variables []string
+
+	setEnv := func(name, value string) {
+		Expect(os.Setenv(name, value)).To(Succeed())
+		variables = append(variables, name)
+	}
+
+	AfterEach(func() {
+		for _, name := range variables {
+			os.Unsetenv(name)
+		}
+		variables = nil
+	})
+
+	DescribeTable("converting the values to the types of the settings",
+		func(name, value string, setting func(*Config) interface{}, expected interface{}) {
+			setEnv(name, value)
+
+			config := &Config{}
+			Expect(applyEnv(config)).To(Succeed())
+			Expect(setting(config)).To(Equal(expected))
+		},
+		Entry("a string", "AMC_BIND", ":9090", func(c *Config) interface{} { return c.AMC.Bind }, ":9090"),
+		Entry("an integer", "AMC_UPDATE_INTERVAL", "5", func(c *Config) interface{} { return c.AMC.UpdateInterval }, 5),
+		Entry("a negative integer", "AMC_REQUEST_TIMEOUT", "-1", func(c *Config) interface{} { return c.AMC.RequestTimeout }, -1),
+		Entry("a boolean", "AMC_PUBLIC_STATUS", "true", func(c *Config) interface{} { return c.AMC.PublicStatus }, true),
+		Entry("a port", "AMC_MAILER_PORT", "587", func(c *Config) interface{} { return c.Mailer.Port }, uint16(587)),
+		Entry("an array", "AMC_MAILER_SEND_TO", "a@example.com, b@example.com,,", func(c *Config) interface{} { return c.Mailer.SendTo },
+			[]string{"a@example.com", "b@example.com"}),
+		Entry("a table of numbers", "AMC_ROUTE_TIMEOUTS", "/a=60, /b = 0", func(c *Config) interface{} { return c.AMC.RouteTimeouts },
+			map[string]int{"/a": 60, "/b": 0}),
+		Entry("a table of strings", "AMC_MAILER_RECIPIENT_LOCALES", "a@example.com=ja", func(c *Config) interface{} { return c.Mailer.RecipientLocales },
+			map[string]string{"a@example.com": "ja"}),
+		Entry("a setting of a section", "AMC_SERVER_LOGS_MAX_LINES", "500", func(c *Config) interface{} { return c.ServerLogs.MaxLines }, 500),
+		Entry("an array of a section", "AMC_FIRE_CMD_DENY", "truncate", func(c *Config) interface{} { return c.FireCmd.Deny }, []string{"truncate"}),
+	)
+
+	DescribeTable("rejecting the values which do not convert",
+		func(name, value, msg string) {
+			setEnv(name, value)
+			Expect(applyEnv(&Config{})).To(MatchError(ContainSubstring(msg)))
+		},
+		Entry("a string for an integer", "AMC_UPDATE_INTERVAL", "5s", "Invalid value of AMC_UPDATE_INTERVAL"),
+		Entry("a word for a boolean", "AMC_PUBLIC_STATUS", "maybe", "Invalid value of AMC_PUBLIC_STATUS"),
+		Entry("a port out of range", "AMC_MAILER_PORT", "70000", "Invalid value of AMC_MAILER_PORT"),
+		Entry("a string in a table of numbers", "AMC_ROUTE_TIMEOUTS", "/a=long", "Invalid value of AMC_ROUTE_TIMEOUTS"),
+		Entry("a table entry which is not a pair", "AMC_ROUTE_TIMEOUTS", "/a", "/a is not a key=value pair"),
+		Entry("a table setting of a new table", "AMC_CLUSTERS_LOCAL_PORT", "-1", "Invalid value of AMC_CLUSTERS_LOCAL_PORT"),
+	)
+
+	Context("with a config file", func() {
+
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "amc-config")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+
+		load := func(content string) *Config {
+			file := filepath.Join(dir, "amc.conf")
+			Expect(ioutil.WriteFile(file, []byte(content), 0600)).To(Succeed())
+
+			config := &Config{}
+			Expect(LoadConfig(file, config)).To(Succeed())
+			return config
+		}
+
+		It("overrides the settings of the file with the variables", func() {
+			setEnv("AMC_BIND", ":9090")
+			setEnv("AMC_MAILER_HOST", "smtp.example.com")
+
+			config := load("[amc]\nbind = \":8081\"\nloglevel = \"info\"\n\n[mailer]\nhost = \"localhost\"\nport = 25\n")
+			Expect(config.AMC.Bind).To(Equal(":9090"))
+			Expect(config.Mailer.Host).To(Equal("smtp.example.com"))
+		})
+
+		It("keeps the settings of the file which have no variable", func() {
+			setEnv("AMC_BIND", ":9090")
+
+			config := load("[amc]\nbind = \":8081\"\nloglevel = \"info\"\n\n[mailer]\nport = 25\n")
+			Expect(config.AMC.LogLevel).To(Equal("info"))
+			Expect(config.Mailer.Port).To(Equal(uint16(25)))
+		})
+
+		It("overrides a setting of a table of the file, keeping its other settings", func() {
+			setEnv("AMC_CLUSTERS_LOCAL_HOST", "10.0.0.1")
+
+			config := load("[amc.clusters.local]\nhost = \"127.0.0.1\"\nport = 3000\n")
+			Expect(config.AMC.Clusters).To(HaveLen(1))
+			Expect(config.AMC.Clusters["local"].Host).To(Equal("10.0.0.1"))
+			Expect(config.AMC.Clusters["local"].Port).To(Equal(uint16(3000)))
+		})
+
+		It("matches the tables of the file case-insensitively", func() {
+			setEnv("AMC_CLUSTERS_PROD_PORT", "4000")
+
+			config := load("[amc.clusters.Prod]\nhost = \"127.0.0.1\"\nport = 3000\n")
+			Expect(config.AMC.Clusters).To(HaveLen(1))
+			Expect(config.AMC.Clusters["Prod"].Port).To(Equal(uint16(4000)))
+		})
+
+		It("adds the tables which are not in the file, named in lower case", func() {
+			setEnv("AMC_CLUSTERS_EDGE_HOST", "10.0.0.2")
+			setEnv("AMC_CLUSTERS_EDGE_PORT", "3000")
+
+			config := load("[amc.clusters.local]\nhost = \"127.0.0.1\"\nport = 3000\n")
+			Expect(config.AMC.Clusters).To(HaveLen(2))
+			Expect(config.AMC.Clusters["edge"]).To(Equal(ClusterConfig{Host: "10.0.0.2", Port: 3000}))
+		})
+
+		It("reads the longest key of a table setting", func() {
+			setEnv("AMC_CLUSTERS_LOCAL_IDLE_TIMEOUT", "30")
+
+			config := load("[amc.clusters.local]\nhost = \"127.0.0.1\"\nport = 3000\n")
+			Expect(config.AMC.Clusters).To(HaveLen(1))
+			Expect(config.AMC.Clusters["local"].IdleTimeout).To(Equal(30))
+			Expect(config.AMC.Clusters["local"].Timeout).To(Equal(0))
+		})
+
+		It("ignores the variables of no setting", func() {
+			setEnv("AMC_NO_SUCH_SETTING", "1")
+
+			config := load("[amc]\nbind = \":8081\"\n")
+			Expect(config.AMC.Bind).To(Equal(":8081"))
+		})
+	})
+})
@@ -0,0 +1,214 @@
+package common
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v2"
+)
+
+// config files are TOML, unless their extension is .yaml, .yml or .json; the keys and the sections
+// are the same in all the formats, e.g. amc.bind is
+//
+//	amc:
+//	  bind: ":8081"
+const (
+	configFormatTOML = "toml"
+	configFormatYAML = "yaml"
+	configFormatJSON = "json"
+)
+
+func configFileFormat(file string) string {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml":
+		return configFormatYAML
+	case ".json":
+		return configFormatJSON
+	default:
+		return configFormatTOML
+	}
+}
+
+// decodeConfigFile - decode the config file into v; YAML and JSON are converted to TOML first,
+// so that the settings are decoded, and their unknown keys found, the same way in all the formats
+func decodeConfigFile(file string, v interface{}) (toml.MetaData, error) {
+	blob, err := ioutil.ReadFile(file)
+	if err != nil {
+		return toml.MetaData{}, err
+	}
+
+	format := configFileFormat(file)
+	if format == configFormatTOML {
+		return toml.Decode(string(blob), v)
+	}
+
+	var data interface{}
+	if format == configFormatYAML {
+		err = yaml.Unmarshal(blob, &data)
+	} else {
+		err = json.Unmarshal(blob, &data)
+		if serr, ok := err.(*json.SyntaxError); ok {
+			err = fmt.Errorf("line %d: %s", bytes.Count(blob[:serr.Offset], []byte("\n"))+1, err.Error())
+		}
+	}
+	if err != nil {
+		return toml.MetaData{}, err
+	}
+
+	tables, ok := normalizeConfigValue(data).(map[string]interface{})
+	if !ok {
+		return toml.MetaData{}, fmt.Errorf("the %s config must be a mapping of the sections", format)
+	}
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(tables); err != nil {
+		return toml.MetaData{}, err
+	}
+	return toml.Decode(buf.String(), v)
+}
+
+// normalizeConfigValue - convert the YAML mappings to string keyed maps, the integral JSON numbers
+// to integers, and drop the null values, which TOML does not have
+func normalizeConfigValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		res := make(map[string]interface{}, len(v))
+		for k, elem := range v {
+			if elem != nil {
+				res[fmt.Sprint(k)] = normalizeConfigValue(elem)
+			}
+		}
+		return res
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(v))
+		for k, elem := range v {
+			if elem != nil {
+				res[k] = normalizeConfigValue(elem)
+			}
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, 0, len(v))
+		for _, elem := range v {
+			if elem != nil {
+				res = append(res, normalizeConfigValue(elem))
+			}
+		}
+		return res
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			return int64(v)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+var _yamlKeyRe = regexp.MustCompile(`^(\s*)(?:-\s+)?("[^"]*"|'[^']*'|[^\s#:][^:#]*?)\s*:(\s|$)`)
+
+// yamlKeyLines - map the dotted keys of a YAML config file to their lines, by the indentation of the keys
+func yamlKeyLines(file string) map[string]int {
+	res := map[string]int{}
+	f, err := os.Open(file)
+	if err != nil {
+		return res
+	}
+	defer f.Close()
+
+	type level struct {
+		indent int
+		key    string
+	}
+	var stack []level
+
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		m := _yamlKeyRe.FindStringSubmatch(scanner.Text())
+		if m == nil {
+			continue
+		}
+
+		indent := len(m[1])
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+		stack = append(stack, level{indent, strings.ToLower(strings.Trim(m[2], `"'`))})
+
+		keys := make([]string, len(stack))
+		for i := range stack {
+			keys[i] = stack[i].key
+		}
+		res[strings.Join(keys, ".")] = line
+	}
+	return res
+}
+
+// jsonKeyLines - map the dotted keys of a JSON config file to their lines
+func jsonKeyLines(file string) map[string]int {
+	res := map[string]int{}
+	blob, err := ioutil.ReadFile(file)
+	if err != nil {
+		return res
+	}
+
+	// the path of the current value; objects expect a key next, arrays do not
+	type level struct {
+		isObject  bool
+		expectKey bool
+		key       string
+	}
+	var stack []*level
+
+	dec := json.NewDecoder(bytes.NewReader(blob))
+	for {
+		token, err := dec.Token()
+		if err != nil {
+			return res
+		}
+
+		var top *level
+		if len(stack) > 0 {
+			top = stack[len(stack)-1]
+		}
+
+		switch t := token.(type) {
+		case json.Delim:
+			switch t {
+			case '{', '[':
+				stack = append(stack, &level{isObject: t == '{', expectKey: t == '{'})
+				continue
+			case '}', ']':
+				stack = stack[:len(stack)-1]
+			}
+		case string:
+			if top != nil && top.isObject && top.expectKey {
+				top.key = strings.ToLower(t)
+				top.expectKey = false
+
+				var keys []string
+				for _, l := range stack {
+					if l.isObject {
+						keys = append(keys, l.key)
+					}
+				}
+				res[strings.Join(keys, ".")] = bytes.Count(blob[:dec.InputOffset()], []byte("\n")) + 1
+				continue
+			}
+		}
+
+		// a value was read; the object it belongs to expects the next key
+		if len(stack) > 0 && stack[len(stack)-1].isObject {
+			stack[len(stack)-1].expectKey = true
+		}
+	}
+}
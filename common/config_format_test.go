@@ -0,0 +1,203 @@
+package common
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"gopkg.in/yaml.v2"
+)
+
+// the same settings in the three formats
+const (
+	_testConfigTOML = `[amc]
+bind = ":8081"
+update_interval = 5
+public_status = true
+tls_cipher_suites = ["TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", "TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"]
+route_timeouts = { "/export" = 0, "/restore" = 600 }
+
+[amc.clusters.local]
+host = "127.0.0.1"
+port = 3000
+alternate_addresses = { "10.0.0.1:3000" = "127.0.0.1:3000" }
+
+[mailer]
+port = 587
+send_to = ["a@example.com"]
+`
+
+	_testConfigYAML = `amc:
+  bind: ":8081"
+  update_interval: 5
+  public_status: true
+  tls_cipher_suites:
+    - TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256
+    - TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384
+  route_timeouts:
+    /export: 0
+    /restore: 600
+  clusters:
+    local:
+      host: 127.0.0.1
+      port: 3000
+      alternate_addresses:
+        "10.0.0.1:3000": "127.0.0.1:3000"
+mailer:
+  port: 587
+  send_to: [a@example.com]
+  # unset settings
+  host: ~
+`
+
+	_testConfigJSON = `{
+  "amc": {
+    "bind": ":8081",
+    "update_interval": 5,
+    "public_status": true,
+    "tls_cipher_suites": ["TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", "TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"],
+    "route_timeouts": {"/export": 0, "/restore": 600},
+    "clusters": {
+      "local": {
+        "host": "127.0.0.1",
+        "port": 3000,
+        "alternate_addresses": {"10.0.0.1:3000": "127.0.0.1:3000"}
+      }
+    }
+  },
+  "mailer": {"port": 587, "send_to": ["a@example.com"], "host": null}
+}
+`
+)
+
+var _ = Describe("Config formats", func() {
+
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "amc-config")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	write := func(name, content string) string {
+		file := filepath.Join(dir, name)
+		Expect(ioutil.WriteFile(file, []byte(content), 0600)).To(Succeed())
+		return file
+	}
+
+	decode := func(name, content string) *Config {
+		config := &Config{}
+		md, err := decodeConfigFile(write(name, content), config)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(md.Undecoded()).To(BeEmpty())
+		return config
+	}
+
+	DescribeTable("reading the format by the extension",
+		func(name, format string) {
+			Expect(configFileFormat(name)).To(Equal(format))
+		},
+		Entry("a .conf file", "amc.conf", configFormatTOML),
+		Entry("a .toml file", "amc.toml", configFormatTOML),
+		Entry("a .yaml file", "amc.yaml", configFormatYAML),
+		Entry("a .yml file in upper case", "AMC.YML", configFormatYAML),
+		Entry("a .json file", "amc.json", configFormatJSON),
+	)
+
+	DescribeTable("decoding the YAML and JSON files to the settings of the TOML file",
+		func(name, content string) {
+			expected := decode("amc.conf", _testConfigTOML)
+			Expect(expected.AMC.Clusters["local"].Port).To(Equal(uint16(3000)))
+
+			Expect(decode(name, content)).To(Equal(expected))
+		},
+		Entry("YAML", "amc.yaml", _testConfigYAML),
+		Entry("JSON", "amc.json", _testConfigJSON),
+	)
+
+	DescribeTable("decoding the YAML and JSON files to the values of the TOML file",
+		func(name, content string) {
+			var expected, values map[string]interface{}
+			_, err := decodeConfigFile(write("amc.conf", _testConfigTOML), &expected)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = decodeConfigFile(write(name, content), &values)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(values).To(Equal(expected))
+		},
+		Entry("YAML", "amc.yaml", _testConfigYAML),
+		Entry("JSON", "amc.json", _testConfigJSON),
+	)
+
+	DescribeTable("round-tripping the values of the TOML file through YAML and JSON",
+		func(name string, marshal func(interface{}) ([]byte, error)) {
+			var values map[string]interface{}
+			_, err := decodeConfigFile(write("amc.conf", _testConfigTOML), &values)
+			Expect(err).NotTo(HaveOccurred())
+
+			blob, err := marshal(values)
+			Expect(err).NotTo(HaveOccurred())
+
+			var res map[string]interface{}
+			_, err = decodeConfigFile(write(name, string(blob)), &res)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal(values))
+			Expect(decode(name, string(blob))).To(Equal(decode("amc.conf", _testConfigTOML)))
+		},
+		Entry("YAML", "amc.yaml", yaml.Marshal),
+		Entry("JSON", "amc.json", json.Marshal),
+	)
+
+	DescribeTable("finding the unknown keys like in TOML",
+		func(name, content string) {
+			md, err := decodeConfigFile(write(name, content), &Config{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(md.Undecoded()).To(HaveLen(1))
+			Expect(md.Undecoded()[0].String()).To(Equal("amc.bnid"))
+		},
+		Entry("TOML", "amc.conf", "[amc]\nbnid = \":8081\"\n"),
+		Entry("YAML", "amc.yaml", "amc:\n  bnid: \":8081\"\n"),
+		Entry("JSON", "amc.json", `{"amc": {"bnid": ":8081"}}`),
+	)
+
+	DescribeTable("rejecting the malformed files",
+		func(name, content, msg string) {
+			_, err := decodeConfigFile(write(name, content), &Config{})
+			Expect(err).To(MatchError(ContainSubstring(msg)))
+		},
+		Entry("a YAML list", "amc.yaml", "- amc\n", "the yaml config must be a mapping of the sections"),
+		Entry("a JSON array", "amc.json", `["amc"]`, "the json config must be a mapping of the sections"),
+		Entry("a JSON syntax error, with its line", "amc.json", "{\n  \"amc\": {\n    \"bind\": \n  }\n}", "line 4:"),
+		Entry("a YAML syntax error", "amc.yaml", "amc:\n  bind: [\n", "yaml"),
+	)
+
+	It("keeps the fractional JSON numbers, and converts the integral ones to integers", func() {
+		Expect(normalizeConfigValue(map[string]interface{}{"a": 1.5, "b": 2.0, "c": []interface{}{3.0, nil}})).To(Equal(
+			map[string]interface{}{"a": 1.5, "b": int64(2), "c": []interface{}{int64(3)}},
+		))
+	})
+
+	DescribeTable("mapping the keys to their lines",
+		func(name, content string, keyLines func(string) map[string]int, expected map[string]int) {
+			lines := keyLines(write(name, content))
+			for key, line := range expected {
+				Expect(lines).To(HaveKeyWithValue(key, line))
+			}
+		},
+		Entry("YAML", "amc.yaml", _testConfigYAML, yamlKeyLines, map[string]int{
+			"amc": 1, "amc.bind": 2, "amc.route_timeouts./restore": 10, "amc.clusters.local.port": 14, "mailer": 17, "mailer.send_to": 19,
+		}),
+		Entry("JSON", "amc.json", _testConfigJSON, jsonKeyLines, map[string]int{
+			"amc": 2, "amc.bind": 3, "amc.route_timeouts": 7, "amc.clusters.local.port": 11, "mailer": 16,
+		}),
+	)
+})
@@ -0,0 +1,112 @@
+package common
+
+import (
+	"errors"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// BasicAuthCredentials - get the basic auth user and password;
+// the AMC_AUTH_USER and AMC_AUTH_PASSWORD environment variables take precedence over the config file
+func (c *Config) BasicAuthCredentials() (string, string) {
+	c.BasicAuth.mutex.RLock()
+	user, password := c.BasicAuth.User, c.BasicAuth.Password
+	c.BasicAuth.mutex.RUnlock()
+
+	if u := os.Getenv("AMC_AUTH_USER"); u != "" {
+		user = u
+	}
+
+	if p := os.Getenv("AMC_AUTH_PASSWORD"); p != "" {
+		password = p
+	}
+
+	return user, password
+}
+
+// Reload - read the config file again and apply the settings which can be changed at runtime:
+// the monitored clusters, mailer, basic auth, fire_cmd, server_logs, TLS certificate pools,
+// log level and format, cluster_inactive_before_removal, cluster_inactive_before_disconnect, info_cache_ttl, prefer_ip_version, dns_refresh_interval, max_clusters, reverse_proxy, test_clusters and the history memory limits.
+// Other settings require a restart and are only reported if they have changed.
+func (c *Config) Reload() error {
+	if c.file == "" {
+		return errors.New("The config was not read from a file")
+	}
+
+	newConfig := &Config{}
+	if err := LoadConfig(c.file, newConfig); err != nil {
+		return err
+	}
+	for _, override := range c.overrides {
+		override(newConfig)
+	}
+
+	// the current settings are kept if the new ones are invalid
+	for _, err := range newConfig.Validate() {
+		if cerr, ok := err.(*ConfigError); ok && cerr.Warning {
+			log.Warn(err)
+			continue
+		}
+		return err
+	}
+
+	if newConfig.AMC.Bind != c.AMC.Bind || newConfig.AMC.CertFile != c.AMC.CertFile || newConfig.AMC.KeyFile != c.AMC.KeyFile ||
+		newConfig.AMC.Database != c.AMC.Database || newConfig.AMC.StaticPath != c.AMC.StaticPath || newConfig.AMC.ErrorLog != c.AMC.ErrorLog ||
+		newConfig.AMC.Chdir != c.AMC.Chdir || newConfig.AMC.PIDFile != c.AMC.PIDFile || newConfig.AMC.SecretKeyFile != c.AMC.SecretKeyFile ||
+		newConfig.AMC.PollConcurrency != c.AMC.PollConcurrency {
+		log.Warn("Changes to bind, certfile, keyfile, database, secret_key_file, static_dir, errorlog, chdir, pidfile and poll_concurrency require a restart of AMC")
+	}
+
+	c.mutex.Lock()
+	c.AMC.Clusters = newConfig.AMC.Clusters
+	c.AMC.InactiveDurBeforeRemoval = newConfig.AMC.InactiveDurBeforeRemoval
+	c.AMC.InactiveDurBeforeDisconnect = newConfig.AMC.InactiveDurBeforeDisconnect
+	c.AMC.InfoCacheTTL = newConfig.AMC.InfoCacheTTL
+	c.AMC.PreferIPVersion = newConfig.AMC.PreferIPVersion
+	c.AMC.DNSRefreshInterval = newConfig.AMC.DNSRefreshInterval
+	c.AMC.MaxClusters = newConfig.AMC.MaxClusters
+	c.AMC.PublicStatus = newConfig.AMC.PublicStatus
+	c.AMC.ReverseProxy = newConfig.AMC.ReverseProxy
+	c.AMC.SlowRequestThreshold = newConfig.AMC.SlowRequestThreshold
+	c.AMC.TestClusters = newConfig.AMC.TestClusters
+	c.AMC.HistoryMemoryLimit = newConfig.AMC.HistoryMemoryLimit
+	c.AMC.HistoryMemoryLimitPerCluster = newConfig.AMC.HistoryMemoryLimitPerCluster
+	c.AMC.LogLevel = newConfig.AMC.LogLevel
+	c.AMC.LogFormat = newConfig.AMC.LogFormat
+	c.FireCmd = newConfig.FireCmd
+	c.ServerLogs = newConfig.ServerLogs
+	c.TLS = newConfig.TLS
+	c.mutex.Unlock()
+
+	setLogLevel(newConfig.AMC.LogLevel)
+	setLogFormat(newConfig.AMC.LogFormat)
+
+	c.Mailer.mutex.Lock()
+	c.Mailer.TemplatePath = newConfig.Mailer.TemplatePath
+	c.Mailer.Host = newConfig.Mailer.Host
+	c.Mailer.Port = newConfig.Mailer.Port
+	c.Mailer.User = newConfig.Mailer.User
+	c.Mailer.Password = newConfig.Mailer.Password
+	c.Mailer.FromAddress = newConfig.Mailer.FromAddress
+	c.Mailer.SendTo = newConfig.Mailer.SendTo
+	c.Mailer.AcceptInvalidCert = newConfig.Mailer.AcceptInvalidCert
+	c.Mailer.Locale = newConfig.Mailer.Locale
+	c.Mailer.RecipientLocales = newConfig.Mailer.RecipientLocales
+	c.Mailer.ReportSchedule = newConfig.Mailer.ReportSchedule
+	c.Mailer.ReportTime = newConfig.Mailer.ReportTime
+	c.Mailer.ReportWeekday = newConfig.Mailer.ReportWeekday
+	c.Mailer.ReportSendTo = newConfig.Mailer.ReportSendTo
+	c.Mailer.mutex.Unlock()
+	resetMessageCatalogs()
+
+	c.BasicAuth.mutex.Lock()
+	c.BasicAuth.User = newConfig.BasicAuth.User
+	c.BasicAuth.Password = newConfig.BasicAuth.Password
+	c.BasicAuth.mutex.Unlock()
+
+	c.loadCertPools()
+
+	log.Info("Config file ", c.file, " reloaded")
+	return nil
+}
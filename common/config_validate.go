@@ -0,0 +1,379 @@
+package common
+
+import (
+	"bufio"
+	"crypto/tls"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ConfigError is an invalid setting of the config file, with the line it is set at if it is set in the file
+type ConfigError struct {
+	File string
+	Line int
+	Key  string
+	Msg  string
+
+	// the setting is ignored, but AMC can run
+	Warning bool
+}
+
+func (e *ConfigError) Error() string {
+	pos := e.File
+	if e.Line > 0 {
+		pos = fmt.Sprintf("%s:%d", e.File, e.Line)
+	}
+	if e.Key == "" {
+		return fmt.Sprintf("%s: %s", pos, e.Msg)
+	}
+	return fmt.Sprintf("%s: %s: %s", pos, e.Key, e.Msg)
+}
+
+var (
+	_configTableRe = regexp.MustCompile(`^\s*\[+\s*([^\]]+?)\s*\]+`)
+	_configKeyRe   = regexp.MustCompile(`^\s*("[^"]*"|[A-Za-z0-9_-]+)\s*=`)
+)
+
+// configKeyLines - map the dotted keys and tables of the config file to the lines they are set at;
+// the keys are lower case, since the sections are matched case-insensitively, e.g. [AMC]
+func configKeyLines(file string) map[string]int {
+	switch configFileFormat(file) {
+	case configFormatYAML:
+		return yamlKeyLines(file)
+	case configFormatJSON:
+		return jsonKeyLines(file)
+	default:
+		return tomlKeyLines(file)
+	}
+}
+
+func tomlKeyLines(file string) map[string]int {
+	res := map[string]int{}
+	f, err := os.Open(file)
+	if err != nil {
+		return res
+	}
+	defer f.Close()
+
+	table := ""
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if m := _configTableRe.FindStringSubmatch(text); m != nil {
+			table = strings.ToLower(strings.Replace(m[1], `"`, "", -1))
+			res[table] = line
+		} else if m := _configKeyRe.FindStringSubmatch(text); m != nil {
+			key := strings.ToLower(strings.Trim(m[1], `"`))
+			if table != "" {
+				key = table + "." + key
+			}
+			res[key] = line
+		}
+	}
+	return res
+}
+
+// path - resolve a path of the config file relative to chdir
+func (c *Config) path(p string) string {
+	if c.AMC.Chdir == "" || filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(c.AMC.Chdir, p)
+}
+
+// Validate - check the config for unknown keys, out of range values and missing files, so that the
+// mistakes are reported when AMC starts rather than when the settings are used. The errors are
+// *ConfigError, in the order of the lines of the config file; unknown keys are warnings.
+// Relative paths are relative to chdir, like when AMC runs.
+func (c *Config) Validate() []error {
+	var errs []*ConfigError
+	add := func(key, format string, args ...interface{}) {
+		errs = append(errs, &ConfigError{Key: key, Msg: fmt.Sprintf(format, args...)})
+	}
+
+	// unknown keys, e.g. misspelled ones, are silently ignored by the decoder
+	if c.file != "" {
+		if md, err := decodeConfigFile(c.file, &Config{}); err == nil {
+			for _, key := range md.Undecoded() {
+				errs = append(errs, &ConfigError{Key: key.String(), Msg: "unknown key; it is ignored", Warning: true})
+			}
+		}
+	}
+
+	if c.AMC.StaticPath == "" {
+		add("amc.static_dir", "not set; it must be the directory of the UI files")
+	} else if fi, err := os.Stat(c.path(c.AMC.StaticPath)); err != nil || !fi.IsDir() {
+		add("amc.static_dir", "%s is not a directory", c.AMC.StaticPath)
+	}
+
+	if len(c.AMC.ACMEDomains) == 0 {
+		c.validateKeyPair("amc.certfile", "amc.keyfile", c.AMC.CertFile, c.AMC.KeyFile, add)
+	}
+
+	if c.AMC.Database != "" {
+		if fi, err := os.Stat(filepath.Dir(c.path(c.AMC.Database))); err != nil || !fi.IsDir() {
+			add("amc.database", "the directory of %s does not exist", c.AMC.Database)
+		}
+	}
+
+	// durations are in seconds
+	for key, value := range map[string]int{
+		"amc.cluster_inactive_before_removal":    c.AMC.InactiveDurBeforeRemoval,
+		"amc.cluster_inactive_before_disconnect": c.AMC.InactiveDurBeforeDisconnect,
+		"amc.info_cache_ttl":                     c.AMC.InfoCacheTTL,
+		"amc.dns_refresh_interval":               c.AMC.DNSRefreshInterval,
+		"amc.static_max_age":                     c.AMC.StaticMaxAge,
+		"amc.timeout":                            c.AMC.Timeout,
+		"amc.poll_concurrency":                   c.AMC.PollConcurrency,
+		"amc.max_clusters":                       c.AMC.MaxClusters,
+		"amc.history_memory_limit":               c.AMC.HistoryMemoryLimit,
+		"amc.history_memory_limit_per_cluster":   c.AMC.HistoryMemoryLimitPerCluster,
+		"amc.max_body_size":                      c.AMC.MaxBodySize,
+		"amc.request_timeout":                    c.AMC.RequestTimeout,
+		"amc.slow_request_threshold":             c.AMC.SlowRequestThreshold,
+		"server_logs.timeout":                    c.ServerLogs.Timeout,
+		"server_logs.max_lines":                  c.ServerLogs.MaxLines,
+	} {
+		if value < 0 {
+			add(key, "%d is negative", value)
+		}
+	}
+
+	for route, value := range c.AMC.RouteTimeouts {
+		if value < 0 {
+			add("amc.route_timeouts."+strings.ToLower(route), "%d is negative", value)
+		}
+	}
+
+	switch strings.ToLower(c.AMC.LogLevel) {
+	case "", "debug", "info", "warn", "warning", "err", "error":
+	default:
+		add("amc.loglevel", "%s is not one of debug, info, warn or error", c.AMC.LogLevel)
+	}
+
+	for key, format := range map[string]string{"amc.log_format": c.AMC.LogFormat, "amc.access_log_format": c.AMC.AccessLogFormat} {
+		switch strings.ToLower(format) {
+		case "", "text", "json":
+		default:
+			add(key, "%s is not one of text or json", format)
+		}
+	}
+
+	switch strings.TrimPrefix(c.AMC.TLSMinVersion, "v") {
+	case "", "1.0", "1.1", "1.2", "1.3":
+	default:
+		add("amc.tls_min_version", "%s is not one of 1.0, 1.1, 1.2 or 1.3", c.AMC.TLSMinVersion)
+	}
+
+	suites := map[string]bool{}
+	for _, suite := range tls.CipherSuites() {
+		suites[suite.Name] = true
+	}
+	for _, name := range c.AMC.TLSCipherSuites {
+		if !suites[strings.TrimSpace(name)] {
+			add("amc.tls_cipher_suites", "%s is an unknown or insecure cipher suite", name)
+		}
+	}
+
+	if v := c.AMC.PreferIPVersion; v != 0 && v != 4 && v != 6 {
+		add("amc.prefer_ip_version", "%d is not one of 4, 6 or 0", v)
+	}
+
+	if c.AMC.UnixSocketMode != "" {
+		if _, err := strconv.ParseUint(c.AMC.UnixSocketMode, 8, 32); err != nil {
+			add("amc.unix_socket_mode", "%s is not an octal mode, e.g. 0660", c.AMC.UnixSocketMode)
+		}
+	}
+
+	for name, server := range c.AMC.Clusters {
+		prefix := "amc.clusters." + name
+		if server.Host == "" && server.SRVRecord == "" && server.KubernetesService == "" {
+			add(prefix, "one of host, srv_record or kubernetes_service must be set")
+		}
+		if server.Host != "" && server.SRVRecord == "" && server.Port == 0 {
+			add(prefix+".port", "not set")
+		}
+		for key, value := range map[string]int{
+			".connection_queue_size": server.ConnectionQueueSize,
+			".timeout":               server.Timeout,
+			".idle_timeout":          server.IdleTimeout,
+			".login_timeout":         server.LoginTimeout,
+		} {
+			if value < 0 {
+				add(prefix+key, "%d is negative", value)
+			}
+		}
+		if _, err := ParseReportSchedule(server.ReportSchedule, "", ""); err != nil {
+			add(prefix+".report_schedule", "%s", err.Error())
+		}
+	}
+
+	for key, schedule := range map[string][3]string{
+		"mailer.report_schedule": {c.Mailer.ReportSchedule, "", ""},
+		"mailer.report_time":     {ReportDaily, c.Mailer.ReportTime, ""},
+		"mailer.report_weekday":  {ReportDaily, "", c.Mailer.ReportWeekday},
+	} {
+		if _, err := ParseReportSchedule(schedule[0], schedule[1], schedule[2]); err != nil {
+			add(key, "%s", err.Error())
+		}
+	}
+
+	for _, file := range c.TLS.ServerPool {
+		if _, err := os.Stat(c.path(file)); err != nil {
+			add("tls.server_cert_pool", "%s cannot be read: %s", file, err.Error())
+		}
+	}
+	for name, cert := range c.TLS.ClientPool {
+		prefix := "tls.client_certs." + name
+		c.validateKeyPair(prefix+".cert_file", prefix+".key_file", cert.CertFile, cert.KeyFile, add)
+	}
+
+	// point at the lines the keys are set at, falling back to the tables they belong to
+	lines := configKeyLines(c.file)
+	for _, err := range errs {
+		err.File = c.file
+		for key := strings.ToLower(err.Key); key != "" && err.Line == 0; {
+			err.Line = lines[key]
+			if i := strings.LastIndex(key, "."); i >= 0 {
+				key = key[:i]
+			} else {
+				key = ""
+			}
+		}
+	}
+
+	sort.SliceStable(errs, func(i, j int) bool { return errs[i].Line < errs[j].Line })
+	res := make([]error, len(errs))
+	for i := range errs {
+		res[i] = errs[i]
+	}
+	return res
+}
+
+// validateKeyPair - the certificate and the key must be set together, and must be loadable
+func (c *Config) validateKeyPair(certKey, keyKey, certFile, keyFile string, add func(key, format string, args ...interface{})) {
+	switch {
+	case certFile == "" && keyFile == "":
+	case keyFile == "":
+		add(keyKey, "not set, but %s is", certKey[strings.LastIndex(certKey, ".")+1:])
+	case certFile == "":
+		add(certKey, "not set, but %s is", keyKey[strings.LastIndex(keyKey, ".")+1:])
+	default:
+		for key, file := range map[string]string{certKey: certFile, keyKey: keyFile} {
+			if _, err := os.Stat(c.path(file)); err != nil {
+				add(key, "%s cannot be read: %s", file, err.Error())
+				return
+			}
+		}
+		if _, err := tls.LoadX509KeyPair(c.path(certFile), c.path(keyFile)); err != nil {
+			add(certKey, "%s and %s are not a valid certificate and key: %s", certFile, keyFile, err.Error())
+		}
+	}
+}
+
+// configTypeErrors - find the settings whose values do not have the type of the setting, e.g. timeout = "30s";
+// the decoder only reports the first one, and without its key
+func configTypeErrors(file string) []error {
+	var data map[string]interface{}
+	if _, err := decodeConfigFile(file, &data); err != nil {
+		return nil
+	}
+
+	var errs []error
+	lines := configKeyLines(file)
+	add := func(key, format string, args ...interface{}) {
+		errs = append(errs, &ConfigError{File: file, Line: lines[strings.ToLower(key)], Key: key, Msg: fmt.Sprintf(format, args...)})
+	}
+	checkConfigType(data, reflect.TypeOf(Config{}), "", add)
+
+	sort.SliceStable(errs, func(i, j int) bool { return errs[i].(*ConfigError).Line < errs[j].(*ConfigError).Line })
+	return errs
+}
+
+func checkConfigType(value interface{}, t reflect.Type, key string, add func(key, format string, args ...interface{})) {
+	subKey := func(k string) string {
+		if key == "" {
+			return k
+		}
+		return key + "." + k
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		tmap, ok := value.(map[string]interface{})
+		if !ok {
+			add(key, "expected a table, found %s", configValueType(value))
+			return
+		}
+		for k, v := range tmap {
+			for i := 0; i < t.NumField(); i++ {
+				f := t.Field(i)
+				name := f.Tag.Get("toml")
+				if name == "" {
+					name = f.Name
+				}
+				if f.PkgPath == "" && strings.EqualFold(name, k) {
+					checkConfigType(v, f.Type, subKey(k), add)
+					break
+				}
+			}
+		}
+	case reflect.Map:
+		tmap, ok := value.(map[string]interface{})
+		if !ok {
+			add(key, "expected a table, found %s", configValueType(value))
+			return
+		}
+		for k, v := range tmap {
+			checkConfigType(v, t.Elem(), subKey(k), add)
+		}
+	case reflect.Slice:
+		rv := reflect.ValueOf(value)
+		if rv.Kind() != reflect.Slice {
+			add(key, "expected an array, found %s", configValueType(value))
+			return
+		}
+		for i := 0; i < rv.Len(); i++ {
+			checkConfigType(rv.Index(i).Interface(), t.Elem(), key, add)
+		}
+	case reflect.String:
+		if _, ok := value.(string); !ok {
+			add(key, "expected a string, found %s", configValueType(value))
+		}
+	case reflect.Bool:
+		if _, ok := value.(bool); !ok {
+			add(key, "expected true or false, found %s", configValueType(value))
+		}
+	case reflect.Int, reflect.Int64, reflect.Uint16:
+		if _, ok := value.(int64); ok {
+			return
+		}
+		if s, ok := value.(string); ok {
+			if _, err := time.ParseDuration(s); err == nil {
+				add(key, "expected an integer, found %s; durations are in seconds, e.g. 30", configValueType(value))
+				return
+			}
+		}
+		add(key, "expected an integer, found %s", configValueType(value))
+	}
+}
+
+func configValueType(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return strconv.Quote(v)
+	case map[string]interface{}:
+		return "a table"
+	case []interface{}, []map[string]interface{}:
+		return "an array"
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,142 @@
+package common
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Config validation", func() {
+
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "amc-config")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	// validate - load the [amc] section with a valid static_dir and the settings given, and validate it;
+	// the settings may start other sections
+	validate := func(settings string) []error {
+		file := filepath.Join(dir, "amc.conf")
+		content := fmt.Sprintf("[amc]\nstatic_dir = %q\n%s\n", dir, settings)
+		Expect(ioutil.WriteFile(file, []byte(content), 0600)).To(Succeed())
+
+		config := &Config{}
+		Expect(LoadConfig(file, config)).To(Succeed())
+		return config.Validate()
+	}
+
+	DescribeTable("accepting the valid settings",
+		func(settings string) {
+			Expect(validate(settings)).To(BeEmpty())
+		},
+		Entry("the defaults", ""),
+		Entry("the log settings", `loglevel = "warning"`+"\n"+`log_format = "json"`+"\n"+`access_log_format = "text"`),
+		Entry("a TLS version", `tls_min_version = "1.2"`),
+		Entry("a TLS version with a v", `tls_min_version = "v1.3"`),
+		Entry("the secure cipher suites", `tls_cipher_suites = ["TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", " TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384"]`),
+		Entry("an IP version", "prefer_ip_version = 6"),
+		Entry("the durations and limits", "request_timeout = 60\nmax_body_size = 20\nroute_timeouts = { \"/export\" = 0 }"),
+		Entry("a unix socket mode", `unix_socket_mode = "0660"`),
+		Entry("a cluster by its host", "[amc.clusters.local]\nhost = \"127.0.0.1\"\nport = 3000\ntimeout = 5"),
+		Entry("a cluster by its SRV record", "[amc.clusters.srv]\nsrv_record = \"_aerospike._tcp.example.com\""),
+		Entry("a cluster by its Kubernetes service", "[amc.clusters.k8s]\nkubernetes_service = \"aerospike/aerospike\""),
+		Entry("a weekly report", "[mailer]\nreport_schedule = \"weekly\"\nreport_time = \"08:30\"\nreport_weekday = \"friday\""),
+	)
+
+	DescribeTable("rejecting the invalid settings",
+		func(settings, key, msg string) {
+			errs := validate(settings)
+			Expect(errs).To(HaveLen(1))
+
+			cerr, ok := errs[0].(*ConfigError)
+			Expect(ok).To(BeTrue())
+			Expect(cerr.Key).To(Equal(key))
+			Expect(cerr.Msg).To(ContainSubstring(msg))
+			Expect(cerr.Warning).To(BeFalse())
+		},
+		Entry("a negative timeout", "request_timeout = -1", "amc.request_timeout", "-1 is negative"),
+		Entry("a negative route timeout", `route_timeouts = { "/export" = -5 }`, "amc.route_timeouts./export", "-5 is negative"),
+		Entry("an unknown log level", `loglevel = "verbose"`, "amc.loglevel", "verbose is not one of"),
+		Entry("an unknown log format", `log_format = "xml"`, "amc.log_format", "xml is not one of text or json"),
+		Entry("an unknown access log format", `access_log_format = "csv"`, "amc.access_log_format", "csv is not one of text or json"),
+		Entry("an unknown TLS version", `tls_min_version = "1.4"`, "amc.tls_min_version", "1.4 is not one of 1.0, 1.1, 1.2 or 1.3"),
+		Entry("an unknown cipher suite", `tls_cipher_suites = ["TLS_FAST"]`, "amc.tls_cipher_suites", "TLS_FAST is an unknown or insecure cipher suite"),
+		Entry("an insecure cipher suite", `tls_cipher_suites = ["TLS_RSA_WITH_RC4_128_SHA"]`, "amc.tls_cipher_suites", "unknown or insecure"),
+		Entry("an unknown IP version", "prefer_ip_version = 5", "amc.prefer_ip_version", "5 is not one of 4, 6 or 0"),
+		Entry("a non-octal socket mode", `unix_socket_mode = "0990"`, "amc.unix_socket_mode", "not an octal mode"),
+		Entry("a missing certificate key", `certfile = "cert.pem"`, "amc.keyfile", "not set, but certfile is"),
+		Entry("a missing certificate", `keyfile = "key.pem"`, "amc.certfile", "not set, but keyfile is"),
+		Entry("a database in a missing directory", `database = "/nonexistent/amc/amc.db"`, "amc.database", "does not exist"),
+		Entry("a cluster without an address", "[amc.clusters.local]\nalias = \"local\"", "amc.clusters.local", "one of host, srv_record or kubernetes_service"),
+		Entry("a cluster without a port", "[amc.clusters.local]\nhost = \"127.0.0.1\"", "amc.clusters.local.port", "not set"),
+		Entry("a cluster with a negative timeout", "[amc.clusters.local]\nhost = \"127.0.0.1\"\nport = 3000\nidle_timeout = -1",
+			"amc.clusters.local.idle_timeout", "-1 is negative"),
+		Entry("a cluster with an unknown report schedule", "[amc.clusters.local]\nhost = \"127.0.0.1\"\nport = 3000\nreport_schedule = \"hourly\"",
+			"amc.clusters.local.report_schedule", "hourly is not one of daily, weekly or off"),
+		Entry("an invalid report time", "[mailer]\nreport_schedule = \"daily\"\nreport_time = \"8am\"", "mailer.report_time", "not a time of day"),
+		Entry("an unknown report weekday", "[mailer]\nreport_schedule = \"weekly\"\nreport_weekday = \"someday\"", "mailer.report_weekday", "not a day of the week"),
+		Entry("a missing server certificate pool", "[tls]\nserver_cert_pool = [\"/nonexistent/ca.pem\"]", "tls.server_cert_pool", "cannot be read"),
+	)
+
+	It("warns about the unknown keys", func() {
+		errs := validate(`bnid = ":8081"`)
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].(*ConfigError).Key).To(Equal("amc.bnid"))
+		Expect(errs[0].(*ConfigError).Warning).To(BeTrue())
+	})
+
+	It("requires the certificate and the key to be readable", func() {
+		key := filepath.Join(dir, "key.pem")
+		Expect(ioutil.WriteFile(key, []byte("key"), 0600)).To(Succeed())
+
+		errs := validate(fmt.Sprintf("certfile = %q\nkeyfile = %q", filepath.Join(dir, "cert.pem"), key))
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].(*ConfigError).Key).To(Equal("amc.certfile"))
+		Expect(errs[0].(*ConfigError).Msg).To(ContainSubstring("cannot be read"))
+	})
+
+	It("requires the certificate and the key to be a valid pair", func() {
+		cert, key := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+		Expect(ioutil.WriteFile(cert, []byte("cert"), 0600)).To(Succeed())
+		Expect(ioutil.WriteFile(key, []byte("key"), 0600)).To(Succeed())
+
+		errs := validate(fmt.Sprintf("certfile = %q\nkeyfile = %q", cert, key))
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].(*ConfigError).Key).To(Equal("amc.certfile"))
+		Expect(errs[0].(*ConfigError).Msg).To(ContainSubstring("are not a valid certificate and key"))
+	})
+
+	It("requires the static directory", func() {
+		file := filepath.Join(dir, "amc.conf")
+		Expect(ioutil.WriteFile(file, []byte("[amc]\nstatic_dir = \"/nonexistent/static\"\n"), 0600)).To(Succeed())
+
+		config := &Config{}
+		Expect(LoadConfig(file, config)).To(Succeed())
+		errs := config.Validate()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Error()).To(Equal(file + ":2: amc.static_dir: /nonexistent/static is not a directory"))
+	})
+
+	It("reports the errors in the order of their lines", func() {
+		errs := validate("loglevel = \"verbose\"\nrequest_timeout = -1\n\n[amc.clusters.local]\nhost = \"127.0.0.1\"")
+		Expect(errs).To(HaveLen(3))
+
+		var lines []int
+		for _, err := range errs {
+			lines = append(lines, err.(*ConfigError).Line)
+		}
+		Expect(lines).To(Equal([]int{3, 4, 6}))
+	})
+})
@@ -0,0 +1,60 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrorKind - what a failure is about, set where the failure is detected so that the API can report its
+// error code without reading the message
+type ErrorKind int
+
+const (
+	// KindUnknown - the errors which were not created with a kind, e.g. the errors of the server and of the network
+	KindUnknown ErrorKind = iota
+	// KindInvalid - a parameter of the request is missing, malformed or out of range
+	KindInvalid
+	// KindNotFound - the object of the request does not exist, e.g. a UDF module or an index
+	KindNotFound
+	// KindNamespaceNotFound - the namespace of the request does not exist
+	KindNamespaceNotFound
+	// KindNodeNotFound - the node of the request is not in the cluster
+	KindNodeNotFound
+	// KindAlreadyExists - the object of the request exists already, or the operation is already running
+	KindAlreadyExists
+	// KindNotAllowed - the user or the config does not allow the operation
+	KindNotAllowed
+	// KindUnavailable - the cluster has no active node, or has been decommissioned
+	KindUnavailable
+	// KindNodeOffline - the node of the request is not active
+	KindNodeOffline
+)
+
+// Error - an error with its kind
+type Error struct {
+	Kind ErrorKind
+	err  error
+}
+
+// Errorf - new error of the given kind; the message is formatted like fmt.Errorf
+func Errorf(kind ErrorKind, format string, args ...interface{}) error {
+	return &Error{Kind: kind, err: fmt.Errorf(format, args...)}
+}
+
+func (e *Error) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap - the error wrapped with %w, if any
+func (e *Error) Unwrap() error {
+	return errors.Unwrap(e.err)
+}
+
+// ErrorKindOf - the kind of the error or of the first error it wraps which has one; KindUnknown if none has
+func ErrorKindOf(err error) ErrorKind {
+	var kerr *Error
+	if errors.As(err, &kerr) {
+		return kerr.Kind
+	}
+	return KindUnknown
+}
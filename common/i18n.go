@@ -0,0 +1,154 @@
+package common
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/BurntSushi/toml"
+	log "github.com/sirupsen/logrus"
+)
+
+// the translations of a locale are in <template_path>/<locale>/messages.toml, keyed by the English message,
+// or its format for the messages with values, e.g.
+//
+//	"Node <strong>%s</strong> is down" = "ノード <strong>%s</strong> がダウンしています"
+//
+// The values may be reordered with explicit indexes, e.g. %[2]s. The messages without a translation are English.
+const messageCatalogFile = "messages.toml"
+
+var (
+	_catalogsMutex sync.Mutex
+	_catalogs      = map[string]*messageCatalog{}
+)
+
+// the verbs of a format, and the literal percent signs
+var _formatVerbRe = regexp.MustCompile(`%%|%(\[\d+\])?[-+# 0]*\d*(?:\.\d+)?[a-zA-Z]`)
+
+type messagePattern struct {
+	msg    string
+	re     *regexp.Regexp
+	format string
+}
+
+type messageCatalog struct {
+	exact    map[string]string
+	patterns []messagePattern
+}
+
+// newMessageCatalog - compile the formats of the translations to patterns matching the formatted messages;
+// their values are substituted as strings into the translated formats
+func newMessageCatalog(translations map[string]string) *messageCatalog {
+	catalog := &messageCatalog{exact: translations}
+	for msg, translation := range translations {
+		if !_formatVerbRe.MatchString(msg) {
+			continue
+		}
+
+		var pattern strings.Builder
+		last := 0
+		for _, loc := range _formatVerbRe.FindAllStringIndex(msg, -1) {
+			pattern.WriteString(regexp.QuoteMeta(msg[last:loc[0]]))
+			if msg[loc[0]:loc[1]] == "%%" {
+				pattern.WriteString("%")
+			} else {
+				pattern.WriteString("(.*?)")
+			}
+			last = loc[1]
+		}
+		pattern.WriteString(regexp.QuoteMeta(msg[last:]))
+
+		re, err := regexp.Compile("^" + pattern.String() + "$")
+		if err != nil {
+			continue
+		}
+		format := _formatVerbRe.ReplaceAllStringFunc(translation, func(verb string) string {
+			if verb == "%%" {
+				return verb
+			}
+			return _formatVerbRe.ReplaceAllString(verb, "%${1}s")
+		})
+		catalog.patterns = append(catalog.patterns, messagePattern{msg: msg, re: re, format: format})
+	}
+
+	// the longer formats are more specific
+	sort.Slice(catalog.patterns, func(i, j int) bool { return len(catalog.patterns[i].msg) > len(catalog.patterns[j].msg) })
+	return catalog
+}
+
+func (c *messageCatalog) translate(msg string) string {
+	if translation, exists := c.exact[msg]; exists {
+		return translation
+	}
+
+	for _, p := range c.patterns {
+		if m := p.re.FindStringSubmatch(msg); m != nil {
+			values := make([]interface{}, len(m)-1)
+			for i := range values {
+				values[i] = m[i+1]
+			}
+			return fmt.Sprintf(p.format, values...)
+		}
+	}
+	return msg
+}
+
+// messageCatalog - load the translations of the locale once; nil if the locale has none
+func (c *Config) messageCatalog(locale string) *messageCatalog {
+	c.Mailer.mutex.RLock()
+	file := filepath.Join(c.Mailer.TemplatePath, filepath.Base(locale), messageCatalogFile)
+	c.Mailer.mutex.RUnlock()
+
+	_catalogsMutex.Lock()
+	defer _catalogsMutex.Unlock()
+
+	if catalog, exists := _catalogs[file]; exists {
+		return catalog
+	}
+
+	var catalog *messageCatalog
+	translations := map[string]string{}
+	if _, err := toml.DecodeFile(file, &translations); err == nil {
+		catalog = newMessageCatalog(translations)
+	} else if !os.IsNotExist(err) {
+		log.Errorf("Error reading the translations of locale %s: %s", locale, err.Error())
+	}
+	_catalogs[file] = catalog
+	return catalog
+}
+
+// resetMessageCatalogs - read the translations again when they are next used, e.g. after the config is reloaded
+func resetMessageCatalogs() {
+	_catalogsMutex.Lock()
+	defer _catalogsMutex.Unlock()
+	_catalogs = map[string]*messageCatalog{}
+}
+
+// Translate - translate the message, e.g. the description of an alert, to the locale;
+// the message is returned as it is if the locale is empty or has no translation of it
+func (c *Config) Translate(locale, msg string) string {
+	if locale == "" {
+		return msg
+	}
+	if catalog := c.messageCatalog(locale); catalog != nil {
+		return catalog.translate(msg)
+	}
+	return msg
+}
+
+// Locale - the locale of the notifications to the recipient: its own from recipient_locales, or locale
+func (c *Config) Locale(recipient string) string {
+	c.Mailer.mutex.RLock()
+	defer c.Mailer.mutex.RUnlock()
+
+	for r, locale := range c.Mailer.RecipientLocales {
+		if strings.EqualFold(r, recipient) {
+			return locale
+		}
+	}
+	return c.Mailer.Locale
+}
@@ -0,0 +1,39 @@
+package common
+
+import (
+	"context"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type loggerCtxKey struct{}
+
+// WithLogger - attach a logger to the context, so that the operations started by a request
+// log with its fields, e.g. its request_id
+func WithLogger(ctx context.Context, logger *log.Entry) context.Context {
+	return context.WithValue(ctx, loggerCtxKey{}, logger)
+}
+
+// Logger - get the logger attached to the context; the standard logger if there is none
+func Logger(ctx context.Context) *log.Entry {
+	if ctx != nil {
+		if logger, ok := ctx.Value(loggerCtxKey{}).(*log.Entry); ok {
+			return logger
+		}
+	}
+	return log.NewEntry(log.StandardLogger())
+}
+
+// setLogFormat - log as text, or as one JSON object per line for log collectors
+func setLogFormat(format string) {
+	switch strings.ToLower(format) {
+	case "json":
+		log.SetFormatter(&log.JSONFormatter{})
+	case "", "text":
+		log.SetFormatter(&log.TextFormatter{})
+	default:
+		log.SetFormatter(&log.TextFormatter{})
+		log.Warnf("Invalid log_format %s; must be text or json", format)
+	}
+}
@@ -0,0 +1,271 @@
+package common
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	_monitoredClusterFields = [...]string{
+		"Id",
+		"Seeds",
+		"TLSName",
+		"Alias",
+		"Username",
+		"Password",
+		"EncryptOnly",
+		"UseServicesAlternate",
+		"ConnectionQueueSize",
+		"Timeout",
+		"IdleTimeout",
+		"LoginTimeout",
+		"AlternateAddresses",
+		"SRVRecord",
+		"Created",
+		"AuthMode",
+		"TLSCACert",
+		"TLSClientCert",
+		"TLSClientKey",
+	}
+)
+
+// MonitoredCluster struct is a cluster registered from the UI
+// which is persisted to be monitored again after a restart.
+// The password is kept encrypted in the database.
+type MonitoredCluster struct {
+	Id                   string
+	Seeds                []string
+	TLSName              string
+	Alias                string
+	Username             string
+	Password             string
+	EncryptOnly          bool
+	UseServicesAlternate bool
+	Created              time.Time
+
+	// client connection policy; timeouts are in seconds
+	ConnectionQueueSize int
+	Timeout             int
+	IdleTimeout         int
+	LoginTimeout        int
+
+	// comma delimited advertised=alternate address pairs
+	AlternateAddresses string
+
+	// the cluster is seeded from this SRV record if set
+	SRVRecord string
+
+	// internal, external or pki
+	AuthMode string
+
+	// PEM encoded; the key is kept encrypted in the database like the password
+	TLSCACert     string
+	TLSClientCert string
+	TLSClientKey  string
+}
+
+// Save - insert or replace the monitored cluster
+func (mc *MonitoredCluster) Save() error {
+	password, err := EncryptSecret(mc.Password)
+	if err != nil {
+		log.Errorf("Error encrypting the password of the monitored cluster: %s", err.Error())
+		return err
+	}
+
+	clientKey, err := EncryptSecret(mc.TLSClientKey)
+	if err != nil {
+		log.Errorf("Error encrypting the TLS client key of the monitored cluster: %s", err.Error())
+		return err
+	}
+
+	_dbGlobalMutex.Lock()
+	defer _dbGlobalMutex.Unlock()
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM monitored_clusters WHERE Id = ?1", mc.Id); err != nil {
+		log.Errorf("Error replacing the monitored cluster in the DB: %s", err.Error())
+		tx.Rollback()
+		return err
+	}
+
+	if _, err := tx.Exec(
+		fmt.Sprintf("INSERT INTO monitored_clusters (%s) VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9, ?10, ?11, ?12, ?13, ?14, ?15, ?16, ?17, ?18, ?19)", strings.Join(_monitoredClusterFields[:], ", ")),
+		mc.Id, strings.Join(mc.Seeds, ","), mc.TLSName, mc.Alias, mc.Username, password, mc.EncryptOnly, mc.UseServicesAlternate,
+		mc.ConnectionQueueSize, mc.Timeout, mc.IdleTimeout, mc.LoginTimeout, mc.AlternateAddresses, mc.SRVRecord, mc.Created,
+		mc.AuthMode, mc.TLSCACert, mc.TLSClientCert, clientKey,
+	); err != nil {
+		log.Errorf("Error registering the monitored cluster in the DB: %s", err.Error())
+		tx.Rollback()
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteMonitoredCluster - delete the monitored cluster with the id; does nothing if it has not been persisted
+func DeleteMonitoredCluster(id string) error {
+	_dbGlobalMutex.Lock()
+	defer _dbGlobalMutex.Unlock()
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM monitored_clusters WHERE Id = ?1", id); err != nil {
+		log.Errorf("Error deleting the monitored cluster from the DB: %s", err.Error())
+		tx.Rollback()
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// UpdateMonitoredClusterAlias - update the alias of the monitored cluster with the id
+func UpdateMonitoredClusterAlias(id, alias string) error {
+	_dbGlobalMutex.Lock()
+	defer _dbGlobalMutex.Unlock()
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	if _, err := tx.Exec("UPDATE monitored_clusters SET Alias = ?1 WHERE Id = ?2", alias, id); err != nil {
+		log.Errorf("Error updating the monitored cluster in the DB: %s", err.Error())
+		tx.Rollback()
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// UpdateMonitoredClusterSeeds - update the seeds of the monitored cluster with the id
+func UpdateMonitoredClusterSeeds(id string, seeds []string) error {
+	_dbGlobalMutex.Lock()
+	defer _dbGlobalMutex.Unlock()
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	if _, err := tx.Exec("UPDATE monitored_clusters SET Seeds = ?1 WHERE Id = ?2", strings.Join(seeds, ","), id); err != nil {
+		log.Errorf("Error updating the monitored cluster in the DB: %s", err.Error())
+		tx.Rollback()
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// UpdateMonitoredClusterClientPolicy - update the client connection policy of the monitored cluster with the id
+func UpdateMonitoredClusterClientPolicy(id string, connectionQueueSize, timeout, idleTimeout, loginTimeout int) error {
+	_dbGlobalMutex.Lock()
+	defer _dbGlobalMutex.Unlock()
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	if _, err := tx.Exec(
+		"UPDATE monitored_clusters SET ConnectionQueueSize = ?1, Timeout = ?2, IdleTimeout = ?3, LoginTimeout = ?4 WHERE Id = ?5",
+		connectionQueueSize, timeout, idleTimeout, loginTimeout, id,
+	); err != nil {
+		log.Errorf("Error updating the monitored cluster in the DB: %s", err.Error())
+		tx.Rollback()
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// MonitoredClusters - return all persisted monitored clusters with their passwords decrypted
+func MonitoredClusters() ([]*MonitoredCluster, error) {
+	_dbGlobalMutex.Lock()
+	defer _dbGlobalMutex.Unlock()
+
+	rows, err := db.Query(fmt.Sprintf("SELECT %s FROM monitored_clusters ORDER BY Created", strings.Join(_monitoredClusterFields[:], ", ")))
+	if err != nil {
+		log.Errorf("Error querying monitored clusters in the DB: %s", err.Error())
+		return nil, err
+	}
+
+	defer rows.Close()
+	return monitoredClustersFromSQLRows(rows)
+}
+
+func monitoredClustersFromSQLRows(rows *sql.Rows) ([]*MonitoredCluster, error) {
+	res := []*MonitoredCluster{}
+	for rows.Next() {
+		mc := MonitoredCluster{}
+		var seeds, password string
+		// the columns added later are null for the clusters persisted before
+		var connectionQueueSize, timeout, idleTimeout, loginTimeout sql.NullInt64
+		var alternateAddresses, srvRecord, authMode, caCert, clientCert, clientKey sql.NullString
+		if err := rows.Scan(&mc.Id, &seeds, &mc.TLSName, &mc.Alias, &mc.Username, &password, &mc.EncryptOnly, &mc.UseServicesAlternate,
+			&connectionQueueSize, &timeout, &idleTimeout, &loginTimeout, &alternateAddresses, &srvRecord, &mc.Created,
+			&authMode, &caCert, &clientCert, &clientKey); err != nil {
+			return res, err
+		}
+		mc.ConnectionQueueSize, mc.Timeout = int(connectionQueueSize.Int64), int(timeout.Int64)
+		mc.IdleTimeout, mc.LoginTimeout = int(idleTimeout.Int64), int(loginTimeout.Int64)
+		mc.AlternateAddresses, mc.SRVRecord = alternateAddresses.String, srvRecord.String
+
+		var err error
+		if mc.Password, err = DecryptSecret(password); err != nil {
+			log.Errorf("Error decrypting the password of the monitored cluster %s: %s", seeds, err.Error())
+			continue
+		}
+
+		if mc.TLSClientKey, err = DecryptSecret(clientKey.String); err != nil {
+			log.Errorf("Error decrypting the TLS client key of the monitored cluster %s: %s", seeds, err.Error())
+			continue
+		}
+		mc.AuthMode, mc.TLSCACert, mc.TLSClientCert = authMode.String, caCert.String, clientCert.String
+
+		mc.Seeds = SplitList(seeds)
+		res = append(res, &mc)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,14 @@
+//go:build !deadlock
+// +build !deadlock
+
+package common
+
+import "sync"
+
+// Mutex and RWMutex are the locks used by the long lived structures of AMC.
+// Builds with the deadlock tag replace them with go-deadlock's, which report
+// lock ordering problems and locks held for too long, at a measurable cost.
+type (
+	Mutex   = sync.Mutex
+	RWMutex = sync.RWMutex
+)
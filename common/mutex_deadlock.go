@@ -0,0 +1,23 @@
+//go:build deadlock
+// +build deadlock
+
+package common
+
+import (
+	"os"
+
+	"github.com/sasha-s/go-deadlock"
+)
+
+// Mutex and RWMutex detect deadlocks in builds with the deadlock tag
+type (
+	Mutex   = deadlock.Mutex
+	RWMutex = deadlock.RWMutex
+)
+
+func init() {
+	// allows running a development build without the overhead
+	if os.Getenv("AMC_DISABLE_DEADLOCK_DETECTION") != "" {
+		deadlock.Opts.Disable = true
+	}
+}
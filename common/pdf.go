@@ -0,0 +1,234 @@
+package common
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// the layout of the PDF pages: A4 in points, Courier which is a standard font so that nothing is embedded
+const (
+	_pdfPageWidth  = 595.0
+	_pdfPageHeight = 842.0
+	_pdfMargin     = 40.0
+	_pdfFontSize   = 8.0
+	_pdfLineHeight = 10.0
+	_pdfCharWidth  = 0.6 * _pdfFontSize // the width of the Courier glyphs is 600/1000 of the font size
+
+	_pdfLineChars = 107 // (_pdfPageWidth - 2*_pdfMargin) / _pdfCharWidth, rounded down
+	_pdfPageLines = 76  // (_pdfPageHeight - 2*_pdfMargin) / _pdfLineHeight, rounded down
+)
+
+type pdfLine struct {
+	text string
+	bold bool
+}
+
+// PDFDocument is a minimal writer of plain text PDF documents, e.g. for reports: headings, paragraphs and tables
+// of monospaced text, laid out on as many pages as needed. Characters outside Latin-1 are written as '?'.
+type PDFDocument struct {
+	title string
+	lines []pdfLine
+}
+
+// NewPDFDocument - create a document; the title is set in its properties and on the footer of every page
+func NewPDFDocument(title string) *PDFDocument {
+	return &PDFDocument{title: title}
+}
+
+// Heading - add a bold heading, after an empty line
+func (d *PDFDocument) Heading(text string) {
+	if len(d.lines) > 0 {
+		d.lines = append(d.lines, pdfLine{})
+	}
+	d.lines = append(d.lines, pdfLine{text: text, bold: true})
+}
+
+// Text - add a paragraph, wrapped to the width of the page
+func (d *PDFDocument) Text(text string) {
+	for _, line := range strings.Split(text, "\n") {
+		for _, chunk := range pdfWrap(line, _pdfLineChars) {
+			d.lines = append(d.lines, pdfLine{text: chunk})
+		}
+	}
+}
+
+// Table - add a table; the columns are shrunk to fit the width of the page, and their cells wrapped
+func (d *PDFDocument) Table(header []string, rows [][]string) {
+	widths := make([]int, len(header))
+	for i, h := range header {
+		widths[i] = utf8.RuneCountInString(h)
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			if i < len(widths) && utf8.RuneCountInString(cell) > widths[i] {
+				widths[i] = utf8.RuneCountInString(cell)
+			}
+		}
+	}
+
+	// shrink the widest column until the columns and the spaces between them fit
+	for {
+		total, widest := 2*(len(widths)-1), 0
+		for i, w := range widths {
+			total += w
+			if w > widths[widest] {
+				widest = i
+			}
+		}
+		if total <= _pdfLineChars || widths[widest] <= 1 {
+			break
+		}
+		widths[widest]--
+	}
+
+	d.tableRow(widths, header, true)
+	separator := make([]string, len(widths))
+	for i, w := range widths {
+		separator[i] = strings.Repeat("-", w)
+	}
+	d.tableRow(widths, separator, false)
+	for _, row := range rows {
+		d.tableRow(widths, row, false)
+	}
+}
+
+func (d *PDFDocument) tableRow(widths []int, row []string, bold bool) {
+	cells := make([][]string, len(widths))
+	height := 1
+	for i := range widths {
+		if i < len(row) {
+			cells[i] = pdfWrap(row[i], widths[i])
+		}
+		if len(cells[i]) > height {
+			height = len(cells[i])
+		}
+	}
+
+	for l := 0; l < height; l++ {
+		var line strings.Builder
+		for i, w := range widths {
+			cell := ""
+			if l < len(cells[i]) {
+				cell = cells[i][l]
+			}
+			if i < len(widths)-1 {
+				cell += strings.Repeat(" ", w-utf8.RuneCountInString(cell)+2)
+			}
+			line.WriteString(cell)
+		}
+		d.lines = append(d.lines, pdfLine{text: strings.TrimRight(line.String(), " "), bold: bold})
+	}
+}
+
+// pdfWrap - split the text in lines of at most width characters, at the spaces if possible
+func pdfWrap(text string, width int) []string {
+	runes := []rune(text)
+	if len(runes) <= width {
+		return []string{text}
+	}
+
+	res := []string{}
+	for len(runes) > width {
+		cut := width
+		for i := width; i > width/2; i-- {
+			if runes[i] == ' ' {
+				cut = i
+				break
+			}
+		}
+		res = append(res, strings.TrimRight(string(runes[:cut]), " "))
+		runes = []rune(strings.TrimLeft(string(runes[cut:]), " "))
+	}
+	if len(runes) > 0 {
+		res = append(res, string(runes))
+	}
+	return res
+}
+
+// pdfString - the text as a PDF literal string in WinAnsiEncoding
+func pdfString(text string) string {
+	var buf bytes.Buffer
+	buf.WriteByte('(')
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			buf.WriteByte('\\')
+			buf.WriteRune(r)
+		case r >= 32 && r < 127, r >= 160 && r <= 255:
+			buf.WriteByte(byte(r))
+		default:
+			buf.WriteByte('?')
+		}
+	}
+	buf.WriteByte(')')
+	return buf.String()
+}
+
+// Bytes - lay out the document on pages and write it
+func (d *PDFDocument) Bytes() []byte {
+	perPage := _pdfPageLines - 2 // the footer and the line above it
+	var pages [][]pdfLine
+	for start := 0; start < len(d.lines) || len(pages) == 0; start += perPage {
+		end := start + perPage
+		if end > len(d.lines) {
+			end = len(d.lines)
+		}
+		pages = append(pages, d.lines[start:end])
+	}
+
+	// the objects are numbered from 1: the catalog, the page tree, the two fonts, the info and then
+	// the page and its content for every page
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>",
+		fmt.Sprintf("<< /Title %s /Producer (Aerospike Management Console) >>", pdfString(d.title)),
+	}
+
+	kids := make([]string, 0, len(pages))
+	for i, lines := range pages {
+		var content bytes.Buffer
+		y := _pdfPageHeight - _pdfMargin - _pdfFontSize
+		for _, line := range lines {
+			if line.text != "" {
+				font := "F1"
+				if line.bold {
+					font = "F2"
+				}
+				fmt.Fprintf(&content, "BT /%s %.0f Tf %.2f %.2f Td %s Tj ET\n", font, _pdfFontSize, _pdfMargin, y, pdfString(line.text))
+			}
+			y -= _pdfLineHeight
+		}
+		footer := fmt.Sprintf("%s - page %d of %d", d.title, i+1, len(pages))
+		fmt.Fprintf(&content, "BT /F1 %.0f Tf %.2f %.2f Td %s Tj ET\n", _pdfFontSize, _pdfMargin, _pdfMargin-_pdfLineHeight, pdfString(footer))
+
+		pageID := len(objects) + 1
+		kids = append(kids, fmt.Sprintf("%d 0 R", pageID))
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+				_pdfPageWidth, _pdfPageHeight, pageID+1),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()),
+		)
+	}
+	objects[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages))
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	return buf.Bytes()
+}
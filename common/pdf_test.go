@@ -0,0 +1,136 @@
+package common
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var (
+	_pdfTrailerRe = regexp.MustCompile(`trailer\n<< /Size (\d+) /Root 1 0 R /Info 5 0 R >>\nstartxref\n(\d+)\n%%EOF\n$`)
+	_pdfStreamRe  = regexp.MustCompile(`(?s)<< /Length (\d+) >>\nstream\n(.*?)endstream`)
+	_pdfTextRe    = regexp.MustCompile(`Td \(((?:[^()\\]|\\.)*)\) Tj`)
+)
+
+// expectWellFormedPDF - check the structure of the document: the header, the cross-reference table pointing
+// at every object, the trailer, the lengths of the streams and the page tree; returns the number of pages
+func expectWellFormedPDF(doc []byte) int {
+	Expect(doc).To(HavePrefix("%PDF-1.4\n"))
+
+	m := _pdfTrailerRe.FindSubmatch(doc)
+	Expect(m).NotTo(BeNil(), "the trailer")
+	size, _ := strconv.Atoi(string(m[1]))
+	xref, _ := strconv.Atoi(string(m[2]))
+
+	Expect(xref).To(BeNumerically("<", len(doc)))
+	table := string(doc[xref:])
+	Expect(table).To(HavePrefix(fmt.Sprintf("xref\n0 %d\n0000000000 65535 f \n", size)))
+
+	// every entry is 20 bytes, and points at its object
+	entries := table[len(fmt.Sprintf("xref\n0 %d\n", size)):]
+	for i := 1; i < size; i++ {
+		entry := entries[20*i : 20*(i+1)]
+		Expect(entry).To(HaveSuffix(" 00000 n \n"))
+		offset, err := strconv.Atoi(entry[:10])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(doc[offset:])).To(HavePrefix(fmt.Sprintf("%d 0 obj\n", i)))
+	}
+	Expect(bytes.Count(doc, []byte(" 0 obj\n"))).To(Equal(size - 1))
+	Expect(bytes.Count(doc, []byte("\nendobj\n"))).To(Equal(size - 1))
+
+	for _, stream := range _pdfStreamRe.FindAllSubmatch(doc, -1) {
+		length, _ := strconv.Atoi(string(stream[1]))
+		Expect(stream[2]).To(HaveLen(length))
+	}
+
+	pages := bytes.Count(doc, []byte("<< /Type /Page /Parent 2 0 R"))
+	Expect(doc).To(ContainSubstring(fmt.Sprintf("/Count %d >>", pages)))
+	Expect(_pdfStreamRe.FindAll(doc, -1)).To(HaveLen(pages))
+	return pages
+}
+
+// pdfTexts - the strings shown on the pages of the document, unescaped
+func pdfTexts(doc []byte) []string {
+	var res []string
+	for _, m := range _pdfTextRe.FindAllSubmatch(doc, -1) {
+		res = append(res, regexp.MustCompile(`\\(.)`).ReplaceAllString(string(m[1]), "$1"))
+	}
+	return res
+}
+
+var _ = Describe("PDF document", func() {
+
+	It("writes an empty document as a well-formed single page", func() {
+		doc := NewPDFDocument("Empty").Bytes()
+		Expect(expectWellFormedPDF(doc)).To(Equal(1))
+		Expect(pdfTexts(doc)).To(Equal([]string{"Empty - page 1 of 1"}))
+	})
+
+	It("writes a report with headings, text and tables as a well-formed document", func() {
+		d := NewPDFDocument("Cluster report (test)")
+		d.Heading("Nodes")
+		d.Table([]string{"Node", "Status"}, [][]string{{"10.0.0.1:3000", "on"}, {"10.0.0.2:3000", "off"}})
+		d.Heading("Notes")
+		d.Text("The back\\slash and the (parentheses) are escaped.\nA second line")
+
+		doc := d.Bytes()
+		Expect(expectWellFormedPDF(doc)).To(Equal(1))
+		Expect(doc).To(ContainSubstring(`/Title (Cluster report \(test\))`))
+
+		texts := pdfTexts(doc)
+		Expect(texts).To(ContainElement("Nodes"))
+		Expect(texts).To(ContainElement(ContainSubstring("10.0.0.2:3000")))
+		Expect(texts).To(ContainElement("The back\\slash and the (parentheses) are escaped."))
+		Expect(texts).To(ContainElement("A second line"))
+	})
+
+	It("lays out the long documents on as many pages as needed, with the page numbers", func() {
+		d := NewPDFDocument("Long")
+		for i := 0; i < 3*(_pdfPageLines-2)+1; i++ {
+			d.Text(fmt.Sprintf("line %d", i))
+		}
+
+		doc := d.Bytes()
+		Expect(expectWellFormedPDF(doc)).To(Equal(4))
+		Expect(pdfTexts(doc)).To(ContainElement("Long - page 4 of 4"))
+		Expect(pdfTexts(doc)).To(ContainElement(fmt.Sprintf("line %d", 3*(_pdfPageLines-2))))
+	})
+
+	It("wraps the long paragraphs to the width of the page", func() {
+		d := NewPDFDocument("Wrapped")
+		d.Text(strings.Repeat("word ", 100))
+
+		doc := d.Bytes()
+		expectWellFormedPDF(doc)
+		for _, text := range pdfTexts(doc) {
+			Expect(len(text)).To(BeNumerically("<=", _pdfLineChars))
+		}
+	})
+
+	DescribeTable("writing the strings in WinAnsiEncoding",
+		func(text, expected string) {
+			Expect(pdfString(text)).To(Equal(expected))
+		},
+		Entry("ASCII", "abc 123", "(abc 123)"),
+		Entry("the delimiters", `(a\b)`, `(\(a\\b\))`),
+		Entry("Latin-1", "café", "(caf\xe9)"),
+		Entry("the characters outside Latin-1", "日本 €", "(?? ?)"),
+		Entry("the control characters", "a\tb", "(a?b)"),
+	)
+
+	DescribeTable("wrapping the lines",
+		func(text string, width int, expected []string) {
+			Expect(pdfWrap(text, width)).To(Equal(expected))
+		},
+		Entry("a short line", "short", 10, []string{"short"}),
+		Entry("at the spaces", "the quick brown fox", 10, []string{"the quick", "brown fox"}),
+		Entry("a long word", "abcdefghijklmnop", 10, []string{"abcdefghij", "klmnop"}),
+		Entry("the runes, not the bytes", "ééééé ééééé", 5, []string{"ééééé", "ééééé"}),
+	)
+})
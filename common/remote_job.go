@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"net"
 	"os"
+	"strconv"
 	"strings"
 
 	"golang.org/x/crypto/ssh"
@@ -96,7 +97,7 @@ func (client *SSHClient) prepareCommand(session *ssh.Session, cmd *SSHCommand) e
 }
 
 func (client *SSHClient) newSession() (*ssh.Session, error) {
-	connection, err := ssh.Dial("tcp", fmt.Sprintf("%s:%d", client.Host, client.Port), client.Config)
+	connection, err := ssh.Dial("tcp", net.JoinHostPort(client.Host, strconv.Itoa(client.Port)), client.Config)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to dial: %s", err)
 	}
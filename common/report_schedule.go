@@ -0,0 +1,109 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// the periods of the summary reports
+const (
+	ReportDaily  = "daily"
+	ReportWeekly = "weekly"
+	ReportOff    = "off"
+)
+
+// ReportSchedule is when the summary reports of a cluster are sent
+type ReportSchedule struct {
+	Period  string // daily or weekly
+	Hour    int
+	Minute  int
+	Weekday time.Weekday // of the weekly reports
+}
+
+var _weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "monday": time.Monday, "tuesday": time.Tuesday, "wednesday": time.Wednesday,
+	"thursday": time.Thursday, "friday": time.Friday, "saturday": time.Saturday,
+}
+
+// ParseReportSchedule - parse the period (daily, weekly, or off or empty for no reports), the local time of day
+// (HH:MM, 00:00 if empty) and the weekday of the weekly reports (monday if empty); nil if the reports are off
+func ParseReportSchedule(period, at, weekday string) (*ReportSchedule, error) {
+	s := &ReportSchedule{Period: strings.ToLower(strings.TrimSpace(period)), Weekday: time.Monday}
+	switch s.Period {
+	case "", ReportOff:
+		return nil, nil
+	case ReportDaily, ReportWeekly:
+	default:
+		return nil, fmt.Errorf("%s is not one of daily, weekly or off", period)
+	}
+
+	if at != "" {
+		tm, err := time.Parse("15:04", strings.TrimSpace(at))
+		if err != nil {
+			return nil, fmt.Errorf("%s is not a time of day, e.g. 08:00", at)
+		}
+		s.Hour, s.Minute = tm.Hour(), tm.Minute()
+	}
+
+	if weekday != "" {
+		wd, exists := _weekdays[strings.ToLower(strings.TrimSpace(weekday))]
+		if !exists {
+			return nil, fmt.Errorf("%s is not a day of the week, e.g. monday", weekday)
+		}
+		s.Weekday = wd
+	}
+
+	return s, nil
+}
+
+// Next - the first time a report is due after tm
+func (s *ReportSchedule) Next(tm time.Time) time.Time {
+	next := time.Date(tm.Year(), tm.Month(), tm.Day(), s.Hour, s.Minute, 0, 0, tm.Location())
+	for !next.After(tm) || (s.Period == ReportWeekly && next.Weekday() != s.Weekday) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// Length - the length of the period a report covers
+func (s *ReportSchedule) Length() time.Duration {
+	if s.Period == ReportWeekly {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// ReportSchedule - the schedule of the summary reports of a cluster with the period, or with the period of
+// the mailer if it is empty; nil if the reports are off or the mailer is not set up
+func (c *Config) ReportSchedule(period string) *ReportSchedule {
+	c.Mailer.mutex.RLock()
+	defer c.Mailer.mutex.RUnlock()
+
+	if c.Mailer.Host == "" {
+		return nil
+	}
+	if period == "" {
+		period = c.Mailer.ReportSchedule
+	}
+
+	s, err := ParseReportSchedule(period, c.Mailer.ReportTime, c.Mailer.ReportWeekday)
+	if err != nil {
+		return nil
+	}
+	return s
+}
+
+// ReportRecipients - the recipients of the summary reports
+func (c *Config) ReportRecipients() []string {
+	c.Mailer.mutex.RLock()
+	defer c.Mailer.mutex.RUnlock()
+
+	to := c.Mailer.ReportSendTo
+	if len(to) == 0 {
+		to = c.Mailer.SendTo
+	}
+	res := make([]string, len(to))
+	copy(res, to)
+	return res
+}
@@ -0,0 +1,156 @@
+package common
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	_roleTemplateFields = [...]string{
+		"Name",
+		"Privileges",
+		"Whitelist",
+		"Created",
+		"Updated",
+	}
+)
+
+// RoleTemplate struct is a reusable role definition
+// which can be applied to any monitored cluster
+type RoleTemplate struct {
+	Name       string    `json:"name"`
+	Privileges []string  `json:"privileges"`
+	Whitelist  []string  `json:"whitelist"`
+	Created    time.Time `json:"created"`
+	Updated    time.Time `json:"updated"`
+}
+
+// NewRoleTemplate - create new role template
+func NewRoleTemplate(name string, privileges, whitelist []string) *RoleTemplate {
+	return &RoleTemplate{
+		Name:       name,
+		Privileges: privileges,
+		Whitelist:  whitelist,
+		Created:    time.Now(),
+		Updated:    time.Now(),
+	}
+}
+
+// Save - insert or replace the role template
+func (rt *RoleTemplate) Save() error {
+	_dbGlobalMutex.Lock()
+	defer _dbGlobalMutex.Unlock()
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM role_templates WHERE Name = ?1", rt.Name); err != nil {
+		log.Errorf("Error replacing the role template in the DB: %s", err.Error())
+		tx.Rollback()
+		return err
+	}
+
+	rt.Updated = time.Now()
+	if _, err := tx.Exec(
+		fmt.Sprintf("INSERT INTO role_templates (%s) VALUES (?1, ?2, ?3, ?4, ?5)", strings.Join(_roleTemplateFields[:], ", ")),
+		rt.Name, strings.Join(rt.Privileges, ","), strings.Join(rt.Whitelist, ","), rt.Created, rt.Updated,
+	); err != nil {
+		log.Errorf("Error registering the role template in the DB: %s", err.Error())
+		tx.Rollback()
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// Delete - delete the role template
+func (rt *RoleTemplate) Delete() error {
+	_dbGlobalMutex.Lock()
+	defer _dbGlobalMutex.Unlock()
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM role_templates WHERE Name = ?1", rt.Name); err != nil {
+		log.Errorf("Error deleting the role template from the DB: %s", err.Error())
+		tx.Rollback()
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// RoleTemplates - return all role templates sorted by name
+func RoleTemplates() ([]*RoleTemplate, error) {
+	_dbGlobalMutex.Lock()
+	defer _dbGlobalMutex.Unlock()
+
+	rows, err := db.Query(fmt.Sprintf("SELECT %s FROM role_templates ORDER BY Name", strings.Join(_roleTemplateFields[:], ", ")))
+	if err != nil {
+		log.Errorf("Error querying role templates in the DB: %s", err.Error())
+		return nil, err
+	}
+
+	defer rows.Close()
+	return roleTemplatesFromSQLRows(rows)
+}
+
+// RoleTemplateByName - return a role template by its name
+func RoleTemplateByName(name string) (*RoleTemplate, error) {
+	_dbGlobalMutex.Lock()
+	defer _dbGlobalMutex.Unlock()
+
+	rows, err := db.Query(fmt.Sprintf("SELECT %s FROM role_templates WHERE Name = ?1", strings.Join(_roleTemplateFields[:], ", ")), name)
+	if err != nil {
+		log.Errorf("Error querying role templates in the DB: %s", err.Error())
+		return nil, err
+	}
+
+	defer rows.Close()
+	res, err := roleTemplatesFromSQLRows(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res) == 0 {
+		return nil, nil
+	}
+
+	return res[0], nil
+}
+
+func roleTemplatesFromSQLRows(rows *sql.Rows) ([]*RoleTemplate, error) {
+	res := []*RoleTemplate{}
+	for rows.Next() {
+		rt := RoleTemplate{}
+		var privileges, whitelist string
+		if err := rows.Scan(&rt.Name, &privileges, &whitelist, &rt.Created, &rt.Updated); err != nil {
+			return res, err
+		}
+		rt.Privileges = SplitList(privileges)
+		rt.Whitelist = SplitList(whitelist)
+		res = append(res, &rt)
+	}
+
+	return res, nil
+}
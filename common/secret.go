@@ -0,0 +1,122 @@
+package common
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// size of the AES-256 key used to encrypt secrets stored in the database
+const _secretKeySize = 32
+
+var (
+	_secretKey      []byte
+	_secretKeyMutex sync.RWMutex
+)
+
+// SecretKeyFile - get the path of the key file used to encrypt secrets stored in the database;
+// defaults to the database path with a .key extension
+func (c *Config) SecretKeyFile() string {
+	if c.AMC.SecretKeyFile != "" {
+		return c.AMC.SecretKeyFile
+	}
+	return c.AMC.Database + ".key"
+}
+
+// LoadSecretKey - load the key used to encrypt secrets stored in the database;
+// a new random key is generated if the file does not exist
+func LoadSecretKey(filepath string) error {
+	key, err := os.ReadFile(filepath)
+	if os.IsNotExist(err) {
+		key = make([]byte, _secretKeySize)
+		if _, err := io.ReadFull(rand.Reader, key); err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(filepath, key, 0600); err != nil {
+			return fmt.Errorf("Error writing the secret key file %s: %s", filepath, err.Error())
+		}
+	} else if err != nil {
+		return fmt.Errorf("Error reading the secret key file %s: %s", filepath, err.Error())
+	}
+
+	if len(key) != _secretKeySize {
+		return fmt.Errorf("Invalid secret key file %s: the key must be %d bytes long", filepath, _secretKeySize)
+	}
+
+	_secretKeyMutex.Lock()
+	_secretKey = key
+	_secretKeyMutex.Unlock()
+
+	return nil
+}
+
+func secretCipher() (cipher.AEAD, error) {
+	_secretKeyMutex.RLock()
+	key := _secretKey
+	_secretKeyMutex.RUnlock()
+
+	if key == nil {
+		return nil, errors.New("The secret key has not been loaded")
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// EncryptSecret - encrypt the value with the secret key; empty values are not encrypted
+func EncryptSecret(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	gcm, err := secretCipher()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, []byte(value), nil)), nil
+}
+
+// DecryptSecret - decrypt a value encrypted by EncryptSecret
+func DecryptSecret(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	gcm, err := secretCipher()
+	if err != nil {
+		return "", err
+	}
+
+	data, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return "", err
+	}
+
+	if len(data) < gcm.NonceSize() {
+		return "", errors.New("Invalid encrypted value")
+	}
+
+	plain, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plain), nil
+}
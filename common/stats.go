@@ -4,7 +4,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
-	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -480,7 +480,7 @@ func (s Stats) TryString(name string, defValue string, aliases ...string) string
 type SyncInfo struct {
 	_Info Info
 
-	mutex sync.RWMutex
+	mutex RWMutex
 }
 
 // NewSyncInfo - return sync info
@@ -606,25 +606,35 @@ func (s *SyncInfo) ToStatsMap(name string, alias string, delim string) map[strin
 ***********************************************************************/
 
 // SyncStats strunct
+// The stats are published as immutable snapshots: writers replace the whole map,
+// so readers never block and never wait for an update in progress.
 type SyncStats struct {
-	_Stats Stats
+	// holds the current Stats snapshot, which must not be modified once stored
+	snapshot atomic.Value
 
-	mutex sync.RWMutex
+	// serializes the writers
+	mutex Mutex
 }
 
 // NewSyncStats - create new SyncStats
 func NewSyncStats(stats Stats) *SyncStats {
-	return &SyncStats{
-		_Stats: stats,
-	}
+	s := &SyncStats{}
+	s.snapshot.Store(stats)
+	return s
+}
+
+// stats - get the current snapshot; must not be modified
+func (s *SyncStats) stats() Stats {
+	stats, _ := s.snapshot.Load().(Stats)
+	return stats
 }
 
-// SetStats SyncStats set stats
+// SetStats SyncStats set stats; info must not be modified afterwards
 func (s *SyncStats) SetStats(info Stats) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
-	s._Stats = info
+	s.snapshot.Store(info)
 }
 
 // Set - SyncStats set value
@@ -632,110 +642,82 @@ func (s *SyncStats) Set(name string, value interface{}) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
-	s._Stats[name] = value
+	stats := s.stats().Clone()
+	stats[name] = value
+	s.snapshot.Store(stats)
 }
 
 // Clone - SyncStats clone
 func (s *SyncStats) Clone() Stats {
-	s.mutex.RLock()
-	defer s.mutex.RUnlock()
-
-	return s._Stats.Clone()
+	return s.stats().Clone()
 }
 
 // Exists - SyncStats check if key exists
 func (s *SyncStats) Exists(name string) bool {
-	s.mutex.RLock()
-	defer s.mutex.RUnlock()
-
-	_, exists := s._Stats[name]
+	_, exists := s.stats()[name]
 	return exists
 }
 
 // CloneInto - SyncStats clone info
 func (s *SyncStats) CloneInto(res Stats) {
-	s.mutex.RLock()
-	defer s.mutex.RUnlock()
-
-	for k, v := range s._Stats {
+	for k, v := range s.stats() {
 		res[k] = v
 	}
 }
 
 // Get - SyncStats get value
 func (s *SyncStats) Get(name string, aliases ...string) interface{} {
-	s.mutex.RLock()
-	defer s.mutex.RUnlock()
-
-	return s._Stats.Get(name, aliases...)
+	return s.stats().Get(name, aliases...)
 }
 
 // ExistsGet - SyncStats get stat if exists
 func (s *SyncStats) ExistsGet(name string) (interface{}, bool) {
-	s.mutex.RLock()
-	defer s.mutex.RUnlock()
-
-	return s._Stats.ExistsGet(name)
+	return s.stats().ExistsGet(name)
 }
 
 // GetMulti - SyncStats - get multi keys
 func (s *SyncStats) GetMulti(names ...string) Stats {
-	s.mutex.RLock()
-	defer s.mutex.RUnlock()
-
-	return s._Stats.GetMulti(names...)
+	return s.stats().GetMulti(names...)
 }
 
 // Del - SyncStats - delete stat
 func (s *SyncStats) Del(names ...string) {
-	s.mutex.RLock()
-	defer s.mutex.RUnlock()
-	s._Stats.Del(names...)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	stats := s.stats().Clone()
+	stats.Del(names...)
+	s.snapshot.Store(stats)
 }
 
 // Int - Value MUST exist, and MUST be an int64 or a convertible string.
 // Panics if the above constraints are not met
 func (s *SyncStats) Int(name string, aliases ...string) int64 {
-	s.mutex.RLock()
-	defer s.mutex.RUnlock()
-
-	return s._Stats.Int(name, aliases...)
+	return s.stats().Int(name, aliases...)
 }
 
 // TryInt - Value should be an int64 or a convertible string; otherwise defValue is returned
 // this function never panics
 func (s *SyncStats) TryInt(name string, defValue int64, aliases ...string) int64 {
-	s.mutex.RLock()
-	defer s.mutex.RUnlock()
-
-	return s._Stats.TryInt(name, defValue, aliases...)
+	return s.stats().TryInt(name, defValue, aliases...)
 }
 
 // TryFloat - Value should be an float64 or a convertible string; otherwise defValue is returned
 // this function never panics
 func (s *SyncStats) TryFloat(name string, defValue float64, aliases ...string) float64 {
-	s.mutex.RLock()
-	defer s.mutex.RUnlock()
-
-	return s._Stats.TryFloat(name, defValue, aliases...)
+	return s.stats().TryFloat(name, defValue, aliases...)
 }
 
 // TryString - Value should be a string; otherwise defValue is returned
 // this function never panics
 func (s *SyncStats) TryString(name string, defValue string, aliases ...string) string {
-	s.mutex.RLock()
-	defer s.mutex.RUnlock()
-
-	return s._Stats.TryString(name, defValue, aliases...)
+	return s.stats().TryString(name, defValue, aliases...)
 }
 
 // AggregateStatsTo - Value should be an float64 or a convertible string
 // this function never panics
 func (s *SyncStats) AggregateStatsTo(other Stats) {
-	s.mutex.RLock()
-	defer s.mutex.RUnlock()
-
-	other.AggregateStats(s._Stats)
+	other.AggregateStats(s.stats())
 }
 
 /*
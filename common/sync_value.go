@@ -1,31 +1,35 @@
 package common
 
-import "sync"
+import "sync/atomic"
 
 // SyncValue struct
+// The value is swapped atomically, so readers never block on writers.
 type SyncValue struct {
+	value atomic.Value // *syncValueBox
+}
+
+// syncValueBox wraps the values, since atomic.Value requires all stored values to have the same type
+type syncValueBox struct {
 	value interface{}
-	mutex sync.RWMutex
 }
 
 // NewSyncValue - new sync value
 func NewSyncValue(val interface{}) SyncValue {
-	return SyncValue{
-		value: val,
-	}
+	sv := SyncValue{}
+	sv.value.Store(&syncValueBox{value: val})
+	return sv
 }
 
 // Get - get sync value
 func (sv *SyncValue) Get() interface{} {
-	sv.mutex.RLock()
-	v := sv.value
-	sv.mutex.RUnlock()
-	return v
+	box, _ := sv.value.Load().(*syncValueBox)
+	if box == nil {
+		return nil
+	}
+	return box.value
 }
 
 // Set - set sync value
 func (sv *SyncValue) Set(val interface{}) {
-	sv.mutex.Lock()
-	sv.value = val
-	sv.mutex.Unlock()
+	sv.value.Store(&syncValueBox{value: val})
 }
@@ -2,8 +2,6 @@ package common
 
 import (
 	"database/sql"
-	"errors"
-	"fmt"
 	"math"
 	"sort"
 	"strconv"
@@ -11,16 +9,37 @@ import (
 	"time"
 )
 
-// SplitHostPort - convert aerospike host string to basic parts
+// SplitHostPort - convert aerospike host string to basic parts;
+// IPv6 addresses with a port must be enclosed in brackets, e.g. [::1]:3000
 func SplitHostPort(addr string) (host string, port int, err error) {
 	addr = strings.Trim(addr, "\t\n\r ")
 	if len(addr) == 0 {
-		return "", 0, errors.New("Invalid address: " + addr)
+		return "", 0, Errorf(KindInvalid, "Invalid address: %s", addr)
+	}
+
+	if strings.HasPrefix(addr, "[") {
+		end := strings.Index(addr, "]")
+		if end < 0 || len(addr) == end+1 || addr[end+1] != ':' {
+			return addr, 0, Errorf(KindInvalid, "Invalid address: %s", addr)
+		}
+
+		host = addr[1:end]
+		if len(addr) > end+2 {
+			if port, err = strconv.Atoi(addr[end+2:]); err != nil {
+				return host, 0, err
+			}
+		}
+		return host, port, nil
+	}
+
+	// a bare IPv6 address has no port
+	if strings.Count(addr, ":") > 1 {
+		return addr, 0, Errorf(KindInvalid, "Invalid address: %s; enclose IPv6 addresses in brackets", addr)
 	}
 
 	index := strings.LastIndex(addr, ":")
 	if index < 0 || len(addr) < index {
-		return addr, 0, errors.New("Invalid address: " + addr)
+		return addr, 0, Errorf(KindInvalid, "Invalid address: %s", addr)
 	}
 
 	portStr := addr[index+1:]
@@ -60,6 +79,17 @@ func DeleteEmpty(s []string) []string {
 	return r
 }
 
+// SplitList - split a comma separated list, trimming the items and dropping the empty ones
+func SplitList(s string) []string {
+	res := []string{}
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
 // StrDiff - compare slices into added and remove slices
 func StrDiff(o, n []string) (added, removed []string) {
 	for _, sn := range n {
@@ -153,10 +183,10 @@ func MaxInt64(a, b int64) int64 {
 func ParseTimeStrict(layout, value string) (time.Time, error) {
 	t, err := time.Parse(layout, value)
 	if err != nil {
-		return t, fmt.Errorf("invalid date time: %q. Must follow the pattern %s", value, layout)
+		return t, Errorf(KindInvalid, "invalid date time: %q. Must follow the pattern %s", value, layout)
 	}
 	if t.Format(layout) != value {
-		return t, fmt.Errorf("invalid date time: %q. Must follow the pattern %s", value, layout)
+		return t, Errorf(KindInvalid, "invalid date time: %q. Must follow the pattern %s", value, layout)
 	}
 	return t, nil
 }
@@ -0,0 +1,120 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// accessLogEntry is a line of the access log
+type accessLogEntry struct {
+	Time      time.Time `json:"time"`
+	RequestID string    `json:"request_id"`
+	RemoteIP  string    `json:"remote_ip"`
+	User      string    `json:"user"`
+	Method    string    `json:"method"`
+	Path      string    `json:"path"`
+	Status    int       `json:"status"`
+	Bytes     int64     `json:"bytes"`
+	LatencyMs float64   `json:"latency_ms"`
+}
+
+// text - the common log format, followed by the latency and the id of the request
+func (e *accessLogEntry) text() string {
+	return fmt.Sprintf("%s - %s [%s] \"%s %s\" %d %d %.3fms %s\n", e.RemoteIP, e.User, e.Time.Format("02/Jan/2006:15:04:05 -0700"), e.Method, e.Path, e.Status, e.Bytes, e.LatencyMs, e.RequestID)
+}
+
+// accessLogger writes a line to access_log for every request; the writes are serialized,
+// so that the lines of concurrent requests are not interleaved
+type accessLogger struct {
+	mutex  sync.Mutex
+	out    io.Writer
+	asJSON bool
+}
+
+// newAccessLogger - open the destination of the access log; stdout, stderr or a file the lines are
+// appended to. Returns nil if access_log is not set.
+func newAccessLogger(config *common.Config) (*accessLogger, error) {
+	l := &accessLogger{}
+	switch dest := config.AMC.AccessLog; dest {
+	case "":
+		return nil, nil
+	case "stdout":
+		l.out = os.Stdout
+	case "stderr":
+		l.out = os.Stderr
+	default:
+		f, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0640)
+		if err != nil {
+			return nil, err
+		}
+		l.out = f
+	}
+
+	switch format := strings.ToLower(config.AMC.AccessLogFormat); format {
+	case "", "text":
+	case "json":
+		l.asJSON = true
+	default:
+		return nil, fmt.Errorf("Invalid access_log_format %s; must be text or json", config.AMC.AccessLogFormat)
+	}
+
+	return l, nil
+}
+
+// middleware - log the requests once they have been served; the errors are handled first,
+// so that their status is logged
+func (l *accessLogger) middleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		start := time.Now()
+		if err := next(c); err != nil {
+			c.Error(err)
+		}
+
+		req, res := c.Request(), c.Response()
+		user, _, ok := req.BasicAuth()
+		if !ok || user == "" {
+			user = "-"
+		}
+
+		l.write(&accessLogEntry{
+			Time:      start,
+			RequestID: res.Header().Get(echo.HeaderXRequestID),
+			RemoteIP:  c.RealIP(),
+			User:      user,
+			Method:    req.Method,
+			Path:      req.URL.RequestURI(),
+			Status:    res.Status,
+			Bytes:     res.Size,
+			LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+		})
+		return nil
+	}
+}
+
+func (l *accessLogger) write(entry *accessLogEntry) {
+	line := entry.text()
+	if l.asJSON {
+		b, err := json.Marshal(entry)
+		if err != nil {
+			log.Errorf("Error encoding the access log entry: %s", err.Error())
+			return
+		}
+		line = string(b) + "\n"
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if _, err := io.WriteString(l.out, line); err != nil {
+		log.Errorf("Error writing the access log: %s", err.Error())
+	}
+}
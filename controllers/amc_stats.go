@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"bytes"
+	"net/http"
+	"runtime"
+	"runtime/pprof"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// getAMCStats - self-monitoring stats of the AMC process; only allowed from the AMC host itself
+func getAMCStats(c echo.Context) error {
+	if !isLocalRequest(c) {
+		return c.JSON(http.StatusForbidden, errorMap(errInsufficientPrivileges, "The AMC stats can only be read from the AMC host"))
+	}
+
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	config := _observer.Config()
+	historyBytes, historyClusters := _observer.HistoryMemoryUsage()
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":     "success",
+		"goroutines": runtime.NumGoroutine(),
+		"clusters":   len(_observer.Clusters()),
+		"memory": map[string]interface{}{
+			"alloc_bytes":  mem.Alloc,
+			"sys_bytes":    mem.Sys,
+			"heap_objects": mem.HeapObjects,
+			"num_gc":       mem.NumGC,
+		},
+		"history": map[string]interface{}{
+			"bytes":             historyBytes,
+			"limit":             config.HistoryMemoryLimit(),
+			"limit_per_cluster": config.HistoryMemoryLimitPerCluster(),
+			"clusters":          historyClusters,
+		},
+		// the response times of the routes, the slowest first
+		"routes": _routeTimings.stats(),
+	})
+}
+
+// getObserverState - dump the internals of the observer to find out why clusters stop updating;
+// the stacks of the goroutines are included with ?goroutines=true. Only allowed from the AMC host itself.
+func getObserverState(c echo.Context) error {
+	if !isLocalRequest(c) {
+		return c.JSON(http.StatusForbidden, errorMap(errInsufficientPrivileges, "The observer state can only be read from the AMC host"))
+	}
+
+	res := map[string]interface{}{
+		"status":     "success",
+		"goroutines": runtime.NumGoroutine(),
+		"observer":   _observer.State(),
+	}
+
+	if withStacks, _ := strconv.ParseBool(c.QueryParam("goroutines")); withStacks {
+		var buf bytes.Buffer
+		if err := pprof.Lookup("goroutine").WriteTo(&buf, 1); err != nil {
+			return c.JSON(http.StatusOK, errorMapOf(err))
+		}
+		res["goroutine_stacks"] = buf.String()
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
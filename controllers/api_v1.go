@@ -0,0 +1,161 @@
+package controllers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// The versioned API serves the /aerospike/service endpoints under /api/v1, but reports errors
+// with proper HTTP status codes and a uniform envelope instead of 200 and {"status": "failure"}:
+//
+//	{"error": {"code": 404, "error_code": "CLUSTER_NOT_FOUND", "message": "Cluster not found"}}
+const (
+	_apiV1Prefix    = "/api/v1/"
+	_apiV1Target    = "/aerospike/service/"
+	_apiV1CtxMarker = "api_v1"
+)
+
+// apiV1Rewrite - route the /api/v1 requests to the /aerospike/service endpoints; registered with Pre
+func apiV1Rewrite(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := c.Request()
+		if strings.HasPrefix(req.URL.Path, _apiV1Prefix) {
+			req.URL.Path = _apiV1Target + strings.TrimPrefix(req.URL.Path, _apiV1Prefix)
+			req.URL.RawPath = ""
+			c.Set(_apiV1CtxMarker, true)
+		}
+
+		return next(c)
+	}
+}
+
+// apiV1Errors - convert the failure responses of the /api/v1 requests to the error envelope;
+// must be registered after the gzip middleware, so that it sees the uncompressed responses
+func apiV1Errors(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if v1, _ := c.Get(_apiV1CtxMarker).(bool); !v1 {
+			return next(c)
+		}
+
+		res := c.Response()
+		w := &apiV1ResponseWriter{ResponseWriter: res.Writer, code: http.StatusOK}
+		res.Writer = w
+		defer func() { res.Writer = w.ResponseWriter }()
+
+		if err := next(c); err != nil {
+			c.Error(err)
+		}
+
+		return w.flush()
+	}
+}
+
+// apiV1ResponseWriter holds back the response until it is known whether it is a failure;
+// only the JSON responses can be failures, so the others are streamed
+type apiV1ResponseWriter struct {
+	http.ResponseWriter
+	code      int
+	body      bytes.Buffer
+	hijacked  bool
+	streaming bool
+}
+
+func (w *apiV1ResponseWriter) WriteHeader(code int) {
+	w.code = code
+	if !strings.HasPrefix(w.Header().Get(echo.HeaderContentType), echo.MIMEApplicationJSON) {
+		w.streaming = true
+		w.ResponseWriter.WriteHeader(code)
+	}
+}
+
+func (w *apiV1ResponseWriter) Write(b []byte) (int, error) {
+	if w.streaming {
+		return w.ResponseWriter.Write(b)
+	}
+	return w.body.Write(b)
+}
+
+// Flush - the JSON responses are held back until the handler returns
+func (w *apiV1ResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok && w.streaming {
+		f.Flush()
+	}
+}
+
+// Hijack - the WebSocket connections are not held back
+func (w *apiV1ResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.hijacked = true
+	return w.ResponseWriter.(http.Hijacker).Hijack()
+}
+
+func (w *apiV1ResponseWriter) flush() error {
+	if w.hijacked || w.streaming {
+		return nil
+	}
+
+	code, body := w.code, w.body.Bytes()
+
+	if strings.HasPrefix(w.Header().Get(echo.HeaderContentType), echo.MIMEApplicationJSON) {
+		if status, errCode, msg, failed := apiV1Failure(code, body); failed {
+			code = status
+			body, _ = json.Marshal(map[string]interface{}{
+				"error": map[string]interface{}{
+					"code":       code,
+					"error_code": errCode,
+					"message":    msg,
+				},
+			})
+			w.Header().Del(echo.HeaderContentLength)
+		}
+	}
+
+	w.ResponseWriter.WriteHeader(code)
+	_, err := w.ResponseWriter.Write(body)
+	return err
+}
+
+// apiV1Failure - check whether the response is a failure, and get its status code, error code and message
+func apiV1Failure(code int, body []byte) (int, apiErrorCode, string, bool) {
+	res := struct {
+		Status    string       `json:"status"`
+		Error     string       `json:"error"`
+		ErrorCode apiErrorCode `json:"error_code"`
+		Message   string       `json:"message"`
+	}{}
+
+	// arrays and other payloads are failures only by their status code
+	if err := json.Unmarshal(body, &res); err != nil {
+		return code, statusErrorCode(code, ""), http.StatusText(code), code >= http.StatusBadRequest
+	}
+
+	msg := res.Error
+	if msg == "" {
+		msg = res.Message
+	}
+
+	if code >= http.StatusBadRequest {
+		if msg == "" {
+			msg = http.StatusText(code)
+		}
+		if res.ErrorCode == "" {
+			res.ErrorCode = statusErrorCode(code, msg)
+		}
+		return code, res.ErrorCode, msg, true
+	}
+
+	if res.Status != "failure" {
+		return code, "", "", false
+	}
+
+	// the handlers set the code of every failure; one without a code is a bug, not a bad request
+	if res.ErrorCode == "" {
+		res.ErrorCode = errInternal
+	}
+	return errorStatusCode(res.ErrorCode), res.ErrorCode, msg, true
+}
@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// API clients can send the version of the API they were written for in the X-AMC-API-Version
+// header; requests for a version this AMC does not serve are rejected. Every response carries
+// the version which served it.
+const (
+	_apiVersionHeader           = "X-AMC-API-Version"
+	_apiSupportedVersionsHeader = "X-AMC-API-Supported-Versions"
+	_apiCurrentVersion          = "1"
+)
+
+var _apiSupportedVersions = []string{"1"}
+
+// _deprecatedRoutes maps the deprecated routes to the routes replacing them;
+// the deprecated routes keep working until they are removed in a later release
+var _deprecatedRoutes = map[string]string{
+	"/session-terminate":                     "/api/v1/session_terminate",
+	"/get_amc_version":                       "/api/v1/amc_version",
+	"/get_current_monitoring_clusters":       "/api/v1/monitoring_clusters",
+	"/set-update-interval/:clusterUUID":      "/api/v1/clusters/:clusterUUID/update_interval",
+	"/alert-emails":                          "/api/v1/alert_emails",
+	"/delete-alert-emails":                   "/api/v1/delete_alert_emails",
+	"/aerospike/get_multicluster_view":       "/api/v1/multicluster_view",
+	"/aerospike/get_multicluster_view/:port": "/api/v1/multicluster_view",
+}
+
+// apiVersion - negotiate the API version and warn about the deprecated routes;
+// registered with Use, so that the matched route is known
+func apiVersion(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// static files
+		if strings.Contains(c.Path(), "*") {
+			return next(c)
+		}
+
+		header := c.Response().Header()
+		header.Set(_apiVersionHeader, _apiCurrentVersion)
+		header.Set(_apiSupportedVersionsHeader, strings.Join(_apiSupportedVersions, ", "))
+
+		if requested := c.Request().Header.Get(_apiVersionHeader); requested != "" && !apiVersionSupported(requested) {
+			return c.JSON(http.StatusBadRequest, errorMap(errInvalidParameter, fmt.Sprintf("API version %s is not supported; supported versions: %s", requested, strings.Join(_apiSupportedVersions, ", "))))
+		}
+
+		if successor, deprecated := _deprecatedRoutes[c.Path()]; deprecated {
+			successor = deprecatedRouteSuccessor(c, successor)
+			header.Set("Deprecation", "true")
+			header.Add("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", successor))
+			header.Add("Warning", fmt.Sprintf("299 - \"Deprecated API: use %s instead\"", successor))
+		}
+
+		return next(c)
+	}
+}
+
+func apiVersionSupported(version string) bool {
+	version = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(version)), "v")
+	for _, v := range _apiSupportedVersions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
+
+// deprecatedRouteSuccessor - fill in the path params of the request in the successor route
+func deprecatedRouteSuccessor(c echo.Context, successor string) string {
+	for i, name := range c.ParamNames() {
+		if i < len(c.ParamValues()) {
+			successor = strings.Replace(successor, ":"+name, c.ParamValues()[i], -1)
+		}
+	}
+	return successor
+}
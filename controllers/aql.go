@@ -0,0 +1,35 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// postClusterAQL - run a statement of the AQL console; see models/aql.go for the supported subset
+func postClusterAQL(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	form := struct {
+		Statement string `form:"statement" json:"statement"`
+	}{}
+	if err := c.Bind(&form); err != nil || form.Statement == "" {
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid statement"))
+	}
+
+	res, err := cluster.ExecAQL(c.Request().Context(), form.Statement)
+	if err != nil {
+		return c.JSON(http.StatusOK, errorMapOf(err))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":  "success",
+		"columns": res.Columns,
+		"rows":    res.Rows,
+		"message": res.Message,
+	})
+}
@@ -19,7 +19,7 @@ func postInitiateBackup(c echo.Context) error {
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("Cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
 	}
 
 	form := struct {
@@ -38,26 +38,26 @@ func postInitiateBackup(c echo.Context) error {
 
 	c.Bind(&form)
 	if len(form.Namespace) == 0 {
-		return c.JSON(http.StatusOK, errorMap("Invalid Namespace"))
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid Namespace"))
 	}
 
 	if len(form.DestinationNodeAddress) == 0 {
-		return c.JSON(http.StatusOK, errorMap("Invalid DestinationNodeAddress"))
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid DestinationNodeAddress"))
 	}
 
 	if len(form.DestinationLocation) == 0 {
-		return c.JSON(http.StatusOK, errorMap("Invalid DestinationLocation"))
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid DestinationLocation"))
 	}
 
 	if len(form.ModifiedBefore) > 0 {
 		if _, err := common.ParseTimeStrict("2006-01-02_15:04:05", form.ModifiedBefore); err != nil {
-			return c.JSON(http.StatusOK, errorMap("Invalid Modified Before Date: "+err.Error()))
+			return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid Modified Before Date: "+err.Error()))
 		}
 	}
 
 	if len(form.ModifiedAfter) > 0 {
 		if _, err := common.ParseTimeStrict("2006-01-02_15:04:05", form.ModifiedAfter); err != nil {
-			return c.JSON(http.StatusOK, errorMap("Invalid Modified After Date: "+err.Error()))
+			return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid Modified After Date: "+err.Error()))
 		}
 	}
 
@@ -74,7 +74,7 @@ func postInitiateBackup(c echo.Context) error {
 		form.ModifiedAfter,
 		form.ScanPriority)
 	if err != nil {
-		return c.JSON(http.StatusOK, errorMap(err.Error()))
+		return c.JSON(http.StatusOK, errorMapOf(err))
 	}
 
 	return c.JSON(http.StatusOK, map[string]interface{}{
@@ -87,7 +87,7 @@ func getBackupProgress(c echo.Context) error {
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("Cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
 	}
 
 	backup := cluster.CurrentBackup()
@@ -112,12 +112,12 @@ func getSuccessfulBackups(c echo.Context) error {
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("Cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
 	}
 
 	backupList, err := common.SuccessfulBackups()
 	if err != nil {
-		return c.JSON(http.StatusOK, errorMap("Error reading backup list from the database: "+err.Error()))
+		return c.JSON(http.StatusOK, errorMap(errInternal, "Error reading backup list from the database: "+err.Error()))
 	}
 
 	res := make([]interface{}, 0, len(backupList))
@@ -140,7 +140,7 @@ func getAvailableBackups(c echo.Context) error {
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("Cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
 	}
 
 	form := struct {
@@ -152,7 +152,7 @@ func getAvailableBackups(c echo.Context) error {
 
 	backupList, err := common.SuccessfulBackups()
 	if err != nil {
-		return c.JSON(http.StatusOK, errorMap("Error reading backup list from the database: "+err.Error()))
+		return c.JSON(http.StatusOK, errorMap(errInternal, "Error reading backup list from the database: "+err.Error()))
 	}
 
 	res := make([]interface{}, 0, len(backupList))
@@ -173,7 +173,7 @@ func postInitiateRestore(c echo.Context) error {
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("Cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
 	}
 
 	form := struct {
@@ -189,11 +189,11 @@ func postInitiateRestore(c echo.Context) error {
 
 	c.Bind(&form)
 	if len(form.DestinationNodeAddress) == 0 {
-		return c.JSON(http.StatusOK, errorMap("Invalid DestinationNodeAddress"))
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid DestinationNodeAddress"))
 	}
 
 	if len(form.DestinationLocation) == 0 {
-		return c.JSON(http.StatusOK, errorMap("Invalid DestinationLocation"))
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid DestinationLocation"))
 	}
 
 	restore, err := cluster.Restore(
@@ -207,7 +207,7 @@ func postInitiateRestore(c echo.Context) error {
 		form.IgnoreGenerationNumber)
 
 	if err != nil {
-		return c.JSON(http.StatusOK, errorMap(err.Error()))
+		return c.JSON(http.StatusOK, errorMapOf(err))
 	}
 
 	return c.JSON(http.StatusOK, map[string]interface{}{
@@ -219,12 +219,12 @@ func getRestoreProgress(c echo.Context) error {
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("Cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
 	}
 
 	restore := cluster.CurrentRestore()
 	if restore == nil {
-		return c.JSON(http.StatusOK, errorMap("No Restore in progress"))
+		return c.JSON(http.StatusOK, errorMap(errNotFound, "No Restore in progress"))
 	}
 
 	return c.JSON(http.StatusOK, map[string]interface{}{
@@ -0,0 +1,45 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/aerospike-community/amc/common"
+	"github.com/aerospike-community/amc/models"
+)
+
+// postClusterBenchmark - run a short benchmark of reads and writes on a test cluster, and return its throughput
+// and latencies along with the latest latencies of the nodes; the JSON body is the spec, see models.BenchmarkSpec
+func postClusterBenchmark(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	var spec models.BenchmarkSpec
+	if err := c.Bind(&spec); err != nil {
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid parameters"))
+	}
+
+	res, err := cluster.RunBenchmark(c.Request().Context(), spec)
+	if err != nil {
+		return c.JSON(http.StatusOK, errorMapOf(err))
+	}
+
+	// the latencies of the nodes, as of their last update, which may include a part of the benchmark
+	latencies := map[string]interface{}{}
+	for _, node := range cluster.Nodes() {
+		latencies[node.Address()] = common.Stats{
+			"latency":     transformLatency(node.LatestLatency()),
+			"node_status": node.Status(),
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":          "success",
+		"benchmark":       res,
+		"cluster_latency": latencies,
+	})
+}
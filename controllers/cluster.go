@@ -1,10 +1,11 @@
 package controllers
 
 import (
-	"crypto/tls"
 	// "crypto/x509"
 	"errors"
+	"fmt"
 	"math"
+	"net"
 	"net/http"
 	"sort"
 	"strconv"
@@ -15,42 +16,97 @@ import (
 	as "github.com/aerospike/aerospike-client-go/v5"
 	ast "github.com/aerospike/aerospike-client-go/v5/types"
 	"github.com/labstack/echo/v4"
-	log "github.com/sirupsen/logrus"
 
 	"github.com/aerospike-community/amc/common"
+	"github.com/aerospike-community/amc/models"
 )
 
 //----------
 // Handlers
 //----------
 
+// postGetClusterID - register a cluster for the session, or add the cluster already monitored with the same seed
+// and credentials to it; the response includes the diagnostics of the connection to the seeds and the nodes
 func postGetClusterID(c echo.Context) error {
 	form := struct {
-		SeedNode             string `form:"seed_node"`
-		TLSName              string `form:"tls_name"`
-		CertFile             string `form:"cert_file"`
-		KeyFile              string `form:"key_file"`
-		Username             string `form:"username"`
-		Password             string `form:"password"`
-		ClusterAlias         string `form:"cluster_name"`
-		EncryptOnly          bool   `form:"encrypt_only"`
-		UseServicesAlternate bool   `form:"use_services_alternate"`
+		SeedNode             string `json:"seed_node" form:"seed_node"`
+		TLSName              string `json:"tls_name" form:"tls_name"`
+		CertFile             string `json:"cert_file" form:"cert_file"`
+		KeyFile              string `json:"key_file" form:"key_file"`
+		Username             string `json:"username" form:"username"`
+		Password             string `json:"password" form:"password"`
+		ClusterAlias         string `json:"cluster_name" form:"cluster_name"`
+		Alias                string `json:"alias" form:"alias"`
+		UseServicesAlternate bool   `json:"use_services_alternate" form:"use_services_alternate"`
+
+		// more seeds in addition to seed_node, each of them may be a comma delimited list
+		SeedNodes []string `json:"seed_nodes" form:"seed_nodes"`
+
+		// internal, external or pki
+		AuthMode string `json:"auth_mode" form:"auth_mode"`
+
+		// seconds between the updates of the cluster, 1 to 10; the default if not set
+		UpdateInterval int `json:"update_interval" form:"update_interval"`
+
+		// comma delimited advertised=alternate address pairs
+		AlternateAddresses string `json:"alternate_addresses" form:"alternate_addresses"`
+
+		// SRV record listing the nodes; used instead of the seed node if that is not specified
+		SRVRecord string `json:"srv_record" form:"srv_record"`
+
+		// encrypt_only, and the PEM encoded CA certificate, client certificate and key of the cluster
+		models.ClusterTLSOptions
+
+		// client connection policy; timeouts are in seconds
+		models.ClientPolicyOptions
 	}{}
 
 	c.Bind(&form)
-	if len(form.SeedNode) == 0 {
-		return c.JSON(http.StatusOK, errorMap("No seed name specified."))
+
+	if form.ClusterAlias == "" {
+		form.ClusterAlias = form.Alias
+	}
+
+	if form.UpdateInterval != 0 && (form.UpdateInterval < 1 || form.UpdateInterval > 10) {
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid update_interval; must be between 1 and 10"))
 	}
 
-	host, port, err := common.SplitHostPort(form.SeedNode)
+	authMode, err := models.ParseAuthMode(form.AuthMode)
 	if err != nil {
-		return c.JSON(http.StatusOK, errorMap(err.Error()))
+		return c.JSON(http.StatusOK, errorMapOf(err))
+	}
+
+	var srvHosts []*as.Host
+	if form.SRVRecord = strings.TrimSpace(form.SRVRecord); form.SRVRecord != "" {
+		hosts, err := _observer.ResolveSRV(form.SRVRecord, form.TLSName)
+		if err != nil {
+			return c.JSON(http.StatusOK, errorMapOf(err))
+		}
+
+		srvHosts = hosts
+		if len(form.SeedNode) == 0 {
+			form.SeedNode = net.JoinHostPort(hosts[0].Name, strconv.Itoa(hosts[0].Port))
+		}
+	}
+
+	seedNodes := common.DeleteEmpty(strings.Split(strings.Join(append([]string{form.SeedNode}, form.SeedNodes...), ","), ","))
+	if len(seedNodes) == 0 {
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "No seed name specified."))
+	}
+
+	seeds := make([]*as.Host, 0, len(seedNodes)+len(srvHosts))
+	for _, seedNode := range seedNodes {
+		host, port, err := common.SplitHostPort(strings.TrimSpace(seedNode))
+		if err != nil {
+			return c.JSON(http.StatusOK, errorMapOf(err))
+		}
+		seeds = append(seeds, as.NewHost(host, port))
 	}
 
 	// update the session cookie
 	sid := manageSession(c)
 
-	seedHost := as.NewHost(host, port)
+	seedHost := seeds[0]
 	if common.AMCIsEnterprise() {
 		if form.TLSName != "" {
 			seedHost.TLSName = form.TLSName
@@ -58,42 +114,55 @@ func postGetClusterID(c echo.Context) error {
 			// handles the case when the user first logs in and there is an auto
 			// monitored cluster configured with tls.
 			cfg := _observer.Config()
-			for _, server := range cfg.AMC.Clusters {
-				if server.Host == host && server.Port == uint16(port) {
+			for _, server := range cfg.ClusterConfigs() {
+				if server.Host == seedHost.Name && server.Port == uint16(seedHost.Port) {
 					seedHost.TLSName = server.TLSName
 				}
 			}
 		}
+		for _, seed := range seeds[1:] {
+			seed.TLSName = seedHost.TLSName
+		}
 	}
 
+	useTLS := len(seedHost.TLSName) > 0 || form.ClusterTLSOptions.Enabled()
+	start := time.Now()
 	cluster := _observer.FindClusterBySeed(sid, seedHost, form.Username, form.Password)
 	if cluster != nil {
-		cluster.SetAlias(form.ClusterAlias)
-		_observer.AppendCluster(sid, cluster)
+		_observer.UpdateClusterAlias(cluster, form.ClusterAlias)
+		_observer.AppendCluster(c.Request().Context(), sid, cluster)
 	} else {
+		if err := form.ClientPolicyOptions.Validate(); err != nil {
+			return c.JSON(http.StatusOK, errorMapOf(err))
+		}
+
+		ipMap, err := models.ParseAlternateAddresses(form.AlternateAddresses)
+		if err != nil {
+			return c.JSON(http.StatusOK, errorMapOf(err))
+		}
+
 		clientPolicy := *_defaultClientPolicy
 		clientPolicy.UseServicesAlternate = form.UseServicesAlternate
+		clientPolicy.IpMap = ipMap
+		form.ClientPolicyOptions.Apply(&clientPolicy)
 
 		if common.AMCIsEnterprise() {
 			clientPolicy.User = strings.Trim(form.Username, " \t")
 			clientPolicy.Password = form.Password
+			clientPolicy.AuthMode = authMode
 
-			if len(seedHost.TLSName) > 0 || form.EncryptOnly {
-				// Setup TLS Config
-				tlsConfig := &tls.Config{
-					Certificates:             _observer.Config().ClientPool(),
-					RootCAs:                  _observer.Config().ServerPool(),
-					InsecureSkipVerify:       form.EncryptOnly,
-					PreferServerCipherSuites: true,
+			if useTLS {
+				tlsConfig, err := form.ClusterTLSOptions.Config(_observer.Config())
+				if err != nil {
+					return c.JSON(http.StatusOK, errorMapOf(err))
 				}
-				tlsConfig.BuildNameToCertificate()
-
 				clientPolicy.TlsConfig = tlsConfig
 			}
 		}
 
-		cluster, err = _observer.Register(sid, &clientPolicy, strings.Trim(form.ClusterAlias, " \t"), seedHost)
+		cluster, err = _observer.Register(c.Request().Context(), sid, &clientPolicy, strings.Trim(form.ClusterAlias, " \t"), append(seeds, srvHosts...)...)
 		if err != nil {
+			diagnostics := connectDiagnostics(nil, append(seeds, srvHosts...), useTLS, authMode, time.Since(start))
 			if common.AMCIsEnterprise() {
 				aerr := new(as.AerospikeError)
 				if errors.As(err, &aerr); aerr.Matches(ast.NOT_AUTHENTICATED) {
@@ -101,14 +170,26 @@ func postGetClusterID(c echo.Context) error {
 					response := map[string]interface{}{
 						"security_enabled": true,
 						"cluster_id":       nil,
+						"diagnostics":      diagnostics,
 					}
 					return c.JSON(http.StatusOK, response)
 				}
 			}
 
-			log.Error(err)
-			return c.JSON(http.StatusOK, errorMap(err.Error()))
+			requestLog(c).Error(err)
+			response := errorMapOf(err)
+			response["diagnostics"] = diagnostics
+			return c.JSON(http.StatusOK, response)
+		}
+
+		if form.SRVRecord != "" {
+			cluster.SetSRVSeed(form.SRVRecord, seedHost.TLSName)
 		}
+		_observer.PersistCluster(cluster, &clientPolicy, form.ClusterTLSOptions)
+	}
+
+	if form.UpdateInterval > 0 {
+		cluster.SetUpdateInterval(form.UpdateInterval)
 	}
 
 	// create output
@@ -121,6 +202,7 @@ func postGetClusterID(c echo.Context) error {
 		"update_interval":     cluster.UpdateInterval(),
 		"nodes":               cluster.NodeList(),
 		"seed_address":        cluster.SeedAddress(),
+		"diagnostics":         connectDiagnostics(cluster, append(seeds, srvHosts...), useTLS, authMode, time.Since(start)),
 	}
 
 	return c.JSON(http.StatusOK, response)
@@ -130,11 +212,11 @@ func postRemoveClusterFromSession(c echo.Context) error {
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "cluster not found"))
 	}
 
 	sid, _ := sessionID(c)
-	if remainingClusterCount := _observer.RemoveCluster(sid, cluster); remainingClusterCount <= 0 {
+	if remainingClusterCount := _observer.RemoveCluster(c.Request().Context(), sid, cluster); remainingClusterCount <= 0 {
 		invalidateSession(c)
 	}
 
@@ -143,11 +225,32 @@ func postRemoveClusterFromSession(c echo.Context) error {
 	})
 }
 
+func deleteCluster(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "cluster not found"))
+	}
+
+	inConfig := cluster.IsPermanent()
+	_observer.DeleteCluster(c.Request().Context(), cluster)
+
+	// the session is kept even without clusters, unlike logging out of the last cluster
+	res := map[string]interface{}{
+		"status": "success",
+	}
+	if inConfig {
+		res["warning"] = "The cluster is defined in the config file and will be monitored again after a reload or restart unless it is removed from the file"
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
 func getCluster(c echo.Context) error {
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "cluster not found"))
 	}
 
 	builds := cluster.NodeBuilds()
@@ -165,6 +268,68 @@ func getCluster(c echo.Context) error {
 		"update_interval":         cluster.UpdateInterval(),
 		"nodes":                   cluster.NodeList(),
 		"cluster_status":          cluster.Status(),
+		"racks":                   cluster.RackTopology(),
+	})
+}
+
+func getClusterSnapshot(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "cluster not found"))
+	}
+
+	snapshot := cluster.Snapshot()
+	if snapshot == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterUnavailable, "The cluster has not been updated yet"))
+	}
+
+	return c.JSON(http.StatusOK, snapshot)
+}
+
+// getClusterDashboard - get everything the dashboard of a cluster needs in one response: the summary
+// and all the stats of the nodes, the throughput and the alerts after last_id. The nodes param limits
+// the nodes to a comma separated list of addresses, and the fields param the stats of the nodes.
+func getClusterDashboard(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	lastID := int64(0)
+	if strLastID := c.QueryParam("last_id"); strLastID != "" {
+		var err error
+		if lastID, err = strconv.ParseInt(strLastID, 10, 64); err != nil {
+			return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid last_id"))
+		}
+	}
+
+	nodeList := common.DeleteEmpty(strings.Split(c.QueryParam("nodes"), ","))
+	if len(nodeList) == 0 {
+		nodeList = cluster.NodeList()
+	}
+
+	fields := statFields(c)
+	allStats := make(map[string]interface{}, len(nodeList))
+	for _, nodeAddress := range nodeList {
+		if node := cluster.FindNodeByAddress(nodeAddress); node != nil {
+			allStats[nodeAddress] = sparseStats(nodeAllStats(node), fields, "node_status", "node_status_reason")
+		} else {
+			allStats[nodeAddress] = map[string]interface{}{"node_status": "off"}
+		}
+	}
+
+	alerts := common.AlertsByID(cluster.AlertsFrom(lastID))
+	sort.Sort(alerts)
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":         "success",
+		"cluster_status": cluster.Status(),
+		"nodes":          clusterNodesStats(cluster, nodeList),
+		"allstats":       allStats,
+		"throughput":     clusterThroughput(cluster),
+		"alerts":         alertRows(c, alerts),
 	})
 }
 
@@ -172,7 +337,7 @@ func getClusterBasic(c echo.Context) error {
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "cluster not found"))
 	}
 
 	builds := cluster.NodeBuilds()
@@ -202,6 +367,8 @@ func getClusterBasic(c echo.Context) error {
 		"disk":                   cluster.Disk(),
 		"build":                  clusterBuild,
 		"update_interval":        cluster.UpdateInterval(),
+		"poll_backoff":           cluster.PollBackoff(),
+		"editions":               cluster.NodeEditions(),
 	})
 }
 
@@ -223,7 +390,7 @@ func getClusterThroughputHistory(c echo.Context) error {
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"status": "failure", "error": "Cluster not found"})
+		return c.JSON(http.StatusBadRequest, errorMap(errClusterNotFound, "Cluster not found"))
 	}
 
 	var tm time.Time // zero value
@@ -233,7 +400,7 @@ func getClusterThroughputHistory(c echo.Context) error {
 	if beginStr != "" {
 		sinceUnix, err := strconv.ParseInt(beginStr, 10, 64)
 		if err != nil {
-			return c.JSON(http.StatusBadRequest, map[string]string{"status": "failure", "error": "Invalid start_time value"})
+			return c.JSON(http.StatusBadRequest, errorMap(errInvalidParameter, "Invalid start_time value"))
 		}
 		since = sinceUnix / 1000
 	}
@@ -284,9 +451,14 @@ func getClusterThroughput(c echo.Context) error {
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("Cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
 	}
 
+	return c.JSON(http.StatusOK, clusterThroughput(cluster))
+}
+
+// clusterThroughput - get the latest throughput of the nodes of the cluster
+func clusterThroughput(cluster *models.Cluster) map[string]interface{} {
 	// make the output. x: timestamp, y: total reqs, y: successful reqs
 	type chartStat struct {
 		X         *int64   `json:"x"`
@@ -313,7 +485,7 @@ func getClusterThroughput(c echo.Context) error {
 		res[outStatName] = statRes
 	}
 
-	return c.JSON(http.StatusOK, res)
+	return res
 }
 
 var statKeys = []string{
@@ -356,10 +528,15 @@ func getClusterNodes(c echo.Context) error {
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("Cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
 	}
 	nodeList := strings.Split(c.Param("nodes"), ",")
 
+	return c.JSON(http.StatusOK, clusterNodesStats(cluster, nodeList))
+}
+
+// clusterNodesStats - get the summary stats of the nodes of the cluster
+func clusterNodesStats(cluster *models.Cluster, nodeList []string) map[string]interface{} {
 	res := make(map[string]interface{}, len(nodeList))
 	for _, node := range cluster.Nodes() {
 		for _, nodeName := range nodeList {
@@ -373,6 +550,10 @@ func getClusterNodes(c echo.Context) error {
 				stats["memory"] = nodeMem
 				stats["disk"] = nodeDisk
 				stats["node_status"] = node.Status()
+				stats["node_status_reason"] = node.StatusReason()
+				if retryAt := node.RetryAt(); !retryAt.IsZero() {
+					stats["node_retry_at"] = retryAt.Unix()
+				}
 
 				// customized calculations
 				if nodeDisk.TryFloat("total-bytes-disk", 0) > 0 {
@@ -392,14 +573,14 @@ func getClusterNodes(c echo.Context) error {
 		}
 	}
 
-	return c.JSON(http.StatusOK, res)
+	return res
 }
 
 func getClusterUDFs(c echo.Context) error {
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("Cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
 	}
 
 	nodes := cluster.Nodes()
@@ -446,17 +627,26 @@ func postClusterAddUDF(c echo.Context) error {
 
 	c.Bind(&form)
 	if len(form.FileName) == 0 || len(form.FileContents) == 0 {
-		return c.JSON(http.StatusOK, errorMap("Invalid filename/contents"))
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid filename/contents"))
 	}
 
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("Cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	// only Lua modules are supported by the server; catch syntax errors before registering
+	if form.UDFType == "" || strings.EqualFold(form.UDFType, "lua") {
+		if errs := models.ValidateLuaUDF(form.FileName, form.FileContents); len(errs) > 0 {
+			res := errorMap(errInvalidParameter, fmt.Sprintf("Syntax error in %s at line %d: %s", form.FileName, errs[0].Line, errs[0].Message))
+			res["syntax_errors"] = errs
+			return c.JSON(http.StatusOK, res)
+		}
 	}
 
 	if err := cluster.CreateUDF(form.FileName, form.FileContents); err != nil {
-		return c.JSON(http.StatusOK, errorMap(err.Error()))
+		return c.JSON(http.StatusOK, errorMapOf(err))
 	}
 
 	// wait for different nodes to sync up
@@ -474,17 +664,17 @@ func postClusterDropUDF(c echo.Context) error {
 
 	c.Bind(&form)
 	if len(form.FileName) == 0 {
-		return c.JSON(http.StatusOK, errorMap("Invalid filename"))
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid filename"))
 	}
 
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("Cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
 	}
 
 	if err := cluster.DropUDF(form.FileName); err != nil {
-		return c.JSON(http.StatusOK, errorMap(err.Error()))
+		return c.JSON(http.StatusOK, errorMapOf(err))
 	}
 
 	// wait for different nodes to sync up
@@ -499,11 +689,16 @@ func getClusterNamespaces(c echo.Context) error {
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("Cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
 	}
 
 	namespaces := strings.Split(c.Param("namespaces"), ",")
 	res := cluster.NamespaceInfo(namespaces)
+	if fields := statFields(c); len(fields) > 0 {
+		for name, stats := range res {
+			res[name] = sparseStats(stats, fields, "cluster_status")
+		}
+	}
 	return c.JSON(http.StatusOK, res)
 }
 
@@ -511,12 +706,19 @@ func getClusterNamespaceNodes(c echo.Context) error {
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("Cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
 	}
 
 	namespace := c.Param("namespace")
 	nodes := strings.Split(c.Param("nodes"), ",")
 	res := cluster.NamespaceInfoPerNode(namespace, nodes)
+	if fields := statFields(c); len(fields) > 0 {
+		for address, info := range res {
+			if stats, ok := info.(common.Stats); ok {
+				res[address] = sparseStats(stats, fields, "node_status")
+			}
+		}
+	}
 	return c.JSON(http.StatusOK, res)
 }
 
@@ -524,38 +726,84 @@ func getClusterAlerts(c echo.Context) error {
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("Cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
 	}
 
 	strLastID := c.QueryParam("last_id")
 	lastID, err := strconv.ParseInt(strLastID, 10, 64)
 	if err != nil {
-		return c.JSON(http.StatusOK, errorMap("Invalid last_id"))
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid last_id"))
 	}
 
-	alerts := common.AlertsByID(cluster.AlertsFrom(int64(lastID)))
-	sort.Sort(alerts)
+	offset, limit, paginated, err := pagination(c)
+	if err != nil {
+		return c.JSON(http.StatusOK, errorMapOf(err))
+	}
+
+	// the severity of the alerts, e.g. red or red,yellow; empty for all
+	statuses := map[string]bool{}
+	for _, status := range common.DeleteEmpty(strings.Split(strings.ToLower(c.QueryParam("status")), ",")) {
+		statuses[status] = true
+	}
+
+	alerts := common.AlertsByID{}
+	for _, alert := range cluster.AlertsFrom(int64(lastID)) {
+		if len(statuses) == 0 || statuses[strings.ToLower(string(alert.Status))] {
+			alerts = append(alerts, alert)
+		}
+	}
+
+	if c.QueryParam("sort_order") == "desc" {
+		sort.Sort(sort.Reverse(alerts))
+	} else {
+		sort.Sort(alerts)
+	}
+
+	alertCount := len(alerts)
+	if paginated {
+		start, end := page(len(alerts), offset, limit)
+		alerts = alerts[start:end]
+	}
+
+	res := alertRows(c, alerts)
+	if paginated {
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"status":      "success",
+			"offset":      offset,
+			"limit":       limit,
+			"alerts":      res,
+			"alert_count": alertCount,
+		})
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// alertRows - format the alerts as the rows of the alerts table of the UI; the descriptions are translated
+// to the locale param, if it is set
+func alertRows(c echo.Context, alerts []*common.Alert) [][]interface{} {
+	locale := c.QueryParam("locale")
+	config := _observer.Config()
 
 	res := [][]interface{}{}
 	for _, alert := range alerts {
 		res = append(res, []interface{}{
 			strconv.FormatInt(alert.ID, 10),
 			alert.ClusterID,
-			alert.Desc,
+			config.Translate(locale, alert.Desc),
 			alert.Status,
 			"alert",
 			alert.LastOccured.UnixNano() / 1e6,
 		})
 	}
-
-	return c.JSON(http.StatusOK, res)
+	return res
 }
 
 func getClusterNodeAllStats(c echo.Context) error {
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("Cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
 	}
 
 	nodeAddress := c.Param("node")
@@ -563,23 +811,30 @@ func getClusterNodeAllStats(c echo.Context) error {
 	if node == nil {
 		return c.JSON(http.StatusOK, map[string]interface{}{
 			"node_status": "off",
+			"error_code":  errNodeOffline,
 		})
 	}
 
+	return c.JSON(http.StatusOK, sparseStats(nodeAllStats(node), statFields(c), "node_status", "node_status_reason"))
+}
+
+// nodeAllStats - get all the stats and the config of the node
+func nodeAllStats(node *models.Node) common.Stats {
 	res := node.StatsAttrs()
 	for k, v := range node.ConfigAttrs() {
 		res[k] = v
 	}
 	res["node_status"] = node.Status()
+	res["node_status_reason"] = node.StatusReason()
 
-	return c.JSON(http.StatusOK, res)
+	return res
 }
 
 func getClusterNamespaceNodeAllStats(c echo.Context) error {
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("Cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
 	}
 
 	nodeAddress := c.Param("node")
@@ -587,6 +842,7 @@ func getClusterNamespaceNodeAllStats(c echo.Context) error {
 	if node == nil {
 		return c.JSON(http.StatusOK, map[string]interface{}{
 			"node_status": "off",
+			"error_code":  errNodeOffline,
 		})
 	}
 
@@ -595,20 +851,21 @@ func getClusterNamespaceNodeAllStats(c echo.Context) error {
 	if ns == nil {
 		return c.JSON(http.StatusOK, map[string]interface{}{
 			"node_status": "off",
+			"error_code":  errNodeOffline,
 		})
 	}
 
 	res := ns.StatsAttrs()
 	res["node_status"] = node.Status()
 
-	return c.JSON(http.StatusOK, res)
+	return c.JSON(http.StatusOK, sparseStats(res, statFields(c), "node_status"))
 }
 
 func getClusterNamespaceSindexNodeAllStats(c echo.Context) error {
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("Cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
 	}
 
 	nodeAddress := c.Param("node")
@@ -616,6 +873,7 @@ func getClusterNamespaceSindexNodeAllStats(c echo.Context) error {
 	if node == nil {
 		return c.JSON(http.StatusOK, map[string]interface{}{
 			"node_status": "off",
+			"error_code":  errNodeOffline,
 		})
 	}
 
@@ -624,6 +882,7 @@ func getClusterNamespaceSindexNodeAllStats(c echo.Context) error {
 	if ns == nil {
 		return c.JSON(http.StatusOK, map[string]interface{}{
 			"node_status": "off",
+			"error_code":  errNodeOffline,
 		})
 	}
 
@@ -631,14 +890,19 @@ func getClusterNamespaceSindexNodeAllStats(c echo.Context) error {
 	res := ns.IndexStats(sindexName)
 	res["node_status"] = node.Status()
 
-	return c.JSON(http.StatusOK, res)
+	return c.JSON(http.StatusOK, sparseStats(res, statFields(c), "node_status"))
 }
 
 func getClusterNamespaceSindexes(c echo.Context) error {
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("Cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	offset, limit, paginated, err := pagination(c)
+	if err != nil {
+		return c.JSON(http.StatusOK, errorMapOf(err))
 	}
 
 	nsName := c.Param("namespace")
@@ -653,24 +917,90 @@ func getClusterNamespaceSindexes(c echo.Context) error {
 		"indexes":        indexes,
 	}
 
+	if paginated {
+		names := make([]string, 0, len(indexes))
+		for name := range indexes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		start, end := page(len(names), offset, limit)
+		pageIndexes := make(map[string]common.Info, end-start)
+		for _, name := range names[start:end] {
+			pageIndexes[name] = indexes[name]
+		}
+
+		res["indexes"] = pageIndexes
+		res["offset"] = offset
+		res["limit"] = limit
+		res["index_count"] = len(indexes)
+	}
+
 	return c.JSON(http.StatusOK, res)
 }
 
+var _setsSortFields = map[string]common.StatsBy{
+	"set":               common.ByStringField,
+	"objects":           common.ByIntField,
+	"tombstones":        common.ByIntField,
+	"memory_data_bytes": common.ByIntField,
+	"stop-writes-count": common.ByIntField,
+}
+
 func getClusterNamespaceSets(c echo.Context) error {
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("Cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	offset, limit, paginated, err := pagination(c)
+	if err != nil {
+		return c.JSON(http.StatusOK, errorMapOf(err))
+	}
+
+	sortField := c.QueryParam("sort_by")
+	if sortField == "" && paginated {
+		sortField = "set"
+	}
+
+	sortFunc, exists := _setsSortFields[sortField]
+	if sortField != "" && !exists {
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Field specified by sort_by not supported."))
 	}
 
 	nsName := c.Param("namespace")
-	sets := cluster.NamespaceSetsInfo(nsName)
+
+	// the sets whose name contains the name param
+	name := c.QueryParam("name")
+	sets := []common.Stats{}
+	for _, set := range cluster.NamespaceSetsInfo(nsName) {
+		if strings.Contains(set.TryString("set", ""), name) {
+			sets = append(sets, set)
+		}
+	}
+
+	if sortField != "" {
+		if c.QueryParam("sort_order") == "desc" {
+			common.StatsBy(sortFunc).SortReverse(sortField, sets)
+		} else {
+			common.StatsBy(sortFunc).Sort(sortField, sets)
+		}
+	}
 
 	res := map[string]interface{}{
 		"cluster_status": "on",
 		"sets":           sets,
 	}
 
+	if paginated {
+		start, end := page(len(sets), offset, limit)
+		res["sets"] = sets[start:end]
+		res["offset"] = offset
+		res["limit"] = limit
+		res["set_count"] = len(sets)
+	}
+
 	return c.JSON(http.StatusOK, res)
 }
 
@@ -678,7 +1008,7 @@ func getClusterNamespaceStorage(c echo.Context) error {
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("Cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
 	}
 
 	if cluster.Status() != "on" {
@@ -695,7 +1025,7 @@ func getClusterJobsNode(c echo.Context) error {
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("Cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
 	}
 
 	nodeAddr := c.Param("node")
@@ -703,6 +1033,7 @@ func getClusterJobsNode(c echo.Context) error {
 	if node == nil {
 		return c.JSON(http.StatusOK, map[string]interface{}{
 			"node_status": "off",
+			"error_code":  errNodeOffline,
 		})
 	}
 
@@ -744,17 +1075,13 @@ func getClusterNodesJobs(c echo.Context) error {
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("Cluster not found"))
-	}
-
-	offset, err := strconv.Atoi(c.QueryParam("offset"))
-	if err != nil {
-		return c.JSON(http.StatusOK, errorMap("Wrong offset param specified."))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
 	}
 
-	limit, err := strconv.Atoi(c.QueryParam("limit"))
+	// all the jobs are returned if no limit is set
+	offset, limit, _, err := pagination(c)
 	if err != nil {
-		return c.JSON(http.StatusOK, errorMap("Wrong limit param specified."))
+		return c.JSON(http.StatusOK, errorMapOf(err))
 	}
 
 	sortField := c.QueryParam("sort_by")
@@ -774,7 +1101,15 @@ func getClusterNodesJobs(c echo.Context) error {
 
 	sortFunc, exists := _jobsSortFields[sortField]
 	if !exists {
-		return c.JSON(http.StatusOK, errorMap("Field specified by sort_by not supported."))
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Field specified by sort_by not supported."))
+	}
+
+	// string filters; empty values match all jobs
+	filters := map[string]string{
+		"ns":       c.QueryParam("ns"),
+		"set":      c.QueryParam("set"),
+		"module":   c.QueryParam("module"),
+		"job-type": c.QueryParam("job_type"),
 	}
 
 	res := common.Stats{
@@ -792,11 +1127,18 @@ func getClusterNodesJobs(c echo.Context) error {
 		}
 
 		jobStats := node.Jobs()
+	jobsLoop:
 		for _, v := range jobStats {
 			if !strings.HasPrefix(v.TryString("status", ""), jobStatus) {
 				continue
 			}
 
+			for field, value := range filters {
+				if value != "" && v.TryString(field, "") != value {
+					continue jobsLoop
+				}
+			}
+
 			v["address"] = node.Address()
 			v["node"] = map[string]interface{}{
 				"node_status": node.Status(),
@@ -809,21 +1151,14 @@ func getClusterNodesJobs(c echo.Context) error {
 	}
 
 	jobCount := len(jobs)
-	if len(jobs) < offset {
-		res["jobs"] = jobs
-		res["job_count"] = jobCount
-		return c.JSON(http.StatusOK, res)
-	}
-
 	if c.QueryParam("sort_order") == "desc" {
 		common.StatsBy(sortFunc).SortReverse(sortField, jobs)
 	} else {
 		common.StatsBy(sortFunc).Sort(sortField, jobs)
 	}
 
-	if offset+limit <= len(jobs) {
-		jobs = jobs[offset : offset+limit]
-	}
+	start, end := page(len(jobs), offset, limit)
+	jobs = jobs[start:end]
 
 	res["jobs"] = jobs
 	res["job_count"] = jobCount
@@ -835,21 +1170,21 @@ func setClusterUpdateInterval(c echo.Context) error {
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("Cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
 	}
 
 	intervalStr := c.FormValue("update_interval")
 	if len(intervalStr) == 0 {
-		return c.JSON(http.StatusOK, errorMap("Invalid interval value"))
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid interval value"))
 	}
 
 	interval, err := strconv.Atoi(intervalStr)
 	if err != nil {
-		return c.JSON(http.StatusOK, errorMap("Invalid interval value"))
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid interval value"))
 	}
 
 	if interval > 10 || interval < 1 {
-		return c.JSON(http.StatusOK, errorMap("Invalid interval value; must be between 1 and 10"))
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid interval value; must be between 1 and 10"))
 	}
 
 	cluster.SetUpdateInterval(interval)
@@ -870,17 +1205,17 @@ func postClusterAddIndex(c echo.Context) error {
 
 	c.Bind(&form)
 	if len(form.IndexName) == 0 || len(form.BinName) == 0 || len(form.SetName) == 0 || len(form.IndexType) == 0 || len(form.Namespace) == 0 {
-		return c.JSON(http.StatusOK, errorMap("Invalid index data."))
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid index data."))
 	}
 
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("Cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
 	}
 
 	if err := cluster.CreateIndex(form.Namespace, form.SetName, form.IndexName, form.BinName, form.IndexType); err != nil {
-		return c.JSON(http.StatusOK, errorMap(err.Error()))
+		return c.JSON(http.StatusOK, errorMapOf(err))
 	}
 
 	// wait for different nodes to sync up
@@ -898,17 +1233,17 @@ func postClusterDropIndex(c echo.Context) error {
 
 	c.Bind(&form)
 	if len(form.IndexName) == 0 {
-		return c.JSON(http.StatusOK, errorMap("Invalid index name."))
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid index name."))
 	}
 
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("Cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
 	}
 
 	if err := cluster.DropIndex(c.Param("namespace"), "", form.IndexName); err != nil {
-		return c.JSON(http.StatusOK, errorMap(err.Error()))
+		return c.JSON(http.StatusOK, errorMapOf(err))
 	}
 
 	// wait for different nodes to sync up
@@ -918,3 +1253,238 @@ func postClusterDropIndex(c echo.Context) error {
 		"status": "success",
 	})
 }
+
+var _jobHistorySortFields = map[string]common.StatsBy{
+	"address":      common.ByStringField,
+	"job-type":     common.ByStringField,
+	"module":       common.ByStringField,
+	"ns":           common.ByStringField,
+	"set":          common.ByStringField,
+	"result":       common.ByStringField,
+	"completed":    common.ByIntField,
+	"duration":     common.ByIntField,
+	"net-io-bytes": common.ByIntField,
+	"recs-read":    common.ByIntField,
+	"trid":         common.ByIntField,
+}
+
+func getClusterJobHistory(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	// the first 100 jobs are returned if no limit is set
+	offset, limit, _, err := pagination(c)
+	if err != nil {
+		return c.JSON(http.StatusOK, errorMapOf(err))
+	}
+	if limit < 0 {
+		limit = 100
+	}
+
+	since := int64(0)
+	if sinceStr := c.QueryParam("since"); sinceStr != "" {
+		if since, err = strconv.ParseInt(sinceStr, 10, 64); err != nil {
+			return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Wrong since param specified."))
+		}
+	}
+
+	sortField := c.QueryParam("sort_by")
+	if sortField == "" {
+		sortField = "completed"
+	}
+
+	sortFunc, exists := _jobHistorySortFields[sortField]
+	if !exists {
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Field specified by sort_by not supported."))
+	}
+
+	// string filters; empty values match all jobs
+	filters := map[string]string{
+		"address":  c.QueryParam("node"),
+		"ns":       c.QueryParam("ns"),
+		"set":      c.QueryParam("set"),
+		"module":   c.QueryParam("module"),
+		"job-type": c.QueryParam("job_type"),
+		"result":   c.QueryParam("result"),
+	}
+
+	jobs := []common.Stats{}
+outer:
+	for _, job := range cluster.JobHistory() {
+		if job.TryInt("completed", 0) < since {
+			continue
+		}
+
+		for field, value := range filters {
+			if value != "" && job.TryString(field, "") != value {
+				continue outer
+			}
+		}
+
+		jobs = append(jobs, job)
+	}
+
+	if c.QueryParam("sort_order") == "asc" {
+		common.StatsBy(sortFunc).Sort(sortField, jobs)
+	} else {
+		common.StatsBy(sortFunc).SortReverse(sortField, jobs)
+	}
+
+	jobCount := len(jobs)
+	start, end := page(len(jobs), offset, limit)
+	jobs = jobs[start:end]
+
+	return c.JSON(http.StatusOK, common.Stats{
+		"status":    "success",
+		"offset":    start,
+		"limit":     limit,
+		"jobs":      jobs,
+		"job_count": jobCount,
+	})
+}
+
+func getClusterNamespaceSetIndexes(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status": "success",
+		"nodes":  cluster.SetIndexes(c.Param("namespace")),
+	})
+}
+
+func postClusterSetIndex(c echo.Context, enable bool) error {
+	form := struct {
+		SetName string `form:"set_name"`
+	}{}
+
+	c.Bind(&form)
+	if len(form.SetName) == 0 {
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid set name."))
+	}
+
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	if err := cluster.SetSetIndex(c.Param("namespace"), form.SetName, enable); err != nil {
+		return c.JSON(http.StatusOK, errorMapOf(err))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status": "success",
+	})
+}
+
+func postClusterEnableSetIndex(c echo.Context) error {
+	return postClusterSetIndex(c, true)
+}
+
+func postClusterDisableSetIndex(c echo.Context) error {
+	return postClusterSetIndex(c, false)
+}
+
+func getClusterClientPolicy(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	return c.JSON(http.StatusOK, cluster.ClientPolicy())
+}
+
+func postClusterClientPolicy(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	// options which are not sent keep their current values
+	opts := cluster.ClientPolicy()
+	if err := c.Bind(&opts); err != nil {
+		return c.JSON(http.StatusOK, errorMapOf(err))
+	}
+
+	if err := _observer.UpdateClusterClientPolicy(cluster, opts); err != nil {
+		return c.JSON(http.StatusOK, errorMapOf(err))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":        "success",
+		"client_policy": cluster.ClientPolicy(),
+	})
+}
+
+func getClusterStatProfile(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	return c.JSON(http.StatusOK, cluster.StatProfile())
+}
+
+func postClusterStatProfile(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	profile, err := models.ParseStatProfile(c.FormValue("stat_profile"))
+	if err != nil {
+		return c.JSON(http.StatusOK, errorMapOf(err))
+	}
+
+	cluster.SetStatProfile(profile)
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":       "success",
+		"stat_profile": profile,
+	})
+}
+
+func postClusterSetName(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	name := strings.TrimSpace(c.FormValue("cluster_name"))
+	errs, err := cluster.SetClusterName(name, requestUser(c))
+
+	nodes := map[string]interface{}{}
+	for node, nodeErr := range errs {
+		nodeRes := map[string]interface{}{
+			"node_status": node.Status(),
+			"status":      "success",
+		}
+		if nodeErr != nil {
+			nodeRes["status"] = "failure"
+			nodeRes["error"] = nodeErr.Error()
+		}
+		nodes[node.Address()] = nodeRes
+	}
+
+	if err != nil {
+		return c.JSON(http.StatusOK, setErrorOf(map[string]interface{}{"nodes": nodes}, err))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":       "success",
+		"cluster_name": name,
+		"nodes":        nodes,
+	})
+}
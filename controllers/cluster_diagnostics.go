@@ -0,0 +1,69 @@
+package controllers
+
+import (
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	as "github.com/aerospike/aerospike-client-go/v5"
+
+	"github.com/aerospike-community/amc/models"
+)
+
+// the time to wait for the TCP connection to a seed when checking if it is reachable
+const seedDialTimeout = 2 * time.Second
+
+// connectDiagnostics - describe the connection to a cluster after registering it: whether every seed accepts
+// TCP connections, and the status of the nodes if the cluster is connected; cluster is nil if the connection failed
+func connectDiagnostics(cluster *models.Cluster, seeds []*as.Host, useTLS bool, authMode as.AuthMode, elapsed time.Duration) map[string]interface{} {
+	seedResults := make([]map[string]interface{}, len(seeds))
+	wg := sync.WaitGroup{}
+	wg.Add(len(seeds))
+	for i, seed := range seeds {
+		go func(i int, seed *as.Host) {
+			defer wg.Done()
+
+			address := net.JoinHostPort(seed.Name, strconv.Itoa(seed.Port))
+			res := map[string]interface{}{
+				"address":   address,
+				"tls_name":  seed.TLSName,
+				"reachable": true,
+			}
+
+			start := time.Now()
+			conn, err := net.DialTimeout("tcp", address, seedDialTimeout)
+			if err != nil {
+				res["reachable"] = false
+				res["error"] = err.Error()
+			} else {
+				conn.Close()
+				res["dial_ms"] = time.Since(start).Milliseconds()
+			}
+			seedResults[i] = res
+		}(i, seed)
+	}
+	wg.Wait()
+
+	res := map[string]interface{}{
+		"connect_ms": elapsed.Milliseconds(),
+		"tls":        useTLS,
+		"auth_mode":  models.AuthModeName(authMode),
+		"seeds":      seedResults,
+	}
+
+	if cluster != nil {
+		nodes := []map[string]interface{}{}
+		for _, node := range cluster.Nodes() {
+			nodes = append(nodes, map[string]interface{}{
+				"address": node.Address(),
+				"status":  string(node.Status()),
+				"build":   node.Build(),
+			})
+		}
+		res["nodes"] = nodes
+		res["security_enabled"] = cluster.SecurityEnabled()
+	}
+
+	return res
+}
@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// observerCached - validate the responses of the GET endpoints serving the stats collected by the observer with an
+// ETag and Last-Modified. The stats only change once per update interval, so the polls of the UI in between
+// are answered with 304. The ETag is derived from the last update of the cluster, its status and the request URI.
+func observerCached(f func(c echo.Context) error) func(c echo.Context) error {
+	return func(c echo.Context) error {
+		cluster := _observer.FindClusterByID(c.Param("clusterUUID"))
+		if cluster == nil {
+			return f(c)
+		}
+
+		updated := cluster.LastUpdated()
+		if updated.IsZero() {
+			return f(c)
+		}
+
+		h := fnv.New64a()
+		fmt.Fprint(h, cluster.Status(), c.Request().URL.RequestURI())
+		etag := fmt.Sprintf(`W/"%x-%x"`, updated.UnixNano(), h.Sum64())
+
+		header := c.Response().Header()
+		header.Set("ETag", etag)
+		header.Set("Last-Modified", updated.UTC().Format(http.TimeFormat))
+		header.Set("Cache-Control", "no-cache")
+
+		if notModified(c.Request(), etag, updated) {
+			return c.NoContent(http.StatusNotModified)
+		}
+		return f(c)
+	}
+}
+
+// notModified - check the conditional headers of the request; If-None-Match takes precedence over If-Modified-Since
+func notModified(req *http.Request, etag string, modified time.Time) bool {
+	if inm := req.Header.Get("If-None-Match"); inm != "" {
+		for _, tag := range strings.Split(inm, ",") {
+			// weak comparison; the responses differ only in their encoding
+			if tag = strings.TrimSpace(tag); tag == "*" || strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ims := req.Header.Get("If-Modified-Since"); ims != "" {
+		if t, err := http.ParseTime(ims); err == nil {
+			return !modified.Truncate(time.Second).After(t)
+		}
+	}
+	return false
+}
@@ -0,0 +1,184 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/aerospike-community/amc/common"
+	"github.com/aerospike-community/amc/models"
+)
+
+const mimePDF = "application/pdf"
+
+// reportSection is a table of the cluster report, rendered the same way to CSV and PDF
+type reportSection struct {
+	title  string
+	header []string
+	rows   [][]string
+}
+
+// getClusterReport - the report of the current state of the cluster, for change-management records;
+// format is json (the default), csv or pdf
+func getClusterReport(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	format := c.QueryParam("format")
+	if format != "" && format != "json" && format != "csv" && format != "pdf" {
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid format"))
+	}
+
+	report := cluster.Report()
+	if format == "" || format == "json" {
+		return c.JSON(http.StatusOK, report)
+	}
+
+	name := report.Alias
+	if name == "" {
+		name = report.ClusterID
+	}
+	filename := fmt.Sprintf("cluster-report-%s-%s.%s", reportFilenamePart(name), report.GeneratedAt.UTC().Format("20060102T150405Z"), format)
+	c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="`+filename+`"`)
+
+	sections := reportSections(report)
+	if format == "pdf" {
+		return c.Blob(http.StatusOK, mimePDF, reportPDF(report, name, sections))
+	}
+
+	buf := new(bytes.Buffer)
+	w := csv.NewWriter(buf)
+	for i, s := range sections {
+		if i > 0 {
+			w.Write([]string{})
+		}
+		w.Write([]string{s.title})
+		w.Write(s.header)
+		w.WriteAll(s.rows)
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return c.JSON(http.StatusOK, errorMapOf(err))
+	}
+	return c.Blob(http.StatusOK, "text/csv", buf.Bytes())
+}
+
+// getClusterSummaryReport - the summary report of the cluster over the current period of the scheduled reports
+func getClusterSummaryReport(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	return c.JSON(http.StatusOK, cluster.SummaryReport())
+}
+
+// reportFilenamePart - the name of the cluster, with the characters which are not safe in a file name replaced
+func reportFilenamePart(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.' {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+func reportSections(report *models.ClusterReport) []reportSection {
+	timeFormat := func(tm time.Time) string {
+		if tm.IsZero() {
+			return ""
+		}
+		return tm.UTC().Format(time.RFC3339)
+	}
+	itoa := func(v int64) string { return strconv.FormatInt(v, 10) }
+
+	summary := reportSection{
+		title:  "Cluster",
+		header: []string{"cluster_id", "alias", "seed_address", "status", "generated_at"},
+		rows:   [][]string{{report.ClusterID, report.Alias, report.SeedAddress, report.Status, timeFormat(report.GeneratedAt)}},
+	}
+
+	nodes := reportSection{title: "Nodes", header: []string{"address", "node_id", "status", "build"}}
+	for _, n := range report.Nodes {
+		nodes.rows = append(nodes.rows, []string{n.Address, n.ID, n.Status, n.Build})
+	}
+
+	versions := reportSection{title: "Versions", header: []string{"build", "node_count", "nodes"}}
+	for _, v := range report.Versions {
+		versions.rows = append(versions.rows, []string{v.Build, strconv.Itoa(len(v.Nodes)), strings.Join(v.Nodes, " ")})
+	}
+
+	namespaces := reportSection{
+		title: "Namespaces",
+		header: []string{"namespace", "nodes", "objects", "repl_factor", "memory_used_bytes", "memory_total_bytes",
+			"disk_used_bytes", "disk_total_bytes", "least_available_pct", "hwm_breached", "stop_writes"},
+	}
+	for _, ns := range report.Namespaces {
+		availablePct := ""
+		if ns.AvailablePct >= 0 {
+			availablePct = itoa(ns.AvailablePct)
+		}
+		namespaces.rows = append(namespaces.rows, []string{
+			ns.Name, strconv.Itoa(ns.Nodes), itoa(ns.Objects), itoa(ns.ReplFactor), itoa(ns.MemoryUsed), itoa(ns.MemoryTotal),
+			itoa(ns.DiskUsed), itoa(ns.DiskTotal), availablePct, strconv.FormatBool(ns.HWMBreached), strconv.FormatBool(ns.StopWrites),
+		})
+	}
+
+	alerts := reportSection{title: "Alerts", header: []string{"status", "node", "namespace", "description", "created", "last_occurred", "resolved"}}
+	for _, a := range report.Alerts {
+		alerts.rows = append(alerts.rows, []string{
+			a.Status, a.Node, a.Namespace, a.Description, timeFormat(a.Created), timeFormat(a.LastOccured), strconv.FormatBool(a.Resolved),
+		})
+	}
+
+	diffs := reportSection{title: "Config differences between nodes", header: []string{"namespace", "parameter", "value", "nodes"}}
+	for _, d := range report.ConfigDiffs {
+		values := make([]string, 0, len(d.Values))
+		for v := range d.Values {
+			values = append(values, v)
+		}
+		sort.Strings(values)
+		for _, v := range values {
+			diffs.rows = append(diffs.rows, []string{d.Namespace, d.Parameter, v, strings.Join(d.Values[v], " ")})
+		}
+	}
+
+	changes := reportSection{
+		title:  "Config changes",
+		header: []string{"time", "node", "context", "namespace", "parameter", "old_value", "new_value", "user"},
+	}
+	for _, cc := range report.ConfigChanges {
+		changes.rows = append(changes.rows, []string{
+			timeFormat(cc.Time), cc.Node, cc.Context, cc.Namespace, cc.Parameter, cc.OldValue, cc.NewValue, cc.User,
+		})
+	}
+
+	return []reportSection{summary, nodes, versions, namespaces, alerts, diffs, changes}
+}
+
+func reportPDF(report *models.ClusterReport, name string, sections []reportSection) []byte {
+	doc := common.NewPDFDocument("Cluster report: " + name)
+	doc.Heading("Cluster report: " + name)
+	doc.Text("Generated at " + report.GeneratedAt.UTC().Format(time.RFC1123))
+
+	for _, s := range sections {
+		doc.Heading(s.title)
+		if len(s.rows) == 0 {
+			doc.Text("None")
+			continue
+		}
+		doc.Table(s.header, s.rows)
+	}
+	return doc.Bytes()
+}
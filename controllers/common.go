@@ -1,6 +1,12 @@
 package controllers
 
 import (
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/aerospike-community/amc/common"
 	"github.com/aerospike-community/amc/models"
 )
 
@@ -15,11 +21,93 @@ type NodeResult struct {
 	Status      string
 	Err         error
 	UnsetParams []string
+
+	InvalidParams    map[string]string
+	UnverifiedParams []string
 }
 
-func errorMap(err string) map[string]interface{} {
+func errorMap(code apiErrorCode, err string) map[string]interface{} {
 	return map[string]interface{}{
-		"status": "failure",
-		"error":  err,
+		"status":     "failure",
+		"error":      err,
+		"error_code": code,
+	}
+}
+
+// errorMapOf - the failure response of an error, with its code
+func errorMapOf(err error) map[string]interface{} {
+	return errorMap(errorCodeOf(err), err.Error())
+}
+
+// pagination - read the optional offset and limit query params of a list endpoint;
+// paginated is false if neither is set, and the whole list is returned in the original format.
+// A negative limit means no limit.
+func pagination(c echo.Context) (offset, limit int, paginated bool, err error) {
+	offsetStr, limitStr := c.QueryParam("offset"), c.QueryParam("limit")
+	if offsetStr == "" && limitStr == "" {
+		return 0, -1, false, nil
+	}
+
+	if offsetStr != "" {
+		if offset, err = strconv.Atoi(offsetStr); err != nil || offset < 0 {
+			return 0, 0, false, newAPIError(errInvalidParameter, "Wrong offset param specified.")
+		}
+	}
+
+	limit = -1
+	if limitStr != "" {
+		if limit, err = strconv.Atoi(limitStr); err != nil || limit < 0 {
+			return 0, 0, false, newAPIError(errInvalidParameter, "Wrong limit param specified.")
+		}
+	}
+
+	return offset, limit, true, nil
+}
+
+// page - get the bounds of the page of a list of n items; a negative limit means no limit
+func page(n, offset, limit int) (start, end int) {
+	if offset > n {
+		offset = n
+	}
+
+	// compared to the items left, since offset+limit overflows for the huge limits
+	end = n
+	if limit >= 0 && limit < n-offset {
+		end = offset + limit
+	}
+
+	return offset, end
+}
+
+// statFields - read the optional fields query param of a stats endpoint, a comma separated list of the stats
+// to return; a name ending with * matches the stats starting with it, e.g. storage-engine.*. Empty if not set.
+func statFields(c echo.Context) []string {
+	return common.SplitList(c.QueryParam("fields"))
+}
+
+// sparseStats - keep only the requested fields of the stats, and the keys which are always returned, e.g. node_status;
+// the stats which do not exist are left out. The stats are returned as they are if no fields are requested.
+func sparseStats(stats common.Stats, fields []string, keep ...string) common.Stats {
+	if len(fields) == 0 || stats == nil {
+		return stats
+	}
+
+	res := make(common.Stats, len(fields)+len(keep))
+	for _, k := range keep {
+		if v, exists := stats[k]; exists {
+			res[k] = v
+		}
+	}
+	for _, field := range fields {
+		if prefix := strings.TrimSuffix(field, "*"); prefix != field {
+			for k, v := range stats {
+				if strings.HasPrefix(k, prefix) {
+					res[k] = v
+				}
+			}
+		} else if v, exists := stats[field]; exists {
+			res[field] = v
+		}
 	}
+	return res
 }
@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+func getClusterConfigHistory(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	limit := 100
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		var err error
+		if limit, err = strconv.Atoi(limitStr); err != nil {
+			return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Wrong limit param specified."))
+		}
+	}
+
+	changes, err := cluster.ConfigChanges(limit)
+	if err != nil {
+		return c.JSON(http.StatusOK, errorMapOf(err))
+	}
+
+	res := make([]common.Stats, 0, len(changes))
+	for _, cc := range changes {
+		res = append(res, common.Stats{
+			"id":          cc.ID,
+			"node":        cc.NodeAddress,
+			"context":     cc.Context,
+			"namespace":   cc.Namespace.String,
+			"parameter":   cc.Parameter,
+			"old_value":   cc.OldValue,
+			"new_value":   cc.NewValue,
+			"user":        cc.User,
+			"created":     cc.Created.UnixNano() / 1e6,
+			"rolled_back": cc.RolledBack,
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":  "success",
+		"changes": res,
+	})
+}
+
+func postClusterConfigRollback(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	cc, err := cluster.RollbackConfigChange(c.Param("changeID"), requestUser(c))
+	if err != nil {
+		return c.JSON(http.StatusOK, errorMapOf(err))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":    "success",
+		"node":      cc.NodeAddress,
+		"parameter": cc.Parameter,
+		"value":     cc.OldValue,
+	})
+}
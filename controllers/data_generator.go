@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/aerospike-community/amc/models"
+)
+
+// getClusterDataGenerator - the progress of the running or the last data generator of the cluster
+func getClusterDataGenerator(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	res := map[string]interface{}{
+		"status":         "success",
+		"test_cluster":   cluster.IsTestCluster(),
+		"data_generator": nil,
+	}
+	if g := cluster.DataGenerator(); g != nil {
+		res["data_generator"] = g.Status()
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// postClusterDataGenerator - start writing generated records to a namespace of a test cluster; the JSON body
+// is the spec of the records, see models.DataGenSpec
+func postClusterDataGenerator(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	var spec models.DataGenSpec
+	if err := c.Bind(&spec); err != nil {
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid parameters"))
+	}
+
+	g, err := cluster.StartDataGenerator(spec)
+	if err != nil {
+		return c.JSON(http.StatusOK, errorMapOf(err))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":         "success",
+		"data_generator": g.Status(),
+	})
+}
+
+// deleteClusterDataGenerator - stop the running data generator of the cluster; the records written are kept
+func deleteClusterDataGenerator(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	g := cluster.DataGenerator()
+	if g == nil {
+		return c.JSON(http.StatusOK, errorMap(errNotFound, "Data generator not found"))
+	}
+	g.Stop()
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status": "success",
+	})
+}
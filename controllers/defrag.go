@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+func getClusterNamespaceDefrag(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	tm, err := namespaceHistoryStartTime(c, cluster)
+	if err != nil {
+		return c.JSON(http.StatusOK, errorMapOf(err))
+	}
+
+	namespace := c.Param("namespace")
+	res := map[string]common.Stats{}
+	for _, node := range cluster.Nodes() {
+		ns := node.NamespaceByName(namespace)
+		if ns == nil {
+			continue
+		}
+
+		defrag := ns.Defrag()
+		defrag["node_status"] = node.Status()
+		defrag["history"] = namespaceStatsHistory(cluster, ns, tm, "defrag-q", "write-q", "defrag-reads", "defrag-writes")
+		res[node.Address()] = defrag
+	}
+
+	if len(res) == 0 {
+		return c.JSON(http.StatusOK, errorMap(errNamespaceNotFound, "Namespace not found"))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status": "success",
+		"nodes":  res,
+	})
+}
+
+func postClusterNamespaceDefrag(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	form := struct {
+		LwmPct string `form:"defrag_lwm_pct"`
+		Sleep  string `form:"defrag_sleep"`
+	}{}
+
+	c.Bind(&form)
+
+	namespace := c.Param("namespace")
+	nodeAddrs := strings.Split(c.Param("nodes"), ",")
+	res := make(common.Stats, len(nodeAddrs))
+	for _, addr := range nodeAddrs {
+		res[addr] = map[string]interface{}{"node_status": "off"}
+	}
+
+	for _, node := range cluster.FindNodesByAddress(nodeAddrs...) {
+		nodeRes := map[string]interface{}{
+			"node_status": string(node.Status()),
+			"status":      "success",
+		}
+
+		ns := node.NamespaceByName(namespace)
+		if ns == nil {
+			nodeRes["status"] = "failure"
+			nodeRes["error"] = "Namespace not found on node"
+		} else if unset, err := ns.SetDefragConfig(form.LwmPct, form.Sleep, requestUser(c)); err != nil {
+			nodeRes["status"] = "failure"
+			nodeRes["error"] = err.Error()
+			nodeRes["unset_parameters"] = unset
+		}
+		res[node.Address()] = nodeRes
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
@@ -4,10 +4,11 @@ import (
 	"errors"
 	"math"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
-	// log "github.com/sirupsen/logrus"
 	"github.com/labstack/echo/v4"
 
 	"github.com/aerospike-community/amc/common"
@@ -18,21 +19,88 @@ func postClusterFireCmd(c echo.Context) error {
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("Cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
 	}
 
-	cmd := c.FormValue("command")
+	form := struct {
+		Command string `form:"command"`
+		Nodes   string `form:"nodes"`
+		Timeout string `form:"timeout"`
+		Format  string `form:"format"`
+	}{}
+
+	if err := c.Bind(&form); err != nil {
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid parameters"))
+	}
+
+	cmd := form.Command
 	if len(cmd) == 0 {
-		return c.JSON(http.StatusOK, errorMap("Invalid command"))
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid command"))
+	}
+
+	if err := cluster.CheckInfoCommand(cmd); err != nil {
+		return c.JSON(http.StatusOK, errorMapOf(err))
+	}
+
+	nodes := cluster.Nodes()
+	if form.Nodes != "" && form.Nodes != "all" {
+		nodeAddrs := strings.Split(form.Nodes, ",")
+		nodes = cluster.FindNodesByAddress(nodeAddrs...)
+		if len(nodes) != len(nodeAddrs) {
+			return c.JSON(http.StatusOK, errorMap(errNodeNotFound, "Node not found"))
+		}
+	}
+
+	// timeout in milliseconds; 0 uses the client policy timeout
+	var timeout time.Duration
+	if form.Timeout != "" {
+		ms, err := strconv.Atoi(form.Timeout)
+		if err != nil || ms < 0 {
+			return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid timeout"))
+		}
+		timeout = time.Duration(ms) * time.Millisecond
+	}
+
+	infos, errs := cluster.RequestInfoNodes(nodes, timeout, cmd)
+
+	// legacy format used by the UI command line
+	if form.Format != "structured" {
+		res := map[string][]string{}
+		for node, r := range infos {
+			if err := errs[node]; err != nil {
+				r = err.Error()
+			}
+			res[node.Address()] = strings.Split(r, ";")
+		}
+		return c.JSON(http.StatusOK, res)
 	}
 
-	infos, _ := cluster.RequestInfoAll(cmd)
-	res := map[string][]string{}
+	res := map[string]interface{}{}
 	for node, r := range infos {
-		res[node.Address()] = strings.Split(r, ";")
+		nodeRes := map[string]interface{}{
+			"node_status": node.Status(),
+		}
+
+		if err := errs[node]; err != nil {
+			nodeRes["status"] = "failure"
+			nodeRes["error"] = err.Error()
+		} else if strings.HasPrefix(strings.ToLower(r), "error") {
+			nodeRes["status"] = "failure"
+			nodeRes["error"] = r
+		} else {
+			nodeRes["status"] = "success"
+			nodeRes["raw"] = r
+			nodeRes["result"] = models.ParseInfoResult(r)
+		}
+
+		res[node.Address()] = nodeRes
 	}
 
-	return c.JSON(http.StatusOK, res)
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":  "success",
+		"command": cmd,
+		"nodes":   res,
+	})
 }
 
 func getCurrentMonitoringClusters(c echo.Context) error {
@@ -43,7 +111,7 @@ func getCurrentMonitoringClusters(c echo.Context) error {
 	if err != nil || !_observer.SessionExists(sid) {
 		if len(autoClusters) == 0 {
 			invalidateSession(c)
-			return c.JSON(http.StatusUnauthorized, errorMap("invalid session : None"))
+			return c.JSON(http.StatusUnauthorized, errorMap(errInvalidSession, "invalid session : None"))
 		}
 		// there are auto clusters; validate and create a new session
 		// update the session cookie
@@ -72,16 +140,37 @@ func getCurrentMonitoringClusters(c echo.Context) error {
 	return c.JSON(http.StatusOK, result)
 }
 
-func getMultiClusterView(c echo.Context) error {
+// multiClusterSession - the session of the multicluster view; a session is created if there are automatic clusters
+func multiClusterSession(c echo.Context) (string, bool) {
 	sid, err := sessionID(c)
 	if err != nil {
 		autoClusters := _observer.AutoClusters()
 		if len(autoClusters) <= 0 {
 			invalidateSession(c)
-			return c.JSON(http.StatusOK, errorMap("invalid session : None"))
+			return "", false
 		} // there are auto clusters; automatically create a session
 		sid = manageSession(c)
 	}
+	return sid, true
+}
+
+// getMultiClusterView - the XDR topology of the clusters, keyed by the cluster identities
+func getMultiClusterView(c echo.Context) error {
+	sid, ok := multiClusterSession(c)
+	if !ok {
+		return c.JSON(http.StatusOK, errorMap(errInvalidSession, "invalid session : None"))
+	}
+
+	return c.JSON(http.StatusOK, _observer.DatacenterTopology(sid))
+}
+
+// getLegacyMultiClusterView - deprecated: the view of the old UI, keyed by the cluster ids and by the seed
+// addresses of the remote datacenters; served on the deprecated routes until the UI uses the topology
+func getLegacyMultiClusterView(c echo.Context) error {
+	sid, ok := multiClusterSession(c)
+	if !ok {
+		return c.JSON(http.StatusOK, errorMap(errInvalidSession, "invalid session : None"))
+	}
 
 	return c.JSON(http.StatusOK, _observer.DatacenterInfo(sid))
 }
@@ -156,12 +245,12 @@ func getNodeLatencyHistory(c echo.Context) error {
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("Cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
 	}
 
 	node := cluster.FindNodeByAddress(c.Param("nodes"))
 	if node == nil {
-		return c.JSON(http.StatusOK, errorMap("Node not found"))
+		return c.JSON(http.StatusOK, errorMap(errNodeNotFound, "Node not found"))
 	}
 
 	latencyHistory := []common.Stats{}
@@ -181,7 +270,7 @@ func getNodesLatencyHistory(c echo.Context) error {
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("Cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
 	}
 
 	nodeAddrs := common.DeleteEmpty(strings.Split(c.Param("nodes"), ","))
@@ -214,12 +303,12 @@ func getNodeLatency(c echo.Context) error {
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("Cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
 	}
 
 	nodes := cluster.FindNodesByAddress(strings.Split(c.Param("nodes"), ",")...)
 	if len(nodes) == 0 {
-		return c.JSON(http.StatusOK, errorMap("Node not found"))
+		return c.JSON(http.StatusOK, errorMap(errNodeNotFound, "Node not found"))
 	}
 
 	res := map[string]interface{}{}
@@ -238,7 +327,7 @@ func getClusterNodeAllConfig(c echo.Context) error {
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("Cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
 	}
 
 	nodeAddr := c.Param("node")
@@ -253,14 +342,47 @@ func getClusterNodeAllConfig(c echo.Context) error {
 	res["address"] = node.Address()
 	res["node_status"] = node.Status()
 
-	return c.JSON(http.StatusOK, res)
+	return c.JSON(http.StatusOK, sparseStats(res, statFields(c), "address", "node_status"))
+}
+
+func getClusterNodeConfFile(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	nodeAddr := c.Param("node")
+	node := cluster.FindNodeByAddress(nodeAddr)
+	if node == nil {
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"node_status": "off",
+		})
+	}
+
+	conf, err := node.GenerateConfFile()
+	if err != nil {
+		return c.JSON(http.StatusOK, errorMapOf(err))
+	}
+
+	if c.QueryParam("download") == "true" {
+		c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="aerospike.conf"`)
+		return c.String(http.StatusOK, conf)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":      "success",
+		"address":     node.Address(),
+		"node_status": node.Status(),
+		"config":      conf,
+	})
 }
 
 func setClusterNodesConfig(c echo.Context) error {
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("Cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
 	}
 
 	nodeAddrs := strings.Split(c.Param("nodes"), ",")
@@ -276,7 +398,7 @@ func setClusterNodesConfig(c echo.Context) error {
 
 	formParams, err := c.FormParams()
 	if err != nil {
-		return c.JSON(http.StatusOK, errorMap("No Parameters found"))
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "No Parameters found"))
 	}
 	config := make(map[string]string, len(formParams))
 	for k, v := range formParams {
@@ -286,6 +408,44 @@ func setClusterNodesConfig(c echo.Context) error {
 		}
 	}
 
+	// validate the parameters on all nodes before applying anything
+	invalidNodes := map[*models.Node]bool{}
+	for _, node := range nodes {
+		if invalidParams := node.ValidateServerConfig("service", config); len(invalidParams) > 0 {
+			invalidNodes[node] = true
+			res[node.Address()] = map[string]interface{}{
+				"node_status":        string(node.Status()),
+				"status":             "failure",
+				"error":              "Invalid parameters",
+				"invalid_parameters": invalidParams,
+			}
+		}
+	}
+
+	if len(invalidNodes) > 0 {
+		for _, node := range nodes {
+			if !invalidNodes[node] {
+				res[node.Address()] = map[string]interface{}{
+					"node_status": string(node.Status()),
+					"status":      "failure",
+					"error":       "Not applied; parameters were invalid on other nodes",
+				}
+			}
+		}
+		return c.JSON(http.StatusOK, res)
+	}
+
+	parameters := make([]string, 0, len(config))
+	for p := range config {
+		parameters = append(parameters, p)
+	}
+
+	oldValues := make(map[*models.Node]map[string]string, len(nodes))
+	for _, node := range nodes {
+		oldValues[node] = node.ServerConfigValues("service", parameters)
+	}
+
+	user := requestUser(c)
 	wg := new(sync.WaitGroup)
 	wg.Add(len(nodes))
 	resChan := make(chan *NodeResult, len(nodes))
@@ -294,24 +454,77 @@ func setClusterNodesConfig(c echo.Context) error {
 		go func(node *models.Node) {
 			defer wg.Done()
 
-			unsetParams, err := node.SetServerConfig("service", config)
-			resChan <- &NodeResult{Node: node, Name: node.Address(), Err: err, UnsetParams: unsetParams}
+			unsetParams, err := node.SetServerConfig("service", config, user)
+			nr := &NodeResult{Node: node, Name: node.Address(), Err: err, UnsetParams: unsetParams}
+			if err == nil {
+				nr.UnverifiedParams = node.VerifyServerConfig("service", config)
+			}
+			resChan <- nr
 		}(node)
 	}
 
 	wg.Wait()
 	close(resChan)
 
+	results := make([]*NodeResult, 0, len(nodes))
+	failed := false
 	for nr := range resChan {
+		if nr.Err != nil || len(nr.UnverifiedParams) > 0 {
+			failed = true
+		}
+		results = append(results, nr)
+	}
+
+	// apply all or nothing: revert the parameters the nodes accepted, including those of the nodes
+	// which failed to set some of them
+	rolledBack := map[*models.Node]bool{}
+	if failed {
+		for _, nr := range results {
+			revert := make(map[string]string, len(oldValues[nr.Node]))
+			for parameter, value := range oldValues[nr.Node] {
+				revert[parameter] = value
+			}
+			for _, parameter := range nr.UnsetParams {
+				delete(revert, parameter)
+			}
+			if len(revert) == 0 {
+				continue
+			}
+
+			if _, err := nr.Node.SetServerConfig("service", revert, user); err != nil {
+				requestLog(c).Errorf("Error reverting config on node %s: %s", nr.Name, err.Error())
+				continue
+			}
+			rolledBack[nr.Node] = true
+		}
+	}
+
+	for _, nr := range results {
 		nodeStatus := nr.Status
 		if nr.Node != nil {
 			nodeStatus = string(nr.Node.Status())
 		}
 
-		res[nr.Name] = map[string]interface{}{
-			"node_status":      nodeStatus,
-			"unset_parameters": nr.UnsetParams,
+		nodeRes := map[string]interface{}{
+			"node_status":           nodeStatus,
+			"status":                "success",
+			"unset_parameters":      nr.UnsetParams,
+			"unverified_parameters": nr.UnverifiedParams,
+			"rolled_back":           rolledBack[nr.Node],
 		}
+
+		if nr.Err != nil {
+			nodeRes["status"] = "failure"
+			nodeRes["error"] = nr.Err.Error()
+		} else if len(nr.UnverifiedParams) > 0 {
+			nodeRes["status"] = "failure"
+			nodeRes["error"] = "New values were not reflected in the node config"
+		} else if failed {
+			nodeRes["status"] = "failure"
+			nodeRes["error"] = "Reverted; config could not be applied on all nodes"
+		}
+
+		res[nr.Name] = nodeRes
 	}
 
 	return c.JSON(http.StatusOK, res)
@@ -321,7 +534,7 @@ func getClusterNamespaceAllConfig(c echo.Context) error {
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("Cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
 	}
 
 	nodeAddr := c.Param("node")
@@ -330,6 +543,7 @@ func getClusterNamespaceAllConfig(c echo.Context) error {
 		return c.JSON(http.StatusNotFound, map[string]interface{}{
 			"node":        nodeAddr,
 			"node_status": "off",
+			"error_code":  errNodeOffline,
 		})
 	}
 
@@ -338,6 +552,7 @@ func getClusterNamespaceAllConfig(c echo.Context) error {
 		return c.JSON(http.StatusOK, map[string]interface{}{
 			"node":        nodeAddr,
 			"node_status": "off",
+			"error_code":  errNodeOffline,
 		})
 	}
 
@@ -345,14 +560,14 @@ func getClusterNamespaceAllConfig(c echo.Context) error {
 	res["node"] = nodeAddr
 	res["node_status"] = node.Status()
 
-	return c.JSON(http.StatusOK, res)
+	return c.JSON(http.StatusOK, sparseStats(res, statFields(c), "node", "node_status"))
 }
 
 func setClusterNamespaceConfig(c echo.Context) error {
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("Cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
 	}
 
 	nodeAddrs := strings.Split(c.Param("node"), ",")
@@ -368,7 +583,7 @@ func setClusterNamespaceConfig(c echo.Context) error {
 
 	formParams, err := c.FormParams()
 	if err != nil {
-		return c.JSON(http.StatusOK, errorMap("Invalid input"))
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid input"))
 	}
 
 	config := make(map[string]string, len(formParams))
@@ -380,6 +595,7 @@ func setClusterNamespaceConfig(c echo.Context) error {
 	}
 
 	namespaceName := c.Param("namespace")
+	user := requestUser(c)
 	resChan := make(chan *NodeResult, len(nodes))
 	wg := new(sync.WaitGroup)
 	for _, node := range nodes {
@@ -389,7 +605,7 @@ func setClusterNamespaceConfig(c echo.Context) error {
 			go func(node *models.Node, ns *models.Namespace) {
 				defer wg.Done()
 
-				unsetParams, err := ns.SetConfig(config)
+				unsetParams, err := ns.SetConfig(config, user)
 				resChan <- &NodeResult{Node: node, Name: node.Address(), Status: string(node.Status()), Err: err, UnsetParams: unsetParams}
 			}(node, ns)
 		} else {
@@ -428,7 +644,7 @@ func postAddClusterNodes(c echo.Context) error {
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "cluster not found"))
 	}
 
 	form := struct {
@@ -437,17 +653,17 @@ func postAddClusterNodes(c echo.Context) error {
 
 	c.Bind(&form)
 	if len(form.Address) == 0 {
-		return c.JSON(http.StatusOK, errorMap("No seed name specified."))
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "No seed name specified."))
 	}
 
 	host, port, err := common.SplitHostPort(form.Address)
 	if err != nil {
-		return c.JSON(http.StatusOK, errorMap(err.Error()))
+		return c.JSON(http.StatusOK, errorMapOf(err))
 	}
 
 	err = cluster.AddNode(host, port)
 	if err != nil {
-		return c.JSON(http.StatusOK, errorMap(err.Error()))
+		return c.JSON(http.StatusOK, errorMapOf(err))
 	}
 
 	// create output
@@ -468,14 +684,14 @@ func postAlertEmails(c echo.Context) error {
 
 	c.Bind(&form)
 	if len(form.Emails) == 0 {
-		return c.JSON(http.StatusOK, errorMap("No emails specified."))
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "No emails specified."))
 	}
 
 	emails := strings.Split(form.Emails, ",")
 	err := _observer.Config().AppendAlertEmails(emails)
 
 	if err != nil {
-		return c.JSON(http.StatusOK, errorMap(err.Error()))
+		return c.JSON(http.StatusOK, errorMapOf(err))
 	}
 
 	// create output
@@ -491,14 +707,14 @@ func deleteAlertEmails(c echo.Context) error {
 
 	c.Bind(&form)
 	if len(form.Emails) == 0 {
-		return c.JSON(http.StatusOK, errorMap("No emails specified."))
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "No emails specified."))
 	}
 
 	emails := strings.Split(form.Emails, ",")
 	err := _observer.Config().DeleteAlertEmails(emails)
 
 	if err != nil {
-		return c.JSON(http.StatusOK, errorMap(err.Error()))
+		return c.JSON(http.StatusOK, errorMapOf(err))
 	}
 
 	// create output
@@ -517,12 +733,12 @@ func postSwitchNodeOff(c echo.Context) error {
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		res["error"] = "Cluster not found"
+		setError(res, errClusterNotFound, "Cluster not found")
 		return c.JSON(http.StatusNotFound, res)
 	}
 
 	if err := cluster.RemoveNodeByAddress(nodeAddr); err != nil {
-		res["error"] = err.Error()
+		setErrorOf(res, err)
 		return c.JSON(http.StatusNotFound, res)
 	}
 
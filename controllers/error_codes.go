@@ -0,0 +1,279 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	as "github.com/aerospike/aerospike-client-go/v5"
+	ast "github.com/aerospike/aerospike-client-go/v5/types"
+	"github.com/labstack/echo/v4"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// apiErrorCode is the machine-readable code of a failure, included in every error response as error_code
+// so that the clients can branch on it instead of on the message, which may change or be translated
+type apiErrorCode string
+
+const (
+	errClusterNotFound        apiErrorCode = "CLUSTER_NOT_FOUND"
+	errNodeNotFound           apiErrorCode = "NODE_NOT_FOUND"
+	errNamespaceNotFound      apiErrorCode = "NAMESPACE_NOT_FOUND"
+	errNotFound               apiErrorCode = "NOT_FOUND"
+	errAlreadyExists          apiErrorCode = "ALREADY_EXISTS"
+	errInvalidSession         apiErrorCode = "INVALID_SESSION"
+	errAuthenticationFailed   apiErrorCode = "AUTHENTICATION_FAILED"
+	errInsufficientPrivileges apiErrorCode = "INSUFFICIENT_PRIVILEGES"
+	errInvalidParameter       apiErrorCode = "INVALID_PARAMETER"
+	errMethodNotAllowed       apiErrorCode = "METHOD_NOT_ALLOWED"
+	errRequestTooLarge        apiErrorCode = "REQUEST_TOO_LARGE"
+	errNodeOffline            apiErrorCode = "NODE_OFFLINE"
+	errClusterUnavailable     apiErrorCode = "CLUSTER_UNAVAILABLE"
+	errTimeout                apiErrorCode = "TIMEOUT"
+	errInternal               apiErrorCode = "INTERNAL_ERROR"
+	errOperationFailed        apiErrorCode = "OPERATION_FAILED"
+)
+
+// apiError - a failure detected by the handlers or their helpers, e.g. a malformed query param, with its code
+type apiError struct {
+	code apiErrorCode
+	msg  string
+}
+
+func newAPIError(code apiErrorCode, msg string) error {
+	return &apiError{code: code, msg: msg}
+}
+
+func (e *apiError) Error() string {
+	return e.msg
+}
+
+// errorCodeOf - the code of an error returned by a helper, the models or the client; the code is derived
+// from the message only for the errors which carry neither a code, a kind nor a result code
+func errorCodeOf(err error) apiErrorCode {
+	var apiErr *apiError
+	if errors.As(err, &apiErr) {
+		return apiErr.code
+	}
+
+	if code := kindErrorCode(common.ErrorKindOf(err)); code != "" {
+		return code
+	}
+
+	var aerr as.Error
+	if errors.As(err, &aerr) {
+		if code := resultErrorCode(aerr); code != "" {
+			return code
+		}
+	}
+
+	var numErr *strconv.NumError
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return errTimeout
+	case errors.As(err, &numErr), errors.As(err, &syntaxErr), errors.As(err, &typeErr):
+		return errInvalidParameter
+	}
+
+	return errorCode(err.Error())
+}
+
+// kindErrorCode - the code of the errors of a kind; empty for KindUnknown
+func kindErrorCode(kind common.ErrorKind) apiErrorCode {
+	switch kind {
+	case common.KindInvalid:
+		return errInvalidParameter
+	case common.KindNotFound:
+		return errNotFound
+	case common.KindNamespaceNotFound:
+		return errNamespaceNotFound
+	case common.KindNodeNotFound:
+		return errNodeNotFound
+	case common.KindAlreadyExists:
+		return errAlreadyExists
+	case common.KindNotAllowed:
+		return errInsufficientPrivileges
+	case common.KindUnavailable:
+		return errClusterUnavailable
+	case common.KindNodeOffline:
+		return errNodeOffline
+	}
+	return ""
+}
+
+// resultErrorCode - the code of an error of the client by its result code; empty for the result codes
+// which have no code of their own, e.g. the server errors
+func resultErrorCode(aerr as.Error) apiErrorCode {
+	switch {
+	case aerr.Matches(ast.TIMEOUT, ast.QUERY_TIMEOUT):
+		return errTimeout
+	case aerr.Matches(ast.NOT_AUTHENTICATED, ast.INVALID_CREDENTIAL, ast.INVALID_PASSWORD, ast.EXPIRED_PASSWORD, ast.EXPIRED_SESSION):
+		return errAuthenticationFailed
+	case aerr.Matches(ast.ROLE_VIOLATION, ast.FORBIDDEN_PASSWORD):
+		return errInsufficientPrivileges
+	case aerr.Matches(ast.INVALID_NAMESPACE):
+		return errNamespaceNotFound
+	case aerr.Matches(ast.KEY_NOT_FOUND_ERROR, ast.INVALID_USER, ast.INVALID_ROLE, ast.INDEX_NOTFOUND, ast.AEROSPIKE_ERR_UDF_NOT_FOUND):
+		return errNotFound
+	case aerr.Matches(ast.KEY_EXISTS_ERROR, ast.USER_ALREADY_EXISTS, ast.ROLE_ALREADY_EXISTS, ast.INDEX_FOUND):
+		return errAlreadyExists
+	case aerr.Matches(ast.PARAMETER_ERROR, ast.INVALID_PRIVILEGE, ast.INVALID_COMMAND, ast.INVALID_FIELD):
+		return errInvalidParameter
+	case aerr.Matches(ast.INVALID_NODE_ERROR, ast.SERVER_NOT_AVAILABLE, ast.NO_AVAILABLE_CONNECTIONS_TO_NODE, ast.NETWORK_ERROR):
+		return errClusterUnavailable
+	}
+	return ""
+}
+
+// errorCode - derive the code of a failure from its message; only the fallback for the errors which carry
+// no code, e.g. the failures reported by the server as text. The names of the result codes are recognized too.
+func errorCode(msg string) apiErrorCode {
+	lmsg := strings.ToLower(msg)
+	hasAny := func(subs ...string) bool {
+		for _, sub := range subs {
+			if strings.Contains(lmsg, sub) {
+				return true
+			}
+		}
+		return false
+	}
+	hasPrefix := func(prefixes ...string) bool {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(lmsg, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch {
+	case hasAny("invalid session"):
+		return errInvalidSession
+	case hasAny("invalid user/password", "not_authenticated", "invalid_credential", "invalid_password", "expired_password"):
+		return errAuthenticationFailed
+	case hasAny("role_violation", "not_whitelisted", "not authorized", "permission", "not allowed"):
+		return errInsufficientPrivileges
+	case hasAny("not found"):
+		switch {
+		case hasPrefix("cluster"):
+			return errClusterNotFound
+		case hasPrefix("node"):
+			return errNodeNotFound
+		case hasPrefix("namespace"):
+			return errNamespaceNotFound
+		}
+		return errNotFound
+	case hasAny("already exists"):
+		return errAlreadyExists
+	case hasAny("decommissioned", "no active nodes"):
+		return errClusterUnavailable
+	case hasPrefix("node") && hasAny("not active"), hasAny("failed to request info"):
+		return errNodeOffline
+	case hasPrefix("invalid", "wrong ", "no ") || hasAny("not supported", "not provided", "required", "must "):
+		return errInvalidParameter
+	case hasAny("timed out", "timeout"):
+		return errTimeout
+	case hasAny("too large"):
+		return errRequestTooLarge
+	}
+	return errOperationFailed
+}
+
+// statusErrorCode - the code of a failure reported by its status code, e.g. by the router; the codes which can
+// stand for several failures are derived from the message
+func statusErrorCode(status int, msg string) apiErrorCode {
+	switch status {
+	case http.StatusNotFound:
+		if code := errorCode(msg); code != errOperationFailed {
+			return code
+		}
+		return errNotFound
+	case http.StatusMethodNotAllowed:
+		return errMethodNotAllowed
+	case http.StatusRequestEntityTooLarge:
+		return errRequestTooLarge
+	case http.StatusUnauthorized:
+		if code := errorCode(msg); code == errInvalidSession {
+			return code
+		}
+		return errAuthenticationFailed
+	case http.StatusForbidden:
+		return errInsufficientPrivileges
+	case http.StatusGatewayTimeout:
+		return errTimeout
+	}
+
+	if code := errorCode(msg); code != errOperationFailed {
+		return code
+	}
+	switch {
+	case status == http.StatusBadRequest:
+		return errInvalidParameter
+	case status >= http.StatusInternalServerError:
+		return errInternal
+	}
+	return errOperationFailed
+}
+
+// errorStatusCode - the HTTP status code of a failure, for the endpoints which report failures with their status code
+func errorStatusCode(code apiErrorCode) int {
+	switch code {
+	case errClusterNotFound, errNodeNotFound, errNamespaceNotFound, errNotFound:
+		return http.StatusNotFound
+	case errAlreadyExists:
+		return http.StatusConflict
+	case errInvalidSession, errAuthenticationFailed:
+		return http.StatusUnauthorized
+	case errInsufficientPrivileges:
+		return http.StatusForbidden
+	case errMethodNotAllowed:
+		return http.StatusMethodNotAllowed
+	case errRequestTooLarge:
+		return http.StatusRequestEntityTooLarge
+	case errNodeOffline, errClusterUnavailable, errTimeout:
+		return http.StatusServiceUnavailable
+	case errInvalidParameter:
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// setError - set the message and the code of a failure in a response built by the handler
+func setError(res map[string]interface{}, code apiErrorCode, msg string) map[string]interface{} {
+	res["status"] = "failure"
+	res["error"] = msg
+	res["error_code"] = code
+	return res
+}
+
+// setErrorOf - set an error and its code in a response built by the handler
+func setErrorOf(res map[string]interface{}, err error) map[string]interface{} {
+	return setError(res, errorCodeOf(err), err.Error())
+}
+
+// httpErrorHandler - report the errors returned by the handlers and the middlewares, e.g. the unknown routes
+// and the bodies over the size limit, like echo does, with their error code
+func httpErrorHandler(e *echo.Echo) echo.HTTPErrorHandler {
+	return func(err error, c echo.Context) {
+		he, ok := err.(*echo.HTTPError)
+		if !ok {
+			he = echo.NewHTTPError(http.StatusInternalServerError)
+		} else if herr, ok := he.Internal.(*echo.HTTPError); ok {
+			he = herr
+		}
+
+		if msg, ok := he.Message.(string); ok {
+			he = &echo.HTTPError{
+				Code:    he.Code,
+				Message: echo.Map{"message": msg, "error_code": statusErrorCode(he.Code, msg)},
+			}
+		}
+		e.DefaultHTTPErrorHandler(he, c)
+	}
+}
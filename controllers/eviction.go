@@ -0,0 +1,125 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/aerospike-community/amc/common"
+	"github.com/aerospike-community/amc/models"
+)
+
+// chartStat - a point of a history chart. x: timestamp, y: the value during the interval
+type chartStat struct {
+	X *int64   `json:"x"`
+	Y *float64 `json:"y"`
+}
+
+// namespaceHistoryStartTime - the start_time query param in ms; defaults to, and is limited to, the last 30 mins
+func namespaceHistoryStartTime(c echo.Context, cluster *models.Cluster) (time.Time, error) {
+	tm := cluster.ServerTime().Add(-time.Minute * 30)
+	if beginStr := c.QueryParam("start_time"); beginStr != "" {
+		sinceUnix, err := strconv.ParseInt(beginStr, 10, 64)
+		if err != nil {
+			return tm, newAPIError(errInvalidParameter, "Invalid start_time value")
+		}
+
+		if since := time.Unix(sinceUnix/1000, 0); since.After(tm) {
+			tm = since
+		}
+	}
+
+	return tm, nil
+}
+
+// namespaceStatsHistory - the recorded history of the namespace stats in chart format
+func namespaceStatsHistory(cluster *models.Cluster, ns *models.Namespace, tm time.Time, names ...string) map[string][]chartStat {
+	zeroValue := float64(0)
+	zeroTime := cluster.ServerTime()
+
+	history := map[string][]chartStat{}
+	for stat, values := range ns.StatsSince(tm, names...) {
+		statList := make([]chartStat, 0, len(values))
+		for _, v := range values {
+			statList = append(statList, chartStat{X: v.TimestampJSON(&zeroTime), Y: v.Value(&zeroValue)})
+		}
+		history[stat] = statList
+	}
+
+	return history
+}
+
+func getClusterNamespaceEviction(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	tm, err := namespaceHistoryStartTime(c, cluster)
+	if err != nil {
+		return c.JSON(http.StatusOK, errorMapOf(err))
+	}
+
+	namespace := c.Param("namespace")
+	res := map[string]common.Stats{}
+	for _, node := range cluster.Nodes() {
+		ns := node.NamespaceByName(namespace)
+		if ns == nil {
+			continue
+		}
+
+		eviction := ns.Eviction()
+		eviction["node_status"] = node.Status()
+		eviction["history"] = namespaceStatsHistory(cluster, ns, tm, "evicted-objects", "expired-objects")
+		res[node.Address()] = eviction
+	}
+
+	if len(res) == 0 {
+		return c.JSON(http.StatusOK, errorMap(errNamespaceNotFound, "Namespace not found"))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status": "success",
+		"nodes":  res,
+	})
+}
+
+func getClusterNamespaceConflicts(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	tm, err := namespaceHistoryStartTime(c, cluster)
+	if err != nil {
+		return c.JSON(http.StatusOK, errorMapOf(err))
+	}
+
+	namespace := c.Param("namespace")
+	res := map[string]common.Stats{}
+	for _, node := range cluster.Nodes() {
+		ns := node.NamespaceByName(namespace)
+		if ns == nil {
+			continue
+		}
+
+		conflicts := ns.Conflicts()
+		conflicts["node_status"] = node.Status()
+		conflicts["history"] = namespaceStatsHistory(cluster, ns, tm,
+			"fail-generation", "dup-res-ask", "dup-res-respond-read", "dup-res-respond-no-read", "retransmits")
+		res[node.Address()] = conflicts
+	}
+
+	if len(res) == 0 {
+		return c.JSON(http.StatusOK, errorMap(errNamespaceNotFound, "Namespace not found"))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status": "success",
+		"nodes":  res,
+	})
+}
@@ -0,0 +1,589 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/aerospike-community/amc/common"
+	"github.com/aerospike-community/amc/graphql"
+	"github.com/aerospike-community/amc/models"
+)
+
+// The GraphQL endpoint lets the dashboards fetch exactly the fields they need of the clusters
+// of the session in one request, e.g.:
+//
+//	{
+//	  clusters {
+//	    id
+//	    status
+//	    nodes { address status stats(names: ["uptime", "client_connections"]) }
+//	    namespaces { name stats(names: ["memory-pct"]) sets { name stats(names: ["objects"]) } }
+//	  }
+//	}
+//
+// The schema:
+//
+//	Query:     clusters(id) [Cluster], cluster(id) Cluster
+//	Cluster:   id, alias, status, updateInterval, nodes(address) [Node], namespaces(name) [Namespace],
+//	           alerts(lastId) [Alert], stats(names) JSON
+//	Node:      id, address, status, statusReason, build, stats(names) JSON, namespaces(name) [Namespace]
+//	Namespace: name, stats(names) JSON, sets(name) [Set], sindexes(name) [Sindex]
+//	Set:       name, stats(names) JSON
+//	Sindex:    name, stats(names) JSON
+//	Alert:     id, nodeAddress, status, description, created, lastOccurred
+//
+// The stats of the namespaces of a cluster are aggregated over its nodes.
+
+// gqlObject is an object type of the schema
+type gqlObject interface {
+	gqlTypeName() string
+	gqlField(field *graphql.Field) (interface{}, error)
+}
+
+type gqlError struct {
+	Message    string                  `json:"message"`
+	Path       []interface{}           `json:"path,omitempty"`
+	Extensions map[string]apiErrorCode `json:"extensions"` // the error code, as code
+}
+
+func newGQLError(err error, path []interface{}) gqlError {
+	return gqlError{Message: err.Error(), Path: path, Extensions: map[string]apiErrorCode{"code": errorCodeOf(err)}}
+}
+
+// gqlResult keeps the fields in the order they were selected
+type gqlResult struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func (r *gqlResult) set(key string, value interface{}) {
+	if _, exists := r.values[key]; !exists {
+		r.keys = append(r.keys, key)
+	}
+	r.values[key] = value
+}
+
+func (r *gqlResult) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range r.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, _ := json.Marshal(key)
+		buf.Write(k)
+		buf.WriteByte(':')
+
+		v, err := json.Marshal(r.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+type gqlExecutor struct {
+	errors []gqlError
+}
+
+func (e *gqlExecutor) fail(path []interface{}, err error) {
+	e.errors = append(e.errors, newGQLError(err, append([]interface{}{}, path...)))
+}
+
+// execute - resolve the selected fields of the object; the fields which fail are null
+func (e *gqlExecutor) execute(obj gqlObject, fields []*graphql.Field, path []interface{}) *gqlResult {
+	res := &gqlResult{values: map[string]interface{}{}}
+	for _, field := range fields {
+		fieldPath := append(path, field.Key())
+
+		if field.Name == "__typename" {
+			res.set(field.Key(), obj.gqlTypeName())
+			continue
+		}
+
+		value, err := obj.gqlField(field)
+		if err != nil {
+			e.fail(fieldPath, err)
+			res.set(field.Key(), nil)
+			continue
+		}
+
+		res.set(field.Key(), e.complete(value, field, fieldPath))
+	}
+
+	return res
+}
+
+// complete - resolve the selections of the objects in the value of a field
+func (e *gqlExecutor) complete(value interface{}, field *graphql.Field, path []interface{}) interface{} {
+	switch v := value.(type) {
+	case gqlObject:
+		if len(field.Selections) == 0 {
+			e.fail(path, newAPIError(errInvalidParameter, fmt.Sprintf("field %s of type %s must have a selection of subfields", field.Name, v.gqlTypeName())))
+			return nil
+		}
+		return e.execute(v, field.Selections, path)
+
+	case []gqlObject:
+		if len(field.Selections) == 0 {
+			e.fail(path, newAPIError(errInvalidParameter, fmt.Sprintf("field %s must have a selection of subfields", field.Name)))
+			return nil
+		}
+		list := make([]interface{}, len(v))
+		for i, obj := range v {
+			list[i] = e.execute(obj, field.Selections, append(path, i))
+		}
+		return list
+
+	default:
+		if len(field.Selections) > 0 {
+			e.fail(path, newAPIError(errInvalidParameter, fmt.Sprintf("field %s must not have a selection since it is a scalar", field.Name)))
+			return nil
+		}
+		return value
+	}
+}
+
+// postGraphQL - execute a GraphQL query; the query and the variables are read from a JSON body
+// ({"query": "...", "variables": {...}}) or from the query params
+func postGraphQL(c echo.Context) error {
+	req := struct {
+		Query     string                 `json:"query"`
+		Variables map[string]interface{} `json:"variables"`
+	}{}
+
+	if strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), echo.MIMEApplicationJSON) {
+		if err := json.NewDecoder(c.Request().Body).Decode(&req); err != nil {
+			return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid request body: "+err.Error()))
+		}
+	} else {
+		req.Query = c.FormValue("query")
+		if vars := c.FormValue("variables"); vars != "" {
+			if err := json.Unmarshal([]byte(vars), &req.Variables); err != nil {
+				return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid variables: "+err.Error()))
+			}
+		}
+	}
+
+	if strings.TrimSpace(req.Query) == "" {
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Query not provided"))
+	}
+
+	fields, err := graphql.Parse(req.Query, req.Variables)
+	if err != nil {
+		gqlErr := newGQLError(err, nil)
+		gqlErr.Extensions["code"] = errInvalidParameter
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"errors": []gqlError{gqlErr},
+		})
+	}
+
+	sid, _ := sessionID(c)
+	executor := &gqlExecutor{}
+	res := map[string]interface{}{
+		"data": executor.execute(gqlQuery{sessionID: sid}, fields, nil),
+	}
+	if len(executor.errors) > 0 {
+		res["errors"] = executor.errors
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// gqlArgString - get a string argument
+func gqlArgString(field *graphql.Field, name string) (string, bool, error) {
+	v, exists := field.Args[name]
+	if !exists || v == nil {
+		return "", false, nil
+	}
+
+	s, ok := v.(string)
+	if !ok {
+		return "", false, newAPIError(errInvalidParameter, fmt.Sprintf("argument %s of field %s must be a string", name, field.Name))
+	}
+	return s, true, nil
+}
+
+// gqlArgStrings - get a string list argument; a single string is accepted as well
+func gqlArgStrings(field *graphql.Field, name string) ([]string, error) {
+	switch v := field.Args[name].(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []string{v}, nil
+	case []interface{}:
+		res := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, newAPIError(errInvalidParameter, fmt.Sprintf("argument %s of field %s must be a list of strings", name, field.Name))
+			}
+			res = append(res, s)
+		}
+		return res, nil
+	}
+
+	return nil, newAPIError(errInvalidParameter, fmt.Sprintf("argument %s of field %s must be a list of strings", name, field.Name))
+}
+
+// gqlArgInt - get an integer argument
+func gqlArgInt(field *graphql.Field, name string) (int64, error) {
+	switch v := field.Args[name].(type) {
+	case nil:
+		return 0, nil
+	case int64:
+		return v, nil
+	case float64:
+		// variables decoded from JSON are floats
+		if v == float64(int64(v)) {
+			return int64(v), nil
+		}
+	}
+
+	return 0, newAPIError(errInvalidParameter, fmt.Sprintf("argument %s of field %s must be an integer", name, field.Name))
+}
+
+// gqlStats - select the stats by the names argument; all stats are returned without it
+func gqlStats(field *graphql.Field, stats common.Stats) (interface{}, error) {
+	names, err := gqlArgStrings(field, "names")
+	if err != nil {
+		return nil, err
+	}
+
+	if names == nil {
+		return stats, nil
+	}
+	return stats.GetMulti(names...), nil
+}
+
+func gqlUnknownField(obj gqlObject, field *graphql.Field) error {
+	return newAPIError(errInvalidParameter, fmt.Sprintf("cannot query field %s on type %s", field.Name, obj.gqlTypeName()))
+}
+
+type gqlQuery struct {
+	sessionID string
+}
+
+func (q gqlQuery) gqlTypeName() string { return "Query" }
+
+func (q gqlQuery) gqlField(field *graphql.Field) (interface{}, error) {
+	id, hasID, err := gqlArgString(field, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	switch field.Name {
+	case "clusters":
+		// the clusters of the session, like the UI
+		clusters, _ := _observer.MonitoringClusters(q.sessionID)
+		clusters = append(clusters, _observer.AutoClusters()...)
+
+		res := []gqlObject{}
+		for _, cluster := range clusters {
+			if !hasID || cluster.ID() == id {
+				res = append(res, gqlCluster{cluster})
+			}
+		}
+		return res, nil
+
+	case "cluster":
+		if !hasID {
+			return nil, newAPIError(errInvalidParameter, "argument id of field cluster is required")
+		}
+
+		cluster := _observer.FindClusterByID(id)
+		if cluster == nil {
+			return nil, newAPIError(errClusterNotFound, "Cluster not found")
+		}
+		return gqlCluster{cluster}, nil
+	}
+
+	return nil, gqlUnknownField(q, field)
+}
+
+type gqlCluster struct {
+	cluster *models.Cluster
+}
+
+func (c gqlCluster) gqlTypeName() string { return "Cluster" }
+
+func (c gqlCluster) gqlField(field *graphql.Field) (interface{}, error) {
+	switch field.Name {
+	case "id":
+		return c.cluster.ID(), nil
+	case "alias":
+		return c.cluster.Alias(), nil
+	case "status":
+		return c.cluster.Status(), nil
+	case "updateInterval":
+		return c.cluster.UpdateInterval(), nil
+
+	case "nodes":
+		address, hasAddress, err := gqlArgString(field, "address")
+		if err != nil {
+			return nil, err
+		}
+
+		res := []gqlObject{}
+		for _, node := range c.cluster.Nodes() {
+			if !hasAddress || node.Address() == address {
+				res = append(res, gqlNode{node})
+			}
+		}
+		return res, nil
+
+	case "namespaces":
+		name, hasName, err := gqlArgString(field, "name")
+		if err != nil {
+			return nil, err
+		}
+
+		res := []gqlObject{}
+		for _, ns := range c.cluster.NamespaceList() {
+			if !hasName || ns == name {
+				res = append(res, gqlClusterNamespace{cluster: c.cluster, name: ns})
+			}
+		}
+		return res, nil
+
+	case "alerts":
+		lastID, err := gqlArgInt(field, "lastId")
+		if err != nil {
+			return nil, err
+		}
+
+		res := []gqlObject{}
+		for _, alert := range c.cluster.AlertsFrom(lastID) {
+			res = append(res, gqlAlert{alert})
+		}
+		return res, nil
+
+	case "stats":
+		snapshot := c.cluster.Snapshot()
+		if snapshot == nil {
+			return nil, nil
+		}
+		return gqlStats(field, common.Stats{
+			"cluster_status":  snapshot.ClusterStatus,
+			"timestamp":       snapshot.Timestamp,
+			"red_alert_count": snapshot.RedAlertCount,
+			"node_count":      len(snapshot.Nodes),
+			"namespace_count": len(snapshot.Namespaces),
+		})
+	}
+
+	return nil, gqlUnknownField(c, field)
+}
+
+type gqlNode struct {
+	node *models.Node
+}
+
+func (n gqlNode) gqlTypeName() string { return "Node" }
+
+func (n gqlNode) gqlField(field *graphql.Field) (interface{}, error) {
+	switch field.Name {
+	case "id":
+		return n.node.ID(), nil
+	case "address":
+		return n.node.Address(), nil
+	case "status":
+		return string(n.node.Status()), nil
+	case "statusReason":
+		return n.node.StatusReason(), nil
+	case "build":
+		return n.node.Build(), nil
+
+	case "stats":
+		return gqlStats(field, n.node.StatsAttrs())
+
+	case "namespaces":
+		name, hasName, err := gqlArgString(field, "name")
+		if err != nil {
+			return nil, err
+		}
+
+		names := []string{}
+		for ns := range n.node.Namespaces() {
+			if !hasName || ns == name {
+				names = append(names, ns)
+			}
+		}
+
+		res := []gqlObject{}
+		for _, ns := range common.SortStrings(names) {
+			if namespace := n.node.NamespaceByName(ns); namespace != nil {
+				res = append(res, gqlNodeNamespace{node: n.node, ns: namespace, name: ns})
+			}
+		}
+		return res, nil
+	}
+
+	return nil, gqlUnknownField(n, field)
+}
+
+// gqlClusterNamespace is a namespace of a cluster, with its stats aggregated over the nodes
+type gqlClusterNamespace struct {
+	cluster *models.Cluster
+	name    string
+}
+
+func (ns gqlClusterNamespace) gqlTypeName() string { return "Namespace" }
+
+func (ns gqlClusterNamespace) gqlField(field *graphql.Field) (interface{}, error) {
+	switch field.Name {
+	case "name":
+		return ns.name, nil
+
+	case "stats":
+		stats := ns.cluster.NamespaceInfo([]string{ns.name})[ns.name]
+		if stats == nil {
+			return nil, nil
+		}
+		return gqlStats(field, stats)
+
+	case "sets":
+		sets := map[string]common.Stats{}
+		for _, set := range ns.cluster.NamespaceSetsInfo(ns.name) {
+			sets[set.TryString("set", "")] = set
+		}
+		return gqlSets(field, sets)
+
+	case "sindexes":
+		return gqlSindexes(field, ns.cluster.NamespaceIndexInfo(ns.name))
+	}
+
+	return nil, gqlUnknownField(ns, field)
+}
+
+// gqlNodeNamespace is a namespace of a node
+type gqlNodeNamespace struct {
+	node *models.Node
+	ns   *models.Namespace
+	name string
+}
+
+func (ns gqlNodeNamespace) gqlTypeName() string { return "Namespace" }
+
+func (ns gqlNodeNamespace) gqlField(field *graphql.Field) (interface{}, error) {
+	switch field.Name {
+	case "name":
+		return ns.name, nil
+	case "stats":
+		return gqlStats(field, ns.ns.Stats())
+	case "sets":
+		return gqlSets(field, ns.ns.SetsInfo())
+	case "sindexes":
+		return gqlSindexes(field, ns.node.Indexes(ns.name))
+	}
+
+	return nil, gqlUnknownField(ns, field)
+}
+
+func gqlSets(field *graphql.Field, sets map[string]common.Stats) (interface{}, error) {
+	name, hasName, err := gqlArgString(field, "name")
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for set := range sets {
+		if !hasName || set == name {
+			names = append(names, set)
+		}
+	}
+
+	res := []gqlObject{}
+	for _, set := range common.SortStrings(names) {
+		res = append(res, gqlSet{name: set, stats: sets[set]})
+	}
+	return res, nil
+}
+
+func gqlSindexes(field *graphql.Field, indexes map[string]common.Info) (interface{}, error) {
+	name, hasName, err := gqlArgString(field, "name")
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for index := range indexes {
+		if !hasName || index == name {
+			names = append(names, index)
+		}
+	}
+
+	res := []gqlObject{}
+	for _, index := range common.SortStrings(names) {
+		res = append(res, gqlSindex{name: index, stats: indexes[index].ToStats()})
+	}
+	return res, nil
+}
+
+type gqlSet struct {
+	name  string
+	stats common.Stats
+}
+
+func (s gqlSet) gqlTypeName() string { return "Set" }
+
+func (s gqlSet) gqlField(field *graphql.Field) (interface{}, error) {
+	switch field.Name {
+	case "name":
+		return s.name, nil
+	case "stats":
+		return gqlStats(field, s.stats)
+	}
+
+	return nil, gqlUnknownField(s, field)
+}
+
+type gqlSindex struct {
+	name  string
+	stats common.Stats
+}
+
+func (s gqlSindex) gqlTypeName() string { return "Sindex" }
+
+func (s gqlSindex) gqlField(field *graphql.Field) (interface{}, error) {
+	switch field.Name {
+	case "name":
+		return s.name, nil
+	case "stats":
+		return gqlStats(field, s.stats)
+	}
+
+	return nil, gqlUnknownField(s, field)
+}
+
+type gqlAlert struct {
+	alert *common.Alert
+}
+
+func (a gqlAlert) gqlTypeName() string { return "Alert" }
+
+func (a gqlAlert) gqlField(field *graphql.Field) (interface{}, error) {
+	switch field.Name {
+	case "id":
+		return a.alert.ID, nil
+	case "nodeAddress":
+		return a.alert.NodeAddress, nil
+	case "status":
+		return string(a.alert.Status), nil
+	case "description":
+		return a.alert.Desc, nil
+	case "created":
+		return a.alert.Created.UnixNano() / 1e6, nil
+	case "lastOccurred":
+		return a.alert.LastOccured.UnixNano() / 1e6, nil
+	}
+
+	return nil, gqlUnknownField(a, field)
+}
@@ -0,0 +1,272 @@
+//go:build grpc
+
+package controllers
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/aerospike-community/amc/api/amcpb"
+	"github.com/aerospike-community/amc/common"
+	"github.com/aerospike-community/amc/models"
+)
+
+// how often the snapshot of a watched cluster is checked for a new update
+const _grpcWatchPollInterval = time.Second
+
+func init() {
+	startGRPCServer = serveGRPC
+}
+
+// serveGRPC - serve the read API over gRPC on grpc_bind; uses the TLS config of the web server
+// and the basic auth credentials if they are set
+func serveGRPC(config *common.Config) {
+	lis, err := net.Listen("tcp", config.AMC.GRPCBind)
+	if err != nil {
+		log.Errorf("Error listening for gRPC on %s: %s", config.AMC.GRPCBind, err.Error())
+		return
+	}
+
+	auth := grpcAuth{config: config}
+	opts := []grpc.ServerOption{
+		grpc.UnaryInterceptor(auth.unary),
+		grpc.StreamInterceptor(auth.stream),
+	}
+
+	if config.TLSEnabled() {
+		tlsConfig, err := serverTLSConfig(config)
+		if err != nil {
+			log.Errorf("Error setting up TLS for gRPC: %s", err.Error())
+			return
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+
+	s := grpc.NewServer(opts...)
+	amcpb.RegisterAMCServer(s, &grpcServer{})
+
+	log.Infof("Serving the gRPC API on %s", config.AMC.GRPCBind)
+	if err := s.Serve(lis); err != nil {
+		log.Errorf("gRPC server stopped: %s", err.Error())
+	}
+}
+
+// grpcAuth checks the basic auth credentials sent in the authorization metadata
+type grpcAuth struct {
+	config *common.Config
+}
+
+func (a grpcAuth) authorize(ctx context.Context) error {
+	user, password := a.config.BasicAuthCredentials()
+	if user == "" {
+		return nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, v := range md.Get("authorization") {
+		if !strings.HasPrefix(v, "Basic ") {
+			continue
+		}
+
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(v, "Basic "))
+		if err == nil && string(decoded) == user+":"+password {
+			return nil
+		}
+	}
+
+	return status.Error(codes.Unauthenticated, "invalid credentials")
+}
+
+func (a grpcAuth) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := a.authorize(ctx); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (a grpcAuth) stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := a.authorize(ss.Context()); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
+
+type grpcServer struct {
+	amcpb.UnimplementedAMCServer
+}
+
+func grpcCluster(id string) (*models.Cluster, error) {
+	cluster := _observer.FindClusterByID(id)
+	if cluster == nil {
+		return nil, status.Errorf(codes.NotFound, "Cluster %s not found", id)
+	}
+	return cluster, nil
+}
+
+// grpcStats - format the stats as strings
+func grpcStats(stats common.Stats) map[string]string {
+	res := make(map[string]string, len(stats))
+	for k, v := range stats {
+		res[k] = fmt.Sprint(v)
+	}
+	return res
+}
+
+func (s *grpcServer) ListClusters(ctx context.Context, req *amcpb.ListClustersRequest) (*amcpb.ListClustersResponse, error) {
+	res := &amcpb.ListClustersResponse{}
+	for _, cluster := range _observer.Clusters() {
+		c := &amcpb.Cluster{
+			Id:             cluster.ID(),
+			Status:         cluster.Status(),
+			Nodes:          cluster.NodeList(),
+			Namespaces:     cluster.NamespaceList(),
+			UpdateInterval: int32(cluster.UpdateInterval()),
+		}
+		if alias := cluster.Alias(); alias != nil {
+			c.Alias = *alias
+		}
+		res.Clusters = append(res.Clusters, c)
+	}
+
+	return res, nil
+}
+
+func (s *grpcServer) GetNodeStats(ctx context.Context, req *amcpb.NodeStatsRequest) (*amcpb.NodeStatsResponse, error) {
+	cluster, err := grpcCluster(req.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+
+	addrs := map[string]bool{}
+	for _, addr := range req.Nodes {
+		addrs[addr] = true
+	}
+
+	res := &amcpb.NodeStatsResponse{}
+	for _, node := range cluster.Nodes() {
+		if len(addrs) > 0 && !addrs[node.Address()] {
+			continue
+		}
+
+		res.Nodes = append(res.Nodes, &amcpb.Node{
+			Id:           node.ID(),
+			Address:      node.Address(),
+			Status:       string(node.Status()),
+			StatusReason: node.StatusReason(),
+			Build:        node.Build(),
+			Stats:        grpcStats(node.StatsAttrs()),
+		})
+	}
+
+	return res, nil
+}
+
+func (s *grpcServer) GetNamespaceStats(ctx context.Context, req *amcpb.NamespaceStatsRequest) (*amcpb.NamespaceStatsResponse, error) {
+	cluster, err := grpcCluster(req.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces := req.Namespaces
+	if len(namespaces) == 0 {
+		namespaces = cluster.NamespaceList()
+	}
+
+	return &amcpb.NamespaceStatsResponse{Namespaces: grpcNamespaces(cluster.NamespaceInfo(namespaces))}, nil
+}
+
+func grpcNamespaces(info map[string]common.Stats) []*amcpb.Namespace {
+	names := make([]string, 0, len(info))
+	for name := range info {
+		names = append(names, name)
+	}
+
+	res := make([]*amcpb.Namespace, 0, len(info))
+	for _, name := range common.SortStrings(names) {
+		res = append(res, &amcpb.Namespace{Name: name, Stats: grpcStats(info[name])})
+	}
+	return res
+}
+
+func (s *grpcServer) ListAlerts(ctx context.Context, req *amcpb.ListAlertsRequest) (*amcpb.ListAlertsResponse, error) {
+	cluster, err := grpcCluster(req.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &amcpb.ListAlertsResponse{}
+	for _, alert := range cluster.AlertsFrom(req.LastId) {
+		res.Alerts = append(res.Alerts, &amcpb.Alert{
+			Id:           alert.ID,
+			ClusterId:    alert.ClusterID,
+			NodeAddress:  alert.NodeAddress,
+			Status:       string(alert.Status),
+			Description:  alert.Desc,
+			Created:      alert.Created.UnixNano() / 1e6,
+			LastOccurred: alert.LastOccured.UnixNano() / 1e6,
+		})
+	}
+
+	return res, nil
+}
+
+func (s *grpcServer) WatchCluster(req *amcpb.WatchClusterRequest, stream amcpb.AMC_WatchClusterServer) error {
+	cluster, err := grpcCluster(req.ClusterId)
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(_grpcWatchPollInterval)
+	defer ticker.Stop()
+
+	var last *models.ClusterSnapshot
+	for {
+		// the snapshot is replaced after every update of the cluster
+		if snapshot := cluster.Snapshot(); snapshot != nil && snapshot != last {
+			last = snapshot
+			if err := stream.Send(grpcSnapshot(snapshot)); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func grpcSnapshot(snapshot *models.ClusterSnapshot) *amcpb.ClusterSnapshot {
+	res := &amcpb.ClusterSnapshot{
+		ClusterId:     snapshot.ClusterID,
+		ClusterStatus: snapshot.ClusterStatus,
+		Timestamp:     snapshot.Timestamp,
+		Namespaces:    grpcNamespaces(snapshot.Namespaces),
+		RedAlertCount: int32(snapshot.RedAlertCount),
+	}
+
+	for _, node := range snapshot.Nodes {
+		res.Nodes = append(res.Nodes, &amcpb.Node{
+			Id:           node.ID,
+			Address:      node.Address,
+			Status:       node.Status,
+			StatusReason: node.StatusReason,
+			Build:        node.Build,
+			Stats:        grpcStats(node.Stats),
+		})
+	}
+
+	return res
+}
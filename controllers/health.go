@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// the probes are served without authentication, for load balancers and Kubernetes
+var _probePaths = map[string]bool{
+	"/healthz": true,
+	"/readyz":  true,
+}
+
+// getHealthz - the process is alive
+func getHealthz(c echo.Context) error {
+	return c.JSON(http.StatusOK, map[string]interface{}{"status": "ok"})
+}
+
+// getReadyz - the server can serve requests: the observer has been initialized,
+// the database is open and the server is listening
+func getReadyz(c echo.Context) error {
+	checks := map[string]string{
+		"observer": "ok",
+		"database": "ok",
+		"listener": "ok",
+	}
+	ready := true
+
+	if _observer == nil {
+		checks["observer"] = "not initialized"
+		ready = false
+	}
+
+	if err := common.PingDatabase(); err != nil {
+		checks["database"] = err.Error()
+		ready = false
+	}
+
+	if _server == nil || (_server.ListenerAddr() == nil && _server.TLSListenerAddr() == nil && _unixServer == nil) {
+		checks["listener"] = "not bound"
+		ready = false
+	}
+
+	if !ready {
+		return c.JSON(http.StatusServiceUnavailable, map[string]interface{}{"status": "not ready", "checks": checks})
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{"status": "ok", "checks": checks})
+}
+
+// isPublicPath - the path is served without authentication: the probes, and /status if public_status is set
+func isPublicPath(config *common.Config, path string) bool {
+	return _probePaths[path] || (path == "/status" && config.PublicStatus())
+}
+
+// getStatus - the names and the up/down state of the clusters in the config file, for status pages;
+// only served if public_status is set, since it requires no authentication
+func getStatus(config *common.Config) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if !config.PublicStatus() {
+			return echo.ErrNotFound
+		}
+
+		clusters := []map[string]string{}
+		for _, cluster := range _observer.ConfigClusters() {
+			name := cluster.ID()
+			if alias := cluster.Alias(); alias != nil {
+				name = *alias
+			}
+
+			status := "down"
+			if cluster.Status() == "on" {
+				status = "up"
+			}
+			clusters = append(clusters, map[string]string{"name": name, "status": status})
+		}
+		sort.Slice(clusters, func(i, j int) bool { return clusters[i]["name"] < clusters[j]["name"] })
+
+		// the status is public, so that status pages on other origins can fetch it
+		c.Response().Header().Set(echo.HeaderAccessControlAllowOrigin, "*")
+		return c.JSON(http.StatusOK, map[string]interface{}{"clusters": clusters})
+	}
+}
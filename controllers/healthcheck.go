@@ -0,0 +1,40 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/aerospike-community/amc/models"
+)
+
+func getClusterHealthCheck(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	// the checks run on every update; refresh=true runs them on demand
+	report := cluster.HealthReport()
+	if report == nil || c.QueryParam("refresh") == "true" {
+		report = cluster.HealthCheck()
+	}
+
+	summary := map[models.HealthSeverity]int{
+		models.HealthSeverityCritical: 0,
+		models.HealthSeverityWarning:  0,
+		models.HealthSeverityInfo:     0,
+	}
+	for _, f := range report.Findings {
+		summary[f.Severity]++
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":     "success",
+		"checked_at": report.CheckedAt.UnixNano() / 1e6,
+		"rules":      report.Rules,
+		"summary":    summary,
+		"findings":   report.Findings,
+	})
+}
@@ -0,0 +1,75 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/aerospike-community/amc/models"
+)
+
+func getClusterNamespaceHistogram(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	histType := c.Param("type")
+	if !models.ValidHistogramType(histType) {
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Histogram type not supported"))
+	}
+
+	buckets, perNode, err := cluster.Histogram(c.Param("namespace"), histType)
+	if err != nil {
+		return c.JSON(http.StatusOK, errorMapOf(err))
+	}
+
+	units := ""
+	for _, h := range perNode {
+		units = h.Units
+		break
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":  "success",
+		"type":    histType,
+		"units":   units,
+		"buckets": buckets,
+		"nodes":   perNode,
+	})
+}
+
+// getClusterObjectSizes - the object size distributions of all the namespaces of the cluster, for the tuning of
+// write-block-size and the capacity planning
+func getClusterObjectSizes(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":     "success",
+		"namespaces": cluster.ObjectSizeDistributions(),
+	})
+}
+
+// getClusterNamespaceObjectSizes - the object size distribution of the namespace
+func getClusterNamespaceObjectSizes(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	d, err := cluster.ObjectSizeDistribution(c.Param("namespace"))
+	if err != nil {
+		return c.JSON(http.StatusOK, errorMapOf(err))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":       "success",
+		"distribution": d,
+	})
+}
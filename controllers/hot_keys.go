@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/aerospike-community/amc/models"
+)
+
+// getClusterNamespaceHotKeys - the most written records of a sample of the namespace; set restricts the sample
+// to a set, sample_size is the number of the records sampled (1000 by default) from sample_pct of the partitions
+// (10 by default), window the seconds between the two reads of the sample (5 by default) and top the number of
+// the keys returned (10 by default)
+func getClusterNamespaceHotKeys(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	spec := models.HotKeySpec{
+		Set:        c.QueryParam("set"),
+		SampleSize: models.HotKeysDefaultSampleSize,
+		SamplePct:  10,
+		Window:     models.HotKeysDefaultWindow,
+		Top:        models.HotKeysDefaultTop,
+	}
+
+	intParams := map[string]*int{"sample_size": &spec.SampleSize, "top": &spec.Top}
+	for name, p := range intParams {
+		if v := c.QueryParam(name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid "+name))
+			}
+			*p = n
+		}
+	}
+	if v := c.QueryParam("sample_pct"); v != "" {
+		var err error
+		if spec.SamplePct, err = strconv.ParseFloat(v, 64); err != nil {
+			return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid sample_pct"))
+		}
+	}
+	if v := c.QueryParam("window"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid window"))
+		}
+		spec.Window = time.Duration(n) * time.Second
+	}
+
+	report, err := cluster.HotKeys(c.Request().Context(), c.Param("namespace"), spec)
+	if err != nil {
+		return c.JSON(http.StatusOK, errorMapOf(err))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":   "success",
+		"hot_keys": report,
+	})
+}
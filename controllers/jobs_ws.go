@@ -0,0 +1,158 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/websocket"
+
+	"github.com/aerospike-community/amc/common"
+	"github.com/aerospike-community/amc/models"
+)
+
+const (
+	// how often the snapshot of the cluster is checked for a new update
+	_jobsWatchPollInterval = time.Second
+	// a client which does not read the messages for this long is disconnected
+	_jobsWatchWriteTimeout = 10 * time.Second
+)
+
+// a job is sent again when one of these changes
+var _jobsProgressFields = []string{"status", "job-progress", "recs-read", "recs-succeeded", "recs-failed", "net-io-bytes"}
+
+// jobsMessage is a message of the job progress channel; the first message lists all the jobs,
+// the following ones only the jobs which were added or progressed, and the ids of the jobs
+// which are no longer listed by the nodes. Jobs are identified by "<address>:<trid>".
+type jobsMessage struct {
+	Type      string         `json:"type"` // jobs | progress
+	ClusterID string         `json:"cluster_id"`
+	Timestamp int64          `json:"timestamp"`
+	Jobs      []common.Stats `json:"jobs"`
+	Removed   []string       `json:"removed,omitempty"`
+}
+
+// getClusterJobsWatch - stream the progress of the index builds, scans and queries of the nodes
+// over a WebSocket; the nodes param limits the jobs to a comma separated list of node addresses
+func getClusterJobsWatch(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	nodeAddrs := common.DeleteEmpty(strings.Split(c.QueryParam("nodes"), ","))
+
+	websocket.Server{
+		Handshake: checkSameOrigin,
+		Handler: func(ws *websocket.Conn) {
+			defer ws.Close()
+			watchClusterJobs(ws, requestLog(c), cluster, nodeAddrs)
+		},
+	}.ServeHTTP(c.Response(), c.Request())
+
+	return nil
+}
+
+// checkSameOrigin - reject the handshakes from the pages of other sites, since browsers send the session
+// cookie with them; clients other than browsers do not send an Origin and are accepted
+func checkSameOrigin(config *websocket.Config, req *http.Request) error {
+	origin, err := websocket.Origin(config, req)
+	if err != nil {
+		return err
+	}
+
+	if origin != nil && !strings.EqualFold(origin.Host, req.Host) {
+		return fmt.Errorf("Origin %s is not allowed", origin)
+	}
+
+	config.Origin = origin
+	return nil
+}
+
+func watchClusterJobs(ws *websocket.Conn, logger *log.Entry, cluster *models.Cluster, nodeAddrs []string) {
+	// the client does not send anything; reading only detects that it went away
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		var msg string
+		for websocket.Message.Receive(ws, &msg) == nil {
+		}
+	}()
+
+	ticker := time.NewTicker(_jobsWatchPollInterval)
+	defer ticker.Stop()
+
+	var lastSnapshot *models.ClusterSnapshot
+	var sent map[string]string // map[job id]progress
+	for {
+		// the snapshot is replaced after every update of the cluster
+		if snapshot := cluster.Snapshot(); snapshot != nil && snapshot != lastSnapshot {
+			lastSnapshot = snapshot
+
+			msg, progress := jobsProgress(cluster, nodeAddrs, sent)
+			if sent == nil || len(msg.Jobs) > 0 || len(msg.Removed) > 0 {
+				ws.SetWriteDeadline(time.Now().Add(_jobsWatchWriteTimeout))
+				if err := websocket.JSON.Send(ws, msg); err != nil {
+					logger.Debugf("Stopped sending the jobs of cluster %s: %s", cluster.ID(), err.Error())
+					return
+				}
+			}
+			sent = progress
+		}
+
+		select {
+		case <-closed:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// jobsProgress - get the jobs which changed since the last message; all jobs if nothing was sent yet
+func jobsProgress(cluster *models.Cluster, nodeAddrs []string, sent map[string]string) (*jobsMessage, map[string]string) {
+	msg := &jobsMessage{
+		Type:      "progress",
+		ClusterID: cluster.ID(),
+		Timestamp: time.Now().UnixNano() / 1e6,
+		Jobs:      []common.Stats{},
+	}
+	if sent == nil {
+		msg.Type = "jobs"
+	}
+
+	nodes := cluster.Nodes()
+	if len(nodeAddrs) > 0 {
+		nodes = nodes[:0:0]
+		for _, addr := range nodeAddrs {
+			if node := cluster.FindNodeByAddress(addr); node != nil {
+				nodes = append(nodes, node)
+			}
+		}
+	}
+
+	progress := map[string]string{}
+	for _, node := range nodes {
+		for _, job := range node.Jobs() {
+			id := node.Address() + ":" + job.TryString("trid", "")
+			progress[id] = fmt.Sprint(job.GetMulti(_jobsProgressFields...))
+
+			if last, exists := sent[id]; sent == nil || !exists || last != progress[id] {
+				job["address"] = node.Address()
+				job["id"] = id
+				msg.Jobs = append(msg.Jobs, job)
+			}
+		}
+	}
+
+	for id := range sent {
+		if _, exists := progress[id]; !exists {
+			msg.Removed = append(msg.Removed, id)
+		}
+	}
+
+	return msg, progress
+}
@@ -0,0 +1,347 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/aerospike-community/amc/common"
+	"github.com/aerospike-community/amc/models"
+)
+
+// The resource endpoints serve the clusters, their nodes and their namespaces as JSON:API documents
+// (https://jsonapi.org), for clients built on a standard data layer:
+//
+//	GET /resources/clusters
+//	GET /resources/clusters/<cluster id>
+//	GET /resources/clusters/<cluster id>/nodes[/<address>]
+//	GET /resources/clusters/<cluster id>/namespaces[/<name>]
+//
+// The resources are typed clusters, nodes and namespaces; a cluster relates to its nodes and namespaces,
+// and the nodes and the namespaces to their cluster. The related resources are added to the document with
+// include, e.g. include=nodes,namespaces, and the attributes are limited with fields[<type>], e.g.
+// fields[nodes]=address,status. The ids of the namespaces are <cluster id>/<name>, since the namespaces of
+// different clusters may have the same name. The stats of the namespaces are aggregated over the nodes.
+const mimeJSONAPI = "application/vnd.api+json"
+
+type jsonAPIIdentifier struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+type jsonAPIRelationship struct {
+	Data  interface{}       `json:"data"` // an identifier, or a list of them
+	Links map[string]string `json:"links,omitempty"`
+}
+
+type jsonAPIResource struct {
+	jsonAPIIdentifier
+	Attributes    map[string]interface{}          `json:"attributes,omitempty"`
+	Relationships map[string]*jsonAPIRelationship `json:"relationships,omitempty"`
+	Links         map[string]string               `json:"links,omitempty"`
+}
+
+type jsonAPIError struct {
+	Status string       `json:"status"`
+	Code   apiErrorCode `json:"code"`
+	Title  string       `json:"title"`
+}
+
+// jsonAPIDocument collects the primary data and the included resources of a response
+type jsonAPIDocument struct {
+	c        echo.Context
+	prefix   string
+	fields   map[string]map[string]bool
+	include  map[string]bool
+	included []*jsonAPIResource
+	seen     map[jsonAPIIdentifier]bool
+}
+
+func newJSONAPIDocument(c echo.Context) *jsonAPIDocument {
+	doc := &jsonAPIDocument{
+		c:       c,
+		prefix:  "/aerospike/service/resources",
+		fields:  map[string]map[string]bool{},
+		include: map[string]bool{},
+		seen:    map[jsonAPIIdentifier]bool{},
+	}
+	if v1, _ := c.Get(_apiV1CtxMarker).(bool); v1 {
+		doc.prefix = "/api/v1/resources"
+	}
+
+	for _, name := range common.SplitList(c.QueryParam("include")) {
+		doc.include[name] = true
+	}
+	for param, values := range c.QueryParams() {
+		if strings.HasPrefix(param, "fields[") && strings.HasSuffix(param, "]") && len(values) > 0 {
+			fields := map[string]bool{}
+			for _, field := range common.SplitList(values[0]) {
+				fields[field] = true
+			}
+			doc.fields[param[len("fields["):len(param)-1]] = fields
+		}
+	}
+	return doc
+}
+
+func (doc *jsonAPIDocument) link(parts ...string) string {
+	for i := range parts {
+		parts[i] = url.PathEscape(parts[i])
+	}
+	return doc.prefix + "/" + strings.Join(parts, "/")
+}
+
+// sparse - keep the attributes and the relationships of the resource requested with fields[<type>]
+func (doc *jsonAPIDocument) sparse(res *jsonAPIResource) *jsonAPIResource {
+	fields, exists := doc.fields[res.Type]
+	if !exists {
+		return res
+	}
+
+	for name := range res.Attributes {
+		if !fields[name] {
+			delete(res.Attributes, name)
+		}
+	}
+	for name := range res.Relationships {
+		if !fields[name] {
+			delete(res.Relationships, name)
+		}
+	}
+	return res
+}
+
+// addIncluded - add the resource to included, once
+func (doc *jsonAPIDocument) addIncluded(res *jsonAPIResource) {
+	if !doc.seen[res.jsonAPIIdentifier] {
+		doc.seen[res.jsonAPIIdentifier] = true
+		doc.included = append(doc.included, doc.sparse(res))
+	}
+}
+
+func (doc *jsonAPIDocument) cluster(cluster *models.Cluster) *jsonAPIResource {
+	nodes, namespaces := []jsonAPIIdentifier{}, []jsonAPIIdentifier{}
+	for _, node := range cluster.Nodes() {
+		nodes = append(nodes, jsonAPIIdentifier{"nodes", node.Address()})
+		if doc.include["nodes"] {
+			doc.addIncluded(doc.node(cluster, node))
+		}
+	}
+	for _, name := range cluster.NamespaceList() {
+		namespaces = append(namespaces, jsonAPIIdentifier{"namespaces", cluster.ID() + "/" + name})
+		if doc.include["namespaces"] {
+			doc.addIncluded(doc.namespace(cluster, name))
+		}
+	}
+
+	return doc.sparse(&jsonAPIResource{
+		jsonAPIIdentifier: jsonAPIIdentifier{"clusters", cluster.ID()},
+		Attributes: map[string]interface{}{
+			"alias":           cluster.Alias(),
+			"status":          cluster.Status(),
+			"seed_address":    cluster.SeedAddress(),
+			"update_interval": cluster.UpdateInterval(),
+			"build_details":   cluster.BuildDetails(),
+		},
+		Relationships: map[string]*jsonAPIRelationship{
+			"nodes":      {Data: nodes, Links: map[string]string{"related": doc.link("clusters", cluster.ID(), "nodes")}},
+			"namespaces": {Data: namespaces, Links: map[string]string{"related": doc.link("clusters", cluster.ID(), "namespaces")}},
+		},
+		Links: map[string]string{"self": doc.link("clusters", cluster.ID())},
+	})
+}
+
+func (doc *jsonAPIDocument) clusterRelationship(cluster *models.Cluster) *jsonAPIRelationship {
+	if doc.include["cluster"] {
+		// the relationships of the included cluster are identifiers only
+		include := doc.include
+		doc.include = map[string]bool{}
+		doc.addIncluded(doc.cluster(cluster))
+		doc.include = include
+	}
+	return &jsonAPIRelationship{
+		Data:  jsonAPIIdentifier{"clusters", cluster.ID()},
+		Links: map[string]string{"related": doc.link("clusters", cluster.ID())},
+	}
+}
+
+func (doc *jsonAPIDocument) node(cluster *models.Cluster, node *models.Node) *jsonAPIResource {
+	namespaces := []string{}
+	for name := range node.Namespaces() {
+		namespaces = append(namespaces, name)
+	}
+
+	return doc.sparse(&jsonAPIResource{
+		jsonAPIIdentifier: jsonAPIIdentifier{"nodes", node.Address()},
+		Attributes: map[string]interface{}{
+			"address":       node.Address(),
+			"node_id":       node.ID(),
+			"status":        string(node.Status()),
+			"status_reason": node.StatusReason(),
+			"build":         node.Build(),
+			"namespaces":    common.SortStrings(namespaces),
+			"stats":         node.StatsAttrs(),
+		},
+		Relationships: map[string]*jsonAPIRelationship{
+			"cluster": doc.clusterRelationship(cluster),
+		},
+		Links: map[string]string{"self": doc.link("clusters", cluster.ID(), "nodes", node.Address())},
+	})
+}
+
+func (doc *jsonAPIDocument) namespace(cluster *models.Cluster, name string) *jsonAPIResource {
+	nodes := []string{}
+	for _, node := range cluster.Nodes() {
+		if node.NamespaceByName(name) != nil {
+			nodes = append(nodes, node.Address())
+		}
+	}
+
+	return doc.sparse(&jsonAPIResource{
+		jsonAPIIdentifier: jsonAPIIdentifier{"namespaces", cluster.ID() + "/" + name},
+		Attributes: map[string]interface{}{
+			"name":  name,
+			"nodes": nodes,
+			"stats": cluster.NamespaceInfo([]string{name})[name],
+		},
+		Relationships: map[string]*jsonAPIRelationship{
+			"cluster": doc.clusterRelationship(cluster),
+		},
+		Links: map[string]string{"self": doc.link("clusters", cluster.ID(), "namespaces", name)},
+	})
+}
+
+// render - write the document with the primary data
+func (doc *jsonAPIDocument) render(data interface{}) error {
+	res := map[string]interface{}{
+		"data":    data,
+		"links":   map[string]string{"self": doc.prefix + strings.TrimPrefix(doc.c.Request().URL.RequestURI(), "/aerospike/service/resources")},
+		"jsonapi": map[string]string{"version": "1.0"},
+	}
+	if len(doc.include) > 0 {
+		included := doc.included
+		if included == nil {
+			included = []*jsonAPIResource{}
+		}
+		res["included"] = included
+	}
+	return jsonAPIRender(doc.c, http.StatusOK, res)
+}
+
+func jsonAPIRender(c echo.Context, code int, res interface{}) error {
+	b, err := json.Marshal(res)
+	if err != nil {
+		return err
+	}
+	return c.Blob(code, mimeJSONAPI, b)
+}
+
+// jsonAPIFail - respond with the errors document; the resource endpoints report failures with their status code
+func jsonAPIFail(c echo.Context, code int, errCode apiErrorCode, title string) error {
+	return jsonAPIRender(c, code, map[string]interface{}{
+		"errors": []jsonAPIError{{Status: strconv.Itoa(code), Code: errCode, Title: title}},
+	})
+}
+
+// jsonAPICluster - find the cluster of the request
+func jsonAPICluster(c echo.Context) (*models.Cluster, error) {
+	cluster := _observer.FindClusterByID(c.Param("clusterUUID"))
+	if cluster == nil {
+		return nil, jsonAPIFail(c, http.StatusNotFound, errClusterNotFound, "Cluster not found")
+	}
+	return cluster, nil
+}
+
+// getResourceClusters - the clusters of the session, like the UI
+func getResourceClusters(c echo.Context) error {
+	sid, _ := sessionID(c)
+	clusters, _ := _observer.MonitoringClusters(sid)
+	clusters = append(clusters, _observer.AutoClusters()...)
+
+	doc := newJSONAPIDocument(c)
+	data := []*jsonAPIResource{}
+	seen := map[string]bool{}
+	for _, cluster := range clusters {
+		if !seen[cluster.ID()] {
+			seen[cluster.ID()] = true
+			data = append(data, doc.cluster(cluster))
+		}
+	}
+	return doc.render(data)
+}
+
+func getResourceCluster(c echo.Context) error {
+	cluster, err := jsonAPICluster(c)
+	if cluster == nil {
+		return err
+	}
+
+	doc := newJSONAPIDocument(c)
+	return doc.render(doc.cluster(cluster))
+}
+
+func getResourceClusterNodes(c echo.Context) error {
+	cluster, err := jsonAPICluster(c)
+	if cluster == nil {
+		return err
+	}
+
+	doc := newJSONAPIDocument(c)
+	data := []*jsonAPIResource{}
+	for _, node := range cluster.Nodes() {
+		data = append(data, doc.node(cluster, node))
+	}
+	return doc.render(data)
+}
+
+func getResourceClusterNode(c echo.Context) error {
+	cluster, err := jsonAPICluster(c)
+	if cluster == nil {
+		return err
+	}
+
+	node := cluster.FindNodeByAddress(c.Param("node"))
+	if node == nil {
+		return jsonAPIFail(c, http.StatusNotFound, errNodeNotFound, "Node not found")
+	}
+
+	doc := newJSONAPIDocument(c)
+	return doc.render(doc.node(cluster, node))
+}
+
+func getResourceClusterNamespaces(c echo.Context) error {
+	cluster, err := jsonAPICluster(c)
+	if cluster == nil {
+		return err
+	}
+
+	doc := newJSONAPIDocument(c)
+	data := []*jsonAPIResource{}
+	for _, name := range cluster.NamespaceList() {
+		data = append(data, doc.namespace(cluster, name))
+	}
+	return doc.render(data)
+}
+
+func getResourceClusterNamespace(c echo.Context) error {
+	cluster, err := jsonAPICluster(c)
+	if cluster == nil {
+		return err
+	}
+
+	name := c.Param("namespace")
+	found := false
+	for _, ns := range cluster.NamespaceList() {
+		found = found || ns == name
+	}
+	if !found {
+		return jsonAPIFail(c, http.StatusNotFound, errNamespaceNotFound, "Namespace not found")
+	}
+
+	doc := newJSONAPIDocument(c)
+	return doc.render(doc.namespace(cluster, name))
+}
@@ -0,0 +1,152 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/aerospike-community/amc/common"
+	"github.com/aerospike-community/amc/models"
+)
+
+func getClusterNodesLogging(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	nodeAddrs := strings.Split(c.Param("nodes"), ",")
+	res := make(common.Stats, len(nodeAddrs))
+	for _, addr := range nodeAddrs {
+		res[addr] = map[string]interface{}{"node_status": "off"}
+	}
+
+	for _, node := range cluster.FindNodesByAddress(nodeAddrs...) {
+		nodeRes := map[string]interface{}{
+			"node_status": string(node.Status()),
+		}
+
+		sinks, err := node.LogSinks()
+		if err != nil {
+			nodeRes["error"] = err.Error()
+		} else {
+			nodeRes["sinks"] = sinks
+		}
+		res[node.Address()] = nodeRes
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+func postClusterNodesLogLevel(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	form := struct {
+		Sink    string `form:"sink"`
+		Context string `form:"context"`
+		Level   string `form:"level"`
+	}{}
+
+	c.Bind(&form)
+
+	sinkID, err := strconv.Atoi(form.Sink)
+	if err != nil || sinkID < 0 {
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid sink"))
+	}
+
+	if len(form.Context) == 0 || len(form.Level) == 0 {
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid context or level"))
+	}
+
+	// log-set is subject to the same restrictions as fire_cmd
+	if err := cluster.CheckInfoCommand("log-set"); err != nil {
+		return c.JSON(http.StatusOK, errorMapOf(err))
+	}
+
+	nodeAddrs := strings.Split(c.Param("nodes"), ",")
+	res := make(common.Stats, len(nodeAddrs))
+	for _, addr := range nodeAddrs {
+		res[addr] = map[string]interface{}{"node_status": "off"}
+	}
+
+	for _, node := range cluster.FindNodesByAddress(nodeAddrs...) {
+		nodeRes := map[string]interface{}{
+			"node_status": string(node.Status()),
+			"status":      "success",
+		}
+
+		if err := node.SetLogLevel(sinkID, form.Context, form.Level); err != nil {
+			nodeRes["status"] = "failure"
+			nodeRes["error"] = err.Error()
+		}
+		res[node.Address()] = nodeRes
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+func getClusterNodeServerLog(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	node := cluster.FindNodeByAddress(c.Param("node"))
+	if node == nil {
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"node_status": "off",
+		})
+	}
+
+	lines := cluster.ServerLogMaxLines()
+	if linesStr := c.QueryParam("lines"); linesStr != "" {
+		var err error
+		if lines, err = strconv.Atoi(linesStr); err != nil || lines < 1 {
+			return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Wrong lines param specified."))
+		}
+	}
+
+	logLines, err := node.TailServerLog(lines)
+	if err != nil {
+		return c.JSON(http.StatusOK, errorMapOf(err))
+	}
+
+	alertLines := node.AlertLogLines(logLines)
+	alerts := []interface{}{}
+	for _, alert := range node.AlertsFrom(0) {
+		if related := alertLines[alert.ID]; len(related) > 0 {
+			alerts = append(alerts, map[string]interface{}{
+				"id":           strconv.FormatInt(alert.ID, 10),
+				"desc":         alert.Desc,
+				"status":       alert.Status,
+				"last_occured": alert.LastOccured.UnixNano() / 1e6,
+				"lines":        related,
+			})
+		}
+	}
+
+	if c.QueryParam("problems_only") == "true" {
+		problems := make([]models.ServerLogLine, 0, len(logLines))
+		for _, l := range logLines {
+			if l.IsProblem() {
+				problems = append(problems, l)
+			}
+		}
+		logLines = problems
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":      "success",
+		"node_status": string(node.Status()),
+		"lines":       logLines,
+		"alerts":      alerts,
+	})
+}
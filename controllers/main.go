@@ -3,9 +3,9 @@ package controllers
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"net/http"
-	"os"
 	"time"
 
 	as "github.com/aerospike/aerospike-client-go/v5"
@@ -25,6 +25,9 @@ var (
 
 	registerEnterpriseAPI func(*echo.Echo)
 
+	// set when built with the grpc tag
+	startGRPCServer func(*common.Config)
+
 	_server *echo.Echo
 )
 
@@ -70,7 +73,7 @@ func postDebug(c echo.Context) error {
 	case "stop":
 		res = _observer.StopDebug()
 	default:
-		return c.JSON(http.StatusOK, errorMap("Cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
 	}
 
 	if res.On {
@@ -99,11 +102,40 @@ func getAMCVersion(c echo.Context) error {
 func ShutdownServer() {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
+	if _unixServer != nil {
+		if err := _unixServer.Shutdown(ctx); err != nil {
+			log.Error(err)
+		}
+	}
 	if err := _server.Shutdown(ctx); err != nil {
 		log.Fatal(err)
 	}
 }
 
+// ReloadConfig - reload the config file and apply it to the running server
+func ReloadConfig() error {
+	if _observer == nil {
+		return errors.New("The server has not been started")
+	}
+	return _observer.ReloadConfig()
+}
+
+// postReloadConfig - reload the config file; only allowed from the AMC host itself
+func postReloadConfig(c echo.Context) error {
+	if !isLocalRequest(c) {
+		return c.JSON(http.StatusForbidden, errorMap(errInsufficientPrivileges, "The config can only be reloaded from the AMC host"))
+	}
+
+	if err := ReloadConfig(); err != nil {
+		log.Error("Error reloading the config: ", err.Error())
+		return c.JSON(http.StatusOK, errorMapOf(err))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status": "success",
+	})
+}
+
 // Server - init server using config
 func Server(config *common.Config) {
 	_observer = models.New(config)
@@ -116,6 +148,17 @@ func Server(config *common.Config) {
 	_defaultClientPolicy.ConnectionQueueSize = 1
 
 	e := echo.New()
+	e.HTTPErrorHandler = httpErrorHandler(e)
+
+	// registered first, so that the latency and the status of every request are logged
+	accessLog, err := newAccessLogger(config)
+	if err != nil {
+		log.Fatalln("Error setting up the access log:", err)
+	}
+	if accessLog != nil {
+		e.Use(accessLog.middleware)
+	}
+
 	e.Use(middleware.SecureWithConfig(middleware.SecureConfig{
 		XSSProtection:         "1; mode=block",
 		ContentTypeNosniff:    "nosniff",
@@ -125,9 +168,12 @@ func Server(config *common.Config) {
 		// ContentSecurityPolicy: "default-src 'self';script-src 'self' 'unsafe-eval'; object-src 'self'", // does not work with underscore.js
 	}))
 
-	// Avoid stale connections
+	// Avoid stale connections; the responses of the requests timing out have to be written in time
+	limits := newRequestLimits(config)
 	e.Server.ReadTimeout = 30 * time.Second
-	e.Server.WriteTimeout = 30 * time.Second
+	if timeout := limits.longestTimeout(); timeout > 0 {
+		e.Server.WriteTimeout = timeout + 5*time.Second
+	}
 
 	store := sessions.NewCookieStore([]byte("amc-secret-key"))
 	e.Use(sessions.Sessions("amc_session", store))
@@ -136,84 +182,167 @@ func Server(config *common.Config) {
 		log.Fatalln("No static dir has been set in the config file. Quiting...")
 	}
 	log.Infoln("Static files path is being set to:" + config.AMC.StaticPath)
-	e.Static("/", config.AMC.StaticPath)
-	e.Static("/static", config.AMC.StaticPath)
+	static := staticFiles{root: config.AMC.StaticPath, config: config}
+	static.register(e, "/")
+	static.register(e, "/static")
 
 	// Middleware
-	if !common.AMCIsProd() {
-		// e.Logger.SetOutput(log.StandardLogger().Writer())
-		// e.Use(middleware.Logger())
-	} else {
+	// the requests are logged by the access log
+	if common.AMCIsProd() {
 		e.Use(middleware.Recover())
 	}
 
 	// Basic Authentication Middleware Setup
-	basicAuthUser := os.Getenv("AMC_AUTH_USER")
-	if basicAuthUser == "" {
-		basicAuthUser = config.BasicAuth.User
-	}
-
-	basicAuthPassword := os.Getenv("AMC_AUTH_PASSWORD")
-	if basicAuthPassword == "" {
-		basicAuthPassword = config.BasicAuth.Password
-	}
-
-	if basicAuthUser != "" {
-		e.Use(middleware.BasicAuth(func(username, password string, c echo.Context) (bool, error) {
+	// credentials are read on every request, so that they can be changed by reloading the config
+	e.Use(middleware.BasicAuthWithConfig(middleware.BasicAuthConfig{
+		Skipper: func(c echo.Context) bool {
+			user, _ := config.BasicAuthCredentials()
+			return user == "" || isPublicPath(config, c.Request().URL.Path)
+		},
+		Validator: func(username, password string, c echo.Context) (bool, error) {
+			basicAuthUser, basicAuthPassword := config.BasicAuthCredentials()
 			if username == basicAuthUser && password == basicAuthPassword {
 				return true, nil
 			}
 			return false, nil
-		}))
-	}
+		},
+	}))
 
-	e.Use(middleware.GzipWithConfig(middleware.DefaultGzipConfig))
+	e.Use(middleware.GzipWithConfig(middleware.GzipConfig{Skipper: static.skipGzip}))
 	// e.Use(middleware.CSRFWithConfig(middleware.DefaultCSRFConfig))
 	e.Use(middleware.SecureWithConfig(middleware.DefaultSecureConfig))
 
+	// the versioned API; the error middleware has to run after gzip
+	e.Pre(apiV1Rewrite)
+	e.Use(apiV1Errors)
+	e.Use(apiVersion)
+
+	// every response carries the id of its request, which is logged with it
+	e.Pre(middleware.RequestID())
+	e.Use(requestLogger)
+	e.Use(_routeTimings.middleware(config))
+
+	// the bodies are limited before they are read, and the timed out requests are logged with their id
+	e.Use(limits.bodyLimit())
+	e.Use(limits.timeoutMiddleware)
+
 	// Routes
-	e.POST("/session-terminate", postSessionTerminate)
+	e.GET("/healthz", getHealthz)
+	e.GET("/readyz", getReadyz)
+	e.GET("/status", getStatus(config))
+
+	e.POST("/aerospike/service/session_terminate", postSessionTerminate)
 
 	e.GET("/aerospike/service/debug", getDebug)
 	e.POST("/aerospike/service/clusters/:clusterUUID/debug", postDebug) // cluster does not matter here
-
+	e.POST("/aerospike/service/reload_config", postReloadConfig)
+	e.GET("/aerospike/service/sessions", getSessions)
+	e.DELETE("/aerospike/service/sessions/:sessionID", deleteSession)
+	e.GET("/aerospike/service/amc_stats", getAMCStats)
+	e.GET("/aerospike/service/observer", getObserverState)
+	e.GET("/aerospike/service/openapi.json", getOpenAPISpec)
+	e.GET("/aerospike/service/graphql", sessionValidator(postGraphQL))
+	e.POST("/aerospike/service/graphql", sessionValidator(postGraphQL))
+
+	// JSON:API documents of the clusters, nodes and namespaces; see jsonapi.go
+	e.GET("/aerospike/service/resources/clusters", sessionValidator(getResourceClusters))
+	e.GET("/aerospike/service/resources/clusters/:clusterUUID", sessionValidator(observerCached(getResourceCluster)))
+	e.GET("/aerospike/service/resources/clusters/:clusterUUID/nodes", sessionValidator(observerCached(getResourceClusterNodes)))
+	e.GET("/aerospike/service/resources/clusters/:clusterUUID/nodes/:node", sessionValidator(observerCached(getResourceClusterNode)))
+	e.GET("/aerospike/service/resources/clusters/:clusterUUID/namespaces", sessionValidator(observerCached(getResourceClusterNamespaces)))
+	e.GET("/aerospike/service/resources/clusters/:clusterUUID/namespaces/:namespace", sessionValidator(observerCached(getResourceClusterNamespace)))
+
+	e.GET("/aerospike/service/amc_version", getAMCVersion)
+	e.GET("/aerospike/service/monitoring_clusters", getCurrentMonitoringClusters)
+	e.POST("/aerospike/service/clusters/:clusterUUID/update_interval", sessionValidator(setClusterUpdateInterval))
+
+	// deprecated; see _deprecatedRoutes
+	e.POST("/session-terminate", postSessionTerminate)
 	e.GET("/get_amc_version", getAMCVersion)
 	e.GET("/get_current_monitoring_clusters", getCurrentMonitoringClusters)
-
 	e.POST("/set-update-interval/:clusterUUID", sessionValidator(setClusterUpdateInterval))
+
 	e.GET("/aerospike/service/clusters/:clusterUUID", sessionValidator(getCluster))
+	e.DELETE("/aerospike/service/clusters/:clusterUUID", sessionValidator(deleteCluster))
+	e.GET("/aerospike/service/clusters/:clusterUUID/snapshot", sessionValidator(getClusterSnapshot))
+	e.GET("/aerospike/service/clusters/:clusterUUID/dashboard", sessionValidator(observerCached(getClusterDashboard)))
 	e.POST("/aerospike/service/clusters/:clusterUUID/logout", postRemoveClusterFromSession)
 
 	e.GET("/aerospike/service/clusters/:clusterUUID/udfs", sessionValidator(getClusterUDFs))
 	e.POST("/aerospike/service/clusters/:clusterUUID/drop_udf", sessionValidator(postClusterDropUDF))
 	e.POST("/aerospike/service/clusters/:clusterUUID/add_udf", sessionValidator(postClusterAddUDF))
+	e.POST("/aerospike/service/clusters/:clusterUUID/execute_udf", sessionValidator(postClusterExecuteUDF))
 
-	e.GET("/aerospike/service/clusters/:clusterUUID/throughput", sessionValidator(getClusterThroughput))
+	e.GET("/aerospike/service/clusters/:clusterUUID/throughput", sessionValidator(observerCached(getClusterThroughput)))
 	e.GET("/aerospike/service/clusters/:clusterUUID/throughput_history", sessionValidator(getClusterThroughputHistory))
+	e.GET("/aerospike/service/clusters/:clusterUUID/migrations", sessionValidator(getClusterMigrations))
+	e.GET("/aerospike/service/clusters/:clusterUUID/stability", sessionValidator(getClusterStability))
+	e.GET("/aerospike/service/clusters/:clusterUUID/healthcheck", sessionValidator(getClusterHealthCheck))
+	e.GET("/aerospike/service/clusters/:clusterUUID/report", sessionValidator(getClusterReport))
+	e.GET("/aerospike/service/clusters/:clusterUUID/summary_report", sessionValidator(getClusterSummaryReport))
+	e.GET("/aerospike/service/clusters/:clusterUUID/stop_writes_prediction", sessionValidator(getClusterStopWritesPrediction))
 	e.GET("/aerospike/service/clusters/:clusterUUID/basic", sessionValidator(getClusterBasic))
+	e.POST("/aerospike/service/clusters/:clusterUUID/set_cluster_name", sessionValidator(postClusterSetName))
+	e.GET("/aerospike/service/clusters/:clusterUUID/client_policy", sessionValidator(getClusterClientPolicy))
+	e.POST("/aerospike/service/clusters/:clusterUUID/client_policy", sessionValidator(postClusterClientPolicy))
+	e.GET("/aerospike/service/clusters/:clusterUUID/stat_profile", sessionValidator(getClusterStatProfile))
+	e.POST("/aerospike/service/clusters/:clusterUUID/stat_profile", sessionValidator(postClusterStatProfile))
 	e.POST("/aerospike/service/clusters/:clusterUUID/add_node", sessionValidator(postAddClusterNodes))
-	e.GET("/aerospike/service/clusters/:clusterUUID/nodes/:nodes", sessionValidator(getClusterNodes))
+	e.GET("/aerospike/service/clusters/:clusterUUID/nodes/:nodes", sessionValidator(observerCached(getClusterNodes)))
 	e.GET("/aerospike/service/clusters/:clusterUUID/nodes/:node/allconfig", sessionValidator(getClusterNodeAllConfig))
+	e.GET("/aerospike/service/clusters/:clusterUUID/nodes/:node/aerospike_conf", sessionValidator(getClusterNodeConfFile))
+	e.GET("/aerospike/service/clusters/:clusterUUID/nodes/:nodes/logging", sessionValidator(getClusterNodesLogging))
+	e.POST("/aerospike/service/clusters/:clusterUUID/nodes/:nodes/logging/set_level", sessionValidator(postClusterNodesLogLevel))
+	e.GET("/aerospike/service/clusters/:clusterUUID/nodes/:node/server_log", sessionValidator(getClusterNodeServerLog))
 	e.POST("/aerospike/service/clusters/:clusterUUID/nodes/:nodes/setconfig", sessionValidator(setClusterNodesConfig))
 	e.POST("/aerospike/service/clusters/:clusterUUID/nodes/:node/switch_off", sessionValidator(postSwitchNodeOff))
-	e.GET("/aerospike/service/clusters/:clusterUUID/namespaces/:namespaces", sessionValidator(getClusterNamespaces))
-	e.GET("/aerospike/service/clusters/:clusterUUID/namespaces/:namespace/nodes/:nodes", sessionValidator(getClusterNamespaceNodes))
+	e.GET("/aerospike/service/clusters/:clusterUUID/namespaces/:namespaces", sessionValidator(observerCached(getClusterNamespaces)))
+	e.GET("/aerospike/service/clusters/:clusterUUID/namespaces/:namespace/nodes/:nodes", sessionValidator(observerCached(getClusterNamespaceNodes)))
 	e.GET("/aerospike/service/clusters/:clusterUUID/namespaces/:namespace/nodes/:node/allconfig", sessionValidator(getClusterNamespaceAllConfig))
 	e.POST("/aerospike/service/clusters/:clusterUUID/namespaces/:namespace/nodes/:node/setconfig", sessionValidator(setClusterNamespaceConfig))
+	e.POST("/aerospike/service/clusters/:clusterUUID/namespaces/:namespace/nodes/:nodes/set_rack_id", sessionValidator(postClusterNamespaceRackID))
+	e.GET("/aerospike/service/clusters/:clusterUUID/racks", sessionValidator(getClusterRacks))
+	e.GET("/aerospike/service/clusters/:clusterUUID/config_history", sessionValidator(getClusterConfigHistory))
+	e.POST("/aerospike/service/clusters/:clusterUUID/config_history/:changeID/rollback", sessionValidator(postClusterConfigRollback))
 
-	e.GET("/aerospike/service/clusters/:clusterUUID/nodes/:node/allstats", sessionValidator(getClusterNodeAllStats))
-	e.GET("/aerospike/service/clusters/:clusterUUID/namespaces/:namespace/nodes/:node/allstats", sessionValidator(getClusterNamespaceNodeAllStats))
-	e.GET("/aerospike/service/clusters/:clusterUUID/xdr/:port/nodes/:node/allstats", sessionValidator(getClusterXdrNodeAllStats))
+	e.GET("/aerospike/service/clusters/:clusterUUID/nodes/:node/allstats", sessionValidator(observerCached(getClusterNodeAllStats)))
+	e.GET("/aerospike/service/clusters/:clusterUUID/namespaces/:namespace/nodes/:node/allstats", sessionValidator(observerCached(getClusterNamespaceNodeAllStats)))
+	e.GET("/aerospike/service/clusters/:clusterUUID/xdr/:port/nodes/:node/allstats", sessionValidator(observerCached(getClusterXdrNodeAllStats)))
 
-	e.GET("/aerospike/service/clusters/:clusterUUID/namespaces/:namespace/sindexes/:sindex/nodes/:node/allstats", sessionValidator(getClusterNamespaceSindexNodeAllStats))
+	e.GET("/aerospike/service/clusters/:clusterUUID/namespaces/:namespace/sindexes/:sindex/nodes/:node/allstats", sessionValidator(observerCached(getClusterNamespaceSindexNodeAllStats)))
 	e.POST("/aerospike/service/clusters/:clusterUUID/namespace/:namespace/add_index", sessionValidator(postClusterAddIndex))
 	e.POST("/aerospike/service/clusters/:clusterUUID/namespace/:namespace/drop_index", sessionValidator(postClusterDropIndex))
-
-	e.GET("/aerospike/service/clusters/:clusterUUID/namespaces/:namespace/sindexes", sessionValidator(getClusterNamespaceSindexes))
-	e.GET("/aerospike/service/clusters/:clusterUUID/namespaces/:namespace/sets", sessionValidator(getClusterNamespaceSets))
+	e.POST("/aerospike/service/clusters/:clusterUUID/namespace/:namespace/enable_set_index", sessionValidator(postClusterEnableSetIndex))
+	e.POST("/aerospike/service/clusters/:clusterUUID/namespace/:namespace/disable_set_index", sessionValidator(postClusterDisableSetIndex))
+
+	e.GET("/aerospike/service/clusters/:clusterUUID/namespaces/:namespace/sindexes", sessionValidator(observerCached(getClusterNamespaceSindexes)))
+	e.GET("/aerospike/service/clusters/:clusterUUID/namespaces/:namespace/sindexes/:sindex/query", sessionValidator(getClusterNamespaceSindexQuery))
+	e.GET("/aerospike/service/clusters/:clusterUUID/namespaces/:namespace/sets", sessionValidator(observerCached(getClusterNamespaceSets)))
+	e.GET("/aerospike/service/clusters/:clusterUUID/namespaces/:namespace/sets/:setName/sample", sessionValidator(getClusterNamespaceSetSample))
+	e.GET("/aerospike/service/clusters/:clusterUUID/namespaces/:namespace/sets/:setName/profile", sessionValidator(getClusterNamespaceSetProfile))
+	e.GET("/aerospike/service/clusters/:clusterUUID/namespaces/:namespace/sets/:setName/export", sessionValidator(getClusterNamespaceSetExport))
+	e.GET("/aerospike/service/clusters/:clusterUUID/namespaces/:namespace/record_metadata", sessionValidator(getClusterNamespaceRecordMetadata))
+	e.GET("/aerospike/service/clusters/:clusterUUID/namespaces/:namespace/hot_keys", sessionValidator(getClusterNamespaceHotKeys))
+	e.GET("/aerospike/service/clusters/:clusterUUID/namespaces/:namespace/set_indexes", sessionValidator(getClusterNamespaceSetIndexes))
 	e.GET("/aerospike/service/clusters/:clusterUUID/namespaces/:namespace/storage", sessionValidator(getClusterNamespaceStorage))
-	e.GET("/aerospike/service/clusters/:clusterUUID/nodes/:nodes/jobs", getClusterNodesJobs)
-	e.GET("/aerospike/service/clusters/:clusterUUID/jobs/nodes/:node", getClusterJobsNode)
+	e.GET("/aerospike/service/clusters/:clusterUUID/namespaces/:namespace/eviction", sessionValidator(getClusterNamespaceEviction))
+	e.GET("/aerospike/service/clusters/:clusterUUID/namespaces/:namespace/conflicts", sessionValidator(getClusterNamespaceConflicts))
+	e.GET("/aerospike/service/clusters/:clusterUUID/namespaces/:namespace/defrag", sessionValidator(getClusterNamespaceDefrag))
+	e.POST("/aerospike/service/clusters/:clusterUUID/namespaces/:namespace/nodes/:nodes/defrag", sessionValidator(postClusterNamespaceDefrag))
+	e.GET("/aerospike/service/clusters/:clusterUUID/namespaces/:namespace/partition_state", sessionValidator(getClusterNamespacePartitionState))
+	e.POST("/aerospike/service/clusters/:clusterUUID/namespaces/:namespace/revive", sessionValidator(postClusterNamespaceRevive))
+	e.GET("/aerospike/service/clusters/:clusterUUID/namespaces/:namespace/histograms/:type", sessionValidator(getClusterNamespaceHistogram))
+	e.GET("/aerospike/service/clusters/:clusterUUID/namespaces/:namespace/object_sizes", sessionValidator(getClusterNamespaceObjectSizes))
+	e.GET("/aerospike/service/clusters/:clusterUUID/object_sizes", sessionValidator(getClusterObjectSizes))
+	e.POST("/aerospike/service/clusters/:clusterUUID/plan_namespace", sessionValidator(postClusterPlanNamespace))
+	e.GET("/aerospike/service/clusters/:clusterUUID/data_generator", sessionValidator(getClusterDataGenerator))
+	e.POST("/aerospike/service/clusters/:clusterUUID/data_generator", sessionValidator(postClusterDataGenerator))
+	e.DELETE("/aerospike/service/clusters/:clusterUUID/data_generator", sessionValidator(deleteClusterDataGenerator))
+	e.POST("/aerospike/service/clusters/:clusterUUID/benchmark", sessionValidator(postClusterBenchmark))
+	e.GET("/aerospike/service/clusters/:clusterUUID/nodes/:nodes/jobs", sessionValidator(getClusterNodesJobs))
+	e.GET("/aerospike/service/clusters/:clusterUUID/jobs/nodes/:node", sessionValidator(getClusterJobsNode))
+	e.GET("/aerospike/service/clusters/:clusterUUID/jobs/watch", sessionValidator(getClusterJobsWatch))
+	e.GET("/aerospike/service/clusters/:clusterUUID/jobs_history", sessionValidator(getClusterJobHistory))
 
 	e.POST("/aerospike/service/clusters/get-cluster-id", postGetClusterID)
 
@@ -221,42 +350,34 @@ func Server(config *common.Config) {
 		registerEnterpriseAPI(e)
 	}
 
+	if config.AMC.GRPCBind != "" {
+		if startGRPCServer != nil {
+			go startGRPCServer(config)
+		} else {
+			log.Warn("grpc_bind is set, but AMC was built without gRPC support")
+		}
+	}
+
+	if config.AMC.UnixSocket != "" {
+		listenUnixSocket(e, config)
+	}
+
 	log.Infof("Starting AMC server, version: %s %s", common.AMCVersion, common.AMCEdition)
 	_server = e
 	// Start server
-	if config.AMC.CertFile != "" {
+	if config.TLSEnabled() {
 		log.Infof("In HTTPS (secure) Mode")
 
-		tlsConfig := new(tls.Config)
-		tlsConfig.Certificates = make([]tls.Certificate, 1)
-		var err error
-		tlsConfig.Certificates[0], err = tls.LoadX509KeyPair(config.AMC.CertFile, config.AMC.KeyFile)
+		tlsConfig, err := serverTLSConfig(config)
 		if err != nil {
-			log.Fatalln("Error reading the certificate files from disk: " + err.Error())
-		}
-
-		if config.AMC.ForceTLS12 || config.AMC.MaxTLSSecurity {
-			log.Infof("Forcing TLS v1.2")
-			tlsConfig.MinVersion = tls.VersionTLS12
-		}
-
-		if config.AMC.MaxTLSSecurity {
-			log.Infof("Forcing Maximum security mode for TLS (Uses >=256 bit curves, ciphersuites and prefers server cypher suites)")
-			tlsConfig.CurvePreferences = []tls.CurveID{tls.CurveP521, tls.CurveP384, tls.CurveP256}
-			tlsConfig.PreferServerCipherSuites = true
-			tlsConfig.CipherSuites = []uint16{
-				tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
-				tls.TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA,
-				tls.TLS_RSA_WITH_AES_256_GCM_SHA384,
-				tls.TLS_RSA_WITH_AES_256_CBC_SHA,
-			}
+			log.Fatalln("Error setting up TLS: " + err.Error())
 		}
 
 		e.TLSServer.TLSNextProto = make(map[string]func(*http.Server, *tls.Conn, http.Handler))
 		e.TLSServer.TLSConfig = tlsConfig
 		e.TLSServer.Addr = config.AMC.Bind
 		// redirect all http requests to https
-		e.Pre(middleware.HTTPSRedirect())
+		e.Pre(middleware.HTTPSRedirectWithConfig(middleware.RedirectConfig{Skipper: viaUnixSocket}))
 
 		// starts a listener for normal http port to support http -> https redirect
 		log.Errorln(e.StartServer(e.TLSServer))
@@ -16,32 +16,65 @@ func registerEnterprise(e *echo.Echo) {
 	e.POST("/aerospike/service/clusters/:clusterUUID/initiate_restore", sessionValidator(postInitiateRestore))
 	e.GET("/aerospike/service/clusters/:clusterUUID/get_restore_progress", sessionValidator(getRestoreProgress))
 
+	e.GET("/aerospike/service/alert_emails", sessionValidator(getAlertEmails))
+	e.POST("/aerospike/service/alert_emails", sessionValidator(postAlertEmails))
+	e.POST("/aerospike/service/delete_alert_emails", sessionValidator(deleteAlertEmails))
+	e.GET("/aerospike/service/multicluster_view", getMultiClusterView)
+
+	// deprecated; see _deprecatedRoutes
 	e.GET("/alert-emails", sessionValidator(getAlertEmails))
 	e.POST("/alert-emails", sessionValidator(postAlertEmails))
 	e.POST("/delete-alert-emails", sessionValidator(deleteAlertEmails))
-
-	e.GET("/aerospike/get_multicluster_view/:port", getMultiClusterView)
+	e.GET("/aerospike/get_multicluster_view", getLegacyMultiClusterView)
+	e.GET("/aerospike/get_multicluster_view/:port", getLegacyMultiClusterView) // the port is ignored; kept for older UIs
 
 	e.POST("/aerospike/service/clusters/:clusterUUID/fire_cmd", sessionValidator(postClusterFireCmd))
+	e.POST("/aerospike/service/clusters/:clusterUUID/aql", sessionValidator(postClusterAQL))
 	e.GET("/aerospike/service/clusters/:clusterUUID/get_all_users", sessionValidator(getClusterAllUsers))
 	e.GET("/aerospike/service/clusters/:clusterUUID/get_all_roles", sessionValidator(getClusterAllRoles))
+	e.GET("/aerospike/service/clusters/:clusterUUID/privileges", sessionValidator(getClusterPrivileges))
 	e.POST("/aerospike/service/clusters/:clusterUUID/add_user", sessionValidator(postClusterAddUser))
+	e.GET("/aerospike/service/clusters/:clusterUUID/users/export", sessionValidator(getClusterExportUsers))
+	e.POST("/aerospike/service/clusters/:clusterUUID/users/import", sessionValidator(postClusterImportUsers))
 	e.POST("/aerospike/service/clusters/:clusterUUID/user/:user/remove", sessionValidator(postClusterDropUser))
 	e.POST("/aerospike/service/clusters/:clusterUUID/user/:user/update", sessionValidator(postClusterUpdateUser))
 	e.POST("/aerospike/service/clusters/:clusterUUID/roles/:role/add_role", sessionValidator(postClusterAddRole))
 	e.POST("/aerospike/service/clusters/:clusterUUID/roles/:role/update", sessionValidator(postClusterUpdateRole))
 	e.POST("/aerospike/service/clusters/:clusterUUID/roles/:role/drop_role", sessionValidator(postClusterDropRole))
 
-	e.GET("/aerospike/service/clusters/:clusterUUID/latency/:nodes", sessionValidator(getNodeLatency))
+	e.GET("/aerospike/service/role_templates", sessionValidator(getRoleTemplates))
+	e.POST("/aerospike/service/role_templates", sessionValidator(postRoleTemplate))
+	e.POST("/aerospike/service/role_templates/:template/delete", sessionValidator(postDeleteRoleTemplate))
+	e.POST("/aerospike/service/clusters/:clusterUUID/role_templates/:template/apply", sessionValidator(postClusterApplyRoleTemplate))
+
+	e.GET("/aerospike/service/clusters/:clusterUUID/latency/:nodes", sessionValidator(observerCached(getNodeLatency)))
 	e.GET("/aerospike/service/clusters/:clusterUUID/latency_history/:nodes", sessionValidator(getNodeLatencyHistory))
 	e.GET("/aerospike/service/clusters/:clusterUUID/nodes/:nodes/latency_history", sessionValidator(getNodesLatencyHistory))
 	e.POST("/aerospike/service/clusters/:clusterUUID/change_password", sessionValidator(postClusterChangePassword))
 	e.GET("/aerospike/service/clusters/:clusterUUID/alerts", sessionValidator(getClusterAlerts))
 	e.POST("/aerospike/service/clusters/:clusterUUID/nodes/:node/switch_xdr_off", sessionValidator(postSwitchXDROff))
 	e.POST("/aerospike/service/clusters/:clusterUUID/nodes/:node/switch_xdr_on", sessionValidator(postSwitchXDROn))
-	e.GET("/aerospike/service/clusters/:clusterUUID/xdr/:xdrPort/nodes/:nodes", sessionValidator(getClusterXdrNodes))
+	e.GET("/aerospike/service/clusters/:clusterUUID/xdr/:xdrPort/nodes/:nodes", sessionValidator(observerCached(getClusterXdrNodes)))
 	e.GET("/aerospike/service/clusters/:clusterUUID/xdr/:xdrPort/nodes/:nodes/allconfig", sessionValidator(getClusterXdrNodesAllConfig))
 	e.POST("/aerospike/service/clusters/:clusterUUID/xdr/:xdrPort/nodes/:nodes/setconfig", sessionValidator(setClusterXdrNodesConfig))
+
+	// the xdr port is not used; the routes above are kept for compatibility
+	e.GET("/aerospike/service/clusters/:clusterUUID/xdr/nodes/:nodes", sessionValidator(observerCached(getClusterXdrNodes)))
+	e.GET("/aerospike/service/clusters/:clusterUUID/xdr/nodes/:nodes/allconfig", sessionValidator(getClusterXdrNodesAllConfig))
+	e.POST("/aerospike/service/clusters/:clusterUUID/xdr/nodes/:nodes/setconfig", sessionValidator(setClusterXdrNodesConfig))
+
+	// XDR 5.0+: per datacenter and per namespace config
+	e.GET("/aerospike/service/clusters/:clusterUUID/xdr/dcs", sessionValidator(getClusterXdrDCs))
+	e.GET("/aerospike/service/clusters/:clusterUUID/xdr/dcs/:dc/nodes/:nodes", sessionValidator(getClusterXdrDCNodes))
+	e.POST("/aerospike/service/clusters/:clusterUUID/xdr/dcs/:dc/nodes/:nodes/setconfig", sessionValidator(setClusterXdrDCNodesConfig))
+	e.POST("/aerospike/service/clusters/:clusterUUID/xdr/dcs/:dc/namespaces/:namespace/nodes/:nodes/setconfig", sessionValidator(setClusterXdrDCNamespaceNodesConfig))
+	e.POST("/aerospike/service/clusters/:clusterUUID/xdr/dcs/:dc/create", sessionValidator(postClusterXdrCreateDC))
+	e.POST("/aerospike/service/clusters/:clusterUUID/xdr/dcs/:dc/delete", sessionValidator(postClusterXdrDeleteDC))
+	e.POST("/aerospike/service/clusters/:clusterUUID/xdr/dcs/:dc/add_node", sessionValidator(postClusterXdrDCAddNode))
+	e.POST("/aerospike/service/clusters/:clusterUUID/xdr/dcs/:dc/remove_node", sessionValidator(postClusterXdrDCRemoveNode))
+	e.POST("/aerospike/service/clusters/:clusterUUID/xdr/dcs/:dc/namespaces/:namespace/add", sessionValidator(postClusterXdrDCAddNamespace))
+	e.POST("/aerospike/service/clusters/:clusterUUID/xdr/dcs/:dc/namespaces/:namespace/remove", sessionValidator(postClusterXdrDCRemoveNamespace))
+	e.POST("/aerospike/service/clusters/:clusterUUID/xdr/dcs/:dc/namespaces/:namespace/rewind", sessionValidator(postClusterXdrDCRewindNamespace))
 }
 
 func init() {
@@ -173,3 +173,14 @@ func Default(c echo.Context) Session {
 	}
 	return ses
 }
+
+// WithWriter - a copy of the session which writes its cookie to w, for a handler given a response writer of its own
+func WithWriter(s Session, w http.ResponseWriter) Session {
+	src, ok := s.(*session)
+	if !ok {
+		return s
+	}
+	res := *src
+	res.writer = w
+	return &res
+}
@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+func getClusterMigrations(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	var tm time.Time // zero value
+	if beginStr := c.QueryParam("start_time"); beginStr != "" {
+		sinceUnix, err := strconv.ParseInt(beginStr, 10, 64)
+		if err != nil {
+			return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid start_time value"))
+		}
+
+		since := time.Unix(sinceUnix/1000, 0)
+		if since.After(cluster.ServerTime().Add(-time.Minute * 30)) {
+			tm = since
+		}
+	}
+
+	// make the output. x: timestamp, y: remaining partitions
+	type chartStat struct {
+		X *int64   `json:"x"`
+		Y *float64 `json:"y"`
+	}
+
+	zeroValue := float64(0)
+	zeroTime := cluster.ServerTime()
+	history := map[string]map[string]map[string][]chartStat{}
+	for node, nsHistory := range cluster.MigrationsSince(tm) {
+		nodeRes := map[string]map[string][]chartStat{}
+		for ns, statsHistory := range nsHistory {
+			nsRes := map[string][]chartStat{}
+			for stat, values := range statsHistory {
+				statList := make([]chartStat, 0, len(values))
+				for _, v := range values {
+					statList = append(statList, chartStat{X: v.TimestampJSON(&zeroTime), Y: v.Value(&zeroValue)})
+				}
+				nsRes[stat] = statList
+			}
+			nodeRes[ns] = nsRes
+		}
+		history[node] = nodeRes
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":         "success",
+		"cluster_status": cluster.Status(),
+		"migrations":     cluster.Migrations(),
+		"history":        history,
+	})
+}
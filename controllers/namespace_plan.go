@@ -0,0 +1,42 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/aerospike-community/amc/common"
+	"github.com/aerospike-community/amc/models"
+)
+
+func postClusterPlanNamespace(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	req := models.NamespacePlanRequest{}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid input"))
+	}
+
+	// devices can also be sent as a comma separated list
+	devices := []string{}
+	for _, d := range req.Devices {
+		devices = append(devices, common.DeleteEmpty(strings.Split(d, ","))...)
+	}
+	req.Devices = devices
+
+	plan := cluster.PlanNamespace(&req)
+	res := map[string]interface{}{
+		"status": "success",
+		"plan":   plan,
+	}
+	if len(plan.Errors) > 0 {
+		setError(res, errInvalidParameter, plan.Errors[0])
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
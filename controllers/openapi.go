@@ -0,0 +1,159 @@
+package controllers
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+var _openAPIPathParam = regexp.MustCompile(`:([A-Za-z0-9_]+)`)
+var _openAPINonWord = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// getOpenAPISpec - describe the routes of the server as an OpenAPI 3 document; it is generated from
+// the registered routes, so it always lists every endpoint, but the parameters other than the path
+// parameters and the payloads are not described in detail
+func getOpenAPISpec(c echo.Context) error {
+	paths := map[string]map[string]interface{}{}
+
+	routes := c.Echo().Routes()
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path == routes[j].Path {
+			return routes[i].Method < routes[j].Method
+		}
+		return routes[i].Path < routes[j].Path
+	})
+
+	for _, route := range routes {
+		// static files
+		if strings.Contains(route.Path, "*") {
+			continue
+		}
+
+		path := route.Path
+		if strings.HasPrefix(path, _apiV1Target) {
+			path = _apiV1Prefix + strings.TrimPrefix(path, _apiV1Target)
+		}
+		path = _openAPIPathParam.ReplaceAllString(path, "{$1}")
+
+		if paths[path] == nil {
+			paths[path] = map[string]interface{}{}
+		}
+		paths[path][strings.ToLower(route.Method)] = openAPIOperation(route, path)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":       "Aerospike Management Console API",
+			"version":     common.AMCVersion,
+			"description": "The /api/v1 endpoints report failures with proper status codes and the Error envelope.",
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"securitySchemes": map[string]interface{}{
+				"session": map[string]interface{}{
+					"type": "apiKey",
+					"in":   "cookie",
+					"name": "amc_session",
+				},
+				"basicAuth": map[string]interface{}{
+					"type":   "http",
+					"scheme": "basic",
+				},
+			},
+			"schemas": map[string]interface{}{
+				"Error": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"error": map[string]interface{}{
+							"type": "object",
+							"properties": map[string]interface{}{
+								"code":       map[string]interface{}{"type": "integer"},
+								"error_code": map[string]interface{}{"type": "string", "example": "CLUSTER_NOT_FOUND"},
+								"message":    map[string]interface{}{"type": "string"},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+func openAPIOperation(route *echo.Route, path string) map[string]interface{} {
+	params := []interface{}{}
+	for _, m := range _openAPIPathParam.FindAllStringSubmatch(route.Path, -1) {
+		params = append(params, map[string]interface{}{
+			"name":     m[1],
+			"in":       "path",
+			"required": true,
+			"schema":   map[string]interface{}{"type": "string"},
+		})
+	}
+
+	responses := map[string]interface{}{
+		"200": map[string]interface{}{
+			"description": "Success",
+			"content": map[string]interface{}{
+				echo.MIMEApplicationJSON: map[string]interface{}{
+					"schema": map[string]interface{}{"type": "object"},
+				},
+			},
+		},
+	}
+	if strings.HasPrefix(path, _apiV1Prefix) {
+		responses["default"] = map[string]interface{}{
+			"description": "Failure",
+			"content": map[string]interface{}{
+				echo.MIMEApplicationJSON: map[string]interface{}{
+					"schema": map[string]interface{}{"$ref": "#/components/schemas/Error"},
+				},
+			},
+		}
+	}
+
+	op := map[string]interface{}{
+		"operationId": strings.ToLower(route.Method) + "_" + strings.Trim(_openAPINonWord.ReplaceAllString(route.Path, "_"), "_"),
+		"summary":     openAPISummary(route.Path),
+		"parameters":  params,
+		"responses":   responses,
+	}
+
+	if route.Method == http.MethodPost || route.Method == http.MethodPut {
+		op["requestBody"] = map[string]interface{}{
+			"content": map[string]interface{}{
+				echo.MIMEApplicationForm: map[string]interface{}{
+					"schema": map[string]interface{}{"type": "object"},
+				},
+			},
+		}
+	}
+
+	if _, deprecated := _deprecatedRoutes[route.Path]; deprecated {
+		op["deprecated"] = true
+	}
+
+	// the handlers wrapped by sessionValidator need the session of a cluster
+	if strings.Contains(route.Name, "sessionValidator") {
+		op["security"] = []interface{}{map[string]interface{}{"session": []string{}}}
+	}
+
+	return op
+}
+
+// openAPISummary - describe the route by its static path segments
+func openAPISummary(path string) string {
+	words := []string{}
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "" || segment == "aerospike" || segment == "service" || strings.HasPrefix(segment, ":") {
+			continue
+		}
+		words = append(words, strings.NewReplacer("_", " ", "-", " ").Replace(segment))
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+func getClusterRacks(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status": "success",
+		"racks":  cluster.RackTopology(),
+	})
+}
+
+func postClusterNamespaceRackID(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	form := struct {
+		RackID    string `form:"rack_id"`
+		Recluster string `form:"recluster"`
+	}{}
+
+	if err := c.Bind(&form); err != nil {
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid input"))
+	}
+
+	rackID, err := strconv.ParseInt(form.RackID, 10, 64)
+	if err != nil || rackID < 0 {
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid rack_id"))
+	}
+
+	nodeAddrs := strings.Split(c.Param("nodes"), ",")
+	res := make(common.Stats, len(nodeAddrs))
+	for _, addr := range nodeAddrs {
+		res[addr] = map[string]interface{}{"node_status": "off"}
+	}
+
+	nodes := cluster.FindNodesByAddress(nodeAddrs...)
+	if len(nodes) == 0 {
+		return c.JSON(http.StatusOK, res)
+	}
+
+	failed := false
+	for node, err := range cluster.SetRackID(c.Param("namespace"), rackID, nodes, requestUser(c)) {
+		nodeRes := map[string]interface{}{
+			"node_status": string(node.Status()),
+			"status":      "success",
+		}
+		if err != nil {
+			failed = true
+			nodeRes["status"] = "failure"
+			nodeRes["error"] = err.Error()
+		}
+		res[node.Address()] = nodeRes
+	}
+
+	// the new rack-id is only effective after a recluster
+	reclustered := false
+	if !failed && form.Recluster != "false" {
+		if err := cluster.Recluster(); err != nil {
+			return c.JSON(http.StatusOK, setError(map[string]interface{}{"nodes": res}, errorCodeOf(err), "rack-id was set but recluster failed: "+err.Error()))
+		}
+		reclustered = true
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":      "success",
+		"reclustered": reclustered,
+		"nodes":       res,
+		"racks":       cluster.NamespaceRacks(c.Param("namespace")),
+	})
+}
@@ -0,0 +1,53 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/aerospike-community/amc/models"
+)
+
+// getClusterNamespaceRecordMetadata - the metadata of a record without its bins, by the set and the key,
+// a string unless key_type is integer, or by its digest in hex
+func getClusterNamespaceRecordMetadata(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	var userKey interface{} = c.QueryParam("key")
+	switch c.QueryParam("key_type") {
+	case "", "string":
+	case "integer":
+		n, err := strconv.ParseInt(c.QueryParam("key"), 10, 64)
+		if err != nil {
+			return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid key"))
+		}
+		userKey = n
+	default:
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid key_type"))
+	}
+
+	digest := c.QueryParam("digest")
+	if digest == "" && c.QueryParam("key") == "" {
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid parameters: key or digest is required"))
+	}
+
+	key, err := models.RecordKey(c.Param("namespace"), c.QueryParam("set"), userKey, digest)
+	if err != nil {
+		return c.JSON(http.StatusOK, errorMapOf(err))
+	}
+
+	metadata, err := cluster.RecordMetadata(key)
+	if err != nil {
+		return c.JSON(http.StatusOK, errorMapOf(err))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status": "success",
+		"record": metadata,
+	})
+}
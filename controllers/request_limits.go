@@ -0,0 +1,202 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+
+	"github.com/aerospike-community/amc/common"
+	"github.com/aerospike-community/amc/controllers/middleware/sessions"
+)
+
+const (
+	defaultMaxBodySize    = 10 // MB
+	defaultRequestTimeout = 30 * time.Second
+)
+
+// the routes which stream their responses: they are cancelled after their timeout, but not held back
+var _streamingRoutes = map[string]bool{
+	"/aerospike/service/clusters/:clusterUUID/namespaces/:namespace/sets/:setName/export": true,
+}
+
+// requestLimits are the maximum size of the request bodies and the time the requests may take
+type requestLimits struct {
+	maxBodySize   int
+	timeout       time.Duration
+	routeTimeouts map[string]time.Duration
+}
+
+func newRequestLimits(config *common.Config) *requestLimits {
+	l := &requestLimits{
+		maxBodySize:   config.AMC.MaxBodySize,
+		timeout:       time.Duration(config.AMC.RequestTimeout) * time.Second,
+		routeTimeouts: make(map[string]time.Duration, len(config.AMC.RouteTimeouts)),
+	}
+	if l.maxBodySize <= 0 {
+		l.maxBodySize = defaultMaxBodySize
+	}
+	if l.timeout <= 0 {
+		l.timeout = defaultRequestTimeout
+	}
+	for route, timeout := range config.AMC.RouteTimeouts {
+		l.routeTimeouts[route] = time.Duration(timeout) * time.Second
+	}
+	return l
+}
+
+// bodyLimit - reject the requests with a larger body with 413
+func (l *requestLimits) bodyLimit() echo.MiddlewareFunc {
+	return middleware.BodyLimit(fmt.Sprintf("%dM", l.maxBodySize))
+}
+
+// routeTimeout - the timeout of the route, by its path as registered; 0 for no timeout
+func (l *requestLimits) routeTimeout(path string) time.Duration {
+	if timeout, exists := l.routeTimeouts[path]; exists {
+		return timeout
+	}
+	return l.timeout
+}
+
+// longestTimeout - the longest time a request may take, for the write timeout of the server; 0 if a route has no timeout
+func (l *requestLimits) longestTimeout() time.Duration {
+	res := l.timeout
+	for _, timeout := range l.routeTimeouts {
+		if timeout <= 0 {
+			return 0
+		}
+		if timeout > res {
+			res = timeout
+		}
+	}
+	return res
+}
+
+// timeoutMiddleware - answer the requests which take longer than the timeout of their route with 504. The context of
+// the request is cancelled, but the handler is not stopped, so like with http.TimeoutHandler it runs with a context and
+// a response of its own: echo reuses the context of the request once the middleware returns, and whatever the handler
+// writes after the timeout is discarded. The WebSocket connections are not limited, and the streaming routes only
+// have their context cancelled.
+func (l *requestLimits) timeoutMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		timeout := l.routeTimeout(c.Path())
+		if timeout <= 0 || c.IsWebSocket() {
+			return next(c)
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request().Context(), timeout)
+		defer cancel()
+		if _streamingRoutes[c.Path()] {
+			c.SetRequest(c.Request().WithContext(ctx))
+			return next(c)
+		}
+
+		res := c.Response()
+		w := &timeoutResponseWriter{header: res.Header().Clone()}
+		handlerContext := isolatedContext(c, c.Request().WithContext(ctx), w)
+
+		done := make(chan error, 1)
+		panicked := make(chan interface{}, 1)
+		go func() {
+			defer func() {
+				if p := recover(); p != nil {
+					panicked <- p
+				}
+			}()
+			done <- next(handlerContext)
+		}()
+
+		select {
+		case err := <-done:
+			w.flush(res)
+			return err
+		case p := <-panicked:
+			panic(p)
+		case <-ctx.Done():
+			w.timeout()
+			requestLog(c).Warnf("%s %s timed out after %s", c.Request().Method, c.Request().URL.Path, timeout)
+			return c.JSON(http.StatusGatewayTimeout, errorMap(errTimeout, fmt.Sprintf("Request timed out after %s", timeout)))
+		}
+	}
+}
+
+// isolatedContext - a context for the handler of the request with its route, its params, the values the middlewares
+// set on the context, and the request and the response writer given
+func isolatedContext(c echo.Context, req *http.Request, w http.ResponseWriter) echo.Context {
+	res := c.Echo().NewContext(req, w)
+	res.SetPath(c.Path())
+	res.SetParamNames(append([]string(nil), c.ParamNames()...)...)
+	res.SetParamValues(c.ParamValues()...)
+	res.SetHandler(c.Handler())
+
+	if v := c.Get(_apiV1CtxMarker); v != nil {
+		res.Set(_apiV1CtxMarker, v)
+	}
+	if s, ok := c.Get(sessions.DefaultKey).(sessions.Session); ok {
+		res.Set(sessions.DefaultKey, sessions.WithWriter(s, w))
+	}
+	return res
+}
+
+// timeoutResponseWriter holds back the response until the handler returns, and discards it if the request timed out
+type timeoutResponseWriter struct {
+	mutex    sync.Mutex
+	header   http.Header
+	code     int
+	body     bytes.Buffer
+	timedOut bool
+}
+
+func (w *timeoutResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *timeoutResponseWriter) WriteHeader(code int) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if !w.timedOut && w.code == 0 {
+		w.code = code
+	}
+}
+
+func (w *timeoutResponseWriter) Write(b []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	if w.code == 0 {
+		w.code = http.StatusOK
+	}
+	return w.body.Write(b)
+}
+
+// Flush - responses are held back until the handler returns
+func (w *timeoutResponseWriter) Flush() {}
+
+func (w *timeoutResponseWriter) timeout() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.timedOut = true
+}
+
+// flush - write the response of the handler to the response of the request
+func (w *timeoutResponseWriter) flush(res *echo.Response) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	dst := res.Header()
+	for k, v := range w.header {
+		dst[k] = v
+	}
+	// nothing is written if the handler failed before writing, so that the error handler can set the status
+	if w.code != 0 {
+		res.WriteHeader(w.code)
+		res.Write(w.body.Bytes())
+	}
+}
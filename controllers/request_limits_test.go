@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Request timeout", func() {
+
+	const (
+		timeout     = 50 * time.Millisecond
+		exportRoute = "/aerospike/service/clusters/:clusterUUID/namespaces/:namespace/sets/:setName/export"
+	)
+
+	var (
+		e      *echo.Echo
+		limits *requestLimits
+	)
+
+	BeforeEach(func() {
+		limits = &requestLimits{timeout: timeout, routeTimeouts: map[string]time.Duration{"/unlimited": 0}}
+		e = echo.New()
+		e.Use(limits.timeoutMiddleware)
+	})
+
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// slowHandler - answer after the request is cancelled, or after twice the timeout if it is not;
+	// the error of the context of the request is sent to cancelled
+	slowHandler := func(cancelled chan<- error) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			select {
+			case <-c.Request().Context().Done():
+			case <-time.After(2 * timeout):
+			}
+			cancelled <- c.Request().Context().Err()
+			return c.String(http.StatusOK, "late")
+		}
+	}
+
+	It("answers a slow handler with 504 and the TIMEOUT code", func() {
+		cancelled := make(chan error, 1)
+		e.GET("/slow", slowHandler(cancelled))
+
+		start := time.Now()
+		rec := serve(httptest.NewRequest(http.MethodGet, "/slow", nil))
+		Expect(time.Since(start)).To(BeNumerically("<", 2*timeout))
+		Expect(rec.Code).To(Equal(http.StatusGatewayTimeout))
+
+		res := map[string]interface{}{}
+		Expect(json.Unmarshal(rec.Body.Bytes(), &res)).To(Succeed())
+		Expect(res).To(HaveKeyWithValue("status", "failure"))
+		Expect(res).To(HaveKeyWithValue("error_code", string(errTimeout)))
+		Expect(rec.Body.String()).NotTo(ContainSubstring("late"))
+		Eventually(cancelled).Should(Receive(MatchError(context.DeadlineExceeded)))
+	})
+
+	It("passes the response of a fast handler through", func() {
+		e.GET("/fast", func(c echo.Context) error {
+			c.Response().Header().Set("X-Test", "yes")
+			return c.String(http.StatusCreated, "done")
+		})
+
+		rec := serve(httptest.NewRequest(http.MethodGet, "/fast", nil))
+		Expect(rec.Code).To(Equal(http.StatusCreated))
+		Expect(rec.Header().Get("X-Test")).To(Equal("yes"))
+		Expect(rec.Body.String()).To(Equal("done"))
+	})
+
+	It("does not limit the routes with a timeout of 0", func() {
+		cancelled := make(chan error, 1)
+		e.GET("/unlimited", slowHandler(cancelled))
+
+		rec := serve(httptest.NewRequest(http.MethodGet, "/unlimited", nil))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(Equal("late"))
+		Expect(cancelled).To(Receive(BeNil()))
+	})
+
+	It("exempts the WebSocket connections", func() {
+		cancelled := make(chan error, 1)
+		e.GET("/ws", slowHandler(cancelled))
+
+		req := httptest.NewRequest(http.MethodGet, "/ws", nil)
+		req.Header.Set(echo.HeaderUpgrade, "websocket")
+		req.Header.Set("Connection", "Upgrade")
+
+		rec := serve(req)
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(Equal("late"))
+		Expect(cancelled).To(Receive(BeNil()))
+	})
+
+	It("streams the responses of the streaming routes and only cancels them", func() {
+		var cancelled error
+		e.GET(exportRoute, func(c echo.Context) error {
+			c.Response().WriteHeader(http.StatusOK)
+			c.Response().Write([]byte("first\n"))
+			c.Response().Flush()
+
+			<-c.Request().Context().Done()
+			cancelled = c.Request().Context().Err()
+			return nil
+		})
+
+		rec := serve(httptest.NewRequest(http.MethodGet, "/aerospike/service/clusters/c1/namespaces/test/sets/demo/export", nil))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Flushed).To(BeTrue())
+		Expect(rec.Body.String()).To(Equal("first\n"))
+		Expect(cancelled).To(MatchError(context.DeadlineExceeded))
+	})
+})
@@ -0,0 +1,29 @@
+package controllers
+
+import (
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// requestLogger - attach a logger with the id of the request to its context, so that the logs
+// of the request and of the model operations it starts can be correlated; the id is set by the
+// RequestID middleware, from the X-Request-ID header of the request if the client sent one
+func requestLogger(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		fields := log.Fields{"request_id": c.Response().Header().Get(echo.HeaderXRequestID)}
+		if clusterUUID := c.Param("clusterUUID"); clusterUUID != "" {
+			fields["cluster_id"] = clusterUUID
+		}
+
+		req := c.Request()
+		c.SetRequest(req.WithContext(common.WithLogger(req.Context(), log.WithFields(fields))))
+		return next(c)
+	}
+}
+
+// requestLog - get the logger of the request
+func requestLog(c echo.Context) *log.Entry {
+	return common.Logger(c.Request().Context())
+}
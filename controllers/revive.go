@@ -0,0 +1,67 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+func getClusterNamespacePartitionState(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	res := cluster.NamespacePartitionState(c.Param("namespace"))
+	if len(res) == 0 {
+		return c.JSON(http.StatusOK, errorMap(errNamespaceNotFound, "Namespace not found"))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status": "success",
+		"nodes":  res,
+	})
+}
+
+func postClusterNamespaceRevive(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	namespace := c.Param("namespace")
+
+	// the namespace name has to be repeated to confirm the revive
+	if c.FormValue("confirm") != namespace {
+		return c.JSON(http.StatusOK, setError(map[string]interface{}{"nodes": cluster.NamespacePartitionState(namespace)},
+			errInvalidParameter, "Reviving dead partitions may restore stale data. Repeat the namespace name in `confirm` to proceed"))
+	}
+
+	before := cluster.NamespacePartitionState(namespace)
+	errs, err := cluster.ReviveNamespace(namespace)
+
+	nodes := map[string]interface{}{}
+	for node, nodeErr := range errs {
+		nodeRes := map[string]interface{}{
+			"node_status": node.Status(),
+			"status":      "success",
+		}
+		if nodeErr != nil {
+			nodeRes["status"] = "failure"
+			nodeRes["error"] = nodeErr.Error()
+		}
+		nodes[node.Address()] = nodeRes
+	}
+
+	if err != nil {
+		return c.JSON(http.StatusOK, setErrorOf(map[string]interface{}{"before": before, "nodes": nodes}, err))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status": "success",
+		"before": before,
+		"nodes":  nodes,
+	})
+}
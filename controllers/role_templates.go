@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+func getRoleTemplates(c echo.Context) error {
+	templates, err := common.RoleTemplates()
+	if err != nil {
+		return c.JSON(http.StatusOK, errorMapOf(err))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":    "success",
+		"templates": templates,
+	})
+}
+
+func postRoleTemplate(c echo.Context) error {
+	form := struct {
+		Name       string `form:"name"`
+		Privileges string `form:"privileges"`
+		Whitelist  string `form:"whitelist"`
+	}{}
+
+	c.Bind(&form)
+	form.Name = strings.TrimSpace(form.Name)
+	if len(form.Name) == 0 {
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid template name"))
+	}
+
+	privileges := common.SplitList(form.Privileges)
+	if len(privileges) == 0 {
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "At least one privilege is required"))
+	}
+
+	for _, p := range privileges {
+		if privilegeFromString(strings.SplitN(p, ".", 2)[0]) == nil {
+			return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid privilege: "+p))
+		}
+	}
+
+	whitelist := common.SplitList(form.Whitelist)
+	for _, addr := range whitelist {
+		if net.ParseIP(addr) == nil {
+			if _, _, err := net.ParseCIDR(addr); err != nil {
+				return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid whitelist address: "+addr))
+			}
+		}
+	}
+
+	template, err := common.RoleTemplateByName(form.Name)
+	if err != nil {
+		return c.JSON(http.StatusOK, errorMapOf(err))
+	}
+
+	if template == nil {
+		template = common.NewRoleTemplate(form.Name, privileges, whitelist)
+	} else {
+		template.Privileges = privileges
+		template.Whitelist = whitelist
+	}
+
+	if err := template.Save(); err != nil {
+		return c.JSON(http.StatusOK, errorMapOf(err))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":   "success",
+		"template": template,
+	})
+}
+
+func postDeleteRoleTemplate(c echo.Context) error {
+	template, err := common.RoleTemplateByName(c.Param("template"))
+	if err != nil {
+		return c.JSON(http.StatusOK, errorMapOf(err))
+	}
+
+	if template == nil {
+		return c.JSON(http.StatusOK, errorMap(errNotFound, "Role template not found"))
+	}
+
+	if err := template.Delete(); err != nil {
+		return c.JSON(http.StatusOK, errorMapOf(err))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status": "success",
+	})
+}
+
+func postClusterApplyRoleTemplate(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	template, err := common.RoleTemplateByName(c.Param("template"))
+	if err != nil {
+		return c.JSON(http.StatusOK, errorMapOf(err))
+	}
+
+	if template == nil {
+		return c.JSON(http.StatusOK, errorMap(errNotFound, "Role template not found"))
+	}
+
+	// the role name defaults to the template name
+	role := strings.TrimSpace(c.FormValue("role"))
+	if role == "" {
+		role = template.Name
+	}
+
+	privileges := parsePrivilegeString(strings.Join(template.Privileges, ","))
+	action, err := cluster.ApplyRole(role, privileges, template.Whitelist)
+	if err != nil {
+		return c.JSON(http.StatusOK, errorMapOf(err))
+	}
+
+	// wait for different nodes to sync up
+	if action != "unchanged" {
+		time.Sleep(1 * time.Second)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status": "success",
+		"role":   role,
+		"action": action,
+	})
+}
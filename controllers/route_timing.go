@@ -0,0 +1,155 @@
+package controllers
+
+import (
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// the number of the latest response times of every route the percentiles are computed from
+const _routeTimingSamples = 1000
+
+// routeTiming - the response times of a route
+type routeTiming struct {
+	count   int64
+	slow    int64
+	total   time.Duration
+	max     time.Duration
+	samples []time.Duration // a ring of the latest response times
+	next    int
+}
+
+// RouteTimingStats - the aggregates of the response times of a route, in milliseconds;
+// the percentiles are those of its latest requests
+type RouteTimingStats struct {
+	Method string  `json:"method"`
+	Route  string  `json:"route"`
+	Count  int64   `json:"count"`
+	Slow   int64   `json:"slow"`
+	MeanMs float64 `json:"mean_ms"`
+	P50Ms  float64 `json:"p50_ms"`
+	P90Ms  float64 `json:"p90_ms"`
+	P99Ms  float64 `json:"p99_ms"`
+	MaxMs  float64 `json:"max_ms"`
+}
+
+// routeTimings - the response times of the routes by their method and path as registered
+type routeTimings struct {
+	mutex  sync.Mutex
+	routes map[[2]string]*routeTiming
+}
+
+var _routeTimings = &routeTimings{routes: map[[2]string]*routeTiming{}}
+
+// middleware - time the requests by their route, and log those slower than slow_request_threshold;
+// registered after requestLogger, so that the slow requests are logged with their id
+func (t *routeTimings) middleware(config *common.Config) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			err := next(c)
+			elapsed := time.Since(start)
+
+			// the paths which are not routed are not tracked, so that the requests cannot add routes
+			route := c.Path()
+			if he, ok := err.(*echo.HTTPError); route == "" || ok && (he.Code == http.StatusNotFound || he.Code == http.StatusMethodNotAllowed) {
+				return err
+			}
+
+			threshold := config.SlowRequestThreshold()
+			slow := threshold > 0 && elapsed >= threshold
+			t.record(c.Request().Method, route, elapsed, slow)
+
+			if slow {
+				status := c.Response().Status
+				if he, ok := err.(*echo.HTTPError); ok {
+					status = he.Code
+				} else if err != nil {
+					status = http.StatusInternalServerError
+				}
+				requestLog(c).WithFields(log.Fields{
+					"method":     c.Request().Method,
+					"route":      route,
+					"path":       c.Request().URL.RequestURI(),
+					"status":     status,
+					"latency_ms": float64(elapsed.Microseconds()) / 1000,
+				}).Warn("Slow request")
+			}
+			return err
+		}
+	}
+}
+
+func (t *routeTimings) record(method, route string, elapsed time.Duration, slow bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	key := [2]string{method, route}
+	rt := t.routes[key]
+	if rt == nil {
+		rt = &routeTiming{}
+		t.routes[key] = rt
+	}
+
+	rt.count++
+	rt.total += elapsed
+	if elapsed > rt.max {
+		rt.max = elapsed
+	}
+	if slow {
+		rt.slow++
+	}
+
+	if len(rt.samples) < _routeTimingSamples {
+		rt.samples = append(rt.samples, elapsed)
+	} else {
+		rt.samples[rt.next] = elapsed
+		rt.next = (rt.next + 1) % _routeTimingSamples
+	}
+}
+
+// stats - the aggregates of the routes, the slowest by their 99th percentile first
+func (t *routeTimings) stats() []RouteTimingStats {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	ms := func(d time.Duration) float64 { return float64(d.Microseconds()) / 1000 }
+
+	res := make([]RouteTimingStats, 0, len(t.routes))
+	for key, rt := range t.routes {
+		samples := make([]time.Duration, len(rt.samples))
+		copy(samples, rt.samples)
+		sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+		// the nearest-rank percentile
+		percentile := func(p float64) time.Duration {
+			return samples[int(math.Ceil(p*float64(len(samples))))-1]
+		}
+
+		res = append(res, RouteTimingStats{
+			Method: key[0],
+			Route:  key[1],
+			Count:  rt.count,
+			Slow:   rt.slow,
+			MeanMs: ms(rt.total / time.Duration(rt.count)),
+			P50Ms:  ms(percentile(.5)),
+			P90Ms:  ms(percentile(.9)),
+			P99Ms:  ms(percentile(.99)),
+			MaxMs:  ms(rt.max),
+		})
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].P99Ms != res[j].P99Ms {
+			return res[i].P99Ms > res[j].P99Ms
+		}
+		return res[i].Route < res[j].Route
+	})
+	return res
+}
@@ -2,11 +2,12 @@ package controllers
 
 import (
 	"errors"
+	"net"
 	"net/http"
+	"strings"
 
 	"github.com/labstack/echo/v4"
 	uuid "github.com/satori/go.uuid"
-	log "github.com/sirupsen/logrus"
 
 	"github.com/aerospike-community/amc/common"
 	"github.com/aerospike-community/amc/controllers/middleware/sessions"
@@ -17,13 +18,85 @@ func sessionValidator(f func(c echo.Context) error) func(c echo.Context) error {
 		sid, err := sessionID(c)
 		if err != nil || !_observer.SessionExists(sid) {
 			invalidateSession(c)
-			return c.JSON(http.StatusUnauthorized, errorMap("invalid session : None"))
+			return c.JSON(http.StatusUnauthorized, errorMap(errInvalidSession, "invalid session : None"))
 		}
+		_observer.TouchSession(sid, c.RealIP())
 
 		return f(c)
 	}
 }
 
+// isLocalRequest - the request comes from the AMC host; the address of the connection is used,
+// since forwarding headers can be set by the client. Behind a local reverse proxy every connection
+// is from the loopback, so the address the proxy forwarded the request for has to be local as well.
+func isLocalRequest(c echo.Context) bool {
+	req := c.Request()
+	host, _, _ := net.SplitHostPort(req.RemoteAddr)
+	if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+		return false
+	}
+
+	if !_observer.Config().ReverseProxy() {
+		return true
+	}
+
+	// the proxy sets X-Real-IP or appends the address of its client to X-Forwarded-For; the one it does not
+	// set may come from the client, so the request is only local if both are
+	forwardedFor := []string{req.Header.Get(echo.HeaderXRealIP)}
+	if xff := req.Header.Values(echo.HeaderXForwardedFor); len(xff) > 0 {
+		addrs := strings.Split(xff[len(xff)-1], ",")
+		forwardedFor = append(forwardedFor, addrs[len(addrs)-1])
+	}
+
+	for _, addr := range forwardedFor {
+		// not forwarded by the proxy
+		if addr = strings.TrimSpace(addr); addr == "" {
+			continue
+		}
+
+		if ip := net.ParseIP(addr); ip == nil || !ip.IsLoopback() {
+			return false
+		}
+	}
+	return true
+}
+
+// getSessions - the active sessions of the users; only allowed from the AMC host itself
+func getSessions(c echo.Context) error {
+	if !isLocalRequest(c) {
+		return c.JSON(http.StatusForbidden, errorMap(errInsufficientPrivileges, "The sessions can only be administered from the AMC host"))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":   "success",
+		"sessions": _observer.Sessions(),
+	})
+}
+
+// deleteSession - revoke a session; only allowed from the AMC host itself
+func deleteSession(c echo.Context) error {
+	if !isLocalRequest(c) {
+		return c.JSON(http.StatusForbidden, errorMap(errInsufficientPrivileges, "The sessions can only be administered from the AMC host"))
+	}
+
+	if !_observer.RevokeSession(c.Request().Context(), c.Param("sessionID")) {
+		return c.JSON(http.StatusOK, errorMap(errNotFound, "Session not found"))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status": "success",
+	})
+}
+
+// requestUser - the AMC user of the request, recorded with the changes it makes: the basic auth user,
+// or the address of the request if AMC does not authenticate its users
+func requestUser(c echo.Context) string {
+	if user, _, ok := c.Request().BasicAuth(); ok && user != "" {
+		return user
+	}
+	return c.RealIP()
+}
+
 func sessionID(c echo.Context) (string, error) {
 	session := sessions.Default(c)
 	id := session.Get("id")
@@ -52,7 +125,7 @@ func invalidateSession(c echo.Context) {
 	session := sessions.Default(c)
 	session.Clear()
 	if err := session.Save(); err != nil {
-		log.Error(err)
+		requestLog(c).Error(err)
 	}
 }
 
@@ -75,7 +148,7 @@ func setSession(c echo.Context) string {
 	session.Clear()
 	session.Set("id", sid)
 	if err := session.Save(); err != nil {
-		log.Error(err)
+		requestLog(c).Error(err)
 	}
 
 	return sid
@@ -0,0 +1,32 @@
+package controllers
+
+import (
+	"net/http/httptest"
+
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Request user", func() {
+	var e *echo.Echo
+
+	BeforeEach(func() {
+		e = echo.New()
+	})
+
+	It("is the basic auth user", func() {
+		req := httptest.NewRequest("POST", "/", nil)
+		req.SetBasicAuth("admin", "secret")
+		req.RemoteAddr = "10.0.0.5:41234"
+
+		Expect(requestUser(e.NewContext(req, httptest.NewRecorder()))).To(Equal("admin"))
+	})
+
+	It("is the address of the request without basic auth", func() {
+		req := httptest.NewRequest("POST", "/", nil)
+		req.RemoteAddr = "10.0.0.5:41234"
+
+		Expect(requestUser(e.NewContext(req, httptest.NewRecorder()))).To(Equal("10.0.0.5"))
+	})
+})
@@ -0,0 +1,155 @@
+package controllers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/aerospike-community/amc/common"
+	"github.com/aerospike-community/amc/models"
+)
+
+const mimeNDJSON = "application/x-ndjson"
+
+// the records written between the flushes of an export
+const _exportFlushInterval = 100
+
+// getClusterNamespaceSetExport - stream the records of the set as newline delimited JSON (format=json, the default)
+// or CSV (format=csv); limit is the number of the records (all by default), filter an expression the records
+// must match and bins a comma separated list of the bins exported (all by default). The CSV columns are the bins
+// of the first record unless bins is given.
+func getClusterNamespaceSetExport(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	format := c.QueryParam("format")
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "csv" {
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid format"))
+	}
+
+	limit := 0
+	if v := c.QueryParam("limit"); v != "" {
+		var err error
+		if limit, err = strconv.Atoi(v); err != nil || limit < 0 {
+			return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid limit"))
+		}
+	}
+
+	filter, err := queryFilter(c)
+	if err != nil {
+		return c.JSON(http.StatusOK, errorMapOf(err))
+	}
+
+	namespace, set := c.Param("namespace"), c.Param("setName")
+	bins := common.SplitList(c.QueryParam("bins"))
+
+	// the response is started with the first record, so that the errors before it are answered as usual
+	res := c.Response()
+	started := false
+	start := func() {
+		started = true
+		contentType, ext := mimeNDJSON, "ndjson"
+		if format == "csv" {
+			contentType, ext = "text/csv", "csv"
+		}
+		filename := fmt.Sprintf("%s-%s.%s", reportFilenamePart(namespace), reportFilenamePart(set), ext)
+		res.Header().Set(echo.HeaderContentType, contentType)
+		res.Header().Set(echo.HeaderContentDisposition, `attachment; filename="`+filename+`"`)
+		res.WriteHeader(http.StatusOK)
+	}
+
+	encoder := json.NewEncoder(res)
+	w := csv.NewWriter(res)
+	var columns []string
+
+	count := 0
+	err = cluster.ExportSet(c.Request().Context(), namespace, set, limit, bins, filter, func(r models.SampleRecord) error {
+		if !started {
+			start()
+		}
+
+		if format == "json" {
+			if err := encoder.Encode(r); err != nil {
+				return err
+			}
+		} else {
+			if columns == nil {
+				columns = exportColumns(bins, r)
+				w.Write(append([]string{"digest", "key", "generation", "ttl"}, columns...))
+			}
+			row := []string{r.Digest, csvCell(r.Key), strconv.FormatUint(uint64(r.Generation), 10), strconv.FormatUint(uint64(r.TTL), 10)}
+			for _, bin := range columns {
+				row = append(row, csvCell(r.Bins[bin]))
+			}
+			if err := w.Write(row); err != nil {
+				return err
+			}
+		}
+
+		count++
+		if count%_exportFlushInterval == 0 {
+			w.Flush()
+			res.Flush()
+		}
+		return nil
+	})
+
+	if err != nil && !started {
+		return c.JSON(http.StatusOK, errorMapOf(err))
+	}
+	if !started {
+		start()
+		if format == "csv" {
+			w.Write(append([]string{"digest", "key", "generation", "ttl"}, bins...))
+		}
+	}
+	w.Flush()
+
+	// the status has been sent; the export ends short, with an error line in JSON
+	if err != nil {
+		requestLog(c).WithField("records", count).Warnf("Export of %s.%s failed: %s", namespace, set, err)
+		if format == "json" {
+			encoder.Encode(errorMapOf(err))
+		}
+	}
+	return nil
+}
+
+// exportColumns - the bins of the CSV export: those requested, or else those of the first record sorted
+func exportColumns(bins []string, r models.SampleRecord) []string {
+	if len(bins) > 0 {
+		return bins
+	}
+	res := make([]string, 0, len(r.Bins))
+	for bin := range r.Bins {
+		res = append(res, bin)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// csvCell - a value in a CSV cell: the strings as they are, and the other values but nil in JSON
+func csvCell(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
@@ -0,0 +1,58 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/aerospike-community/amc/models"
+)
+
+// getClusterNamespaceSetProfile - the names, the types and the average sizes of the bins of a sample of the set;
+// limit is the number of the records sampled (1000 by default), sample_pct the percent of the partitions
+// scanned (1 by default) and filter an expression the records sampled must match
+func getClusterNamespaceSetProfile(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	limit := models.SetProfileDefaultLimit
+	if v := c.QueryParam("limit"); v != "" {
+		var err error
+		if limit, err = strconv.Atoi(v); err != nil {
+			return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid limit"))
+		}
+	}
+
+	samplePct := float64(1)
+	if v := c.QueryParam("sample_pct"); v != "" {
+		var err error
+		if samplePct, err = strconv.ParseFloat(v, 64); err != nil {
+			return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid sample_pct"))
+		}
+	}
+
+	filter, err := queryFilter(c)
+	if err != nil {
+		return c.JSON(http.StatusOK, errorMapOf(err))
+	}
+
+	namespace, set := c.Param("namespace"), c.Param("setName")
+	profile, err := cluster.ProfileSet(c.Request().Context(), namespace, set, limit, samplePct, filter)
+	if err != nil {
+		return c.JSON(http.StatusOK, errorMapOf(err))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":          "success",
+		"namespace":       namespace,
+		"set":             set,
+		"sample_pct":      samplePct,
+		"sampled_records": profile.SampledRecords,
+		"avg_bin_count":   profile.AvgBinCount,
+		"bins":            profile.Bins,
+	})
+}
@@ -0,0 +1,67 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/aerospike-community/amc/common"
+	"github.com/aerospike-community/amc/models"
+)
+
+// getClusterNamespaceSetSample - the first records of a scan of the set, to see what data it contains;
+// limit is the number of the records (10 by default), sample_pct the percent of the partitions scanned
+// (100 by default), filter an expression the records must match (see models.RecordFilter) and bins a comma
+// separated list of the bins returned (all by default)
+func getClusterNamespaceSetSample(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	limit := models.SetSampleDefaultLimit
+	if v := c.QueryParam("limit"); v != "" {
+		var err error
+		if limit, err = strconv.Atoi(v); err != nil {
+			return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid limit"))
+		}
+	}
+
+	samplePct := float64(100)
+	if v := c.QueryParam("sample_pct"); v != "" {
+		var err error
+		if samplePct, err = strconv.ParseFloat(v, 64); err != nil {
+			return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid sample_pct"))
+		}
+	}
+
+	filter, err := queryFilter(c)
+	if err != nil {
+		return c.JSON(http.StatusOK, errorMapOf(err))
+	}
+
+	namespace, set := c.Param("namespace"), c.Param("setName")
+	records, err := cluster.SampleSet(c.Request().Context(), namespace, set, limit, samplePct, filter, common.SplitList(c.QueryParam("bins"))...)
+	if err != nil {
+		return c.JSON(http.StatusOK, errorMapOf(err))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":       "success",
+		"namespace":    namespace,
+		"set":          set,
+		"sample_pct":   samplePct,
+		"record_count": len(records),
+		"records":      records,
+	})
+}
+
+// queryFilter - the record filter of the filter query param, nil if there is none
+func queryFilter(c echo.Context) (*models.RecordFilter, error) {
+	if v := c.QueryParam("filter"); v != "" {
+		return models.ParseRecordFilter(v)
+	}
+	return nil, nil
+}
@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/aerospike-community/amc/common"
+	"github.com/aerospike-community/amc/models"
+)
+
+// the default timeout of the sindex queries, in seconds
+const _sindexQueryTimeout = 10
+
+// getClusterNamespaceSindexQuery - the records matching a query on the index: equal to value, or between begin
+// and end on the numeric indexes, and filter if given; limit is the number of the records (10 by default),
+// timeout in seconds (10 by default) and bins a comma separated list of the bins returned (all by default)
+func getClusterNamespaceSindexQuery(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	q := models.SindexQuery{
+		Value:   c.QueryParam("value"),
+		Limit:   models.SetSampleDefaultLimit,
+		Timeout: _sindexQueryTimeout * time.Second,
+		Bins:    common.SplitList(c.QueryParam("bins")),
+	}
+
+	if v := c.QueryParam("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid limit"))
+		}
+		q.Limit = limit
+	}
+
+	if v := c.QueryParam("timeout"); v != "" {
+		timeout, err := strconv.Atoi(v)
+		if err != nil || timeout <= 0 {
+			return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid timeout"))
+		}
+		q.Timeout = time.Duration(timeout) * time.Second
+	}
+
+	for param, bound := range map[string]**int64{"begin": &q.Begin, "end": &q.End} {
+		if v := c.QueryParam(param); v != "" {
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid "+param))
+			}
+			*bound = &n
+		}
+	}
+
+	filter, err := queryFilter(c)
+	if err != nil {
+		return c.JSON(http.StatusOK, errorMapOf(err))
+	}
+	q.Filter = filter
+
+	namespace, index := c.Param("namespace"), c.Param("sindex")
+	records, err := cluster.QuerySindex(c.Request().Context(), namespace, index, q)
+	if err != nil {
+		return c.JSON(http.StatusOK, errorMapOf(err))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":       "success",
+		"namespace":    namespace,
+		"sindex":       index,
+		"record_count": len(records),
+		"records":      records,
+	})
+}
@@ -0,0 +1,40 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+func getClusterStability(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	expectedSize := 0
+	if sizeStr := c.QueryParam("expected_size"); sizeStr != "" {
+		var err error
+		if expectedSize, err = strconv.Atoi(sizeStr); err != nil || expectedSize < 1 {
+			return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Wrong expected_size param specified."))
+		}
+	}
+
+	check := cluster.CheckStability(expectedSize)
+	status := "pass"
+	if !check.Stable {
+		status = "fail"
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":       "success",
+		"result":       status,
+		"stable":       check.Stable,
+		"reasons":      check.Reasons,
+		"cluster_keys": check.ClusterKeys,
+		"cluster_size": check.ClusterSize,
+		"migrations":   check.Migrations,
+	})
+}
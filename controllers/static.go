@@ -0,0 +1,174 @@
+package controllers
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// the precompressed variants of the static files, in the order of preference
+var _staticEncodings = []struct {
+	encoding string
+	ext      string
+}{
+	{"br", ".br"},
+	{"gzip", ".gz"},
+}
+
+// staticFiles serves the UI files with validators and cache headers, and the precompressed
+// variants of the files to the browsers accepting them
+type staticFiles struct {
+	root   string
+	config *common.Config
+}
+
+// register - register the routes like echo.Static
+func (s staticFiles) register(e *echo.Echo, prefix string) {
+	if prefix[len(prefix)-1] == '/' {
+		e.GET(prefix+"*", s.serve)
+		return
+	}
+	e.GET(prefix, s.serve)
+	e.GET(prefix+"/*", s.serve)
+}
+
+// isStatic - check whether the route of the request is a static route
+func isStatic(c echo.Context) bool {
+	return c.Path() == "/static" || strings.HasSuffix(c.Path(), "/*")
+}
+
+// resolve - get the file requested; directories are served by their index.html
+func (s staticFiles) resolve(c echo.Context) (name string, fi os.FileInfo, isDir bool, err error) {
+	p, err := url.PathUnescape(c.Param("*"))
+	if err != nil {
+		return "", nil, false, err
+	}
+
+	name = filepath.Join(s.root, filepath.Clean("/"+p)) // "/"+ for security
+	if fi, err = os.Stat(name); err != nil {
+		return "", nil, false, err
+	}
+
+	if fi.IsDir() {
+		name = filepath.Join(name, "index.html")
+		if fi, err = os.Stat(name); err != nil {
+			return "", nil, true, err
+		}
+		return name, fi, true, nil
+	}
+
+	return name, fi, false, nil
+}
+
+// precompressed - get the precompressed variant of the file accepted by the client, if there is one
+func (s staticFiles) precompressed(c echo.Context, name string) (string, string, os.FileInfo) {
+	if !s.config.AMC.StaticPrecompressed {
+		return "", "", nil
+	}
+
+	accepted := acceptedEncodings(c.Request().Header.Get(echo.HeaderAcceptEncoding))
+	for _, enc := range _staticEncodings {
+		if !accepted[enc.encoding] {
+			continue
+		}
+		if fi, err := os.Stat(name + enc.ext); err == nil && !fi.IsDir() {
+			return enc.encoding, name + enc.ext, fi
+		}
+	}
+
+	return "", "", nil
+}
+
+// skipGzip - the precompressed variants must not be compressed again by the gzip middleware
+func (s staticFiles) skipGzip(c echo.Context) bool {
+	if !s.config.AMC.StaticPrecompressed || !isStatic(c) {
+		return false
+	}
+
+	name, _, _, err := s.resolve(c)
+	if err != nil {
+		return false
+	}
+
+	encoding, _, _ := s.precompressed(c, name)
+	return encoding != ""
+}
+
+func (s staticFiles) serve(c echo.Context) error {
+	name, fi, isDir, err := s.resolve(c)
+	if err != nil {
+		return echo.NotFoundHandler(c)
+	}
+
+	// If the request is for a directory and does not end with "/"
+	if p := c.Request().URL.Path; isDir && p[len(p)-1] != '/' {
+		return c.Redirect(http.StatusMovedPermanently, p+"/")
+	}
+
+	header := c.Response().Header()
+	if ctype := mime.TypeByExtension(filepath.Ext(name)); ctype != "" {
+		header.Set(echo.HeaderContentType, ctype)
+	}
+
+	file := name
+	if s.config.AMC.StaticPrecompressed {
+		if !strings.Contains(strings.Join(header.Values(echo.HeaderVary), ","), echo.HeaderAcceptEncoding) {
+			header.Add(echo.HeaderVary, echo.HeaderAcceptEncoding)
+		}
+		if encoding, variant, variantInfo := s.precompressed(c, name); encoding != "" {
+			header.Set(echo.HeaderContentEncoding, encoding)
+			file, fi = variant, variantInfo
+		}
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return echo.NotFoundHandler(c)
+	}
+	defer f.Close()
+
+	// the validators differ between the variants, since their contents differ
+	header.Set("ETag", fmt.Sprintf(`"%x-%x"`, fi.ModTime().UnixNano(), fi.Size()))
+	header.Set("Cache-Control", s.cacheControl(name))
+
+	http.ServeContent(c.Response(), c.Request(), filepath.Base(name), fi.ModTime(), f)
+	return nil
+}
+
+// cacheControl - the pages are always revalidated, so that new releases of the UI are picked up;
+// the scripts and the other assets may be cached for static_max_age seconds
+func (s staticFiles) cacheControl(name string) string {
+	if maxAge := s.config.AMC.StaticMaxAge; maxAge > 0 && filepath.Ext(name) != ".html" {
+		return fmt.Sprintf("public, max-age=%d", maxAge)
+	}
+	return "no-cache"
+}
+
+// acceptedEncodings - parse the Accept-Encoding header; the encodings with q=0 are not accepted
+func acceptedEncodings(header string) map[string]bool {
+	res := map[string]bool{}
+	for _, part := range strings.Split(header, ",") {
+		params := strings.Split(part, ";")
+		encoding := strings.ToLower(strings.TrimSpace(params[0]))
+		if encoding == "" {
+			continue
+		}
+
+		accepted := true
+		for _, param := range params[1:] {
+			if q := strings.TrimSpace(param); strings.HasPrefix(q, "q=") {
+				accepted = strings.Trim(strings.TrimPrefix(q, "q="), "0.") != ""
+			}
+		}
+		res[encoding] = accepted
+	}
+	return res
+}
@@ -0,0 +1,64 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+func getClusterStopWritesPrediction(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	// the growth rate is calculated over the last hour by default
+	window := time.Hour
+	if windowStr := c.QueryParam("window"); windowStr != "" {
+		mins, err := strconv.Atoi(windowStr)
+		if err != nil || mins < 1 {
+			return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Wrong window param specified."))
+		}
+		window = time.Duration(mins) * time.Minute
+	}
+
+	predictions := cluster.PredictStopWrites(window)
+	if namespace := c.QueryParam("namespace"); namespace != "" {
+		if predictions[namespace] == nil {
+			return c.JSON(http.StatusOK, errorMap(errNamespaceNotFound, "Namespace not found"))
+		}
+
+		for name := range predictions {
+			if name != namespace {
+				delete(predictions, name)
+			}
+		}
+	}
+
+	res := map[string]interface{}{}
+	for name, nodes := range predictions {
+		var stopWritesAt *int64
+		stopWrites := false
+		for _, p := range nodes {
+			stopWrites = stopWrites || p.StopWrites
+			if p.StopWritesAt != nil && (stopWritesAt == nil || *p.StopWritesAt < *stopWritesAt) {
+				stopWritesAt = p.StopWritesAt
+			}
+		}
+
+		res[name] = map[string]interface{}{
+			"stop_writes":    stopWrites,
+			"stop_writes_at": stopWritesAt,
+			"nodes":          nodes,
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":         "success",
+		"window_minutes": int(window.Minutes()),
+		"namespaces":     res,
+	})
+}
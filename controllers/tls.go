@@ -0,0 +1,221 @@
+package controllers
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// how often the certificate files are checked for changes
+const _certCheckInterval = 30 * time.Second
+
+var _tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// the TLS config of the servers is built once, and shared by the web and the gRPC servers
+var _serverTLS struct {
+	once   sync.Once
+	config *tls.Config
+	err    error
+}
+
+// serverTLSConfig - get the TLS config of the servers; the certificate is obtained with ACME,
+// or read from the certificate files and reloaded when they change
+func serverTLSConfig(config *common.Config) (*tls.Config, error) {
+	_serverTLS.once.Do(func() {
+		_serverTLS.config, _serverTLS.err = newServerTLSConfig(config)
+	})
+	return _serverTLS.config, _serverTLS.err
+}
+
+func newServerTLSConfig(config *common.Config) (*tls.Config, error) {
+	tlsConfig := &tls.Config{}
+	if len(config.AMC.ACMEDomains) > 0 {
+		manager := acmeManager(config)
+		tlsConfig.GetCertificate = manager.GetCertificate
+		tlsConfig.NextProtos = append(tlsConfig.NextProtos, acme.ALPNProto)
+
+		if config.AMC.ACMEHTTPBind != "" {
+			go serveACMEChallenges(config.AMC.ACMEHTTPBind, manager)
+		}
+	} else {
+		reloader, err := newCertReloader(config.AMC.CertFile, config.AMC.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.GetCertificate = reloader.getCertificate
+		go reloader.watch()
+	}
+
+	if config.AMC.ForceTLS12 || config.AMC.MaxTLSSecurity {
+		log.Infof("Forcing TLS v1.2")
+		tlsConfig.MinVersion = tls.VersionTLS12
+	}
+
+	if config.AMC.TLSMinVersion != "" {
+		version, exists := _tlsVersions[strings.TrimPrefix(config.AMC.TLSMinVersion, "v")]
+		if !exists {
+			return nil, fmt.Errorf("Invalid tls_min_version %s; must be one of 1.0, 1.1, 1.2 or 1.3", config.AMC.TLSMinVersion)
+		}
+		log.Infof("Minimum TLS version: %s", config.AMC.TLSMinVersion)
+		tlsConfig.MinVersion = version
+	}
+
+	if config.AMC.MaxTLSSecurity {
+		log.Infof("Forcing Maximum security mode for TLS (Uses >=256 bit curves, ciphersuites and prefers server cypher suites)")
+		tlsConfig.CurvePreferences = []tls.CurveID{tls.CurveP521, tls.CurveP384, tls.CurveP256}
+		tlsConfig.PreferServerCipherSuites = true
+		tlsConfig.CipherSuites = []uint16{
+			tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA,
+			tls.TLS_RSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_RSA_WITH_AES_256_CBC_SHA,
+		}
+	}
+
+	if len(config.AMC.TLSCipherSuites) > 0 {
+		suites, err := cipherSuites(config.AMC.TLSCipherSuites)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.CipherSuites = suites
+	}
+
+	return tlsConfig, nil
+}
+
+// acmeManager - get the certificates of the acme_domains from Let's Encrypt, and renew them before they expire;
+// the certificates are cached in acme_cache_dir, so that they are not requested again after a restart
+func acmeManager(config *common.Config) *autocert.Manager {
+	cacheDir := config.AMC.ACMECacheDir
+	if cacheDir == "" {
+		cacheDir = filepath.Join(filepath.Dir(config.AMC.Database), "acme")
+	}
+
+	log.Infof("Getting the certificates of %s from Let's Encrypt; cache dir: %s", strings.Join(config.AMC.ACMEDomains, ", "), cacheDir)
+	return &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		HostPolicy: autocert.HostWhitelist(config.AMC.ACMEDomains...),
+		Cache:      autocert.DirCache(cacheDir),
+		Email:      config.AMC.ACMEEmail,
+	}
+}
+
+// serveACMEChallenges - answer the http-01 challenges, and redirect the other requests to https
+func serveACMEChallenges(bind string, manager *autocert.Manager) {
+	log.Infof("Serving the ACME http-01 challenges on %s", bind)
+	if err := http.ListenAndServe(bind, manager.HTTPHandler(nil)); err != nil {
+		log.Errorf("Error serving the ACME http-01 challenges on %s: %s", bind, err.Error())
+	}
+}
+
+// cipherSuites - get the ids of the cipher suites by their names, e.g. TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384;
+// the insecure suites are not accepted
+func cipherSuites(names []string) ([]uint16, error) {
+	ids := map[string]uint16{}
+	for _, suite := range tls.CipherSuites() {
+		ids[suite.Name] = suite.ID
+	}
+
+	res := make([]uint16, 0, len(names))
+	for _, name := range names {
+		id, exists := ids[strings.TrimSpace(name)]
+		if !exists {
+			return nil, fmt.Errorf("Unknown or insecure cipher suite in tls_cipher_suites: %s", name)
+		}
+		res = append(res, id)
+	}
+	return res, nil
+}
+
+// certReloader serves the certificate of the certificate files, and loads it again when the files change,
+// so that the certificates can be rotated without restarting AMC
+type certReloader struct {
+	certFile, keyFile string
+
+	cert    common.SyncValue //*tls.Certificate
+	modTime time.Time
+}
+
+func newCertReloader(certFile, keyFile string) (*certReloader, error) {
+	r := &certReloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+		cert:     common.NewSyncValue((*tls.Certificate)(nil)),
+	}
+
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// filesModTime - get the latest modification time of the certificate files
+func (r *certReloader) filesModTime() (time.Time, error) {
+	var latest time.Time
+	for _, file := range []string{r.certFile, r.keyFile} {
+		fi, err := os.Stat(file)
+		if err != nil {
+			return latest, err
+		}
+		if fi.ModTime().After(latest) {
+			latest = fi.ModTime()
+		}
+	}
+	return latest, nil
+}
+
+func (r *certReloader) load() error {
+	modTime, err := r.filesModTime()
+	if err != nil {
+		return err
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return err
+	}
+
+	r.cert.Set(&cert)
+	r.modTime = modTime
+	return nil
+}
+
+// watch - reload the certificate when its files change; the old certificate is kept
+// if the new files cannot be loaded, e.g. because only one of them has been replaced yet
+func (r *certReloader) watch() {
+	ticker := time.NewTicker(_certCheckInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		modTime, err := r.filesModTime()
+		if err != nil || modTime.Equal(r.modTime) {
+			continue
+		}
+
+		if err := r.load(); err != nil {
+			log.Errorf("Error reloading the certificate files, the previous certificate is still used: %s", err.Error())
+			continue
+		}
+		log.Infof("Reloaded the certificate from %s", r.certFile)
+	}
+}
+
+func (r *certReloader) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return r.cert.Get().(*tls.Certificate), nil
+}
@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/aerospike-community/amc/models"
+)
+
+// postClusterExecuteUDF - execute a function of a registered UDF module with the arguments, to smoke test it;
+// the JSON body gives the namespace, the set and the record by its key or digest, or else the UDF is executed
+// on the first limit records of a scan of the set (10 by default). The timeout is in seconds (10 by default).
+func postClusterExecuteUDF(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	form := struct {
+		Namespace string        `json:"namespace"`
+		Set       string        `json:"set"`
+		Key       interface{}   `json:"key"`
+		Digest    string        `json:"digest"`
+		Module    string        `json:"module"`
+		Function  string        `json:"function"`
+		Args      []interface{} `json:"args"`
+		Limit     int           `json:"limit"`
+		Timeout   int           `json:"timeout"`
+	}{Limit: models.UDFSampleDefaultLimit, Timeout: 10}
+
+	// the numbers of the key and the arguments are decoded as such, so that the integers remain integers
+	decoder := json.NewDecoder(c.Request().Body)
+	decoder.UseNumber()
+	if err := decoder.Decode(&form); err != nil {
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid parameters: "+err.Error()))
+	}
+	if form.Namespace == "" {
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid parameters: namespace is required"))
+	}
+
+	call := models.UDFCall{
+		Module:   form.Module,
+		Function: form.Function,
+		Args:     form.Args,
+		Timeout:  time.Duration(form.Timeout) * time.Second,
+	}
+
+	if form.Key == nil && form.Digest == "" {
+		results, err := cluster.ExecuteUDFOnSample(c.Request().Context(), form.Namespace, form.Set, form.Limit, call)
+		if err != nil {
+			return c.JSON(http.StatusOK, errorMapOf(err))
+		}
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"status":  "success",
+			"results": results,
+		})
+	}
+
+	var userKey interface{}
+	switch k := form.Key.(type) {
+	case nil, string:
+		userKey = k
+	case json.Number:
+		n, err := k.Int64()
+		if err != nil {
+			return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid key"))
+		}
+		userKey = n
+	default:
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid key"))
+	}
+
+	key, err := models.RecordKey(form.Namespace, form.Set, userKey, form.Digest)
+	if err != nil {
+		return c.JSON(http.StatusOK, errorMapOf(err))
+	}
+
+	result, err := cluster.ExecuteUDF(key, call)
+	if err != nil {
+		return c.JSON(http.StatusOK, errorMapOf(err))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":  "success",
+		"results": []*models.UDFResult{result},
+	})
+}
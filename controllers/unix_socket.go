@@ -0,0 +1,69 @@
+package controllers
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// the unix socket server, if unix_socket is set
+var _unixServer *http.Server
+
+type unixSocketCtxKey struct{}
+
+// viaUnixSocket - check whether the request came in through the unix socket; these requests
+// come from a local reverse proxy, which terminates TLS, so they must not be redirected to https
+func viaUnixSocket(c echo.Context) bool {
+	v, _ := c.Request().Context().Value(unixSocketCtxKey{}).(bool)
+	return v
+}
+
+// listenUnixSocket - serve the API on the unix socket in addition to the TCP address
+func listenUnixSocket(e *echo.Echo, config *common.Config) {
+	path := config.AMC.UnixSocket
+
+	// remove the socket left behind by a previous run
+	if fi, err := os.Lstat(path); err == nil && fi.Mode()&os.ModeSocket != 0 {
+		if err := os.Remove(path); err != nil {
+			log.Errorf("Error removing the stale unix socket %s: %s", path, err.Error())
+		}
+	}
+
+	lis, err := net.Listen("unix", path)
+	if err != nil {
+		log.Errorf("Error listening on the unix socket %s: %s", path, err.Error())
+		return
+	}
+
+	if config.AMC.UnixSocketMode != "" {
+		mode, err := strconv.ParseUint(config.AMC.UnixSocketMode, 8, 32)
+		if err != nil {
+			log.Errorf("Invalid unix_socket_mode %s: %s", config.AMC.UnixSocketMode, err.Error())
+		} else if err := os.Chmod(path, os.FileMode(mode)); err != nil {
+			log.Errorf("Error setting the mode of the unix socket %s: %s", path, err.Error())
+		}
+	}
+
+	_unixServer = &http.Server{
+		Handler:      e,
+		ReadTimeout:  e.Server.ReadTimeout,
+		WriteTimeout: e.Server.WriteTimeout,
+		ConnContext: func(ctx context.Context, c net.Conn) context.Context {
+			return context.WithValue(ctx, unixSocketCtxKey{}, true)
+		},
+	}
+
+	log.Infof("Serving on the unix socket %s", path)
+	go func() {
+		if err := _unixServer.Serve(lis); err != nil && err != http.ErrServerClosed {
+			log.Errorf("Unix socket server stopped: %s", err.Error())
+		}
+	}()
+}
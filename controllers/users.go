@@ -1,7 +1,10 @@
 package controllers
 
 import (
+	"bytes"
+	"encoding/csv"
 	"net/http"
+	"sort"
 	"strings"
 	"time"
 
@@ -12,13 +15,14 @@ import (
 	// ast "github.com/aerospike/aerospike-client-go/v5/types"
 
 	"github.com/aerospike-community/amc/common"
+	"github.com/aerospike-community/amc/models"
 )
 
 func getClusterCurrentUser(c echo.Context) error {
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("Cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
 	}
 
 	return c.JSON(http.StatusOK, map[string]interface{}{
@@ -32,7 +36,7 @@ func postClusterChangePassword(c echo.Context) error {
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
 		invalidateSession(c)
-		return c.JSON(http.StatusOK, errorMap("Cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
 	}
 
 	user := c.FormValue("user")
@@ -41,7 +45,7 @@ func postClusterChangePassword(c echo.Context) error {
 
 	if err := cluster.UpdatePassword(user, currentPass, newPass); err != nil {
 		invalidateSession(c)
-		return c.JSON(http.StatusOK, errorMap(err.Error()))
+		return c.JSON(http.StatusOK, errorMapOf(err))
 	}
 
 	return c.JSON(http.StatusOK, map[string]interface{}{"status": "success"})
@@ -51,7 +55,7 @@ func getClusterUserRoles(c echo.Context) error {
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("Cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
 	}
 
 	res := map[string]interface{}{
@@ -65,12 +69,41 @@ func getClusterAllUsers(c echo.Context) error {
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("Cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	offset, limit, paginated, err := pagination(c)
+	if err != nil {
+		return c.JSON(http.StatusOK, errorMapOf(err))
 	}
 
 	users := cluster.Users()
 	roles := cluster.Roles()
 
+	// the users granted the role param
+	if role := c.QueryParam("role"); role != "" {
+		filtered := []*as.UserRoles{}
+		for _, u := range users {
+			for _, r := range u.Roles {
+				if r == role {
+					filtered = append(filtered, u)
+					break
+				}
+			}
+		}
+		users = filtered
+	}
+
+	// only the users are paginated; there are few roles
+	userCount := len(users)
+	if paginated {
+		users = append([]*as.UserRoles{}, users...)
+		sort.Slice(users, func(i, j int) bool { return users[i].User < users[j].User })
+
+		start, end := page(len(users), offset, limit)
+		users = users[start:end]
+	}
+
 	uList := make([]interface{}, 0, len(users))
 	for _, u := range users {
 		user := map[string]interface{}{
@@ -84,14 +117,7 @@ func getClusterAllUsers(c echo.Context) error {
 	for _, r := range roles {
 		privileges := make([]string, 0, len(r.Privileges))
 		for _, p := range r.Privileges {
-			s := string(p.Code)
-			if len(p.Namespace) > 0 {
-				s += "." + p.Namespace
-			}
-			if len(p.SetName) > 0 {
-				s += "." + p.SetName
-			}
-			privileges = append(privileges, s)
+			privileges = append(privileges, privilegeToString(p))
 		}
 
 		role := map[string]interface{}{
@@ -106,6 +132,12 @@ func getClusterAllUsers(c echo.Context) error {
 		"roles":  rList,
 	}
 
+	if paginated {
+		res["offset"] = offset
+		res["limit"] = limit
+		res["user_count"] = userCount
+	}
+
 	return c.JSON(http.StatusOK, res)
 }
 
@@ -113,7 +145,7 @@ func getClusterAllRoles(c echo.Context) error {
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("Cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
 	}
 
 	roles := cluster.Roles()
@@ -122,14 +154,7 @@ func getClusterAllRoles(c echo.Context) error {
 	for _, r := range roles {
 		privileges := make([]string, 0, len(r.Privileges))
 		for _, p := range r.Privileges {
-			s := string(p.Code)
-			if len(p.Namespace) > 0 {
-				s += "." + p.Namespace
-			}
-			if len(p.SetName) > 0 {
-				s += "." + p.SetName
-			}
-			privileges = append(privileges, s)
+			privileges = append(privileges, privilegeToString(p))
 		}
 
 		role := map[string]interface{}{
@@ -155,17 +180,17 @@ func postClusterAddUser(c echo.Context) error {
 
 	c.Bind(&form)
 	if len(form.Username) == 0 || len(form.Password) == 0 {
-		return c.JSON(http.StatusOK, errorMap("Invalid user/password."))
+		return c.JSON(http.StatusOK, errorMap(errAuthenticationFailed, "Invalid user/password."))
 	}
 
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("Cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
 	}
 
 	if err := cluster.CreateUser(form.Username, form.Password, strings.Split(form.Roles, ",")); err != nil {
-		return c.JSON(http.StatusOK, errorMap(err.Error()))
+		return c.JSON(http.StatusOK, errorMapOf(err))
 	}
 
 	// wait for different nodes to sync up
@@ -180,11 +205,11 @@ func postClusterDropUser(c echo.Context) error {
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("Cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
 	}
 
 	if err := cluster.DropUser(c.Param("user")); err != nil {
-		return c.JSON(http.StatusOK, errorMap(err.Error()))
+		return c.JSON(http.StatusOK, errorMapOf(err))
 	}
 
 	// wait for different nodes to sync up
@@ -205,32 +230,32 @@ func postClusterUpdateUser(c echo.Context) error {
 
 	c.Bind(&form)
 	if len(form.User) == 0 {
-		return c.JSON(http.StatusOK, errorMap("Invalid user name"))
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid user name"))
 	}
 
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("Cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
 	}
 
 	added, removed := common.StrDiff(strings.Split(form.OldRoles, ","), strings.Split(form.Roles, ","))
 
 	if len(added) > 0 {
 		if err := cluster.GrantRoles(c.Param("user"), added); err != nil {
-			return c.JSON(http.StatusOK, errorMap(err.Error()))
+			return c.JSON(http.StatusOK, errorMapOf(err))
 		}
 	}
 
 	if len(removed) > 0 {
 		if err := cluster.RevokeRoles(c.Param("user"), removed); err != nil {
-			return c.JSON(http.StatusOK, errorMap(err.Error()))
+			return c.JSON(http.StatusOK, errorMapOf(err))
 		}
 	}
 
 	if len(form.Password) > 0 {
 		if err := cluster.ChangeUserPassword(c.Param("user"), form.Password); err != nil {
-			return c.JSON(http.StatusOK, errorMap(err.Error()))
+			return c.JSON(http.StatusOK, errorMapOf(err))
 		}
 	}
 
@@ -269,6 +294,18 @@ func privilegeFromString(s string) *as.Privilege {
 	return nil
 }
 
+// privilegeToString - the inverse of parsePrivilegeString for a single privilege, e.g. `read.test.demo`
+func privilegeToString(p as.Privilege) string {
+	s := string(p.Code)
+	if len(p.Namespace) > 0 {
+		s += "." + p.Namespace
+	}
+	if len(p.SetName) > 0 {
+		s += "." + p.SetName
+	}
+	return s
+}
+
 func parsePrivilegeString(s string) []as.Privilege {
 	var res []as.Privilege
 
@@ -302,18 +339,18 @@ func postClusterAddRole(c echo.Context) error {
 
 	c.Bind(&form)
 	if len(form.Role) == 0 || len(form.Privileges) == 0 {
-		return c.JSON(http.StatusOK, errorMap("Invalid role name or privileges."))
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid role name or privileges."))
 	}
 
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("Cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
 	}
 
 	privileges := parsePrivilegeString(form.Privileges)
 	if err := cluster.CreateRole(form.Role, privileges); err != nil {
-		return c.JSON(http.StatusOK, errorMap(err.Error()))
+		return c.JSON(http.StatusOK, errorMapOf(err))
 	}
 
 	// wait for different nodes to sync up
@@ -328,11 +365,11 @@ func postClusterDropRole(c echo.Context) error {
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("Cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
 	}
 
 	if err := cluster.DropRole(c.Param("role")); err != nil {
-		return c.JSON(http.StatusOK, errorMap(err.Error()))
+		return c.JSON(http.StatusOK, errorMapOf(err))
 	}
 
 	// wait for different nodes to sync up
@@ -352,25 +389,25 @@ func postClusterUpdateRole(c echo.Context) error {
 
 	c.Bind(&form)
 	if len(form.Role) == 0 || len(form.Privileges) == 0 {
-		return c.JSON(http.StatusOK, errorMap("Invalid role name or privileges."))
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid role name or privileges."))
 	}
 
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("Cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
 	}
 
 	added, removed := common.StrDiff(strings.Split(form.OldPrivileges, ","), strings.Split(form.Privileges, ","))
 
 	privileges := parsePrivilegeString(strings.Join(added, ","))
 	if err := cluster.AddPrivileges(c.Param("role"), privileges); err != nil {
-		return c.JSON(http.StatusOK, errorMap(err.Error()))
+		return c.JSON(http.StatusOK, errorMapOf(err))
 	}
 
 	privileges = parsePrivilegeString(strings.Join(removed, ","))
 	if err := cluster.RemovePrivileges(c.Param("role"), privileges); err != nil {
-		return c.JSON(http.StatusOK, errorMap(err.Error()))
+		return c.JSON(http.StatusOK, errorMapOf(err))
 	}
 
 	// wait for different nodes to sync up
@@ -380,3 +417,177 @@ func postClusterUpdateRole(c echo.Context) error {
 		"status": "success",
 	})
 }
+
+func getClusterExportUsers(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	users := cluster.ExportUsers()
+
+	roles := cluster.Roles()
+	sort.Slice(roles, func(i, j int) bool { return roles[i].Name < roles[j].Name })
+
+	switch c.QueryParam("format") {
+	case "", "json":
+		rList := make([]interface{}, 0, len(roles))
+		for _, r := range roles {
+			privileges := make([]string, 0, len(r.Privileges))
+			for _, p := range r.Privileges {
+				privileges = append(privileges, privilegeToString(p))
+			}
+
+			rList = append(rList, map[string]interface{}{
+				"role":       r.Name,
+				"privileges": privileges,
+			})
+		}
+
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"status": "success",
+			"users":  users,
+			"roles":  rList,
+		})
+
+	case "csv":
+		buf := new(bytes.Buffer)
+		w := csv.NewWriter(buf)
+		filename := "users.csv"
+
+		if c.QueryParam("type") == "roles" {
+			filename = "roles.csv"
+			w.Write([]string{"role", "privileges"})
+			for _, r := range roles {
+				privileges := make([]string, 0, len(r.Privileges))
+				for _, p := range r.Privileges {
+					privileges = append(privileges, privilegeToString(p))
+				}
+				w.Write([]string{r.Name, strings.Join(privileges, ",")})
+			}
+		} else {
+			w.Write([]string{"user", "roles"})
+			for _, u := range users {
+				w.Write([]string{u.User, strings.Join(u.Roles, ",")})
+			}
+		}
+
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return c.JSON(http.StatusOK, errorMapOf(err))
+		}
+
+		c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="`+filename+`"`)
+		return c.Blob(http.StatusOK, "text/csv", buf.Bytes())
+	}
+
+	return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid format"))
+}
+
+// parseUsersCSV - parse users from CSV with a header row; recognized columns are
+// user, password, roles (comma separated) and email
+func parseUsersCSV(s string) ([]models.BulkUser, error) {
+	records, err := csv.NewReader(strings.NewReader(s)).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		return nil, newAPIError(errInvalidParameter, "CSV is empty")
+	}
+
+	columns := map[string]int{}
+	for i, h := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+
+	if _, exists := columns["user"]; !exists {
+		return nil, newAPIError(errInvalidParameter, "CSV header must include the `user` column")
+	}
+
+	field := func(record []string, name string) string {
+		if i, exists := columns[name]; exists && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	users := make([]models.BulkUser, 0, len(records)-1)
+	for _, record := range records[1:] {
+		u := models.BulkUser{
+			User:     field(record, "user"),
+			Password: field(record, "password"),
+			Email:    field(record, "email"),
+		}
+		if roles := field(record, "roles"); roles != "" {
+			u.Roles = strings.Split(roles, ",")
+		}
+		users = append(users, u)
+	}
+
+	return users, nil
+}
+
+func postClusterImportUsers(c echo.Context) error {
+	form := struct {
+		Users          []models.BulkUser `json:"users"`
+		CSV            string            `json:"csv" form:"csv"`
+		EmailPasswords bool              `json:"email_passwords" form:"email_passwords"`
+	}{}
+
+	if err := c.Bind(&form); err != nil {
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "Invalid parameters"))
+	}
+
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	users := form.Users
+	if form.CSV != "" {
+		csvUsers, err := parseUsersCSV(form.CSV)
+		if err != nil {
+			return c.JSON(http.StatusOK, errorMapOf(err))
+		}
+		users = append(users, csvUsers...)
+	}
+
+	if len(users) == 0 {
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "No users to import"))
+	}
+
+	results := cluster.ImportUsers(users, form.EmailPasswords)
+
+	// wait for different nodes to sync up
+	time.Sleep(1 * time.Second)
+
+	failed := 0
+	for _, r := range results {
+		if r.Status != "success" {
+			failed++
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":  "success",
+		"created": len(results) - failed,
+		"failed":  failed,
+		"users":   results,
+	})
+}
+
+func getClusterPrivileges(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":     "success",
+		"privileges": cluster.PrivilegeCatalog(),
+	})
+}
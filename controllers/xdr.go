@@ -18,7 +18,7 @@ func getClusterXdrNodes(c echo.Context) error {
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("Cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
 	}
 
 	keys := []string{
@@ -51,7 +51,7 @@ func getClusterXdrNodeAllStats(c echo.Context) error {
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("Cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
 	}
 
 	nodeAddress := c.Param("node")
@@ -67,14 +67,36 @@ func getClusterXdrNodeAllStats(c echo.Context) error {
 	res["node_status"] = "on"
 	res["xdr_status"] = node.XdrStatus()
 
-	return c.JSON(http.StatusOK, res)
+	return c.JSON(http.StatusOK, sparseStats(res, statFields(c), "node_status", "xdr_status"))
 }
 
 func setClusterXdrNodesConfig(c echo.Context) error {
+	return setClusterXdrConfig(c, func(node *models.Node, config map[string]string) ([]string, error) {
+		return node.SetServerConfig("xdr", config, requestUser(c))
+	})
+}
+
+func setClusterXdrDCNodesConfig(c echo.Context) error {
+	dc := c.Param("dc")
+	return setClusterXdrConfig(c, func(node *models.Node, config map[string]string) ([]string, error) {
+		return node.SetXdrDCConfig(dc, "", config, requestUser(c))
+	})
+}
+
+func setClusterXdrDCNamespaceNodesConfig(c echo.Context) error {
+	dc := c.Param("dc")
+	namespace := c.Param("namespace")
+	return setClusterXdrConfig(c, func(node *models.Node, config map[string]string) ([]string, error) {
+		return node.SetXdrDCConfig(dc, namespace, config, requestUser(c))
+	})
+}
+
+// setClusterXdrConfig - apply the form params as config to the nodes in parallel using the setter
+func setClusterXdrConfig(c echo.Context, setter func(node *models.Node, config map[string]string) ([]string, error)) error {
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("Cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
 	}
 
 	nodeAddrs := strings.Split(c.Param("nodes"), ",")
@@ -90,7 +112,7 @@ func setClusterXdrNodesConfig(c echo.Context) error {
 
 	formParams, err := c.FormParams()
 	if err != nil {
-		return c.JSON(http.StatusOK, errorMap("No Parameters found"))
+		return c.JSON(http.StatusOK, errorMap(errInvalidParameter, "No Parameters found"))
 	}
 	config := make(map[string]string, len(formParams))
 	for k, v := range formParams {
@@ -108,7 +130,7 @@ func setClusterXdrNodesConfig(c echo.Context) error {
 		go func(node *models.Node) {
 			defer wg.Done()
 
-			unsetParams, err := node.SetServerConfig("xdr", config)
+			unsetParams, err := setter(node, config)
 			resChan <- &NodeResult{Name: node.Address(), Status: string(node.Status()), Err: err, UnsetParams: unsetParams}
 		}(node)
 	}
@@ -147,32 +169,32 @@ func postSwitchXDR(c echo.Context, on bool) error {
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		res["error"] = "Cluster not found"
+		setError(res, errClusterNotFound, "Cluster not found")
 		return c.JSON(http.StatusNotFound, res)
 	}
 
 	node := cluster.FindNodeByAddress(nodeAddr)
 	if node == nil {
 		res["node_status"] = "off"
-		res["error"] = "Node not found"
+		setError(res, errNodeNotFound, "Node not found")
 		return c.JSON(http.StatusBadRequest, res)
 	}
 
 	switch string(node.XdrStatus()) {
 	case "on":
 		if on {
-			res["error"] = "XDR Already On"
+			setError(res, errOperationFailed, "XDR Already On")
 			return c.JSON(http.StatusOK, res)
 		}
 	case "off":
 		if !on {
-			res["error"] = "XDR Already Off"
+			setError(res, errOperationFailed, "XDR Already Off")
 			return c.JSON(http.StatusOK, res)
 		}
 	}
 
 	if err := node.SwitchXDR(on); err != nil {
-		res["error"] = err
+		setErrorOf(res, err)
 		return c.JSON(http.StatusBadRequest, res)
 	}
 
@@ -192,7 +214,7 @@ func getClusterXdrNodesAllConfig(c echo.Context) error {
 	clusterUUID := c.Param("clusterUUID")
 	cluster := _observer.FindClusterByID(clusterUUID)
 	if cluster == nil {
-		return c.JSON(http.StatusOK, errorMap("Cluster not found"))
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
 	}
 
 	nodeAddr := c.Param("nodes")
@@ -209,3 +231,166 @@ func getClusterXdrNodesAllConfig(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, res)
 }
+
+func getClusterXdrDCs(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status": "success",
+		"dcs":    cluster.XdrDCs(),
+	})
+}
+
+func getClusterXdrDCNodes(c echo.Context) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	dc := c.Param("dc")
+	nodeAddrs := strings.Split(c.Param("nodes"), ",")
+	res := make(map[string]common.Stats, len(nodeAddrs))
+	for _, addr := range nodeAddrs {
+		res[addr] = common.Stats{"node_status": "off"}
+	}
+
+	for _, node := range cluster.FindNodesByAddress(nodeAddrs...) {
+		if !node.Xdr5() {
+			res[node.Address()] = common.Stats{
+				"node_status": node.Status(),
+				"error":       "XDR datacenters are managed per node only in server 5.0+",
+			}
+			continue
+		}
+
+		namespaces := map[string]common.Stats{}
+		for _, ns := range node.XdrDCNamespaces(dc) {
+			namespaces[ns] = node.XdrDCNamespaceConfig(dc, ns)
+		}
+
+		res[node.Address()] = common.Stats{
+			"node_status": node.Status(),
+			"xdr_status":  node.XdrStatus(),
+			"config":      node.XdrDCConfig(dc),
+			"stats":       node.XdrDCStats(dc),
+			"namespaces":  namespaces,
+		}
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// applyXdrDCAction - run the action on all nodes of the cluster in parallel,
+// as XDR datacenters have to be configured on every node
+func applyXdrDCAction(c echo.Context, action func(node *models.Node) error) error {
+	clusterUUID := c.Param("clusterUUID")
+	cluster := _observer.FindClusterByID(clusterUUID)
+	if cluster == nil {
+		return c.JSON(http.StatusOK, errorMap(errClusterNotFound, "Cluster not found"))
+	}
+
+	nodes := cluster.Nodes()
+	wg := new(sync.WaitGroup)
+	wg.Add(len(nodes))
+	resChan := make(chan *NodeResult, len(nodes))
+
+	for _, node := range nodes {
+		go func(node *models.Node) {
+			defer wg.Done()
+
+			err := action(node)
+			resChan <- &NodeResult{Node: node, Name: node.Address(), Err: err}
+		}(node)
+	}
+
+	wg.Wait()
+	close(resChan)
+
+	failed := false
+	res := make(common.Stats, len(nodes))
+	for nr := range resChan {
+		nodeRes := map[string]interface{}{
+			"node_status": string(nr.Node.Status()),
+			"status":      "success",
+		}
+		if nr.Err != nil {
+			failed = true
+			nodeRes["status"] = "failure"
+			nodeRes["error"] = nr.Err.Error()
+		}
+		res[nr.Name] = nodeRes
+	}
+
+	if failed {
+		return c.JSON(http.StatusOK, setError(map[string]interface{}{"nodes": res}, errOperationFailed, "The action failed on some nodes"))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status": "success",
+		"nodes":  res,
+	})
+}
+
+func postClusterXdrCreateDC(c echo.Context) error {
+	dc := c.Param("dc")
+	return applyXdrDCAction(c, func(node *models.Node) error {
+		return node.CreateXdrDC(dc)
+	})
+}
+
+func postClusterXdrDeleteDC(c echo.Context) error {
+	dc := c.Param("dc")
+	return applyXdrDCAction(c, func(node *models.Node) error {
+		return node.DeleteXdrDC(dc)
+	})
+}
+
+func postClusterXdrDCAddNode(c echo.Context) error {
+	dc := c.Param("dc")
+	address := c.FormValue("node_address")
+	return applyXdrDCAction(c, func(node *models.Node) error {
+		return node.AddXdrDCNode(dc, address)
+	})
+}
+
+func postClusterXdrDCRemoveNode(c echo.Context) error {
+	dc := c.Param("dc")
+	address := c.FormValue("node_address")
+	return applyXdrDCAction(c, func(node *models.Node) error {
+		return node.RemoveXdrDCNode(dc, address)
+	})
+}
+
+func postClusterXdrDCAddNamespace(c echo.Context) error {
+	dc := c.Param("dc")
+	namespace := c.Param("namespace")
+	rewind := c.FormValue("rewind")
+	return applyXdrDCAction(c, func(node *models.Node) error {
+		return node.AddXdrDCNamespace(dc, namespace, rewind)
+	})
+}
+
+func postClusterXdrDCRemoveNamespace(c echo.Context) error {
+	dc := c.Param("dc")
+	namespace := c.Param("namespace")
+	return applyXdrDCAction(c, func(node *models.Node) error {
+		return node.RemoveXdrDCNamespace(dc, namespace)
+	})
+}
+
+func postClusterXdrDCRewindNamespace(c echo.Context) error {
+	dc := c.Param("dc")
+	namespace := c.Param("namespace")
+	rewind := c.FormValue("rewind")
+	if rewind == "" {
+		rewind = "all"
+	}
+	return applyXdrDCAction(c, func(node *models.Node) error {
+		return node.RewindXdrDCNamespace(dc, namespace, rewind)
+	})
+}
@@ -0,0 +1,356 @@
+// Package graphql parses the subset of GraphQL queries served by AMC: a single query operation
+// with fields, aliases, arguments and variables. Fragments, directives and mutations are not supported.
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Field is a selected field of a query
+type Field struct {
+	Alias      string
+	Name       string
+	Args       map[string]interface{}
+	Selections []*Field
+}
+
+// Key - get the key of the field in the result
+func (f *Field) Key() string {
+	if f.Alias != "" {
+		return f.Alias
+	}
+	return f.Name
+}
+
+// Parse - parse the query and substitute the variables in the arguments;
+// returns the selections of the operation
+func Parse(query string, variables map[string]interface{}) ([]*Field, error) {
+	p := &parser{src: query, variables: variables}
+	p.next()
+
+	if p.tok.kind == tokName {
+		switch p.tok.val {
+		case "query":
+			p.next()
+		case "mutation", "subscription", "fragment":
+			return nil, fmt.Errorf("%s is not supported", p.tok.val)
+		default:
+			return nil, p.errorf("expected query")
+		}
+
+		// the operation name and the variable definitions are not used
+		if p.tok.kind == tokName {
+			p.next()
+		}
+		if p.tok.is("(") {
+			if err := p.skipVariableDefinitions(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	fields, err := p.selectionSet()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.tok.kind != tokEOF {
+		return nil, p.errorf("only a single operation is supported")
+	}
+
+	return fields, nil
+}
+
+// _maxDepth - the deepest nesting of the selection sets and the lists of a query; the parser is recursive,
+// so the deeper queries are rejected before they can exhaust the stack
+const _maxDepth = 32
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokPunct
+	tokName
+	tokString
+	tokInt
+	tokFloat
+	tokError
+)
+
+type token struct {
+	kind tokenKind
+	val  string
+	pos  int
+}
+
+func (t token) is(punct string) bool {
+	return t.kind == tokPunct && t.val == punct
+}
+
+type parser struct {
+	src       string
+	pos       int
+	tok       token
+	depth     int
+	variables map[string]interface{}
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	if p.tok.kind == tokError {
+		return fmt.Errorf("syntax error at %d: %s", p.tok.pos, p.tok.val)
+	}
+	return fmt.Errorf("syntax error at %d: %s", p.tok.pos, fmt.Sprintf(format, args...))
+}
+
+// next - read the next token; whitespace, commas and comments are ignored
+func (p *parser) next() {
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if c == '#' {
+			for p.pos < len(p.src) && p.src[p.pos] != '\n' {
+				p.pos++
+			}
+		} else if c == ',' || unicode.IsSpace(rune(c)) {
+			p.pos++
+		} else {
+			break
+		}
+	}
+
+	start := p.pos
+	if p.pos >= len(p.src) {
+		p.tok = token{kind: tokEOF, pos: start}
+		return
+	}
+
+	c := p.src[p.pos]
+	switch {
+	case strings.IndexByte("{}()[]:$!=", c) >= 0:
+		p.pos++
+		p.tok = token{kind: tokPunct, val: string(c), pos: start}
+
+	case c == '_' || unicode.IsLetter(rune(c)):
+		for p.pos < len(p.src) && (p.src[p.pos] == '_' || unicode.IsLetter(rune(p.src[p.pos])) || unicode.IsDigit(rune(p.src[p.pos]))) {
+			p.pos++
+		}
+		p.tok = token{kind: tokName, val: p.src[start:p.pos], pos: start}
+
+	case c == '-' || unicode.IsDigit(rune(c)):
+		p.pos++
+		kind := tokInt
+		for p.pos < len(p.src) && strings.IndexByte("0123456789.eE+-", p.src[p.pos]) >= 0 {
+			if strings.IndexByte(".eE", p.src[p.pos]) >= 0 {
+				kind = tokFloat
+			}
+			p.pos++
+		}
+		p.tok = token{kind: kind, val: p.src[start:p.pos], pos: start}
+
+	case c == '"':
+		p.pos++
+		for p.pos < len(p.src) && p.src[p.pos] != '"' {
+			if p.src[p.pos] == '\\' {
+				p.pos++
+			}
+			p.pos++
+		}
+		if p.pos >= len(p.src) {
+			p.tok = token{kind: tokError, val: "unterminated string", pos: start}
+			return
+		}
+		p.pos++
+
+		s, err := strconv.Unquote(p.src[start:p.pos])
+		if err != nil {
+			p.tok = token{kind: tokError, val: "invalid string", pos: start}
+			return
+		}
+		p.tok = token{kind: tokString, val: s, pos: start}
+
+	default:
+		p.tok = token{kind: tokError, val: fmt.Sprintf("unexpected character %q", c), pos: start}
+	}
+}
+
+func (p *parser) expect(punct string) error {
+	if !p.tok.is(punct) {
+		return p.errorf("expected %s", punct)
+	}
+	p.next()
+	return nil
+}
+
+// enter - go down a level of nesting; leave must be called when the level is done
+func (p *parser) enter() error {
+	p.depth++
+	if p.depth > _maxDepth {
+		return p.errorf("the query is nested deeper than %d levels", _maxDepth)
+	}
+	return nil
+}
+
+func (p *parser) leave() {
+	p.depth--
+}
+
+func (p *parser) skipVariableDefinitions() error {
+	depth := 0
+	for {
+		switch {
+		case p.tok.kind == tokEOF || p.tok.kind == tokError:
+			return p.errorf("unterminated variable definitions")
+		case p.tok.is("("):
+			depth++
+		case p.tok.is(")"):
+			depth--
+		}
+		p.next()
+
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+func (p *parser) selectionSet() ([]*Field, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+
+	fields := []*Field{}
+	for !p.tok.is("}") {
+		field, err := p.field()
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+	}
+	p.next()
+
+	return fields, nil
+}
+
+func (p *parser) field() (*Field, error) {
+	if p.tok.kind != tokName {
+		return nil, p.errorf("expected a field name")
+	}
+
+	field := &Field{Name: p.tok.val, Args: map[string]interface{}{}}
+	p.next()
+
+	if p.tok.is(":") {
+		p.next()
+		if p.tok.kind != tokName {
+			return nil, p.errorf("expected a field name after the alias")
+		}
+		field.Alias, field.Name = field.Name, p.tok.val
+		p.next()
+	}
+
+	if p.tok.is("(") {
+		p.next()
+		for !p.tok.is(")") {
+			if p.tok.kind != tokName {
+				return nil, p.errorf("expected an argument name")
+			}
+			name := p.tok.val
+			p.next()
+
+			if err := p.expect(":"); err != nil {
+				return nil, err
+			}
+
+			val, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			field.Args[name] = val
+		}
+		p.next()
+	}
+
+	if p.tok.is("{") {
+		selections, err := p.selectionSet()
+		if err != nil {
+			return nil, err
+		}
+		field.Selections = selections
+	}
+
+	return field, nil
+}
+
+func (p *parser) value() (interface{}, error) {
+	tok := p.tok
+	switch {
+	case tok.is("$"):
+		p.next()
+		if p.tok.kind != tokName {
+			return nil, p.errorf("expected a variable name")
+		}
+		val := p.variables[p.tok.val]
+		p.next()
+		return val, nil
+
+	case tok.is("["):
+		if err := p.enter(); err != nil {
+			return nil, err
+		}
+		defer p.leave()
+
+		p.next()
+		list := []interface{}{}
+		for !p.tok.is("]") {
+			val, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, val)
+		}
+		p.next()
+		return list, nil
+
+	case tok.kind == tokString:
+		p.next()
+		return tok.val, nil
+
+	case tok.kind == tokInt:
+		p.next()
+		v, err := strconv.ParseInt(tok.val, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("syntax error at %d: invalid number %s", tok.pos, tok.val)
+		}
+		return v, nil
+
+	case tok.kind == tokFloat:
+		p.next()
+		v, err := strconv.ParseFloat(tok.val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("syntax error at %d: invalid number %s", tok.pos, tok.val)
+		}
+		return v, nil
+
+	case tok.kind == tokName:
+		p.next()
+		switch tok.val {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+		// enum values are passed as strings
+		return tok.val, nil
+	}
+
+	return nil, p.errorf("expected a value")
+}
@@ -0,0 +1,94 @@
+package graphql
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+// leaf - a selected field without subfields or arguments
+func leaf(name string) *Field {
+	return &Field{Name: name, Args: map[string]interface{}{}}
+}
+
+// nestedQuery - a query with depth nested selection sets
+func nestedQuery(depth int) string {
+	return strings.Repeat("{a", depth) + strings.Repeat("}", depth)
+}
+
+var _ = Describe("Parser", func() {
+
+	DescribeTable("parsing the valid queries",
+		func(query string, variables map[string]interface{}, expected []*Field) {
+			fields, err := Parse(query, variables)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fields).To(Equal(expected))
+		},
+		Entry("a shorthand query", "{ clusters }", nil, []*Field{leaf("clusters")}),
+		Entry("a named query with variable definitions", "query Q($id: String!) { clusters }", nil, []*Field{leaf("clusters")}),
+		Entry("commas and comments", "{ a, # the first\n b }", nil, []*Field{leaf("a"), leaf("b")}),
+		Entry("an alias", "{ c: clusters }", nil, []*Field{{Alias: "c", Name: "clusters", Args: map[string]interface{}{}}}),
+		Entry("nested selections", "{ cluster { nodes { id } } }", nil, []*Field{{
+			Name: "cluster", Args: map[string]interface{}{}, Selections: []*Field{{
+				Name: "nodes", Args: map[string]interface{}{}, Selections: []*Field{leaf("id")},
+			}},
+		}}),
+		Entry("the argument values", `{ f(s: "a\"b", i: -5, x: 1.5, t: true, n: null, e: ENUM, l: ["a", 1]) }`, nil, []*Field{{
+			Name: "f", Args: map[string]interface{}{
+				"s": `a"b`, "i": int64(-5), "x": 1.5, "t": true, "n": nil, "e": "ENUM", "l": []interface{}{"a", int64(1)},
+			},
+		}}),
+		Entry("the variables", "query($id: String) { cluster(id: $id, missing: $other) { id } }", map[string]interface{}{"id": "c1"}, []*Field{{
+			Name: "cluster", Args: map[string]interface{}{"id": "c1", "missing": nil}, Selections: []*Field{leaf("id")},
+		}}),
+	)
+
+	DescribeTable("rejecting the invalid queries",
+		func(query string, expected string) {
+			_, err := Parse(query, nil)
+			Expect(err).To(MatchError(ContainSubstring(expected)))
+		},
+		Entry("an empty query", "", "expected {"),
+		Entry("a mutation", "mutation { a }", "mutation is not supported"),
+		Entry("a fragment", "fragment F on T { a }", "fragment is not supported"),
+		Entry("an unknown operation", "operation { a }", "expected query"),
+		Entry("an unterminated selection set", "{ a { b }", "expected a field name"),
+		Entry("a missing alias target", "{ a: }", "expected a field name after the alias"),
+		Entry("a missing argument name", "{ a(1) }", "expected an argument name"),
+		Entry("a missing colon after an argument", `{ a(b "c") }`, "expected :"),
+		Entry("a missing value", "{ a(b: ) }", "expected a value"),
+		Entry("a missing variable name", "{ a(b: $) }", "expected a variable name"),
+		Entry("an unterminated string", `{ a(b: "c) }`, "unterminated string"),
+		Entry("an invalid number", "{ a(b: 1e) }", "invalid number 1e"),
+		Entry("an unexpected character", "{ a(b: @) }", "unexpected character '@'"),
+		Entry("unterminated variable definitions", "query Q($id: String { a }", "unterminated variable definitions"),
+		Entry("a second operation", "{ a } { b }", "only a single operation is supported"),
+	)
+
+	Context("nesting", func() {
+
+		It("allows the selection sets up to the maximum depth", func() {
+			fields, err := Parse(nestedQuery(_maxDepth), nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fields).To(HaveLen(1))
+		})
+
+		It("rejects the selection sets nested deeper than the maximum", func() {
+			_, err := Parse(nestedQuery(_maxDepth+1), nil)
+			Expect(err).To(MatchError(ContainSubstring("nested deeper than")))
+		})
+
+		It("rejects the lists nested deeper than the maximum", func() {
+			query := "{ a(b: " + strings.Repeat("[", _maxDepth) + strings.Repeat("]", _maxDepth) + ") }"
+			_, err := Parse(query, nil)
+			Expect(err).To(MatchError(ContainSubstring("nested deeper than")))
+		})
+
+		It("rejects a very deep query without exhausting the stack", func() {
+			_, err := Parse(nestedQuery(1000000), nil)
+			Expect(err).To(MatchError(ContainSubstring("nested deeper than")))
+		})
+	})
+})
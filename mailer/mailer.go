@@ -5,6 +5,8 @@ import (
 	"crypto/tls"
 	"fmt"
 	"html/template"
+	"os"
+	"path/filepath"
 
 	log "github.com/sirupsen/logrus"
 	gomail "gopkg.in/gomail.v2"
@@ -12,7 +14,9 @@ import (
 	"github.com/aerospike-community/amc/common"
 )
 
-func processTemplate(config *common.Config, tplName string, context interface{}) ([]byte, error) {
+// processTemplate - execute the template of the locale, <template_path>/<locale>/<tplName>, or the default one
+// if the locale has none
+func processTemplate(config *common.Config, locale, tplName string, context interface{}) ([]byte, error) {
 
 	defer func() {
 		if r := recover(); r != nil {
@@ -20,7 +24,7 @@ func processTemplate(config *common.Config, tplName string, context interface{})
 		}
 	}()
 
-	t := template.Must(template.ParseFiles(config.Mailer.TemplatePath+"/"+tplName, config.Mailer.TemplatePath+"/base.html"))
+	t := template.Must(template.ParseFiles(localizedTemplate(config, locale, tplName), localizedTemplate(config, locale, "base.html")))
 
 	// Execute the template for each recipient.
 	data := bytes.Buffer{}
@@ -33,16 +37,47 @@ func processTemplate(config *common.Config, tplName string, context interface{})
 	return data.Bytes(), nil
 }
 
-// SendMail - send email
+func localizedTemplate(config *common.Config, locale, tplName string) string {
+	if locale != "" {
+		file := filepath.Join(config.Mailer.TemplatePath, filepath.Base(locale), tplName)
+		if _, err := os.Stat(file); err == nil {
+			return file
+		}
+	}
+	return filepath.Join(config.Mailer.TemplatePath, tplName)
+}
+
+// RecipientsByLocale - group the recipients by the locale of their notifications
+func RecipientsByLocale(config *common.Config, to []string) map[string][]string {
+	res := map[string][]string{}
+	for _, recipient := range to {
+		locale := config.Locale(recipient)
+		res[locale] = append(res[locale], recipient)
+	}
+	return res
+}
+
+// SendMail - send email to the alert recipients
 func SendMail(config *common.Config, tplName, subject string, context interface{}) error {
-	body, err := processTemplate(config, tplName, context)
+	return SendMailTo(config, config.AlertEmails(), tplName, subject, context)
+}
+
+// SendMailTo - send email to the given recipients
+func SendMailTo(config *common.Config, to []string, tplName, subject string, context interface{}) error {
+	return SendLocalizedMailTo(config, "", to, tplName, subject, context)
+}
+
+// SendLocalizedMailTo - send email to the given recipients with the template of the locale; the subject
+// and the context should be translated already
+func SendLocalizedMailTo(config *common.Config, locale string, to []string, tplName, subject string, context interface{}) error {
+	body, err := processTemplate(config, locale, tplName, context)
 	if err != nil {
 		return err
 	}
 
 	msg := gomail.NewMessage(gomail.SetEncoding(gomail.Unencoded))
 	msg.SetHeader("From", fmt.Sprintf("AMC <%s>", config.FromAddress()))
-	msg.SetHeader("To", config.AlertEmails()...)
+	msg.SetHeader("To", to...)
 	msg.SetHeader("Subject", subject)
 	msg.SetBody("text/html", string(body))
 
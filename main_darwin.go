@@ -4,10 +4,10 @@ package main
 
 import (
 	"flag"
-	"net/http"
 	_ "net/http/pprof"
-	"runtime"
-	"runtime/debug"
+	"os"
+	"os/signal"
+	"syscall"
 
 	log "github.com/sirupsen/logrus"
 
@@ -15,37 +15,18 @@ import (
 	"github.com/aerospike-community/amc/controllers"
 )
 
-var (
-	configFile   = flag.String("config-file", "/etc/amc/amc.conf", "Configuration file.")
-	configDir    = flag.String("config-dir", "/etc/amc/", "Configuration dir.")
-	profileMode  = flag.Bool("profile", false, "Run benchmarks with profiler active on port 6060.")
-	daemonMode   = flag.Bool("daemon", false, "Run AMC in daemon mode.")
-	daemonSignal = flag.String("signal", "", `send signal to the daemon
-		stop — graceful shutdown.`)
-)
-
-func main() {
-	defer func() {
-		if err := recover(); err != nil {
-			log.Fatal(string(debug.Stack()))
-		}
-	}()
-
-	runtime.GOMAXPROCS(runtime.NumCPU())
+// serve - run the AMC server
+func serve(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	flags := newServeFlags(fs)
+	fs.Parse(args)
 
-	flag.Parse()
-
-	// launch profiler if in profile mode
-	if *profileMode {
-		go func() {
-			log.Println(http.ListenAndServe(":6060", nil))
-		}()
-	}
+	flags.startProfiler()
 
 	log.Infof("Trying to start the AMC server...")
 
 	config := common.Config{}
-	common.InitConfig(*configFile, *configDir, &config)
+	common.InitConfig(*flags.configFile, *flags.configDir, &config, flags.override)
 
 	// close the log file on exit
 	defer func() {
@@ -55,5 +36,18 @@ func main() {
 	}()
 
 	common.SetupDatabase(config.AMC.Database)
+	go handleReloadSignal()
 	controllers.Server(&config)
 }
+
+// handleReloadSignal - reload the config on SIGHUP
+func handleReloadSignal() {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGHUP)
+	for range ch {
+		log.Println("Reloading the config file...")
+		if err := controllers.ReloadConfig(); err != nil {
+			log.Errorln("Error reloading the config file:", err)
+		}
+	}
+}
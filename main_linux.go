@@ -5,11 +5,9 @@ package main
 import (
 	"flag"
 	"io/ioutil"
-	"net/http"
 	_ "net/http/pprof"
 	"os"
-	"runtime"
-	"runtime/debug"
+	"os/signal"
 	"syscall"
 
 	"github.com/sevlyar/go-daemon"
@@ -19,41 +17,26 @@ import (
 	"github.com/aerospike-community/amc/controllers"
 )
 
-var (
-	configFile   = flag.String("config-file", "/etc/amc/amc.conf", "Configuration file.")
-	configDir    = flag.String("config-dir", "/etc/amc/", "Configuration dir.")
-	profileMode  = flag.Bool("profile", false, "Run benchmarks with profiler active on port 6060.")
-	daemonMode   = flag.Bool("daemon", false, "Run AMC in daemon mode.")
-	daemonSignal = flag.String("signal", "", `send signal to the daemon
-		stop — graceful shutdown.`)
-)
-
-func main() {
-	defer func() {
-		if err := recover(); err != nil {
-			log.Fatal(string(debug.Stack()))
-		}
-	}()
-
-	runtime.GOMAXPROCS(runtime.NumCPU())
-
-	flag.Parse()
+// serve - run the AMC server, as a daemon with -daemon; -signal sends a signal to the running daemon
+func serve(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	flags := newServeFlags(fs)
+	daemonMode := fs.Bool("daemon", false, "Run AMC in daemon mode.")
+	daemonSignal := fs.String("signal", "", `send signal to the daemon
+		stop — graceful shutdown.
+		reload — reload the config file.`)
+	fs.Parse(args)
 
 	if *daemonSignal == "stop" {
 		log.SetOutput(ioutil.Discard)
 	}
 
-	// launch profiler if in profile mode
-	if *profileMode {
-		go func() {
-			log.Println(http.ListenAndServe(":6060", nil))
-		}()
-	}
+	flags.startProfiler()
 
 	log.Infof("Trying to start the AMC server...")
 
 	config := common.Config{}
-	common.InitConfig(*configFile, *configDir, &config)
+	common.InitConfig(*flags.configFile, *flags.configDir, &config, flags.override)
 
 	// close the log file on exit
 	defer func() {
@@ -69,6 +52,7 @@ func main() {
 	*/
 
 	daemon.AddCommand(daemon.StringFlag(daemonSignal, "stop"), syscall.SIGTERM, shutdownHandler)
+	daemon.AddCommand(daemon.StringFlag(daemonSignal, "reload"), syscall.SIGHUP, reloadHandler)
 
 	cntxt := &daemon.Context{
 		PidFileName: config.AMC.PIDFile,
@@ -77,7 +61,7 @@ func main() {
 		LogFilePerm: 0640,
 		WorkDir:     config.AMC.Chdir,
 		Umask:       027,
-		Args:        flag.Args(),
+		Args:        fs.Args(),
 	}
 
 	if len(daemon.ActiveFlags()) > 0 {
@@ -113,10 +97,28 @@ func main() {
 		log.Println("daemon terminated.")
 	} else {
 		common.SetupDatabase(config.AMC.Database)
+		go handleReloadSignal()
 		controllers.Server(&config)
 	}
 }
 
+func reloadHandler(sig os.Signal) error {
+	log.Println("Reloading the config file...")
+	if err := controllers.ReloadConfig(); err != nil {
+		log.Errorln("Error reloading the config file:", err)
+	}
+	return nil
+}
+
+// handleReloadSignal - reload the config on SIGHUP when not running as a daemon
+func handleReloadSignal() {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGHUP)
+	for sig := range ch {
+		reloadHandler(sig)
+	}
+}
+
 func shutdownHandler(sig os.Signal) error {
 	log.Println("Shutting down AMC gracefully...")
 	controllers.ShutdownServer()
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// ParseAlternateAddresses - parse a comma delimited list of advertised=reachable address pairs,
+// used to reach nodes which advertise addresses that are not routable from AMC
+func ParseAlternateAddresses(s string) (map[string]string, error) {
+	res := map[string]string{}
+	for _, pair := range common.SplitList(s) {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" || strings.TrimSpace(parts[1]) == "" {
+			return nil, common.Errorf(common.KindInvalid, "Invalid alternate address %q; expected <advertised address>=<alternate address>", pair)
+		}
+		res[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+
+	if len(res) == 0 {
+		return nil, nil
+	}
+	return res, nil
+}
+
+// FormatAlternateAddresses - format the alternate addresses the way they are parsed by ParseAlternateAddresses
+func FormatAlternateAddresses(m map[string]string) string {
+	pairs := make([]string, 0, len(m))
+	for advertised, alternate := range m {
+		pairs = append(pairs, advertised+"="+alternate)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (c *Cluster) usesServicesAlternate() bool {
+	policy := c.policy
+	return policy != nil && policy.UseServicesAlternate
+}
+
+// alternateAddress - translate an address advertised by the nodes to the address AMC reaches them at
+func (c *Cluster) alternateAddress(address string) string {
+	policy := c.policy
+	if policy == nil || len(policy.IpMap) == 0 {
+		return address
+	}
+
+	host, port, err := common.SplitHostPort(address)
+	if err != nil {
+		return address
+	}
+
+	if alternate, exists := policy.IpMap[host]; exists {
+		return net.JoinHostPort(alternate, strconv.Itoa(port))
+	}
+	return address
+}
+
+// serviceAddress - get the address the node is reachable at; the alternate access address
+// is preferred when the cluster is monitored through services-alternate
+func (n *Node) serviceAddress() string {
+	names := []string{"service-tls-std", "service-clear-std", "service"}
+	if n.cluster.usesServicesAlternate() {
+		names = append([]string{"service-tls-alt", "service-clear-alt"}, names...)
+	}
+
+	s := n.InfoAttrFirstValidValueAmong(names...)
+	if s == common.NOT_AVAILABLE {
+		return s
+	}
+	return n.cluster.alternateAddress(s)
+}
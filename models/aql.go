@@ -0,0 +1,582 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	as "github.com/aerospike/aerospike-client-go/v5"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// AQLResult - the result of an AQL statement as a table; Message is set for the statements returning no rows
+type AQLResult struct {
+	Columns []string        `json:"columns"`
+	Rows    [][]interface{} `json:"rows"`
+	Message string          `json:"message,omitempty"`
+}
+
+// the metadata columns of the records selected, named as AQL prints them
+const (
+	_aqlDigestColumn     = "{digest}"
+	_aqlGenerationColumn = "{gen}"
+	_aqlTTLColumn        = "{ttl}"
+)
+
+// aqlWhere - the condition of a SELECT or a DELETE: the primary key, or a bin equal to a value
+// or between two integers
+type aqlWhere struct {
+	pk         bool
+	bin        string
+	value      interface{} // string or int64
+	begin, end *int64
+}
+
+// aqlStatement - a parsed AQL statement
+type aqlStatement struct {
+	verb      string // select, show, stat, delete or truncate
+	object    string // what is shown or stat'ed: namespaces, sets, indexes, namespace, system or index
+	namespace string
+	set       string
+	index     string
+	bins      []string // the bins selected; all if empty
+	where     *aqlWhere
+	limit     int
+}
+
+// destructive - the statement changes the data; only admins may run them, like the destructive info commands
+func (s *aqlStatement) destructive() bool {
+	return s.verb == "delete" || s.verb == "truncate"
+}
+
+// aqlToken - a token of a statement; quoted is set for the string literals
+type aqlToken struct {
+	text   string
+	quoted bool
+}
+
+// tokenizeAQL - split the statement in identifiers, numbers, quoted strings and the symbols , . = * ;
+func tokenizeAQL(stmt string) ([]aqlToken, error) {
+	res := []aqlToken{}
+	runes := []rune(stmt)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '\'' || r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				end++
+			}
+			if end == len(runes) {
+				return nil, errors.New("Unterminated string")
+			}
+			res = append(res, aqlToken{text: string(runes[i+1 : end]), quoted: true})
+			i = end + 1
+		case strings.ContainsRune(",.=*;", r):
+			res = append(res, aqlToken{text: string(r)})
+			i++
+		default:
+			end := i
+			for end < len(runes) && !unicode.IsSpace(runes[end]) && !strings.ContainsRune(",.=*;'\"", runes[end]) {
+				end++
+			}
+			res = append(res, aqlToken{text: string(runes[i:end])})
+			i = end
+		}
+	}
+
+	// a trailing semicolon is allowed
+	if len(res) > 0 && !res[len(res)-1].quoted && res[len(res)-1].text == ";" {
+		res = res[:len(res)-1]
+	}
+	return res, nil
+}
+
+// aqlParser - a recursive descent parser of the supported subset of AQL:
+//
+//	SHOW NAMESPACES | SHOW SETS [<ns>] | SHOW INDEXES [<ns>]
+//	STAT SYSTEM | STAT NAMESPACE <ns> | STAT INDEX <ns> <index>
+//	SELECT * | <bin>, ... FROM <ns>[.<set>] [WHERE PK = <key> | <bin> = <value> | <bin> BETWEEN <n> AND <n>] [LIMIT <n>]
+//	DELETE FROM <ns>[.<set>] WHERE PK = <key>
+//	TRUNCATE <ns>[.<set>]
+type aqlParser struct {
+	tokens []aqlToken
+	pos    int
+}
+
+func (p *aqlParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+// next - the next token; empty at the end
+func (p *aqlParser) next() aqlToken {
+	if p.done() {
+		return aqlToken{}
+	}
+	p.pos++
+	return p.tokens[p.pos-1]
+}
+
+// peekKeyword - check whether the next token is the keyword, case insensitively
+func (p *aqlParser) peekKeyword(keyword string) bool {
+	return !p.done() && !p.tokens[p.pos].quoted && strings.EqualFold(p.tokens[p.pos].text, keyword)
+}
+
+func (p *aqlParser) expect(keyword string) error {
+	if !p.peekKeyword(keyword) {
+		return fmt.Errorf("Expected %s", strings.ToUpper(keyword))
+	}
+	p.pos++
+	return nil
+}
+
+// name - an identifier, or a quoted one
+func (p *aqlParser) name(what string) (string, error) {
+	t := p.next()
+	if t.text == "" || (!t.quoted && strings.ContainsAny(t.text, ",.=*;")) {
+		return "", fmt.Errorf("Expected a %s", what)
+	}
+	return t.text, nil
+}
+
+// value - a string literal or an integer
+func (p *aqlParser) value() (interface{}, error) {
+	t := p.next()
+	if t.quoted {
+		return t.text, nil
+	}
+	n, err := strconv.ParseInt(t.text, 10, 64)
+	if err != nil {
+		return nil, errors.New("Expected a quoted string or an integer")
+	}
+	return n, nil
+}
+
+func (p *aqlParser) integer() (int64, error) {
+	v, err := p.value()
+	if n, ok := v.(int64); ok && err == nil {
+		return n, nil
+	}
+	return 0, errors.New("Expected an integer")
+}
+
+// namespaceSet - <ns>[.<set>]
+func (p *aqlParser) namespaceSet(s *aqlStatement) (err error) {
+	if s.namespace, err = p.name("namespace"); err != nil {
+		return err
+	}
+	if !p.done() && p.tokens[p.pos].text == "." && !p.tokens[p.pos].quoted {
+		p.pos++
+		s.set, err = p.name("set")
+	}
+	return err
+}
+
+func (p *aqlParser) where(s *aqlStatement) error {
+	if !p.peekKeyword("where") {
+		return nil
+	}
+	p.pos++
+
+	bin, err := p.name("bin")
+	if err != nil {
+		return err
+	}
+	w := &aqlWhere{bin: bin, pk: strings.EqualFold(bin, "pk")}
+
+	switch {
+	case p.peekKeyword("between") && !w.pk:
+		p.pos++
+		begin, err := p.integer()
+		if err != nil {
+			return err
+		}
+		if err := p.expect("and"); err != nil {
+			return err
+		}
+		end, err := p.integer()
+		if err != nil {
+			return err
+		}
+		w.begin, w.end = &begin, &end
+	case p.peekKeyword("="):
+		p.pos++
+		if w.value, err = p.value(); err != nil {
+			return err
+		}
+	default:
+		return errors.New("Expected = or BETWEEN")
+	}
+
+	s.where = w
+	return nil
+}
+
+func (p *aqlParser) parse() (*aqlStatement, error) {
+	verb := strings.ToLower(p.next().text)
+	s := &aqlStatement{verb: verb}
+
+	var err error
+	switch verb {
+	case "show":
+		s.object = strings.ToLower(p.next().text)
+		switch s.object {
+		case "namespaces":
+		case "sets", "indexes":
+			if !p.done() {
+				s.namespace, err = p.name("namespace")
+			}
+		default:
+			return nil, errors.New("Expected NAMESPACES, SETS or INDEXES")
+		}
+
+	case "stat":
+		s.object = strings.ToLower(p.next().text)
+		switch s.object {
+		case "system":
+		case "namespace":
+			s.namespace, err = p.name("namespace")
+		case "index":
+			if s.namespace, err = p.name("namespace"); err == nil {
+				s.index, err = p.name("index")
+			}
+		default:
+			return nil, errors.New("Expected SYSTEM, NAMESPACE or INDEX")
+		}
+
+	case "select":
+		if p.peekKeyword("*") {
+			p.pos++
+		} else {
+			for {
+				bin, err := p.name("bin")
+				if err != nil {
+					return nil, err
+				}
+				s.bins = append(s.bins, bin)
+				if !p.peekKeyword(",") {
+					break
+				}
+				p.pos++
+			}
+		}
+		if err := p.expect("from"); err != nil {
+			return nil, err
+		}
+		if err := p.namespaceSet(s); err != nil {
+			return nil, err
+		}
+		if err := p.where(s); err != nil {
+			return nil, err
+		}
+		if p.peekKeyword("limit") {
+			p.pos++
+			limit, err := p.integer()
+			if err != nil {
+				return nil, err
+			}
+			s.limit = int(limit)
+		}
+
+	case "delete":
+		if err := p.expect("from"); err != nil {
+			return nil, err
+		}
+		if err := p.namespaceSet(s); err != nil {
+			return nil, err
+		}
+		if err := p.where(s); err != nil {
+			return nil, err
+		}
+		if s.where == nil || !s.where.pk {
+			return nil, errors.New("DELETE requires WHERE PK = <key>")
+		}
+
+	case "truncate":
+		err = p.namespaceSet(s)
+
+	case "":
+		return nil, errors.New("Empty statement")
+
+	default:
+		return nil, fmt.Errorf("Statement %s is not supported", strings.ToUpper(verb))
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, fmt.Errorf("Unexpected %s", p.next().text)
+	}
+	return s, nil
+}
+
+// parseAQL - parse a statement of the supported subset of AQL
+func parseAQL(stmt string) (*aqlStatement, error) {
+	tokens, err := tokenizeAQL(stmt)
+	if err != nil {
+		return nil, err
+	}
+	return (&aqlParser{tokens: tokens}).parse()
+}
+
+// ExecAQL - run a statement of the supported subset of AQL; DELETE and TRUNCATE require admin privileges
+func (c *Cluster) ExecAQL(ctx context.Context, stmt string) (*AQLResult, error) {
+	s, err := parseAQL(stmt)
+	if err != nil {
+		return nil, common.Errorf(common.KindInvalid, "Invalid statement: %s", err.Error())
+	}
+
+	if s.destructive() && !c.IsAdmin() {
+		return nil, common.Errorf(common.KindNotAllowed, "%s requires admin privileges", strings.ToUpper(s.verb))
+	}
+
+	switch s.verb {
+	case "show":
+		return c.aqlShow(s)
+	case "stat":
+		return c.aqlStat(s)
+	case "select":
+		return c.aqlSelect(ctx, s)
+	}
+	return c.aqlModify(s)
+}
+
+func (c *Cluster) aqlShow(s *aqlStatement) (*AQLResult, error) {
+	namespaces := c.NamespaceList()
+	if s.namespace != "" {
+		namespaces = []string{s.namespace}
+	}
+
+	switch s.object {
+	case "namespaces":
+		res := &AQLResult{Columns: []string{"namespace"}, Rows: [][]interface{}{}}
+		for _, ns := range namespaces {
+			res.Rows = append(res.Rows, []interface{}{ns})
+		}
+		return res, nil
+
+	case "sets":
+		res := &AQLResult{Columns: []string{"ns", "set", "objects", "tombstones", "memory_data_bytes"}, Rows: [][]interface{}{}}
+		for _, ns := range namespaces {
+			sets := c.NamespaceSetsInfo(ns)
+			sort.Slice(sets, func(i, j int) bool { return sets[i].TryString("set", "") < sets[j].TryString("set", "") })
+			for _, set := range sets {
+				res.Rows = append(res.Rows, []interface{}{
+					ns, set.TryString("set", ""), set.TryInt("objects", 0), set.TryInt("tombstones", 0), set.TryInt("memory_data_bytes", 0),
+				})
+			}
+		}
+		return res, nil
+	}
+
+	res := &AQLResult{Columns: []string{"ns", "set", "indexname", "bin", "type", "indextype", "state"}, Rows: [][]interface{}{}}
+	for _, ns := range namespaces {
+		indexes := c.NamespaceIndexInfo(ns)
+		names := make([]string, 0, len(indexes))
+		for name := range indexes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			index := indexes[name]
+			res.Rows = append(res.Rows, []interface{}{
+				ns, index.TryString("set", ""), name, index.TryString("bin", index.TryString("bins", "")),
+				index.TryString("type", ""), index.TryString("indextype", ""), index.TryString("state", ""),
+			})
+		}
+	}
+	return res, nil
+}
+
+// aqlStat - the stats of every node as a column, by their name
+func (c *Cluster) aqlStat(s *aqlStatement) (*AQLResult, error) {
+	if strings.ContainsAny(s.namespace+s.index, "/:;\n") {
+		return nil, common.Errorf(common.KindInvalid, "Invalid name")
+	}
+
+	cmd := "statistics"
+	switch s.object {
+	case "namespace":
+		cmd = "namespace/" + s.namespace
+	case "index":
+		cmd = "sindex/" + s.namespace + "/" + s.index
+	}
+
+	// the stats are read with info commands, which may be restricted in the config
+	if err := c.CheckInfoCommand(cmd); err != nil {
+		return nil, err
+	}
+
+	res, err := c.RequestInfoAll(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]*Node, 0, len(res))
+	for node := range res {
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Address() < nodes[j].Address() })
+
+	stats := make([]map[string]string, len(nodes))
+	names := []string{}
+	for i, node := range nodes {
+		raw := strings.TrimSpace(res[node])
+		if sindexStatFailed(raw) {
+			return nil, fmt.Errorf("%s: %s", node.Address(), raw)
+		}
+		stats[i] = parseInfoPairs(strings.Split(strings.TrimSuffix(raw, ";"), ";"))
+		for name := range stats[i] {
+			names = append(names, name)
+		}
+	}
+
+	result := &AQLResult{Columns: []string{"stat"}, Rows: [][]interface{}{}}
+	for _, node := range nodes {
+		result.Columns = append(result.Columns, node.Address())
+	}
+	for _, name := range common.SortStrings(common.StrUniq(names)) {
+		row := []interface{}{name}
+		for i := range nodes {
+			row = append(row, stats[i][name])
+		}
+		result.Rows = append(result.Rows, row)
+	}
+	return result, nil
+}
+
+func (c *Cluster) aqlSelect(ctx context.Context, s *aqlStatement) (*AQLResult, error) {
+	limit := s.limit
+	if limit == 0 {
+		limit = SetSampleDefaultLimit
+	}
+
+	var records []SampleRecord
+	var err error
+	switch {
+	case s.where == nil:
+		records, err = c.SampleSet(ctx, s.namespace, s.set, limit, 100, nil, s.bins...)
+
+	case s.where.pk:
+		var record *SampleRecord
+		if record, err = c.aqlGet(s); record != nil {
+			records = []SampleRecord{*record}
+		}
+
+	default:
+		index := c.aqlIndexOn(s.namespace, s.set, s.where.bin)
+		if index == "" {
+			return nil, common.Errorf(common.KindInvalid, "No index on bin %s of %s", s.where.bin, strings.TrimSuffix(s.namespace+"."+s.set, "."))
+		}
+
+		q := SindexQuery{Limit: limit, Timeout: 10 * time.Second, Bins: s.bins, Begin: s.where.begin, End: s.where.end}
+		if s.where.value != nil {
+			q.Value = fmt.Sprint(s.where.value)
+		}
+		records, err = c.QuerySindex(ctx, s.namespace, index, q)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// the bins selected in their order, or all the bins of the records
+	columns := s.bins
+	if len(columns) == 0 {
+		names := []string{}
+		for _, r := range records {
+			for name := range r.Bins {
+				names = append(names, name)
+			}
+		}
+		columns = common.SortStrings(common.StrUniq(names))
+	}
+
+	res := &AQLResult{Columns: append(append([]string{}, columns...), _aqlDigestColumn, _aqlGenerationColumn, _aqlTTLColumn), Rows: [][]interface{}{}}
+	for _, r := range records {
+		row := make([]interface{}, 0, len(res.Columns))
+		for _, bin := range columns {
+			row = append(row, r.Bins[bin])
+		}
+		res.Rows = append(res.Rows, append(row, r.Digest, r.Generation, r.TTL))
+	}
+	return res, nil
+}
+
+// aqlIndexOn - the name of the index on the bin of the set
+func (c *Cluster) aqlIndexOn(namespace, set, bin string) string {
+	for name, index := range c.NamespaceIndexInfo(namespace) {
+		indexSet := index.TryString("set", "")
+		if indexSet == "NULL" {
+			indexSet = ""
+		}
+		if indexSet == set && index.TryString("bin", index.TryString("bins", "")) == bin {
+			return name
+		}
+	}
+	return ""
+}
+
+// aqlGet - the record with the primary key; nil if it does not exist
+func (c *Cluster) aqlGet(s *aqlStatement) (*SampleRecord, error) {
+	client := c.origClient()
+	if client == nil {
+		return nil, common.Errorf(common.KindUnavailable, "Cluster %s has been decommissioned", c.ID())
+	}
+
+	key, aerr := as.NewKey(s.namespace, s.set, s.where.value)
+	if aerr != nil {
+		return nil, aerr
+	}
+
+	r, aerr := client.Get(nil, key, s.bins...)
+	if errors.Is(aerr, as.ErrKeyNotFound) {
+		return nil, nil
+	} else if aerr != nil {
+		return nil, aerr
+	}
+
+	record := sampleRecord(r)
+	return &record, nil
+}
+
+// aqlModify - run a DELETE or a TRUNCATE
+func (c *Cluster) aqlModify(s *aqlStatement) (*AQLResult, error) {
+	client := c.origClient()
+	if client == nil {
+		return nil, common.Errorf(common.KindUnavailable, "Cluster %s has been decommissioned", c.ID())
+	}
+
+	res := &AQLResult{Columns: []string{}, Rows: [][]interface{}{}}
+	if s.verb == "truncate" {
+		if err := client.Truncate(nil, s.namespace, s.set, nil); err != nil {
+			return nil, err
+		}
+		res.Message = "OK, truncated"
+		return res, nil
+	}
+
+	key, aerr := as.NewKey(s.namespace, s.set, s.where.value)
+	if aerr != nil {
+		return nil, aerr
+	}
+	existed, aerr := client.Delete(nil, key)
+	if aerr != nil {
+		return nil, aerr
+	}
+
+	res.Message = "OK, 1 record affected."
+	if !existed {
+		res.Message = "OK, 0 records affected."
+	}
+	return res, nil
+}
@@ -0,0 +1,81 @@
+package models
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AQL", func() {
+
+	one, five := int64(1), int64(5)
+
+	DescribeTable("parsing the valid statements",
+		func(stmt string, expected *aqlStatement) {
+			s, err := parseAQL(stmt)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s).To(Equal(expected))
+		},
+		Entry("show namespaces", "SHOW NAMESPACES", &aqlStatement{verb: "show", object: "namespaces"}),
+		Entry("show the sets of a namespace", "show sets test;", &aqlStatement{verb: "show", object: "sets", namespace: "test"}),
+		Entry("show all indexes", "Show Indexes", &aqlStatement{verb: "show", object: "indexes"}),
+		Entry("stat system", "STAT SYSTEM", &aqlStatement{verb: "stat", object: "system"}),
+		Entry("stat a namespace", "STAT NAMESPACE test", &aqlStatement{verb: "stat", object: "namespace", namespace: "test"}),
+		Entry("stat an index", "STAT INDEX test idx_age", &aqlStatement{verb: "stat", object: "index", namespace: "test", index: "idx_age"}),
+		Entry("select all bins by the primary key", "SELECT * FROM test.demo WHERE PK = 'k1'", &aqlStatement{
+			verb: "select", namespace: "test", set: "demo", where: &aqlWhere{pk: true, bin: "PK", value: "k1"},
+		}),
+		Entry("select bins by a bin value", `select name, age from test where name = "Joe Doe" limit 10`, &aqlStatement{
+			verb: "select", namespace: "test", bins: []string{"name", "age"}, where: &aqlWhere{bin: "name", value: "Joe Doe"}, limit: 10,
+		}),
+		Entry("select a range", "SELECT * FROM test.demo WHERE age BETWEEN 1 AND 5", &aqlStatement{
+			verb: "select", namespace: "test", set: "demo", where: &aqlWhere{bin: "age", begin: &one, end: &five},
+		}),
+		Entry("select quoted names", `SELECT 'my bin' FROM "test"."my set"`, &aqlStatement{
+			verb: "select", namespace: "test", set: "my set", bins: []string{"my bin"},
+		}),
+		Entry("delete by an integer key", "DELETE FROM test.demo WHERE PK = 5", &aqlStatement{
+			verb: "delete", namespace: "test", set: "demo", where: &aqlWhere{pk: true, bin: "PK", value: int64(5)},
+		}),
+		Entry("truncate a set", "TRUNCATE test.demo;", &aqlStatement{verb: "truncate", namespace: "test", set: "demo"}),
+	)
+
+	DescribeTable("rejecting the malformed statements",
+		func(stmt, msg string) {
+			_, err := parseAQL(stmt)
+			Expect(err).To(MatchError(msg))
+		},
+		Entry("an empty statement", " ;", "Empty statement"),
+		Entry("an unsupported statement", "DROP INDEX test idx", "Statement DROP is not supported"),
+		Entry("an unknown show", "SHOW BINS", "Expected NAMESPACES, SETS or INDEXES"),
+		Entry("an unknown stat", "STAT SET test", "Expected SYSTEM, NAMESPACE or INDEX"),
+		Entry("a stat of an index without its name", "STAT INDEX test", "Expected a index"),
+		Entry("a select without from", "SELECT * test", "Expected FROM"),
+		Entry("a select without a namespace", "SELECT * FROM", "Expected a namespace"),
+		Entry("a select without a set after the dot", "SELECT * FROM test.", "Expected a set"),
+		Entry("a dangling comma", "SELECT a, FROM test", "Expected FROM"),
+		Entry("an unsupported condition", "SELECT * FROM test WHERE age > 1", "Expected = or BETWEEN"),
+		Entry("a range of the primary key", "SELECT * FROM test WHERE PK BETWEEN 1 AND 2", "Expected = or BETWEEN"),
+		Entry("a range of strings", "SELECT * FROM test WHERE age BETWEEN 'a' AND 'b'", "Expected an integer"),
+		Entry("a range without and", "SELECT * FROM test WHERE age BETWEEN 1 5", "Expected AND"),
+		Entry("an unquoted string", "SELECT * FROM test WHERE name = Joe", "Expected a quoted string or an integer"),
+		Entry("an invalid limit", "SELECT * FROM test LIMIT ten", "Expected an integer"),
+		Entry("an unterminated string", "SELECT * FROM test WHERE PK = 'k1", "Unterminated string"),
+		Entry("a delete without the primary key", "DELETE FROM test WHERE name = 'Joe'", "DELETE requires WHERE PK = <key>"),
+		Entry("a delete without a condition", "DELETE FROM test.demo", "DELETE requires WHERE PK = <key>"),
+		Entry("trailing tokens", "SHOW NAMESPACES now", "Unexpected now"),
+		Entry("two statements", "TRUNCATE test; TRUNCATE bar", "Unexpected ;"),
+	)
+
+	DescribeTable("telling the destructive statements",
+		func(stmt string, destructive bool) {
+			s, err := parseAQL(stmt)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s.destructive()).To(Equal(destructive))
+		},
+		Entry("select", "SELECT * FROM test", false),
+		Entry("show", "SHOW SETS", false),
+		Entry("delete", "DELETE FROM test WHERE PK = 1", true),
+		Entry("truncate", "TRUNCATE test", true),
+	)
+})
@@ -2,7 +2,6 @@ package models
 
 import (
 	"bufio"
-	"errors"
 	"fmt"
 	"io"
 	"regexp"
@@ -75,7 +74,7 @@ func (b *Backup) Execute() error {
 	node := b.cluster.RandomActiveNode()
 	if node == nil {
 		b.UpdateStatus(common.BackupStatusFailed)
-		return errors.New("No active nodes found in the cluster")
+		return common.Errorf(common.KindUnavailable, "No active nodes found in the cluster")
 	}
 
 	// try to connect to the remote address and run the command
@@ -0,0 +1,351 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	as "github.com/aerospike/aerospike-client-go/v5"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// the limits of a benchmark; it runs within a request, so it is kept shorter than the default request timeout
+const (
+	benchmarkMaxDuration        = 20
+	benchmarkDefaultDuration    = 10
+	benchmarkMaxKeys            = 1000000
+	benchmarkDefaultKeys        = 10000
+	benchmarkDefaultRecordSize  = 1024
+	benchmarkDefaultSet         = "amc_benchmark"
+	benchmarkMaxConsecutiveFail = 100
+)
+
+// the latency buckets grow by 2%, so that the percentiles of millions of operations are within 2%
+const _latencyBucketGrowth = 1.02
+
+// BenchmarkSpec - a benchmark of reads and writes of random keys among keys records of the set,
+// with a bin of record_size bytes, at rate operations per second (0 for as fast as the cluster allows)
+type BenchmarkSpec struct {
+	Namespace   string `json:"namespace"`
+	Set         string `json:"set"`
+	Duration    int    `json:"duration"` // seconds
+	Rate        int    `json:"rate"`
+	ReadPct     *int   `json:"read_pct"` // 50 if unset
+	RecordSize  int    `json:"record_size"`
+	Keys        int    `json:"keys"`
+	Concurrency int    `json:"concurrency"`
+}
+
+// BenchmarkOpStats - the operations of a type of a benchmark and their latencies in milliseconds
+type BenchmarkOpStats struct {
+	Count     int64   `json:"count"`
+	Errors    int64   `json:"errors"`
+	NotFound  int64   `json:"not_found,omitempty"` // the reads of the keys which were not written yet
+	OpsPerSec float64 `json:"ops_per_sec"`
+	MeanMs    float64 `json:"mean_ms"`
+	P50Ms     float64 `json:"p50_ms"`
+	P90Ms     float64 `json:"p90_ms"`
+	P99Ms     float64 `json:"p99_ms"`
+	MaxMs     float64 `json:"max_ms"`
+}
+
+// BenchmarkResult - the throughput and the latencies achieved by a benchmark
+type BenchmarkResult struct {
+	Spec        BenchmarkSpec    `json:"spec"`
+	DurationSec float64          `json:"duration_sec"`
+	OpsPerSec   float64          `json:"ops_per_sec"`
+	Reads       BenchmarkOpStats `json:"reads"`
+	Writes      BenchmarkOpStats `json:"writes"`
+	LastError   string           `json:"last_error,omitempty"`
+}
+
+// validate - check the spec and fill in its defaults
+func (spec *BenchmarkSpec) validate() error {
+	if spec.Namespace == "" {
+		return common.Errorf(common.KindInvalid, "Invalid parameters: namespace is required")
+	}
+	if spec.Set == "" {
+		spec.Set = benchmarkDefaultSet
+	}
+	if spec.Duration == 0 {
+		spec.Duration = benchmarkDefaultDuration
+	}
+	if spec.Duration < 0 || spec.Duration > benchmarkMaxDuration {
+		return common.Errorf(common.KindInvalid, "The duration must be between 1 and %d seconds", benchmarkMaxDuration)
+	}
+	if spec.Rate < 0 {
+		return common.Errorf(common.KindInvalid, "Invalid rate")
+	}
+	if spec.ReadPct == nil {
+		readPct := 50
+		spec.ReadPct = &readPct
+	}
+	if *spec.ReadPct < 0 || *spec.ReadPct > 100 {
+		return common.Errorf(common.KindInvalid, "The read percentage must be between 0 and 100")
+	}
+	if spec.RecordSize == 0 {
+		spec.RecordSize = benchmarkDefaultRecordSize
+	}
+	if spec.RecordSize < 0 || spec.RecordSize > dataGenMaxBinSize {
+		return common.Errorf(common.KindInvalid, "The record size must be between 1 and %d", dataGenMaxBinSize)
+	}
+	if spec.Keys == 0 {
+		spec.Keys = benchmarkDefaultKeys
+	}
+	if spec.Keys < 0 || spec.Keys > benchmarkMaxKeys {
+		return common.Errorf(common.KindInvalid, "The number of keys must be between 1 and %d", benchmarkMaxKeys)
+	}
+	if spec.Concurrency == 0 {
+		spec.Concurrency = dataGenDefaultConcurrency
+	}
+	if spec.Concurrency < 0 || spec.Concurrency > dataGenMaxConcurrency {
+		return common.Errorf(common.KindInvalid, "The concurrency must be between 1 and %d", dataGenMaxConcurrency)
+	}
+	return nil
+}
+
+// RunBenchmark - run the benchmark and return its results; only on the test clusters, like the data generator,
+// and one at a time. The records written are left in the set.
+func (c *Cluster) RunBenchmark(ctx context.Context, spec BenchmarkSpec) (*BenchmarkResult, error) {
+	if err := c.checkTestCluster("Benchmarking"); err != nil {
+		return nil, err
+	}
+	if err := spec.validate(); err != nil {
+		return nil, err
+	}
+	if err := c.checkNamespace(spec.Namespace); err != nil {
+		return nil, err
+	}
+	duration := time.Duration(spec.Duration) * time.Second
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < duration+time.Second {
+		return nil, common.Errorf(common.KindInvalid, "The duration must be shorter than the timeout of the request")
+	}
+
+	client := c.origClient()
+	if client == nil {
+		return nil, common.Errorf(common.KindUnavailable, "Cluster %s has been decommissioned", c.ID())
+	}
+
+	if !atomic.CompareAndSwapInt32(&c.benchmarking, 0, 1) {
+		return nil, common.Errorf(common.KindAlreadyExists, "A benchmark is already running on the cluster")
+	}
+	defer atomic.StoreInt32(&c.benchmarking, 0)
+
+	log.WithField("cluster_id", c.ID()).Infof("Running a benchmark of %s on %s.%s", duration, spec.Namespace, spec.Set)
+
+	ctx, cancel := context.WithTimeout(ctx, duration)
+	defer cancel()
+
+	var tokens chan struct{}
+	if spec.Rate > 0 {
+		tokens = make(chan struct{})
+		go paceBenchmark(ctx, spec.Rate, tokens)
+	}
+
+	workers := make([]*benchmarkWorker, spec.Concurrency)
+	var wg sync.WaitGroup
+	start := time.Now()
+	for i := range workers {
+		workers[i] = newBenchmarkWorker(client, &spec, time.Now().UnixNano()+int64(i))
+		wg.Add(1)
+		go func(w *benchmarkWorker) {
+			defer wg.Done()
+			if err := w.run(ctx, tokens); err != nil {
+				cancel()
+			}
+		}(workers[i])
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	reads, writes := &latencyHistogram{}, &latencyHistogram{}
+	res := &BenchmarkResult{Spec: spec, DurationSec: elapsed.Seconds()}
+	for _, w := range workers {
+		reads.merge(&w.reads)
+		writes.merge(&w.writes)
+		res.Reads.Errors += w.readErrors
+		res.Reads.NotFound += w.notFound
+		res.Writes.Errors += w.writeErrors
+		if w.lastError != "" {
+			res.LastError = w.lastError
+		}
+	}
+	res.Reads = reads.stats(res.Reads, elapsed)
+	res.Writes = writes.stats(res.Writes, elapsed)
+	res.OpsPerSec = res.Reads.OpsPerSec + res.Writes.OpsPerSec
+	return res, nil
+}
+
+// paceBenchmark - hand out the operations at the rate until the context is done
+func paceBenchmark(ctx context.Context, rate int, tokens chan<- struct{}) {
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+
+	start := time.Now()
+	sent := 0
+	for {
+		for due := int(time.Since(start).Seconds() * float64(rate)); sent < due; sent++ {
+			select {
+			case <-ctx.Done():
+				return
+			case tokens <- struct{}{}:
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// benchmarkWorker - the operations of a goroutine of a benchmark
+type benchmarkWorker struct {
+	client  *as.Client
+	spec    *BenchmarkSpec
+	rnd     *rand.Rand
+	payload []byte
+
+	reads, writes                     latencyHistogram
+	readErrors, writeErrors, notFound int64
+	lastError                         string
+}
+
+func newBenchmarkWorker(client *as.Client, spec *BenchmarkSpec, seed int64) *benchmarkWorker {
+	w := &benchmarkWorker{
+		client:  client,
+		spec:    spec,
+		rnd:     rand.New(rand.NewSource(seed)),
+		payload: make([]byte, spec.RecordSize),
+	}
+	w.rnd.Read(w.payload)
+	return w
+}
+
+// run - run operations until the context is done; fails after too many operations in a row failed
+func (w *benchmarkWorker) run(ctx context.Context, tokens <-chan struct{}) error {
+	readPolicy := as.NewPolicy()
+	readPolicy.TotalTimeout = time.Second
+	writePolicy := as.NewWritePolicy(0, 0)
+	writePolicy.TotalTimeout = time.Second
+
+	failures := 0
+	for ctx.Err() == nil {
+		if tokens != nil {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-tokens:
+			}
+		}
+
+		key, err := as.NewKey(w.spec.Namespace, w.spec.Set, w.rnd.Intn(w.spec.Keys))
+		if err != nil {
+			w.lastError = err.Error()
+			return err
+		}
+
+		var opErr error
+		start := time.Now()
+		if w.rnd.Intn(100) < *w.spec.ReadPct {
+			_, rerr := w.client.Get(readPolicy, key)
+			w.reads.record(time.Since(start))
+			if errors.Is(rerr, as.ErrKeyNotFound) {
+				w.notFound++
+			} else if rerr != nil {
+				w.readErrors++
+				opErr = rerr
+			}
+		} else {
+			werr := w.client.PutBins(writePolicy, key, as.NewBin("payload", w.payload))
+			w.writes.record(time.Since(start))
+			if werr != nil {
+				w.writeErrors++
+				opErr = werr
+			}
+		}
+
+		// the operations cut short by the end of the benchmark are not failures
+		if opErr != nil && ctx.Err() == nil {
+			w.lastError = opErr.Error()
+			if failures++; failures >= benchmarkMaxConsecutiveFail {
+				return opErr
+			}
+		} else if opErr == nil {
+			failures = 0
+		}
+	}
+	return nil
+}
+
+// latencyHistogram - the counts of the latencies by bucket; bucket i > 0 holds the latencies up to
+// _latencyBucketGrowth^i microseconds
+type latencyHistogram struct {
+	counts     []int64
+	count      int64
+	total, max time.Duration
+}
+
+func (h *latencyHistogram) record(d time.Duration) {
+	i := 0
+	if us := float64(d.Microseconds()); us >= 1 {
+		i = int(math.Ceil(math.Log(us) / math.Log(_latencyBucketGrowth)))
+	}
+	for len(h.counts) <= i {
+		h.counts = append(h.counts, 0)
+	}
+	h.counts[i]++
+	h.count++
+	h.total += d
+	if d > h.max {
+		h.max = d
+	}
+}
+
+func (h *latencyHistogram) merge(other *latencyHistogram) {
+	for len(h.counts) < len(other.counts) {
+		h.counts = append(h.counts, 0)
+	}
+	for i, n := range other.counts {
+		h.counts[i] += n
+	}
+	h.count += other.count
+	h.total += other.total
+	if other.max > h.max {
+		h.max = other.max
+	}
+}
+
+// percentile - the upper bound of the bucket of the nearest-rank percentile, in milliseconds
+func (h *latencyHistogram) percentile(p float64) float64 {
+	rank := int64(math.Ceil(p * float64(h.count)))
+	seen := int64(0)
+	for i, n := range h.counts {
+		if seen += n; seen >= rank {
+			return math.Min(math.Pow(_latencyBucketGrowth, float64(i))/1000, float64(h.max.Microseconds())/1000)
+		}
+	}
+	return float64(h.max.Microseconds()) / 1000
+}
+
+// stats - the stats of the operations with the latencies of the histogram
+func (h *latencyHistogram) stats(res BenchmarkOpStats, elapsed time.Duration) BenchmarkOpStats {
+	res.Count = h.count
+	if h.count == 0 {
+		return res
+	}
+	res.OpsPerSec = float64(h.count) / elapsed.Seconds()
+	res.MeanMs = float64((h.total / time.Duration(h.count)).Microseconds()) / 1000
+	res.P50Ms = h.percentile(.5)
+	res.P90Ms = h.percentile(.9)
+	res.P99Ms = h.percentile(.99)
+	res.MaxMs = float64(h.max.Microseconds()) / 1000
+	return res
+}
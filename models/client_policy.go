@@ -0,0 +1,193 @@
+package models
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"strings"
+	"time"
+
+	as "github.com/aerospike/aerospike-client-go/v5"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// maximum values accepted for the client policy options
+const (
+	_maxConnectionQueueSize = 1024
+	_maxClientTimeout       = 3600 // seconds
+)
+
+// ClientPolicyOptions - the connection settings of a monitored cluster;
+// timeouts are in seconds, and zero values keep the defaults
+type ClientPolicyOptions struct {
+	ConnectionQueueSize int `json:"connection_queue_size" form:"connection_queue_size"`
+	Timeout             int `json:"timeout" form:"timeout"`
+	IdleTimeout         int `json:"idle_timeout" form:"idle_timeout"`
+	LoginTimeout        int `json:"login_timeout" form:"login_timeout"`
+}
+
+// Validate - check the options are within their allowed ranges
+func (o ClientPolicyOptions) Validate() error {
+	if o.ConnectionQueueSize < 0 || o.ConnectionQueueSize > _maxConnectionQueueSize {
+		return common.Errorf(common.KindInvalid, "connection_queue_size must be between 0 and %d", _maxConnectionQueueSize)
+	}
+
+	for name, v := range map[string]int{"timeout": o.Timeout, "idle_timeout": o.IdleTimeout, "login_timeout": o.LoginTimeout} {
+		if v < 0 || v > _maxClientTimeout {
+			return common.Errorf(common.KindInvalid, "%s must be between 0 and %d seconds", name, _maxClientTimeout)
+		}
+	}
+
+	return nil
+}
+
+// Apply - set the non-zero options on the policy
+func (o ClientPolicyOptions) Apply(cp *as.ClientPolicy) {
+	if o.ConnectionQueueSize > 0 {
+		cp.ConnectionQueueSize = o.ConnectionQueueSize
+	}
+	if o.Timeout > 0 {
+		cp.Timeout = time.Duration(o.Timeout) * time.Second
+	}
+	if o.IdleTimeout > 0 {
+		cp.IdleTimeout = time.Duration(o.IdleTimeout) * time.Second
+	}
+	if o.LoginTimeout > 0 {
+		cp.LoginTimeout = time.Duration(o.LoginTimeout) * time.Second
+	}
+}
+
+var _authModes = map[string]as.AuthMode{
+	"internal": as.AuthModeInternal,
+	"external": as.AuthModeExternal,
+	"pki":      as.AuthModePKI,
+}
+
+// ParseAuthMode - get the authentication mode by its name: internal (the default), external, e.g. LDAP, or pki
+func ParseAuthMode(name string) (as.AuthMode, error) {
+	if name == "" {
+		return as.AuthModeInternal, nil
+	}
+	mode, exists := _authModes[strings.ToLower(name)]
+	if !exists {
+		return as.AuthModeInternal, common.Errorf(common.KindInvalid, "Invalid auth_mode %s; must be one of internal, external or pki", name)
+	}
+	return mode, nil
+}
+
+// AuthModeName - the name of the authentication mode, as accepted by ParseAuthMode
+func AuthModeName(mode as.AuthMode) string {
+	for name, m := range _authModes {
+		if m == mode {
+			return name
+		}
+	}
+	return "internal"
+}
+
+// ClusterTLSOptions - the TLS settings of a cluster registered from the UI or the API, in addition to
+// the certificate pools of the config file; the certificates and the key are PEM encoded
+type ClusterTLSOptions struct {
+	EncryptOnly bool   `json:"encrypt_only" form:"encrypt_only"`
+	CACert      string `json:"tls_ca_cert" form:"tls_ca_cert"`
+	ClientCert  string `json:"tls_client_cert" form:"tls_client_cert"`
+	ClientKey   string `json:"tls_client_key" form:"tls_client_key"`
+}
+
+// Enabled - whether TLS is requested by the options, regardless of the TLS name of the seeds
+func (o ClusterTLSOptions) Enabled() bool {
+	return o.EncryptOnly || o.CACert != "" || o.ClientCert != ""
+}
+
+// Config - build the TLS config of the connections to the cluster
+func (o ClusterTLSOptions) Config(config *common.Config) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		Certificates:             config.ClientPool(),
+		RootCAs:                  config.ServerPool(),
+		InsecureSkipVerify:       o.EncryptOnly,
+		PreferServerCipherSuites: true,
+	}
+
+	if o.CACert != "" {
+		pool := x509.NewCertPool()
+		if tlsConfig.RootCAs != nil {
+			pool = tlsConfig.RootCAs.Clone()
+		}
+		if !pool.AppendCertsFromPEM([]byte(o.CACert)) {
+			return nil, common.Errorf(common.KindInvalid, "tls_ca_cert has no valid PEM encoded certificate")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if o.ClientCert != "" || o.ClientKey != "" {
+		cert, err := tls.X509KeyPair([]byte(o.ClientCert), []byte(o.ClientKey))
+		if err != nil {
+			return nil, common.Errorf(common.KindInvalid, "Invalid tls_client_cert or tls_client_key: %s", err.Error())
+		}
+		tlsConfig.Certificates = append([]tls.Certificate{cert}, tlsConfig.Certificates...)
+	}
+
+	tlsConfig.BuildNameToCertificate()
+	return tlsConfig, nil
+}
+
+func clientPolicyOptions(cp *as.ClientPolicy) ClientPolicyOptions {
+	return ClientPolicyOptions{
+		ConnectionQueueSize: cp.ConnectionQueueSize,
+		Timeout:             int(cp.Timeout / time.Second),
+		IdleTimeout:         int(cp.IdleTimeout / time.Second),
+		LoginTimeout:        int(cp.LoginTimeout / time.Second),
+	}
+}
+
+// ClientPolicy - get the connection settings of the cluster
+func (c *Cluster) ClientPolicy() ClientPolicyOptions {
+	c.connMutex.Lock()
+	defer c.connMutex.Unlock()
+
+	if c.policy == nil {
+		return ClientPolicyOptions{}
+	}
+	return clientPolicyOptions(c.policy)
+}
+
+// SetClientPolicy - change the connection settings of the cluster and reconnect with them;
+// the nodes and their history are kept
+func (c *Cluster) SetClientPolicy(opts ClientPolicyOptions) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+
+	c.connMutex.Lock()
+	defer c.connMutex.Unlock()
+
+	if c.policy == nil {
+		return errors.New("The connection settings of this cluster cannot be changed")
+	}
+
+	policy := *c.policy
+	opts.Apply(&policy)
+
+	// connections are opened on the next request if the cluster is idle
+	if !c.Sleeping() {
+		client, err := as.NewClientWithPolicyAndHost(&policy, c.connectHosts()...)
+		if err != nil {
+			return err
+		}
+
+		old := c.origClient()
+		c.client.Set(client)
+		if old != nil {
+			old.Close()
+		}
+
+		c.updateCluster()
+	}
+
+	c.policy = &policy
+	log.Infof("Connection settings of cluster %s changed to %+v", c.ID(), clientPolicyOptions(&policy))
+
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"runtime/debug"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	as "github.com/aerospike/aerospike-client-go/v5"
@@ -15,7 +16,6 @@ import (
 	version "github.com/mcuadros/go-version"
 	log "github.com/sirupsen/logrus"
 
-	// "github.com/sasha-s/go-deadlock"
 	uuid "github.com/satori/go.uuid"
 
 	"github.com/aerospike-community/amc/common"
@@ -34,18 +34,57 @@ type Cluster struct {
 	//pinged by user
 	lastPing common.SyncValue //time.Time
 
+	// when the running or the last update started, and how long the last one took
+	updateStarted      common.SyncValue //time.Time
+	lastUpdateDuration common.SyncValue //time.Duration
+
 	// _datacenterInfo                      common.SyncStats
 	aggNodeStats, aggNodeCalcStats       common.SyncStats
 	aggNsStats, aggNsCalcStats           common.SyncValue //map[string]common.Stats
 	aggTotalNsStats, aggTotalNsCalcStats common.SyncStats
 	aggNsSetStats                        common.SyncValue //map[string]map[string]common.Stats // [namespace][set]aggregated stats
 	jobs                                 common.SyncValue //[]common.Stats
+	jobHistory                           common.SyncValue //[]common.Stats
+	healthReport                         common.SyncValue //*HealthCheckReport
+
+	// the state of the cluster at the end of the last update
+	snapshot common.SyncValue //*ClusterSnapshot
 
 	// either a uuid.V4, or a sorted comma delimited string of host:port
 	uuid            string
 	securityEnabled bool
 	updateInterval  common.SyncValue //int // seconds
 
+	// polling backs off when updates take longer than the update interval
+	pollBackoff              common.SyncValue //int
+	updating                 int32
+	benchmarking             int32
+	slowUpdates, fastUpdates int
+
+	// the set aggregates of the last update by namespace
+	setAggregates map[string]*nsSetAggregate
+
+	// the retention of the history is divided by this factor to stay within the memory budget
+	historyShrinkFactor common.SyncValue //int
+
+	// the policy used to reconnect after the connections were closed while the cluster was idle
+	policy    *as.ClientPolicy
+	sleeping  common.SyncValue //bool
+	connMutex common.Mutex
+
+	// the addresses of the live nodes, used along with the seeds to reconnect
+	knownHosts common.SyncValue //[]*as.Host
+
+	// the DNS name, SRV record or Kubernetes service the cluster is seeded from
+	discoverySeed common.SyncValue //*discoverySeed
+
+	// the optional stat groups collected
+	statProfile common.SyncValue //*StatProfile
+
+	// the period of the summary reports, overriding the one of the mailer, and the stats of the current period
+	reportSchedule common.SyncValue //string
+	summary        *summaryPeriod
+
 	seeds    common.SyncValue //[]*as.Host
 	alias    common.SyncValue //string
 	user     common.SyncValue //string
@@ -67,10 +106,9 @@ type Cluster struct {
 
 	activeBackup  common.SyncValue //*Backup
 	activeRestore common.SyncValue //*Restore
+	dataGenerator common.SyncValue //*DataGenerator
 
 	redAlertCount common.SyncValue
-
-	// mutex deadlock.RWMutex
 }
 
 // newCluster - create new Cluster struct
@@ -84,13 +122,19 @@ func newCluster(observer *ObserverT, client *as.Client, alias, user, password st
 		lastPing:       common.NewSyncValue(time.Time{}),                        //seconds
 		permanent:      common.NewSyncValue(false),                              //seconds
 		showInUI:       common.NewSyncValue(false),
+		pollBackoff:    common.NewSyncValue(1),
 		uuid:           uuid.NewV4().String(),
 		seeds:          common.NewSyncValue(seeds),
 		// _datacenterInfo: *common.NewSyncStats(nil),
 		alerts:        common.NewAlertBucket(50),
 		redAlertCount: common.NewSyncValue(0),
+		jobHistory:    common.NewSyncValue([]common.Stats{}),
+		healthReport:  common.NewSyncValue(nil),
+		summary:       &summaryPeriod{},
 	}
 
+	newCluster.historyShrinkFactor.Set(1)
+	newCluster.sleeping.Set(false)
 	newCluster.SetAlias(alias)
 
 	if user != "" {
@@ -114,13 +158,19 @@ func (c *Cluster) setPermanent(v bool) {
 	c.permanent.Set(v)
 }
 
+// IsPermanent - check if the cluster is loaded from the config file
+func (c *Cluster) IsPermanent() bool {
+	return c.permanent.Get().(bool)
+}
+
 // ShowInUI - get show in UI flag
 func (c *Cluster) ShowInUI() bool {
 	return c.showInUI.Get().(bool)
 }
 
 func (c *Cluster) origClient() *as.Client {
-	return c.client.Get().(*as.Client)
+	client, _ := c.client.Get().(*as.Client)
+	return client
 }
 
 func (c *Cluster) updateLastestPing() {
@@ -135,14 +185,15 @@ func (c *Cluster) shouldAutoRemove() bool {
 	}
 
 	// InactiveDurBeforeRemoval <= 0 means never remove
-	return c.observer.config.AMC.InactiveDurBeforeRemoval > 0 && time.Since(lastPing) > time.Duration(c.observer.config.AMC.InactiveDurBeforeRemoval)*time.Second
+	idle := c.observer.config.InactiveDurBeforeRemoval()
+	return idle > 0 && time.Since(lastPing) > idle
 }
 
 // AddNode - Add node to cluster struct
 func (c *Cluster) AddNode(address string, port int) error {
 	nodes := c.nodesCopy()
 
-	hostAddrList, err := resolveHost(address)
+	hostAddrList, err := c.observer.resolveHost(address)
 	if err != nil || len(hostAddrList) == 0 {
 		return err
 	}
@@ -150,7 +201,7 @@ func (c *Cluster) AddNode(address string, port int) error {
 	for _, address := range hostAddrList {
 		host := as.NewHost(address, port)
 		if _, exists := nodes[*host]; exists {
-			return errors.New("Node already exists")
+			return common.Errorf(common.KindAlreadyExists, "Node already exists")
 		}
 	}
 
@@ -160,7 +211,7 @@ func (c *Cluster) AddNode(address string, port int) error {
 	// In case ALL nodes are removed
 	client := c.origClient()
 	if client == nil {
-		return fmt.Errorf("Cluster %s has been decommissioned", c.ID())
+		return common.Errorf(common.KindUnavailable, "Cluster %s has been decommissioned", c.ID())
 	}
 	client.Cluster().AddSeeds([]*as.Host{host})
 
@@ -178,7 +229,7 @@ func (c *Cluster) AddNode(address string, port int) error {
 func (c *Cluster) RemoveNodeByAddress(address string) error {
 	node := c.FindNodeByAddress(address)
 	if node == nil {
-		return fmt.Errorf("Node %s not found", address)
+		return common.Errorf(common.KindNodeNotFound, "Node %s not found", address)
 	}
 
 	if node.Status() == nodeStatus.On {
@@ -205,6 +256,7 @@ func (c *Cluster) UpdateInterval() int {
 // SetUpdateInterval - set update interval
 func (c *Cluster) SetUpdateInterval(val int) {
 	c.updateInterval.Set(val)
+	c.pollBackoff.Set(1)
 
 	for _, node := range c.Nodes() {
 		node.setUpdateInterval(val)
@@ -285,20 +337,20 @@ func (c *Cluster) Users() []*as.UserRoles {
 // UpdatePassword - update password
 func (c *Cluster) UpdatePassword(user, currentPass, newPass string) error {
 	if currentPass == newPass {
-		return errors.New("New password cannot be same as current password")
+		return common.Errorf(common.KindInvalid, "New password cannot be same as current password")
 	}
 
 	if pass := c.Password(); pass != nil && currentPass != *pass {
-		return errors.New("Invalid current password")
+		return common.Errorf(common.KindInvalid, "Invalid current password")
 	}
 
 	if u := c.User(); u != nil && user != *u {
-		return errors.New("Invalid current user")
+		return common.Errorf(common.KindInvalid, "Invalid current user")
 	}
 
 	client := c.origClient()
 	if client == nil {
-		return fmt.Errorf("Cluster %s has been decommissioned", c.ID())
+		return common.Errorf(common.KindUnavailable, "Cluster %s has been decommissioned", c.ID())
 	}
 
 	err := client.ChangePassword(nil, user, newPass)
@@ -314,7 +366,7 @@ func (c *Cluster) UpdatePassword(user, currentPass, newPass string) error {
 func (c *Cluster) ChangeUserPassword(user, pass string) error {
 	client := c.origClient()
 	if client == nil {
-		return fmt.Errorf("Cluster %s has been decommissioned", c.ID())
+		return common.Errorf(common.KindUnavailable, "Cluster %s has been decommissioned", c.ID())
 	}
 
 	return client.ChangePassword(nil, user, pass)
@@ -324,7 +376,7 @@ func (c *Cluster) ChangeUserPassword(user, pass string) error {
 func (c *Cluster) CreateUser(user, password string, roles []string) error {
 	client := c.origClient()
 	if client == nil {
-		return fmt.Errorf("Cluster %s has been decommissioned", c.ID())
+		return common.Errorf(common.KindUnavailable, "Cluster %s has been decommissioned", c.ID())
 	}
 	return client.CreateUser(nil, user, password, roles)
 }
@@ -333,7 +385,7 @@ func (c *Cluster) CreateUser(user, password string, roles []string) error {
 func (c *Cluster) DropUser(user string) error {
 	client := c.origClient()
 	if client == nil {
-		return fmt.Errorf("Cluster %s has been decommissioned", c.ID())
+		return common.Errorf(common.KindUnavailable, "Cluster %s has been decommissioned", c.ID())
 	}
 	return client.DropUser(nil, user)
 }
@@ -342,7 +394,7 @@ func (c *Cluster) DropUser(user string) error {
 func (c *Cluster) GrantRoles(user string, roles []string) error {
 	client := c.origClient()
 	if client == nil {
-		return fmt.Errorf("Cluster %s has been decommissioned", c.ID())
+		return common.Errorf(common.KindUnavailable, "Cluster %s has been decommissioned", c.ID())
 	}
 	return client.GrantRoles(nil, user, roles)
 }
@@ -351,7 +403,7 @@ func (c *Cluster) GrantRoles(user string, roles []string) error {
 func (c *Cluster) RevokeRoles(user string, roles []string) error {
 	client := c.origClient()
 	if client == nil {
-		return fmt.Errorf("Cluster %s has been decommissioned", c.ID())
+		return common.Errorf(common.KindUnavailable, "Cluster %s has been decommissioned", c.ID())
 	}
 	return client.RevokeRoles(nil, user, roles)
 }
@@ -360,7 +412,7 @@ func (c *Cluster) RevokeRoles(user string, roles []string) error {
 func (c *Cluster) CreateRole(role string, privileges []as.Privilege) error {
 	client := c.origClient()
 	if client == nil {
-		return fmt.Errorf("Cluster %s has been decommissioned", c.ID())
+		return common.Errorf(common.KindUnavailable, "Cluster %s has been decommissioned", c.ID())
 	}
 	return client.CreateRole(nil, role, privileges, nil, 0, 0)
 }
@@ -369,7 +421,7 @@ func (c *Cluster) CreateRole(role string, privileges []as.Privilege) error {
 func (c *Cluster) DropRole(role string) error {
 	client := c.origClient()
 	if client == nil {
-		return fmt.Errorf("Cluster %s has been decommissioned", c.ID())
+		return common.Errorf(common.KindUnavailable, "Cluster %s has been decommissioned", c.ID())
 	}
 	return client.DropRole(nil, role)
 }
@@ -378,7 +430,7 @@ func (c *Cluster) DropRole(role string) error {
 func (c *Cluster) AddPrivileges(role string, privileges []as.Privilege) error {
 	client := c.origClient()
 	if client == nil {
-		return fmt.Errorf("Cluster %s has been decommissioned", c.ID())
+		return common.Errorf(common.KindUnavailable, "Cluster %s has been decommissioned", c.ID())
 	}
 	return client.GrantPrivileges(nil, role, privileges)
 }
@@ -387,7 +439,7 @@ func (c *Cluster) AddPrivileges(role string, privileges []as.Privilege) error {
 func (c *Cluster) RemovePrivileges(role string, privileges []as.Privilege) error {
 	client := c.origClient()
 	if client == nil {
-		return fmt.Errorf("Cluster %s has been decommissioned", c.ID())
+		return common.Errorf(common.KindUnavailable, "Cluster %s has been decommissioned", c.ID())
 	}
 	return client.RevokePrivileges(nil, role, privileges)
 }
@@ -396,7 +448,7 @@ func (c *Cluster) RemovePrivileges(role string, privileges []as.Privilege) error
 func (c *Cluster) CreateUDF(name, body string) error {
 	client := c.origClient()
 	if client == nil {
-		return fmt.Errorf("Cluster %s has been decommissioned", c.ID())
+		return common.Errorf(common.KindUnavailable, "Cluster %s has been decommissioned", c.ID())
 	}
 	_, err := client.RegisterUDF(nil, []byte(body), name, as.LUA)
 	return err
@@ -406,7 +458,7 @@ func (c *Cluster) CreateUDF(name, body string) error {
 func (c *Cluster) DropUDF(udf string) error {
 	client := c.origClient()
 	if client == nil {
-		return fmt.Errorf("Cluster %s has been decommissioned", c.ID())
+		return common.Errorf(common.KindUnavailable, "Cluster %s has been decommissioned", c.ID())
 	}
 	_, err := client.RemoveUDF(nil, udf)
 	return err
@@ -416,9 +468,10 @@ func (c *Cluster) DropUDF(udf string) error {
 func (c *Cluster) CreateIndex(namespace, setName, indexName, binName, indexType string) error {
 	client := c.origClient()
 	if client == nil {
-		return fmt.Errorf("Cluster %s has been decommissioned", c.ID())
+		return common.Errorf(common.KindUnavailable, "Cluster %s has been decommissioned", c.ID())
 	}
 	_, err := client.CreateIndex(nil, namespace, setName, indexName, binName, as.IndexType(indexType))
+	c.invalidateSindexLists()
 	return err
 }
 
@@ -426,9 +479,11 @@ func (c *Cluster) CreateIndex(namespace, setName, indexName, binName, indexType
 func (c *Cluster) DropIndex(namespace, setName, indexName string) error {
 	client := c.origClient()
 	if client == nil {
-		return fmt.Errorf("Cluster %s has been decommissioned", c.ID())
+		return common.Errorf(common.KindUnavailable, "Cluster %s has been decommissioned", c.ID())
 	}
-	return client.DropIndex(nil, namespace, setName, indexName)
+	err := client.DropIndex(nil, namespace, setName, indexName)
+	c.invalidateSindexLists()
+	return err
 }
 
 // Nodes - add nodes to cluster
@@ -611,6 +666,9 @@ func (c *Cluster) RoleNames() []string {
 }
 
 func (c *Cluster) close() {
+	if g := c.DataGenerator(); g != nil {
+		g.Stop()
+	}
 	if cl := c.origClient(); cl != nil {
 		cl.Close()
 		c.client.Set(nil)
@@ -634,7 +692,7 @@ func (c *Cluster) shouldUpdate() bool {
 		return true
 	}
 	lastUpdate := lastUpdateIfc.(time.Time)
-	updateInterval := c.updateInterval.Get().(int)
+	updateInterval := c.EffectiveUpdateInterval()
 	return lastUpdate.IsZero() || time.Since(lastUpdate) >= time.Second*time.Duration(updateInterval)
 }
 
@@ -642,7 +700,8 @@ func (c *Cluster) setUpdatedAt(tm time.Time) {
 	c.lastUpdate.Set(tm)
 }
 
-func (c *Cluster) lastUpdated() (res time.Time) {
+// LastUpdated - the time the stats of the cluster were last updated; zero if they never were
+func (c *Cluster) LastUpdated() (res time.Time) {
 	lastUpdateIfc := c.lastUpdate.Get()
 	if lastUpdateIfc == nil {
 		return res
@@ -662,6 +721,13 @@ func (c *Cluster) update(wg *sync.WaitGroup) error {
 	if wg != nil {
 		defer wg.Done()
 	}
+
+	// skip if the previous update is still running
+	if !atomic.CompareAndSwapInt32(&c.updating, 0, 1) {
+		return nil
+	}
+	defer atomic.StoreInt32(&c.updating, 0)
+
 	defer func() { go c.SendEmailNotifications() }()
 
 	if !c.IsSet() {
@@ -674,13 +740,19 @@ func (c *Cluster) update(wg *sync.WaitGroup) error {
 	}
 
 	t := time.Now()
+	c.updateStarted.Set(t)
 	c.updateCluster()
+	c.reseed()
 	c.updateStats()
 	c.updateJobs()
 	c.updateUsers()
 	c.checkHealth()
 	c.updateRedAlertCount()
+	c.takeSnapshot()
+	c.recordSummaryStats()
 	log.Debugf("Updating stats for cluster %s took: %s", c.ID(), time.Since(t))
+	c.adaptPolling(time.Since(t))
+	c.lastUpdateDuration.Set(time.Since(t))
 
 	c.setUpdatedAt(time.Now())
 
@@ -701,38 +773,44 @@ func (c *Cluster) SendEmailNotifications() {
 		clusterName = *alias
 	}
 
+	config := c.observer.config
+	recipients := mailer.RecipientsByLocale(config, config.AlertEmails())
 	for _, alert := range newAlerts {
-		// make the data structure, and send the mail
-		msg := map[string]template.HTML{
-			"Title":   template.HTML(fmt.Sprintf("Alert")),
-			"Cluster": template.HTML(fmt.Sprintf("%s", clusterName)),
-			"Node":    template.HTML(fmt.Sprintf("%s", alert.NodeAddress)),
-			"Status":  template.HTML(fmt.Sprintf("<font color='%s'><strong>%s</strong></font>", alert.Status, strings.ToUpper(string(alert.Status)))),
-			"Message": template.HTML(fmt.Sprintf("%s", alert.Desc)),
-		}
+		// make the data structure, and send the mail to the recipients of every locale in their language
+		for locale, to := range recipients {
+			msg := map[string]template.HTML{
+				"Title":   template.HTML(config.Translate(locale, "Alert")),
+				"Cluster": template.HTML(fmt.Sprintf("%s", clusterName)),
+				"Node":    template.HTML(fmt.Sprintf("%s", alert.NodeAddress)),
+				"Status":  template.HTML(fmt.Sprintf("<font color='%s'><strong>%s</strong></font>", alert.Status, config.Translate(locale, strings.ToUpper(string(alert.Status))))),
+				"Message": template.HTML(config.Translate(locale, alert.Desc)),
+			}
+			subject := fmt.Sprintf(config.Translate(locale, "AMC Alert: %s"), sanitize.HTML(string(msg["Message"])))
 
-		go func(context map[string]template.HTML) {
-			for i := 0; i < 5; i++ {
-				err := mailer.SendMail(c.observer.config, "alerts/generic.html", "AMC Alert: "+sanitize.HTML(string(context["Message"])), context)
-				if err == nil {
-					break
-				}
+			go func(locale string, to []string, subject string, context map[string]template.HTML) {
+				for i := 0; i < 5; i++ {
+					err := mailer.SendLocalizedMailTo(config, locale, to, "alerts/generic.html", subject, context)
+					if err == nil {
+						break
+					}
 
-				log.Errorf("Failed to send the notification email: %s", err.Error())
-				time.Sleep(5 * time.Second)
-			}
-		}(msg)
+					log.Errorf("Failed to send the notification email: %s", err.Error())
+					time.Sleep(5 * time.Second)
+				}
+			}(locale, to, subject, msg)
+		}
 	}
 }
 
 func (c *Cluster) checkHealth() error {
+	c.healthReport.Set(c.HealthCheck())
 	return nil
 }
 
 func (c *Cluster) updateUsers() error {
 	client := c.origClient()
 	if client == nil {
-		return fmt.Errorf("Cluster %s has been decommissioned", c.ID())
+		return common.Errorf(common.KindUnavailable, "Cluster %s has been decommissioned", c.ID())
 	}
 
 	user := c.User()
@@ -771,13 +849,32 @@ func (c *Cluster) updateUsers() error {
 
 // RequestInfoAll - get all info attributes
 func (c *Cluster) RequestInfoAll(cmd string) (map[*Node]string, error) {
+	res, nodeErrs := c.RequestInfoNodes(c.Nodes(), 0, cmd)
+
+	errsStr := []string{}
+	for node, err := range nodeErrs {
+		errsStr = append(errsStr, err.Error())
+		res[node] = err.Error()
+	}
+
+	var err error
+	if len(errsStr) > 0 {
+		err = errors.New(strings.Join(errsStr, ", "))
+	}
+
+	return res, err
+}
+
+// RequestInfoNodes - request an info command from the nodes in parallel;
+// if timeout is 0, the client policy timeout is used.
+// Returns the result and the error of each node separately.
+func (c *Cluster) RequestInfoNodes(nodes []*Node, timeout time.Duration, cmd string) (map[*Node]string, map[*Node]error) {
 	type nodeCommand struct {
 		Node *Node
 		Res  map[string]string
 		Err  error
 	}
 
-	nodes := c.Nodes()
 	ch := make(chan nodeCommand, len(nodes))
 
 	wg := new(sync.WaitGroup)
@@ -787,7 +884,7 @@ func (c *Cluster) RequestInfoAll(cmd string) (map[*Node]string, error) {
 			go func(node *Node) {
 				defer wg.Done()
 
-				result, err := node.RequestInfo(1, cmd)
+				result, err := node.RequestInfoWithTimeout(1, timeout, cmd)
 				ch <- nodeCommand{Node: node, Res: result, Err: err}
 			}(node)
 		} else {
@@ -799,23 +896,17 @@ func (c *Cluster) RequestInfoAll(cmd string) (map[*Node]string, error) {
 	close(ch)
 
 	res := make(map[*Node]string, len(nodes))
-	errsStr := []string{}
+	errs := map[*Node]error{}
 	for r := range ch {
 		res[r.Node] = ""
 		if r.Err != nil {
-			errsStr = append(errsStr, r.Err.Error())
-			res[r.Node] = r.Err.Error()
+			errs[r.Node] = r.Err
 		} else if len(r.Res) > 0 && len(r.Res[cmd]) > 0 {
 			res[r.Node] = r.Res[cmd]
 		}
 	}
 
-	var err error
-	if len(errsStr) > 0 {
-		err = errors.New(strings.Join(errsStr, ", "))
-	}
-
-	return res, err
+	return res, errs
 }
 
 func (c *Cluster) registerNode(h *as.Host, n *Node) {
@@ -827,7 +918,7 @@ func (c *Cluster) registerNode(h *as.Host, n *Node) {
 func (c *Cluster) updateCluster() error {
 	client := c.origClient()
 	if client == nil {
-		return fmt.Errorf("Cluster %s has been decommissioned", c.ID())
+		return common.Errorf(common.KindUnavailable, "Cluster %s has been decommissioned", c.ID())
 	}
 
 	for _, n := range client.GetNodes() {
@@ -854,14 +945,15 @@ func (c *Cluster) updateCluster() error {
 func (c *Cluster) updateStats() error {
 	nodes := c.nodesCopy()
 
-	// do the info calls in parallel
+	// do the info calls in parallel, bounded by the observer's worker pool
 	wg := sync.WaitGroup{}
 	wg.Add(len(nodes))
 	for _, node := range nodes {
-		go func(node *Node) {
+		node := node
+		c.observer.workers.Submit(func() {
 			defer wg.Done()
 			node.update()
-		}(node)
+		})
 	}
 	wg.Wait()
 
@@ -869,13 +961,12 @@ func (c *Cluster) updateStats() error {
 	aggNodeCalcStats := common.Stats{}
 	aggNsStats := map[string]common.Stats{}
 	aggNsCalcStats := map[string]common.Stats{}
-	aggNsSetStats := map[string]map[string]common.Stats{}
+	aggNsSetStats := c.aggregateNsSetStats(nodes)
 
 	// then do the calculations synchronously, since they are fast and need synchronization anyway
 	for _, node := range nodes {
 		node.applyStatsToAggregate(aggNodeStats, aggNodeCalcStats)
 		node.applyNsStatsToAggregate(aggNsStats, aggNsCalcStats)
-		aggNsSetStats = node.applyNsSetStatsToAggregate(aggNsSetStats)
 	}
 
 	aggTotalNsStats := common.Stats{}
@@ -968,7 +1059,7 @@ func (c *Cluster) ThroughputSince(tm time.Time) map[string]map[string][]*common.
 	res := map[string]map[string][]*common.SinglePointValue{}
 
 	// if there are no data points, return immediately
-	if lu := c.lastUpdated(); lu.IsZero() || tm.After(c.lastUpdated()) {
+	if lu := c.LastUpdated(); lu.IsZero() || tm.After(c.LastUpdated()) {
 		return res
 	}
 
@@ -1063,8 +1154,9 @@ func (c *Cluster) NamespaceInfo(namespaces []string) map[string]common.Stats {
 		}
 	}
 
-	for _, stats := range res {
+	for nsName, stats := range res {
 		stats["repl-factor"] = stats.TryInt("repl-factor", 0) / int64(len(nodes))
+		stats["racks"] = c.NamespaceRacks(nsName)
 	}
 
 	return res
@@ -1100,6 +1192,7 @@ func (c *Cluster) NamespaceInfoPerNode(ns string, nodeAddrs []string) map[string
 			"master-objects-tombstones": fmt.Sprintf("%v / %v", common.Comma(nsStats.TryInt("master-objects", 0), ","), common.Comma(nsStats.TryInt("master_tombstones", 0), ",")),
 			"prole-objects-tombstones":  fmt.Sprintf("%v / %v", common.Comma(nsStats.TryInt("prole-objects", 0), ","), common.Comma(nsStats.TryInt("prole_tombstones", 0), ",")),
 			"least_available_pct":       ns.StatsAttr("available_pct"),
+			"rack-id":                   ns.RackID(),
 		}
 
 		subsetOfStats := []string{"expired-objects", "evicted-objects", "repl-factor",
@@ -1172,6 +1265,7 @@ func (c *Cluster) updateJobs() {
 	}
 
 	c.jobs.Set(res)
+	c.recordCompletedJobs(res)
 }
 
 // Jobs - get scan jobs
@@ -1404,7 +1498,7 @@ func (c *Cluster) Backup(
 	ScanPriority int) (*Backup, error) {
 
 	if c.CurrentBackup() != nil && c.CurrentBackup().Status == common.BackupStatusInProgress {
-		return nil, errors.New("Another backup operation already exists and is in progress")
+		return nil, common.Errorf(common.KindAlreadyExists, "Another backup operation already exists and is in progress")
 	}
 
 	newBackup := &Backup{
@@ -1458,7 +1552,7 @@ func (c *Cluster) Restore(
 	IgnoreGenerationNum bool) (*Restore, error) {
 
 	if c.CurrentRestore() != nil && c.CurrentRestore().Status == common.BackupStatusInProgress {
-		return nil, errors.New("Another backup operation already exists and is in progress")
+		return nil, common.Errorf(common.KindAlreadyExists, "Another backup operation already exists and is in progress")
 	}
 
 	newRestore := &Restore{
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// clusters pinged more recently than this are in use and never evicted
+const _minIdleBeforeEviction = time.Minute
+
+// makeRoomForCluster - make sure another cluster can be monitored without exceeding max_clusters
+// by evicting the least recently pinged cluster which is neither permanent nor in use.
+// Evicted clusters stay persisted, and are restored on startup if there is room.
+// The clusters in the config file are always monitored, but count towards the limit.
+func (o *ObserverT) makeRoomForCluster(ctx context.Context, sessionID string) error {
+	max := o.config.MaxClusters()
+	if max <= 0 || sessionID == "automatic" {
+		return nil
+	}
+
+	clusters := o.Clusters()
+	for len(clusters) >= max {
+		// clusters are not evicted to restore others; the rest stay persisted until there is room
+		if sessionID == _restoredSessionID {
+			return fmt.Errorf("AMC is already monitoring the maximum of %d clusters", max)
+		}
+
+		victim := o.leastRecentlyUsedCluster(clusters)
+		if victim == nil {
+			return fmt.Errorf("AMC is already monitoring the maximum of %d clusters, all of which are in use", max)
+		}
+
+		common.Logger(ctx).WithField("cluster_id", victim.ID()).Warnf("Monitoring %d clusters, the maximum; evicting cluster %s which was last used at %s", len(clusters), victim.ID(), victim.lastPing.Get().(time.Time).Format(time.RFC3339))
+		o.dropCluster(victim)
+		clusters = o.Clusters()
+	}
+
+	return nil
+}
+
+// leastRecentlyUsedCluster - get the non-permanent cluster pinged least recently, if it has been idle
+// long enough to be evicted; clusters which were never pinged come first
+func (o *ObserverT) leastRecentlyUsedCluster(clusters []*Cluster) *Cluster {
+	var res *Cluster
+	var resPing time.Time
+	for _, c := range clusters {
+		if c.IsPermanent() {
+			continue
+		}
+
+		lastPing := c.lastPing.Get().(time.Time)
+		if time.Since(lastPing) < _minIdleBeforeEviction {
+			continue
+		}
+
+		if res == nil || lastPing.Before(resPing) {
+			res, resPing = c, lastPing
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// ValidateClusterName - check that the name can be sent in an info command
+func ValidateClusterName(name string) error {
+	if name == "" {
+		return common.Errorf(common.KindInvalid, "Cluster name cannot be empty")
+	}
+
+	if strings.ContainsAny(name, ";:= \t\n") {
+		return common.Errorf(common.KindInvalid, "Cluster name cannot contain whitespace or any of `;`, `:`, `=`")
+	}
+
+	return nil
+}
+
+// SetClusterName - set cluster-name on all nodes and verify that all nodes report the new name
+func (c *Cluster) SetClusterName(name, user string) (map[*Node]error, error) {
+	if err := ValidateClusterName(name); err != nil {
+		return nil, err
+	}
+
+	nodes := c.Nodes()
+	res := make(map[*Node]error, len(nodes))
+	failed := false
+	for _, node := range nodes {
+		if _, err := node.SetServerConfig("service", map[string]string{"cluster-name": name}, user); err != nil {
+			res[node] = err
+			failed = true
+		} else {
+			res[node] = nil
+		}
+	}
+
+	if failed {
+		return res, errors.New("Failed to set the cluster name on some nodes")
+	}
+
+	names, errs := c.RequestInfoNodes(nodes, 0, "cluster-name")
+	mismatched := []string{}
+	for node, n := range names {
+		if err := errs[node]; err != nil {
+			res[node] = err
+			mismatched = append(mismatched, node.Address())
+		} else if n != name {
+			res[node] = fmt.Errorf("Node reports cluster name `%s`", n)
+			mismatched = append(mismatched, node.Address())
+		}
+	}
+
+	if len(mismatched) > 0 {
+		sort.Strings(mismatched)
+		return res, fmt.Errorf("The cluster name could not be verified on nodes [%s]", strings.Join(mismatched, ", "))
+	}
+
+	return res, nil
+}
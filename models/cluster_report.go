@@ -0,0 +1,219 @@
+package models
+
+import (
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// the number of the latest config changes listed in a cluster report
+const _reportConfigChanges = 100
+
+// ReportNode - a node of the cluster report
+type ReportNode struct {
+	Address string `json:"address"`
+	ID      string `json:"node_id"`
+	Status  string `json:"status"`
+	Build   string `json:"build"`
+}
+
+// ReportVersion - a server build of the cluster and the nodes running it
+type ReportVersion struct {
+	Build string   `json:"build"`
+	Nodes []string `json:"nodes"`
+}
+
+// ReportNamespace - the usage of a namespace, summed over the nodes
+type ReportNamespace struct {
+	Name         string `json:"name"`
+	Nodes        int    `json:"nodes"`
+	Objects      int64  `json:"objects"`
+	MemoryUsed   int64  `json:"memory_used_bytes"`
+	MemoryTotal  int64  `json:"memory_total_bytes"`
+	DiskUsed     int64  `json:"disk_used_bytes"`
+	DiskTotal    int64  `json:"disk_total_bytes"`
+	StopWrites   bool   `json:"stop_writes"`
+	HWMBreached  bool   `json:"hwm_breached"`
+	ReplFactor   int64  `json:"repl_factor"`
+	AvailablePct int64  `json:"least_available_pct"` // -1 if unknown
+}
+
+// ReportAlert - an alert of the cluster report
+type ReportAlert struct {
+	Status      string    `json:"status"`
+	Node        string    `json:"node"`
+	Namespace   string    `json:"namespace,omitempty"`
+	Description string    `json:"description"`
+	Created     time.Time `json:"created"`
+	LastOccured time.Time `json:"last_occured"`
+	Resolved    bool      `json:"resolved"`
+}
+
+// ReportConfigDiff - a config parameter whose value differs between the nodes; value => nodes
+type ReportConfigDiff struct {
+	Namespace string              `json:"namespace,omitempty"`
+	Parameter string              `json:"parameter"`
+	Values    map[string][]string `json:"values"`
+}
+
+// ReportConfigChange - a config change applied through AMC
+type ReportConfigChange struct {
+	Time      time.Time `json:"time"`
+	Node      string    `json:"node"`
+	Context   string    `json:"context"`
+	Namespace string    `json:"namespace,omitempty"`
+	Parameter string    `json:"parameter"`
+	OldValue  string    `json:"old_value"`
+	NewValue  string    `json:"new_value"`
+	User      string    `json:"user"`
+}
+
+// ClusterReport - the state of a cluster at a point in time, for change-management records
+type ClusterReport struct {
+	GeneratedAt   time.Time            `json:"generated_at"`
+	ClusterID     string               `json:"cluster_id"`
+	Alias         string               `json:"alias"`
+	SeedAddress   string               `json:"seed_address"`
+	Status        string               `json:"status"`
+	Nodes         []ReportNode         `json:"nodes"`
+	Versions      []ReportVersion      `json:"versions"`
+	Namespaces    []ReportNamespace    `json:"namespaces"`
+	Alerts        []ReportAlert        `json:"alerts"`
+	ConfigDiffs   []ReportConfigDiff   `json:"config_diffs"`
+	ConfigChanges []ReportConfigChange `json:"config_changes"`
+}
+
+// Report - build the report of the current state of the cluster: its nodes and their builds, the usage of the
+// namespaces, the recent alerts, the config parameters which differ between the nodes and the latest config changes
+func (c *Cluster) Report() *ClusterReport {
+	report := &ClusterReport{
+		GeneratedAt:   time.Now(),
+		ClusterID:     c.ID(),
+		SeedAddress:   c.SeedAddress(),
+		Status:        c.Status(),
+		Nodes:         []ReportNode{},
+		Versions:      []ReportVersion{},
+		Namespaces:    []ReportNamespace{},
+		Alerts:        []ReportAlert{},
+		ConfigDiffs:   []ReportConfigDiff{},
+		ConfigChanges: []ReportConfigChange{},
+	}
+	if alias := c.Alias(); alias != nil {
+		report.Alias = *alias
+	}
+
+	nodes := c.Nodes()
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Address() < nodes[j].Address() })
+
+	builds := map[string][]string{}
+	for _, node := range nodes {
+		report.Nodes = append(report.Nodes, ReportNode{
+			Address: node.Address(),
+			ID:      node.ID(),
+			Status:  string(node.Status()),
+			Build:   node.Build(),
+		})
+		builds[node.Build()] = append(builds[node.Build()], node.Address())
+	}
+	for build, addrs := range builds {
+		report.Versions = append(report.Versions, ReportVersion{Build: build, Nodes: addrs})
+	}
+	sort.Slice(report.Versions, func(i, j int) bool { return report.Versions[i].Build < report.Versions[j].Build })
+
+	for _, name := range common.SortStrings(common.StrUniq(c.NamespaceList())) {
+		report.Namespaces = append(report.Namespaces, reportNamespace(name, nodes))
+	}
+
+	alerts := c.AlertsFrom(0)
+	sort.Slice(alerts, func(i, j int) bool { return alerts[i].LastOccured.After(alerts[j].LastOccured) })
+	for _, alert := range alerts {
+		report.Alerts = append(report.Alerts, ReportAlert{
+			Status:      string(alert.Status),
+			Node:        alert.NodeAddress,
+			Namespace:   alert.Namespace.String,
+			Description: alert.Desc,
+			Created:     alert.Created,
+			LastOccured: alert.LastOccured,
+			Resolved:    alert.Resolved.Valid(),
+		})
+	}
+
+	report.ConfigDiffs = append(report.ConfigDiffs, reportConfigDiffs(nodes, "")...)
+	for _, ns := range report.Namespaces {
+		report.ConfigDiffs = append(report.ConfigDiffs, reportConfigDiffs(nodes, ns.Name)...)
+	}
+
+	changes, err := c.ConfigChanges(_reportConfigChanges)
+	if err != nil {
+		log.Errorf("Error reading the config changes of cluster %s for its report: %s", c.ID(), err.Error())
+	}
+	for _, cc := range changes {
+		report.ConfigChanges = append(report.ConfigChanges, ReportConfigChange{
+			Time:      cc.Created,
+			Node:      cc.NodeAddress,
+			Context:   cc.Context,
+			Namespace: cc.Namespace.String,
+			Parameter: cc.Parameter,
+			OldValue:  cc.OldValue,
+			NewValue:  cc.NewValue,
+			User:      cc.User,
+		})
+	}
+
+	return report
+}
+
+func reportNamespace(name string, nodes []*Node) ReportNamespace {
+	res := ReportNamespace{Name: name, AvailablePct: -1}
+	for _, node := range nodes {
+		ns := node.NamespaceByName(name)
+		if ns == nil {
+			continue
+		}
+
+		res.Nodes++
+		stats := ns.StatsAttrs("master-objects", "stop_writes", "hwm_breached", "effective_replication_factor", "repl-factor", "available_pct")
+		res.Objects += stats.TryInt("master-objects", 0)
+		res.StopWrites = res.StopWrites || stats.TryString("stop_writes", "false") == "true"
+		res.HWMBreached = res.HWMBreached || stats.TryString("hwm_breached", "false") == "true"
+		if rf := stats.TryInt("effective_replication_factor", stats.TryInt("repl-factor", 0)); rf > res.ReplFactor {
+			res.ReplFactor = rf
+		}
+		if pct := stats.TryInt("available_pct", -1); pct >= 0 && (res.AvailablePct < 0 || pct < res.AvailablePct) {
+			res.AvailablePct = pct
+		}
+
+		memory, disk := ns.Memory(), ns.Disk()
+		res.MemoryUsed += memory.TryInt("used-bytes-memory", 0)
+		res.MemoryTotal += memory.TryInt("total-bytes-memory", 0)
+		res.DiskUsed += disk.TryInt("used-bytes-disk", 0)
+		res.DiskTotal += disk.TryInt("total-bytes-disk", 0)
+	}
+	return res
+}
+
+// reportConfigDiffs - the parameters of the service config, or of the namespace, which differ between the nodes
+func reportConfigDiffs(nodes []*Node, namespace string) []ReportConfigDiff {
+	configs := map[string]common.Stats{}
+	for _, node := range nodes {
+		if namespace == "" {
+			configs[node.Address()] = node.ConfigAttrs()
+		} else if ns := node.NamespaceByName(namespace); ns != nil {
+			configs[node.Address()] = ns.ConfigAttrs()
+		}
+	}
+
+	mismatches := configMismatches(configs)
+	res := make([]ReportConfigDiff, 0, len(mismatches))
+	for param, values := range mismatches {
+		for _, addrs := range values {
+			sort.Strings(addrs)
+		}
+		res = append(res, ReportConfigDiff{Namespace: namespace, Parameter: param, Values: values})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Parameter < res[j].Parameter })
+	return res
+}
@@ -0,0 +1,152 @@
+package models
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// confSection - a stanza of aerospike.conf, e.g. `network { heartbeat { ... } }`
+type confSection struct {
+	name     string
+	params   [][2]string
+	sections []*confSection
+}
+
+var confIndexRegexp = regexp.MustCompile(`\[\d+\]$`)
+
+func (cs *confSection) section(name string) *confSection {
+	for _, s := range cs.sections {
+		if s.name == name {
+			return s
+		}
+	}
+
+	s := &confSection{name: name}
+	cs.sections = append(cs.sections, s)
+	return s
+}
+
+// add - add a flattened get-config parameter (e.g. `heartbeat.mode` or `storage-engine.file[0]`)
+func (cs *confSection) add(key, value string) {
+	parts := strings.Split(key, ".")
+	s := cs
+	for _, p := range parts[:len(parts)-1] {
+		s = s.section(confIndexRegexp.ReplaceAllString(p, ""))
+	}
+	s.params = append(s.params, [2]string{confIndexRegexp.ReplaceAllString(parts[len(parts)-1], ""), value})
+}
+
+func (cs *confSection) write(buf *bytes.Buffer, header string, depth int) {
+	indent := strings.Repeat("\t", depth)
+	fmt.Fprintf(buf, "%s%s {\n", indent, header)
+
+	subHeaders := make(map[string]string, len(cs.sections))
+	for _, s := range cs.sections {
+		subHeaders[s.name] = s.name
+	}
+
+	for _, p := range cs.params {
+		// a parameter with the same name as a subsection is the subsection's type,
+		// e.g. `storage-engine device { ... }`
+		if _, exists := subHeaders[p[0]]; exists {
+			subHeaders[p[0]] = p[0] + " " + p[1]
+			continue
+		}
+		fmt.Fprintf(buf, "%s\t%s %s\n", indent, p[0], p[1])
+	}
+
+	for _, s := range cs.sections {
+		s.write(buf, subHeaders[s.name], depth+1)
+	}
+
+	fmt.Fprintf(buf, "%s}\n", indent)
+}
+
+func newConfSection(name string, config common.Info) *confSection {
+	keys := make([]string, 0, len(config))
+	for k := range config {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	cs := &confSection{name: name}
+	for _, k := range keys {
+		v := config[k]
+		if k == "" || v == "" || v == "null" {
+			continue
+		}
+		cs.add(k, v)
+	}
+
+	return cs
+}
+
+// GenerateConfFile - render the node's running configuration in aerospike.conf syntax.
+// Only the parameters reported by the server via get-config are included; static-only
+// parameters (e.g. logging sinks, TLS files) should be reviewed before replacing the config file.
+func (n *Node) GenerateConfFile() (string, error) {
+	contexts := []string{"service", "network"}
+	if n.Enterprise() {
+		contexts = append(contexts, "security")
+	}
+
+	cmds := make([]string, 0, len(contexts)+len(n.Namespaces())+1)
+	for _, ctx := range contexts {
+		cmds = append(cmds, "get-config:context="+ctx)
+	}
+
+	namespaces := n.NamespaceList()
+	sort.Strings(namespaces)
+	for _, ns := range namespaces {
+		cmds = append(cmds, "get-config:context=namespace;id="+ns)
+	}
+
+	if n.Enterprise() {
+		cmds = append(cmds, "get-config:context=xdr")
+	}
+
+	res, err := n.RequestInfo(3, cmds...)
+	if err != nil {
+		return "", err
+	}
+
+	info := common.Info(res)
+	valid := func(cmd string) bool {
+		v := strings.TrimSpace(info[cmd])
+		return v != "" && !strings.HasPrefix(strings.ToLower(v), "error")
+	}
+
+	buf := new(bytes.Buffer)
+	fmt.Fprintf(buf, "# Generated by AMC from the running configuration of node %s (build %s) on %s.\n", n.Address(), n.Build(), time.Now().Format(time.RFC1123))
+	fmt.Fprintf(buf, "# Static-only parameters (logging, TLS, mod-lua, ...) are not reported by the server; review before use.\n\n")
+
+	for _, ctx := range contexts {
+		cmd := "get-config:context=" + ctx
+		if !valid(cmd) {
+			continue
+		}
+		newConfSection(ctx, info.ToInfo(cmd)).write(buf, ctx, 0)
+		buf.WriteString("\n")
+	}
+
+	for _, ns := range namespaces {
+		cmd := "get-config:context=namespace;id=" + ns
+		if !valid(cmd) {
+			continue
+		}
+		newConfSection("namespace", info.ToInfo(cmd)).write(buf, "namespace "+ns, 0)
+		buf.WriteString("\n")
+	}
+
+	if n.Enterprise() && valid("get-config:context=xdr") {
+		newConfSection("xdr", info.ToInfo("get-config:context=xdr")).write(buf, "xdr", 0)
+	}
+
+	return buf.String(), nil
+}
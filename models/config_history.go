@@ -0,0 +1,114 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// contextConfig - get the latest known config values for a set-config context
+func (n *Node) contextConfig(context string) common.Stats {
+	switch {
+	case context == "xdr":
+		return n.XdrConfig()
+	case strings.HasPrefix(context, "xdr;"):
+		// 5.0+ XDR datacenter and namespace contexts, e.g. `xdr;dc=DC1;namespace=test`
+		cmd := "get-config:context=" + context
+		return n.InfoAttrs(cmd).ToInfo(cmd).ToStats()
+	default:
+		return n.ConfigAttrs()
+	}
+}
+
+// recordConfigChanges - persist the successfully applied parameters to the config history, along with the AMC user
+// who changed them
+func (n *Node) recordConfigChanges(context, namespace string, oldConfig common.Stats, config map[string]string, unsetParams []string, user string) {
+	unset := make(map[string]struct{}, len(unsetParams))
+	for _, p := range unsetParams {
+		unset[p] = struct{}{}
+	}
+
+	for parameter, value := range config {
+		if _, exists := unset[parameter]; exists {
+			continue
+		}
+
+		oldValue := ""
+		if v := oldConfig.Get(parameter); v != nil {
+			oldValue = fmt.Sprintf("%v", v)
+		}
+
+		cc := common.NewConfigChange(n.cluster.ID(), n.Address(), context, namespace, parameter, oldValue, value, user)
+		if err := cc.Save(); err != nil {
+			log.Errorf("Error recording config change for node %s: %s", n.Address(), err.Error())
+		}
+	}
+}
+
+// ConfigChanges - get the history of dynamic config changes applied to the cluster
+func (c *Cluster) ConfigChanges(limit int) ([]*common.ConfigChange, error) {
+	return common.ConfigChanges(c.ID(), limit)
+}
+
+// RollbackConfigChange - re-apply the old value of a recorded config change on behalf of the user
+func (c *Cluster) RollbackConfigChange(id, user string) (*common.ConfigChange, error) {
+	cc, err := common.ConfigChangeByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if cc == nil || cc.ClusterID != c.ID() {
+		return nil, common.Errorf(common.KindNotFound, "Config change not found")
+	}
+
+	if cc.RolledBack {
+		return nil, errors.New("Config change has already been rolled back")
+	}
+
+	if cc.OldValue == "" {
+		return nil, fmt.Errorf("Previous value of `%s` is unknown; cannot roll back", cc.Parameter)
+	}
+
+	node := c.FindNodeByAddress(cc.NodeAddress)
+	if node == nil {
+		return nil, common.Errorf(common.KindNodeNotFound, "Node %s not found", cc.NodeAddress)
+	}
+
+	config := map[string]string{cc.Parameter: cc.OldValue}
+	if cc.Namespace.Valid {
+		ns := node.NamespaceByName(cc.Namespace.String)
+		if ns == nil {
+			return nil, common.Errorf(common.KindNamespaceNotFound, "Namespace %s not found on node %s", cc.Namespace.String, cc.NodeAddress)
+		}
+
+		if _, err := ns.SetConfig(config, user); err != nil {
+			return nil, err
+		}
+	} else if _, err := node.SetServerConfig(cc.Context, config, user); err != nil {
+		return nil, err
+	}
+
+	if err := cc.MarkRolledBack(); err != nil {
+		return nil, err
+	}
+
+	return cc, nil
+}
+
+// ServerConfigValues - get the current values of the parameters in the context as strings.
+// Parameters unknown to the node are omitted
+func (n *Node) ServerConfigValues(context string, parameters []string) map[string]string {
+	current := n.contextConfig(context)
+	res := make(map[string]string, len(parameters))
+	for _, p := range parameters {
+		if v := current.Get(p); v != nil {
+			res[p] = fmt.Sprintf("%v", v)
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"strings"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// retransmits - the total of the retransmit counters (retransmit_all_read_dup_res, retransmit_all_write_repl_write, ...)
+func retransmits(stats common.Stats) int64 {
+	var res int64
+	for k := range stats {
+		if strings.HasPrefix(k, "retransmit_") {
+			res += stats.TryInt(k, 0)
+		}
+	}
+	return res
+}
+
+// Conflicts - get the generation conflict, duplicate resolution and retransmit counters
+// of the namespace on the node. These grow during migrations, when the replicas have to
+// be resolved before reads and writes.
+func (ns *Namespace) Conflicts() common.Stats {
+	stats := ns.latestStats.Clone()
+
+	retransmitStats := common.Stats{}
+	for k := range stats {
+		if strings.HasPrefix(k, "retransmit_") {
+			retransmitStats[k] = stats.TryInt(k, 0)
+		}
+	}
+
+	return common.Stats{
+		"fail-generation":         stats.TryInt("fail_generation", 0),
+		"dup-res-ask":             stats.TryInt("dup_res_ask", 0),
+		"dup-res-respond-read":    stats.TryInt("dup_res_respond_read", 0),
+		"dup-res-respond-no-read": stats.TryInt("dup_res_respond_no_read", 0),
+		"retransmits":             retransmits(stats),
+		"retransmit-details":      retransmitStats,
+		"migrations-in-progress":  stats.TryInt("migrate_tx_partitions_remaining", 0)+stats.TryInt("migrate_rx_partitions_remaining", 0) > 0,
+	}
+}
@@ -0,0 +1,437 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	as "github.com/aerospike/aerospike-client-go/v5"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// the limits of a data generator, so that a typo does not fill the namespace or flood the cluster
+const (
+	dataGenMaxRecords         = 100000000
+	dataGenMaxBins            = 64
+	dataGenMaxBinSize         = 1024 * 1024
+	dataGenMaxConcurrency     = 128
+	dataGenDefaultConcurrency = 16
+	dataGenDefaultBinSize     = 16
+
+	// the generator fails after this many writes in a row failed
+	dataGenMaxConsecutiveFailures = 100
+)
+
+// the statuses of a data generator
+const (
+	DataGenStatusRunning = "running"
+	DataGenStatusDone    = "done"
+	DataGenStatusStopped = "stopped"
+	DataGenStatusFailed  = "failed"
+)
+
+var _dataGenBinTypes = map[string]bool{
+	"integer": true,
+	"float":   true,
+	"string":  true,
+	"bytes":   true,
+	"list":    true,
+	"map":     true,
+	"boolean": true,
+}
+
+// only one data generator may run on a cluster at once
+var _dataGenMutex sync.Mutex
+
+// DataGenBin - a bin of the generated records; size is the length of the strings and the blobs, and the number of
+// the elements of the lists and the maps (16 by default)
+type DataGenBin struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Size int    `json:"size,omitempty"`
+}
+
+// DataGenSpec - the records written by a data generator: count records with the integer keys from start_key,
+// with random values of the bins, at rate writes per second (0 for as fast as the cluster allows)
+type DataGenSpec struct {
+	Namespace   string       `json:"namespace"`
+	Set         string       `json:"set"`
+	Records     int          `json:"records"`
+	StartKey    int          `json:"start_key"`
+	Bins        []DataGenBin `json:"bins"`
+	Rate        int          `json:"rate"`
+	Concurrency int          `json:"concurrency"`
+	TTL         int          `json:"ttl"` // seconds; 0 for the default of the namespace, -1 to never expire
+}
+
+// DataGenerator - the writes of a spec to a test cluster, running in the background
+type DataGenerator struct {
+	mutex sync.Mutex
+
+	cluster *Cluster
+	spec    DataGenSpec
+	cancel  context.CancelFunc
+
+	status                   string
+	written, failed          int64
+	consecutiveFailures      int
+	totalLatency, maxLatency time.Duration
+	lastError                string
+	startedAt, finishedAt    time.Time
+}
+
+// DataGeneratorStatus - the progress of a data generator, and the latencies of its writes in milliseconds
+type DataGeneratorStatus struct {
+	Spec          DataGenSpec `json:"spec"`
+	Status        string      `json:"status"`
+	Written       int64       `json:"written"`
+	Failed        int64       `json:"failed"`
+	ProgressPct   float64     `json:"progress_pct"`
+	WritesPerSec  float64     `json:"writes_per_sec"`
+	MeanLatencyMs float64     `json:"mean_latency_ms"`
+	MaxLatencyMs  float64     `json:"max_latency_ms"`
+	LastError     string      `json:"last_error,omitempty"`
+	StartedAt     time.Time   `json:"started_at"`
+	FinishedAt    *time.Time  `json:"finished_at,omitempty"`
+}
+
+// IsTestCluster - whether the cluster-name of the cluster is one of the test_clusters of the config,
+// which the data generator may write to
+func (c *Cluster) IsTestCluster() bool {
+	name := c.Name()
+	if name == nil {
+		return false
+	}
+	for _, testCluster := range c.observer.config.TestClusters() {
+		if testCluster == *name {
+			return true
+		}
+	}
+	return false
+}
+
+// checkTestCluster - fail unless the cluster is a test cluster, which the operation may load with writes
+func (c *Cluster) checkTestCluster(operation string) error {
+	if c.IsTestCluster() {
+		return nil
+	}
+	name := c.ID()
+	if n := c.Name(); n != nil {
+		name = *n
+	}
+	return common.Errorf(common.KindNotAllowed, "%s is not allowed on cluster %s; add its cluster-name to test_clusters in the config", operation, name)
+}
+
+// validate - check the spec and fill in its defaults
+func (spec *DataGenSpec) validate() error {
+	if spec.Namespace == "" {
+		return common.Errorf(common.KindInvalid, "Invalid parameters: namespace is required")
+	}
+	if spec.Records <= 0 || spec.Records > dataGenMaxRecords {
+		return common.Errorf(common.KindInvalid, "The number of records must be between 1 and %d", dataGenMaxRecords)
+	}
+	if spec.StartKey < 0 {
+		return common.Errorf(common.KindInvalid, "Invalid start_key")
+	}
+	if spec.Rate < 0 {
+		return common.Errorf(common.KindInvalid, "Invalid rate")
+	}
+	if spec.TTL < -1 {
+		return common.Errorf(common.KindInvalid, "Invalid ttl")
+	}
+	if spec.Concurrency == 0 {
+		spec.Concurrency = dataGenDefaultConcurrency
+	}
+	if spec.Concurrency < 0 || spec.Concurrency > dataGenMaxConcurrency {
+		return common.Errorf(common.KindInvalid, "The concurrency must be between 1 and %d", dataGenMaxConcurrency)
+	}
+
+	if len(spec.Bins) == 0 || len(spec.Bins) > dataGenMaxBins {
+		return common.Errorf(common.KindInvalid, "The number of bins must be between 1 and %d", dataGenMaxBins)
+	}
+	names := map[string]bool{}
+	for i := range spec.Bins {
+		bin := &spec.Bins[i]
+		if bin.Name == "" || len(bin.Name) > 15 || names[bin.Name] {
+			return common.Errorf(common.KindInvalid, "Invalid bin name %q: the names must be unique and at most 15 characters", bin.Name)
+		}
+		names[bin.Name] = true
+
+		if !_dataGenBinTypes[bin.Type] {
+			return common.Errorf(common.KindInvalid, "Invalid type %q of bin %s", bin.Type, bin.Name)
+		}
+		if bin.Size == 0 {
+			bin.Size = dataGenDefaultBinSize
+		}
+		if bin.Size < 0 || bin.Size > dataGenMaxBinSize {
+			return common.Errorf(common.KindInvalid, "The size of bin %s must be between 1 and %d", bin.Name, dataGenMaxBinSize)
+		}
+	}
+	return nil
+}
+
+// StartDataGenerator - start writing the records of the spec in the background; only on the test clusters,
+// and one generator at a time
+func (c *Cluster) StartDataGenerator(spec DataGenSpec) (*DataGenerator, error) {
+	if err := c.checkTestCluster("Data generation"); err != nil {
+		return nil, err
+	}
+	if err := spec.validate(); err != nil {
+		return nil, err
+	}
+	if err := c.checkNamespace(spec.Namespace); err != nil {
+		return nil, err
+	}
+	if c.origClient() == nil {
+		return nil, common.Errorf(common.KindUnavailable, "Cluster %s has been decommissioned", c.ID())
+	}
+
+	_dataGenMutex.Lock()
+	defer _dataGenMutex.Unlock()
+
+	// a failed generator is running until its writes in flight return
+	if g := c.DataGenerator(); g != nil && g.Status().FinishedAt == nil {
+		return nil, common.Errorf(common.KindAlreadyExists, "A data generator is already running on the cluster")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	g := &DataGenerator{
+		cluster:   c,
+		spec:      spec,
+		cancel:    cancel,
+		status:    DataGenStatusRunning,
+		startedAt: time.Now(),
+	}
+	c.dataGenerator.Set(g)
+
+	log.WithField("cluster_id", c.ID()).Infof("Started generating %d records in %s.%s", spec.Records, spec.Namespace, spec.Set)
+	go g.run(ctx)
+	return g, nil
+}
+
+// DataGenerator - the running or the last data generator of the cluster, nil if none was started
+func (c *Cluster) DataGenerator() *DataGenerator {
+	if g := c.dataGenerator.Get(); g != nil {
+		return g.(*DataGenerator)
+	}
+	return nil
+}
+
+// Stop - stop writing; the records written are kept
+func (g *DataGenerator) Stop() {
+	g.cancel()
+}
+
+// Status - the progress of the generator
+func (g *DataGenerator) Status() DataGeneratorStatus {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	ms := func(d time.Duration) float64 { return float64(d.Microseconds()) / 1000 }
+
+	res := DataGeneratorStatus{
+		Spec:         g.spec,
+		Status:       g.status,
+		Written:      g.written,
+		Failed:       g.failed,
+		ProgressPct:  100 * float64(g.written+g.failed) / float64(g.spec.Records),
+		MaxLatencyMs: ms(g.maxLatency),
+		LastError:    g.lastError,
+		StartedAt:    g.startedAt,
+	}
+	if count := g.written + g.failed; count > 0 {
+		res.MeanLatencyMs = ms(g.totalLatency / time.Duration(count))
+	}
+
+	end := time.Now()
+	if !g.finishedAt.IsZero() {
+		end = g.finishedAt
+		finishedAt := g.finishedAt
+		res.FinishedAt = &finishedAt
+	}
+	if elapsed := end.Sub(g.startedAt).Seconds(); elapsed > 0 {
+		res.WritesPerSec = float64(g.written) / elapsed
+	}
+	return res
+}
+
+func (g *DataGenerator) run(ctx context.Context) {
+	defer g.cancel()
+
+	keys := make(chan int)
+	go g.produceKeys(ctx, keys)
+
+	var wg sync.WaitGroup
+	for i := 0; i < g.spec.Concurrency; i++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			rnd := rand.New(rand.NewSource(seed))
+			for key := range keys {
+				if !g.write(rnd, key) {
+					g.cancel()
+				}
+			}
+		}(time.Now().UnixNano() + int64(i))
+	}
+	wg.Wait()
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	g.finishedAt = time.Now()
+	switch {
+	case g.status != DataGenStatusRunning:
+	case g.written+g.failed == int64(g.spec.Records):
+		g.status = DataGenStatusDone
+	default:
+		g.status = DataGenStatusStopped
+	}
+	log.WithField("cluster_id", g.cluster.ID()).Infof("Data generator %s after writing %d records, %d failed", g.status, g.written, g.failed)
+}
+
+// produceKeys - send the keys of the records to the writers at the rate of the spec, and keep the cluster
+// from being considered idle while the generator runs
+func (g *DataGenerator) produceKeys(ctx context.Context, keys chan<- int) {
+	defer close(keys)
+
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+
+	lastPing := time.Time{}
+	ping := func() {
+		if time.Since(lastPing) >= time.Second {
+			g.cluster.updateLastestPing()
+			lastPing = time.Now()
+		}
+	}
+
+	start := time.Now()
+	sent := 0
+	for sent < g.spec.Records {
+		ping()
+
+		due := g.spec.Records
+		if g.spec.Rate > 0 {
+			due = int(math.Min(float64(g.spec.Records), time.Since(start).Seconds()*float64(g.spec.Rate)))
+		}
+		for ; sent < due; sent++ {
+			select {
+			case <-ctx.Done():
+				return
+			case keys <- g.spec.StartKey + sent:
+			}
+			if sent%1000 == 0 {
+				ping()
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// write - write the record with the key; false if the generator should fail
+func (g *DataGenerator) write(rnd *rand.Rand, userKey int) bool {
+	client := g.cluster.origClient()
+	if client == nil {
+		g.fail(fmt.Sprintf("Cluster %s has been decommissioned", g.cluster.ID()))
+		return false
+	}
+
+	key, err := as.NewKey(g.spec.Namespace, g.spec.Set, userKey)
+	if err != nil {
+		g.fail(err.Error())
+		return false
+	}
+
+	bins := make(as.BinMap, len(g.spec.Bins))
+	for _, bin := range g.spec.Bins {
+		bins[bin.Name] = dataGenValue(rnd, bin)
+	}
+
+	expiration := uint32(as.TTLServerDefault)
+	if g.spec.TTL == -1 {
+		expiration = as.TTLDontExpire
+	} else if g.spec.TTL > 0 {
+		expiration = uint32(g.spec.TTL)
+	}
+	policy := as.NewWritePolicy(0, expiration)
+	policy.TotalTimeout = 5 * time.Second
+
+	start := time.Now()
+	err = client.Put(policy, key, bins)
+	elapsed := time.Since(start)
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	g.totalLatency += elapsed
+	if elapsed > g.maxLatency {
+		g.maxLatency = elapsed
+	}
+	if err != nil {
+		g.failed++
+		g.consecutiveFailures++
+		g.lastError = err.Error()
+		if g.consecutiveFailures >= dataGenMaxConsecutiveFailures {
+			g.status = DataGenStatusFailed
+			return false
+		}
+		return true
+	}
+	g.written++
+	g.consecutiveFailures = 0
+	return true
+}
+
+func (g *DataGenerator) fail(msg string) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	g.status, g.lastError = DataGenStatusFailed, msg
+}
+
+// dataGenValue - a random value of the type and the size of the bin
+func dataGenValue(rnd *rand.Rand, bin DataGenBin) interface{} {
+	const letters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+	switch bin.Type {
+	case "integer":
+		return rnd.Int63()
+	case "float":
+		return rnd.Float64() * 1000000
+	case "boolean":
+		return rnd.Intn(2) == 1
+	case "string":
+		b := make([]byte, bin.Size)
+		for i := range b {
+			b[i] = letters[rnd.Intn(len(letters))]
+		}
+		return string(b)
+	case "bytes":
+		b := make([]byte, bin.Size)
+		rnd.Read(b)
+		return b
+	case "list":
+		l := make([]interface{}, bin.Size)
+		for i := range l {
+			l[i] = rnd.Int63()
+		}
+		return l
+	case "map":
+		m := make(map[interface{}]interface{}, bin.Size)
+		for i := 0; i < bin.Size; i++ {
+			m[fmt.Sprintf("k%d", i)] = rnd.Int63()
+		}
+		return m
+	}
+	return nil
+}
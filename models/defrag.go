@@ -0,0 +1,111 @@
+package models
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// e.g. storage-engine.device[0].defrag_q or storage-engine.file[1].write_q
+var deviceStatRegexp = regexp.MustCompile(`^storage-engine\.(device|file)\[(\d+)\]\.(\w+)$`)
+
+// per device stats related to defragmentation
+var _deviceDefragStats = map[string]string{
+	"defrag_q":      "defrag-q",
+	"write_q":       "write-q",
+	"defrag_reads":  "defrag-reads",
+	"defrag_writes": "defrag-writes",
+	"free_wblocks":  "free-wblocks",
+	"used_bytes":    "used-bytes",
+}
+
+// devicesDefragStats - the defrag stats of every device of the namespace and their totals;
+// reported per device by server versions 4.3 and later
+func (ns *Namespace) devicesDefragStats(stats common.Stats) common.Stats {
+	totals := common.Stats{}
+	devices := map[string]common.Stats{}
+	for k, v := range stats {
+		m := deviceStatRegexp.FindStringSubmatch(k)
+		if m == nil {
+			continue
+		}
+
+		name, exists := _deviceDefragStats[m[3]]
+		if !exists {
+			continue
+		}
+
+		key := m[1] + "[" + m[2] + "]"
+		if devices[key] == nil {
+			devices[key] = common.Stats{
+				"type":  m[1],
+				"index": m[2],
+				"path":  stats.TryString("storage-engine."+key, ""),
+			}
+		}
+
+		val, _ := v.(int64)
+		devices[key][name] = val
+		totals[name] = totals.TryInt(name, 0) + val
+	}
+
+	keys := make([]string, 0, len(devices))
+	for k := range devices {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	list := make([]common.Stats, 0, len(keys))
+	for _, k := range keys {
+		list = append(list, devices[k])
+	}
+
+	totals["devices"] = list
+	return totals
+}
+
+// Defrag - get the defrag config and the per device defrag stats of the namespace on the node
+func (ns *Namespace) Defrag() common.Stats {
+	stats := ns.latestStats.Clone()
+	res := ns.devicesDefragStats(stats)
+
+	res["storage-engine"] = stats.TryString("storage-engine", "memory")
+	res["defrag-lwm-pct"] = stats.TryInt("storage-engine.defrag-lwm-pct", 0)
+	res["defrag-sleep"] = stats.TryInt("storage-engine.defrag-sleep", 0)
+	res["available_pct"] = stats.TryFloat("device_available_pct", 0)
+
+	return res
+}
+
+// SetDefragConfig - tune defrag-lwm-pct and defrag-sleep; empty values are not changed
+func (ns *Namespace) SetDefragConfig(lwmPct, sleep, user string) ([]string, error) {
+	if ns.latestStats.TryString("storage-engine", "memory") == "memory" {
+		return nil, errors.New("Defragmentation only applies to the device storage engine")
+	}
+
+	config := common.Info{}
+	if lwmPct != "" {
+		v, err := strconv.Atoi(lwmPct)
+		if err != nil || v < 1 || v > 99 {
+			return nil, common.Errorf(common.KindInvalid, "defrag-lwm-pct must be between 1 and 99")
+		}
+		config["defrag-lwm-pct"] = lwmPct
+	}
+
+	if sleep != "" {
+		v, err := strconv.Atoi(sleep)
+		if err != nil || v < 0 || v > 1000000 {
+			return nil, common.Errorf(common.KindInvalid, "defrag-sleep must be between 0 and 1000000 microseconds")
+		}
+		config["defrag-sleep"] = sleep
+	}
+
+	if len(config) == 0 {
+		return nil, errors.New("Nothing to change")
+	}
+
+	return ns.SetConfig(config, user)
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	as "github.com/aerospike/aerospike-client-go/v5"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// rawInfoChanged - check whether the raw values of the keys differ from the last ones, and remember them.
+// Parsing and aggregating the values which have not changed since the last update is skipped.
+func rawInfoChanged(last map[string]string, info common.Info, keys ...string) bool {
+	changed := false
+	for _, key := range keys {
+		if v, exists := last[key]; !exists || v != info[key] {
+			last[key] = info[key]
+			changed = true
+		}
+	}
+	return changed
+}
+
+// nsSetAggregate is the aggregate of the sets of a namespace over the nodes,
+// reused until the sets of one of the nodes change
+type nsSetAggregate struct {
+	// the namespaces aggregated and the generations of their sets
+	signature string
+	stats     map[string]common.Stats
+}
+
+// aggregateNsSetStats - aggregate the sets of each namespace over the nodes; only the namespaces
+// whose sets have changed on any node since the last update are aggregated again.
+// Only called from updateStats, which never runs concurrently for the same cluster.
+func (c *Cluster) aggregateNsSetStats(nodes map[as.Host]*Node) map[string]map[string]common.Stats {
+	byName := map[string][]*Namespace{}
+	for _, node := range nodes {
+		for name, ns := range node.Namespaces() {
+			byName[name] = append(byName[name], ns)
+		}
+	}
+
+	res := make(map[string]map[string]common.Stats, len(byName))
+	aggregates := make(map[string]*nsSetAggregate, len(byName))
+	for name, namespaces := range byName {
+		parts := make([]string, 0, len(namespaces))
+		for _, ns := range namespaces {
+			parts = append(parts, fmt.Sprintf("%p:%d", ns, ns.setsGeneration))
+		}
+		sort.Strings(parts)
+		signature := strings.Join(parts, ",")
+
+		// the published aggregates are never modified, so they can be reused as they are
+		if prev := c.setAggregates[name]; prev != nil && prev.signature == signature {
+			res[name] = prev.stats
+			aggregates[name] = prev
+			continue
+		}
+
+		stats := map[string]common.Stats{}
+		for _, ns := range namespaces {
+			for setName, setInfo := range ns.SetsInfo() {
+				if stats[setName] == nil {
+					stats[setName] = setInfo
+				} else {
+					stats[setName].AggregateStats(setInfo)
+				}
+			}
+		}
+
+		res[name] = stats
+		aggregates[name] = &nsSetAggregate{signature: signature, stats: stats}
+	}
+
+	c.setAggregates = aggregates
+	return res
+}
@@ -0,0 +1,164 @@
+package models
+
+import (
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	as "github.com/aerospike/aerospike-client-go/v5"
+	log "github.com/sirupsen/logrus"
+)
+
+// default time between re-resolving the DNS names used as cluster seeds
+const _defaultDNSRefreshInterval = 60 * time.Second
+
+type seedKind int
+
+const (
+	// a host name
+	seedKindDNS seedKind = iota
+	// an SRV record, which provides the ports of the nodes
+	seedKindSRV
+	// a Kubernetes service in the namespace/service form, whose endpoints are the nodes
+	seedKindKubernetes
+)
+
+// discoverySeed is a DNS name, SRV record or Kubernetes service used to seed a cluster;
+// it is re-resolved periodically so that nodes whose addresses change are picked up
+type discoverySeed struct {
+	kind    seedKind
+	name    string
+	port    int
+	tlsName string
+}
+
+// resolve - get the current hosts of the seed
+func (s *discoverySeed) resolve(o *ObserverT) ([]*as.Host, error) {
+	switch s.kind {
+	case seedKindSRV:
+		return o.ResolveSRV(s.name, s.tlsName)
+	case seedKindKubernetes:
+		return o.ResolveKubernetesService(s.name, s.port, s.tlsName)
+	}
+
+	addresses, err := o.resolveHost(s.name)
+	if err != nil {
+		return nil, err
+	}
+
+	hosts := make([]*as.Host, 0, len(addresses))
+	for _, addr := range addresses {
+		host := as.NewHost(addr, s.port)
+		host.TLSName = s.tlsName
+		hosts = append(hosts, host)
+	}
+	return hosts, nil
+}
+
+// ResolveSRV - get the hosts of the nodes listed in the SRV record
+func (o *ObserverT) ResolveSRV(name, tlsName string) ([]*as.Host, error) {
+	_, records, err := net.LookupSRV("", "", name)
+	if err != nil {
+		return nil, err
+	}
+
+	hosts := []*as.Host{}
+	for _, record := range records {
+		addresses, err := o.resolveHost(strings.TrimSuffix(record.Target, "."))
+		if err != nil {
+			log.Warnf("Error resolving %s from SRV record %s: %s", record.Target, name, err.Error())
+			continue
+		}
+
+		for _, addr := range addresses {
+			host := as.NewHost(addr, int(record.Port))
+			host.TLSName = tlsName
+			hosts = append(hosts, host)
+		}
+	}
+
+	if len(hosts) == 0 {
+		return nil, &net.DNSError{Err: "no hosts found", Name: name}
+	}
+	return hosts, nil
+}
+
+// SetSRVSeed - seed the cluster from the SRV record from now on
+func (c *Cluster) SetSRVSeed(name, tlsName string) {
+	c.discoverySeed.Set(&discoverySeed{kind: seedKindSRV, name: name, tlsName: tlsName})
+}
+
+// SRVSeed - get the SRV record the cluster is seeded from, if any
+func (c *Cluster) SRVSeed() string {
+	if seed, _ := c.discoverySeed.Get().(*discoverySeed); seed != nil && seed.kind == seedKindSRV {
+		return seed.name
+	}
+	return ""
+}
+
+// refreshDiscoverySeed - re-resolve the seed of the cluster, and add the addresses
+// which do not belong to any known node as seeds of the client
+func (c *Cluster) refreshDiscoverySeed() {
+	seed, _ := c.discoverySeed.Get().(*discoverySeed)
+	if seed == nil || c.Sleeping() {
+		return
+	}
+
+	hosts, err := seed.resolve(c.observer)
+	if err != nil {
+		log.Warnf("Error resolving the seed %s of cluster %s: %s", seed.name, c.ID(), err.Error())
+		return
+	}
+
+	known := map[string]bool{}
+	for host := range c.nodesCopy() {
+		known[net.JoinHostPort(host.Name, strconv.Itoa(host.Port))] = true
+	}
+
+	current := map[string]bool{}
+	newHosts := []*as.Host{}
+	for _, host := range hosts {
+		addr := net.JoinHostPort(host.Name, strconv.Itoa(host.Port))
+		current[addr] = true
+		if !known[addr] {
+			newHosts = append(newHosts, host)
+		}
+	}
+
+	// pods removed from the service are gone for good, unlike nodes which are down
+	if seed.kind == seedKindKubernetes {
+		for host, node := range c.nodesCopy() {
+			if addr := net.JoinHostPort(host.Name, strconv.Itoa(host.Port)); !current[addr] && node.Status() != nodeStatus.On {
+				log.Infof("Removing node %s of cluster %s, which is no longer an endpoint of %s", addr, c.ID(), seed.name)
+				c.RemoveNodeByAddress(node.Address())
+			}
+		}
+	}
+
+	client := c.origClient()
+	if len(newHosts) == 0 || client == nil {
+		return
+	}
+
+	log.Infof("Seed %s of cluster %s resolves to new addresses %v", seed.name, c.ID(), newHosts)
+	client.Cluster().AddSeeds(newHosts)
+}
+
+// refreshDiscoverySeeds - re-resolve the seeds of all clusters
+func (o *ObserverT) refreshDiscoverySeeds() {
+	for _, c := range o.Clusters() {
+		c.refreshDiscoverySeed()
+	}
+}
+
+// dnsRefreshInterval - the configured time between re-resolving the seeds; 0 disables it
+func (o *ObserverT) dnsRefreshInterval() time.Duration {
+	secs := o.config.DNSRefreshInterval()
+	if secs == 0 {
+		return _defaultDNSRefreshInterval
+	} else if secs < 0 {
+		return 0
+	}
+	return time.Duration(secs) * time.Second
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"time"
+
+	ast "github.com/aerospike/aerospike-client-go/v5/types"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// voidTime - convert a server void time to unix time; 0 means not set
+func voidTime(v int64) int64 {
+	if v <= 0 {
+		return 0
+	}
+	return v + ast.CITRUSLEAF_EPOCH
+}
+
+// Eviction - get the eviction and expiration state of the namespace on the node
+func (ns *Namespace) Eviction() common.Stats {
+	stats := ns.latestStats.Clone()
+
+	memUsedPct := 100 - stats.TryFloat("memory_free_pct", 100)
+	diskUsedPct := 100 - stats.TryFloat("device_free_pct", 100)
+	hwmMemory := stats.TryFloat("high-water-memory-pct", 0)
+	hwmDisk := stats.TryFloat("high-water-disk-pct", 0)
+
+	// 0 disables the corresponding high water mark
+	imminent := stats.TryString("hwm_breached", "false") == "true" ||
+		(hwmMemory > 0 && memUsedPct >= hwmMemory) ||
+		(hwmDisk > 0 && stats.Get("device_total_bytes") != nil && diskUsedPct >= hwmDisk)
+
+	res := common.Stats{
+		"evicted-objects":       stats.TryInt("evicted_objects", 0),
+		"expired-objects":       stats.TryInt("expired_objects", 0),
+		"non-expirable-objects": stats.TryInt("non_expirable_objects", 0),
+
+		// eviction depth: records with a TTL below evict_ttl were evicted in the last cycle
+		"evict-ttl":           stats.TryInt("evict_ttl", 0),
+		"evict-void-time":     voidTime(stats.TryInt("evict_void_time", 0)),
+		"smd-evict-void-time": voidTime(stats.TryInt("smd_evict_void_time", 0)),
+		"evict-tenths-pct":    stats.TryInt("evict-tenths-pct", 0),
+
+		"high-water-memory-pct": hwmMemory,
+		"high-water-disk-pct":   hwmDisk,
+		"memory-used-pct":       memUsedPct,
+		"disk-used-pct":         diskUsedPct,
+		"hwm-breached":          stats.TryString("hwm_breached", "false") == "true",
+		"stop-writes":           stats.TryString("stop_writes", "false") == "true",
+		"eviction-imminent":     imminent,
+
+		"nsup-period":         stats.TryInt("nsup-period", 0),
+		"nsup-cycle-duration": stats.TryInt("nsup_cycle_duration", 0),
+	}
+
+	if hwmMemory > 0 {
+		res["memory-headroom-pct"] = hwmMemory - memUsedPct
+	}
+	if hwmDisk > 0 && stats.Get("device_total_bytes") != nil {
+		res["disk-headroom-pct"] = hwmDisk - diskUsedPct
+	}
+
+	return res
+}
+
+// StatsSince - get the recorded history of the namespace stats since time
+func (ns *Namespace) StatsSince(tm time.Time, names ...string) map[string][]*common.SinglePointValue {
+	// the history maps are not written to, so they don't need synchronization
+	res := make(map[string][]*common.SinglePointValue, len(names))
+	for _, name := range names {
+		if bucket := ns.statsHistory[name]; bucket != nil {
+			res[name] = bucket.ValuesSince(tm)
+		} else if bucket := ns.gaugeHistory[name]; bucket != nil {
+			res[name] = bucket.ValuesSince(tm)
+		}
+	}
+
+	return res
+}
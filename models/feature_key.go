@@ -0,0 +1,126 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+const _featureKeyDateLayout = "2006-01-02"
+
+// days before the feature key expiry to raise the alerts
+const (
+	_featureKeyExpiryWarningDays  = 30
+	_featureKeyExpiryCriticalDays = 7
+)
+
+// Edition - get the server edition of the node, e.g. `Aerospike Enterprise Edition`
+func (n *Node) Edition() string {
+	return n.InfoAttr("edition")
+}
+
+// FeatureKey - get the feature key entitlements of the node; only reported by enterprise servers 5.0 and later
+func (n *Node) FeatureKey() common.Stats {
+	raw := strings.TrimSpace(n.InfoAttr("feature-key"))
+	if raw == "" || raw == common.NOT_AVAILABLE || strings.HasPrefix(strings.ToLower(raw), "error") {
+		return nil
+	}
+
+	return n.latestInfo.ToInfo("feature-key").ToStats()
+}
+
+// FeatureKeyExpiry - get the expiry of the node's feature key; false if the key doesn't expire or is unknown
+func (n *Node) FeatureKeyExpiry() (time.Time, bool) {
+	validUntil := n.FeatureKey().TryString("valid-until-date", "")
+	if validUntil == "" {
+		return time.Time{}, false
+	}
+
+	tm, err := time.Parse(_featureKeyDateLayout, validUntil)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return tm, true
+}
+
+// EditionInfo - get the edition and feature key info of the node
+func (n *Node) EditionInfo() common.Stats {
+	res := common.Stats{
+		"edition":     n.Edition(),
+		"enterprise":  n.Enterprise(),
+		"feature_key": n.FeatureKey(),
+	}
+
+	if expiry, ok := n.FeatureKeyExpiry(); ok {
+		res["valid_until"] = expiry.Format(_featureKeyDateLayout)
+		res["days_remaining"] = int64(math.Floor(time.Until(expiry).Hours() / 24))
+	}
+
+	return res
+}
+
+// NodeEditions - get the edition and feature key info of every node; node address => info
+func (c *Cluster) NodeEditions() map[string]common.Stats {
+	res := map[string]common.Stats{}
+	for _, node := range c.Nodes() {
+		res[node.Address()] = node.EditionInfo()
+	}
+	return res
+}
+
+// CheckFeatureKey - alert when the feature key approaches its expiry
+func (n *Node) CheckFeatureKey(latestState common.Stats) {
+	messages := common.Info{
+		"red":    "Feature key of node <strong>%s</strong> expires on %s",
+		"yellow": "Feature key of node <strong>%s</strong> expires on %s",
+		"green":  "Feature key of node <strong>%s</strong> is no longer about to expire",
+	}
+
+	featureKeyAlert := "off"
+	expiry, ok := n.FeatureKeyExpiry()
+	daysRemaining := time.Until(expiry).Hours() / 24
+	if ok && daysRemaining <= _featureKeyExpiryWarningDays {
+		featureKeyAlert = "on"
+	}
+
+	switch featureKeyAlert {
+	case "on":
+		msg := messages["yellow"]
+		status := common.AlertStatusYellow
+		if daysRemaining <= _featureKeyExpiryCriticalDays {
+			msg = messages["red"]
+			status = common.AlertStatusRed
+		}
+
+		alert := common.Alert{
+			ID:          time.Now().UnixNano(),
+			ClusterID:   n.cluster.ID(),
+			Type:        common.AlertTypeNodeFeatureKeyExpiry,
+			NodeAddress: n.Address(),
+			Desc:        fmt.Sprintf(msg, n.Address(), expiry.Format(_featureKeyDateLayout)),
+			Created:     time.Now(),
+			LastOccured: time.Now(),
+			Status:      status,
+		}
+
+		n.alerts().Register(&alert)
+	case "off":
+		if latestState.TryString("featureKeyAlert", "off") == "on" {
+			alert := common.Alert{
+				ID:          time.Now().UnixNano(),
+				ClusterID:   n.cluster.ID(),
+				Type:        common.AlertTypeNodeFeatureKeyExpiry,
+				NodeAddress: n.Address(),
+				Desc:        fmt.Sprintf(messages["green"], n.Address()),
+				Status:      common.AlertStatusGreen,
+			}
+			n.alerts().Register(&alert)
+		}
+	}
+
+	n.setAlertState("featureKeyAlert", featureKeyAlert)
+}
@@ -0,0 +1,322 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// HealthSeverity - severity of a health check finding
+type HealthSeverity string
+
+// Health check severities
+const (
+	HealthSeverityCritical HealthSeverity = "critical"
+	HealthSeverityWarning  HealthSeverity = "warning"
+	HealthSeverityInfo     HealthSeverity = "info"
+)
+
+// HealthFinding - a single issue found by a health check rule
+type HealthFinding struct {
+	Rule      string         `json:"rule"`
+	Severity  HealthSeverity `json:"severity"`
+	Node      string         `json:"node,omitempty"`
+	Namespace string         `json:"namespace,omitempty"`
+	Message   string         `json:"message"`
+}
+
+// HealthCheckReport - the result of running all health check rules on a cluster
+type HealthCheckReport struct {
+	CheckedAt time.Time       `json:"checked_at"`
+	Rules     []string        `json:"rules"`
+	Findings  []HealthFinding `json:"findings"`
+}
+
+type healthCheckRule struct {
+	name  string
+	check func(c *Cluster) []HealthFinding
+}
+
+// health check rules in the order they are run
+var _healthCheckRules = []healthCheckRule{
+	{"homogeneous-builds", checkHomogeneousBuilds},
+	{"service-config-mismatch", checkServiceConfigMismatch},
+	{"namespace-config-mismatch", checkNamespaceConfigMismatch},
+	{"hwm-vs-stop-writes", checkHighWaterMarks},
+	{"device-provisioning", checkDeviceProvisioning},
+	{"clock-skew", checkClockSkew},
+}
+
+// config parameters which are expected to differ between nodes
+var _nodeSpecificConfigs = []string{
+	"node-id", "node-id-interface", "rack-id", "address", "port",
+	"access-address", "alternate-access-address", "tls-address", "tls-access-address",
+	"tls-alternate-access-address", "interface-address", "mesh-seed-address-port",
+	"service-threads", "transaction-queues", "file", "device", "pidfile", "work-directory",
+}
+
+// clock skew thresholds in milliseconds
+const (
+	_clockSkewWarningMs  = 2000
+	_clockSkewCriticalMs = 10000
+)
+
+// minimum available storage before warning; writes stop at min-avail-pct (5% by default)
+const _deviceAvailablePctWarning = 20
+
+func isNodeSpecificConfig(key string) bool {
+	for _, k := range _nodeSpecificConfigs {
+		if key == k || strings.HasSuffix(key, "."+k) || strings.HasPrefix(key, k+"[") || strings.Contains(key, "."+k+"[") {
+			return true
+		}
+	}
+	return false
+}
+
+// HealthCheck - run all health check rules against the latest stats and configs of the cluster
+func (c *Cluster) HealthCheck() *HealthCheckReport {
+	report := &HealthCheckReport{
+		CheckedAt: time.Now(),
+		Rules:     make([]string, 0, len(_healthCheckRules)),
+		Findings:  []HealthFinding{},
+	}
+
+	for _, rule := range _healthCheckRules {
+		report.Rules = append(report.Rules, rule.name)
+		for _, f := range rule.check(c) {
+			f.Rule = rule.name
+			report.Findings = append(report.Findings, f)
+		}
+	}
+
+	severityOrder := map[HealthSeverity]int{HealthSeverityCritical: 0, HealthSeverityWarning: 1, HealthSeverityInfo: 2}
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		return severityOrder[report.Findings[i].Severity] < severityOrder[report.Findings[j].Severity]
+	})
+
+	return report
+}
+
+// HealthReport - get the report of the latest health check run
+func (c *Cluster) HealthReport() *HealthCheckReport {
+	report, _ := c.healthReport.Get().(*HealthCheckReport)
+	return report
+}
+
+func checkHomogeneousBuilds(c *Cluster) []HealthFinding {
+	builds := map[string][]string{}
+	for _, node := range c.Nodes() {
+		builds[node.Build()] = append(builds[node.Build()], node.Address())
+	}
+
+	if len(builds) <= 1 {
+		return nil
+	}
+
+	versions := make([]string, 0, len(builds))
+	for build, nodes := range builds {
+		sort.Strings(nodes)
+		versions = append(versions, fmt.Sprintf("%s on [%s]", build, strings.Join(nodes, ", ")))
+	}
+	sort.Strings(versions)
+
+	return []HealthFinding{{
+		Severity: HealthSeverityWarning,
+		Message:  "Nodes run different server builds: " + strings.Join(versions, "; "),
+	}}
+}
+
+// configMismatches - find the parameters whose values differ between nodes; param => value => nodes
+func configMismatches(configs map[string]common.Stats) map[string]map[string][]string {
+	values := map[string]map[string][]string{}
+	for addr, config := range configs {
+		for k, v := range config {
+			if isNodeSpecificConfig(k) {
+				continue
+			}
+			if values[k] == nil {
+				values[k] = map[string][]string{}
+			}
+			values[k][fmt.Sprint(v)] = append(values[k][fmt.Sprint(v)], addr)
+		}
+	}
+
+	for k, v := range values {
+		if len(v) <= 1 {
+			delete(values, k)
+		}
+	}
+
+	return values
+}
+
+func mismatchFindings(mismatches map[string]map[string][]string, namespace string) []HealthFinding {
+	params := make([]string, 0, len(mismatches))
+	for k := range mismatches {
+		params = append(params, k)
+	}
+	sort.Strings(params)
+
+	res := make([]HealthFinding, 0, len(params))
+	for _, param := range params {
+		values := make([]string, 0, len(mismatches[param]))
+		for v, nodes := range mismatches[param] {
+			sort.Strings(nodes)
+			values = append(values, fmt.Sprintf("%s on [%s]", v, strings.Join(nodes, ", ")))
+		}
+		sort.Strings(values)
+
+		res = append(res, HealthFinding{
+			Severity:  HealthSeverityWarning,
+			Namespace: namespace,
+			Message:   fmt.Sprintf("`%s` differs between nodes: %s", param, strings.Join(values, "; ")),
+		})
+	}
+
+	return res
+}
+
+func checkServiceConfigMismatch(c *Cluster) []HealthFinding {
+	configs := map[string]common.Stats{}
+	for _, node := range c.Nodes() {
+		configs[node.Address()] = node.ConfigAttrs()
+	}
+
+	return mismatchFindings(configMismatches(configs), "")
+}
+
+func checkNamespaceConfigMismatch(c *Cluster) []HealthFinding {
+	var res []HealthFinding
+	for _, nsName := range c.NamespaceList() {
+		configs := map[string]common.Stats{}
+		for _, node := range c.Nodes() {
+			if ns := node.NamespaceByName(nsName); ns != nil {
+				configs[node.Address()] = ns.ConfigAttrs()
+			}
+		}
+
+		res = append(res, mismatchFindings(configMismatches(configs), nsName)...)
+	}
+
+	return res
+}
+
+func checkHighWaterMarks(c *Cluster) []HealthFinding {
+	var res []HealthFinding
+	for _, node := range c.Nodes() {
+		for name, ns := range node.Namespaces() {
+			config := ns.ConfigAttrs()
+
+			// pairs of eviction high water marks and the matching stop-writes thresholds
+			pairs := [][2]string{
+				{"high-water-memory-pct", "stop-writes-pct"},
+				{"evict-used-pct", "stop-writes-used-pct"},
+			}
+
+			for _, p := range pairs {
+				hwm := config.TryFloat(p[0], 0)
+				stopWrites := config.TryFloat(p[1], 0)
+				if hwm <= 0 || stopWrites <= 0 || hwm < stopWrites {
+					continue
+				}
+
+				res = append(res, HealthFinding{
+					Severity:  HealthSeverityCritical,
+					Node:      node.Address(),
+					Namespace: name,
+					Message:   fmt.Sprintf("`%s` (%v) is not below `%s` (%v); writes will stop before eviction starts", p[0], hwm, p[1], stopWrites),
+				})
+			}
+
+			diskHwm := config.TryFloat("high-water-disk-pct", 0)
+			if diskHwm > 0 && diskHwm >= 100-config.TryFloat("storage-engine.min-avail-pct", 5) {
+				res = append(res, HealthFinding{
+					Severity:  HealthSeverityWarning,
+					Node:      node.Address(),
+					Namespace: name,
+					Message:   fmt.Sprintf("`high-water-disk-pct` (%v) leaves less free space than `min-avail-pct`; writes may stop before eviction starts", diskHwm),
+				})
+			}
+		}
+	}
+
+	return res
+}
+
+func checkDeviceProvisioning(c *Cluster) []HealthFinding {
+	var res []HealthFinding
+	for _, node := range c.Nodes() {
+		// the same device or file configured for more than one namespace
+		used := map[string][]string{}
+		for name, ns := range node.Namespaces() {
+			config := ns.ConfigAttrs()
+			for k, v := range config {
+				if (strings.HasPrefix(k, "storage-engine.device") || strings.HasPrefix(k, "storage-engine.file")) && !strings.HasPrefix(k, "storage-engine.filesize") {
+					if path, ok := v.(string); ok && path != "" {
+						used[path] = append(used[path], name)
+					}
+				}
+			}
+
+			if availPct := ns.StatsAttrs("device_available_pct").TryFloat("device_available_pct", -1); availPct >= 0 && availPct < _deviceAvailablePctWarning {
+				res = append(res, HealthFinding{
+					Severity:  HealthSeverityWarning,
+					Node:      node.Address(),
+					Namespace: name,
+					Message:   fmt.Sprintf("Only %v%% of the storage is available for writes", availPct),
+				})
+			}
+
+			if lwm := config.TryFloat("storage-engine.defrag-lwm-pct", 0); lwm > 50 {
+				res = append(res, HealthFinding{
+					Severity:  HealthSeverityInfo,
+					Node:      node.Address(),
+					Namespace: name,
+					Message:   fmt.Sprintf("`defrag-lwm-pct` is %v; values above 50 increase write amplification", lwm),
+				})
+			}
+		}
+
+		for path, namespaces := range used {
+			if len(namespaces) > 1 {
+				sort.Strings(namespaces)
+				res = append(res, HealthFinding{
+					Severity: HealthSeverityCritical,
+					Node:     node.Address(),
+					Message:  fmt.Sprintf("%s is configured for multiple namespaces: %s", path, strings.Join(namespaces, ", ")),
+				})
+			}
+		}
+	}
+
+	return res
+}
+
+func checkClockSkew(c *Cluster) []HealthFinding {
+	// reported by server versions 4.0 and later; every node reports the skew of the whole cluster
+	var maxSkew int64
+	var maxNode string
+	for _, node := range c.Nodes() {
+		if ms, ok := node.StatsAttr("cluster_clock_skew_ms").(int64); ok && ms > maxSkew {
+			maxSkew, maxNode = ms, node.Address()
+		}
+	}
+
+	if maxSkew < _clockSkewWarningMs {
+		return nil
+	}
+
+	severity := HealthSeverityWarning
+	if maxSkew >= _clockSkewCriticalMs {
+		severity = HealthSeverityCritical
+	}
+
+	return []HealthFinding{{
+		Severity: severity,
+		Node:     maxNode,
+		Message:  fmt.Sprintf("Cluster clock skew is %dms; check the time synchronization of the nodes", maxSkew),
+	}}
+}
@@ -0,0 +1,172 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// supported histogram types; object-size-linear is the pre 5.x linear object size histogram
+var _histogramTypes = map[string]struct{}{
+	"ttl":                {},
+	"object-size":        {},
+	"object-size-linear": {},
+}
+
+// Histogram - a linear histogram dump of a namespace
+type Histogram struct {
+	Units       string  `json:"units"`
+	BucketWidth int64   `json:"bucket_width"`
+	Buckets     []int64 `json:"buckets"`
+}
+
+// HistogramBucket - a bucket in a histogram for charting
+type HistogramBucket struct {
+	From  int64 `json:"from"`
+	To    int64 `json:"to"`
+	Count int64 `json:"count"`
+}
+
+// parseHistogram - parse the output of the histogram info command, e.g.
+// units=seconds:hist-width=2592000:bucket-width=25920:buckets=0,1,...
+func parseHistogram(s string) (*Histogram, error) {
+	h := &Histogram{}
+	for _, part := range strings.Split(strings.TrimSpace(s), ":") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+
+		switch kv[0] {
+		case "units":
+			h.Units = kv[1]
+		case "bucket-width":
+			w, err := strconv.ParseInt(kv[1], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid histogram bucket width `%s`", kv[1])
+			}
+			h.BucketWidth = w
+		case "buckets":
+			for _, b := range strings.Split(kv[1], ",") {
+				v, err := strconv.ParseInt(b, 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("Invalid histogram bucket value `%s`", b)
+				}
+				h.Buckets = append(h.Buckets, v)
+			}
+		}
+	}
+
+	if h.BucketWidth <= 0 {
+		return nil, fmt.Errorf("Invalid histogram: %s", s)
+	}
+
+	return h, nil
+}
+
+// ValidHistogramType - check if the histogram type is supported
+func ValidHistogramType(histType string) bool {
+	_, exists := _histogramTypes[histType]
+	return exists
+}
+
+// Histogram - dump a histogram of the namespace on the node
+func (n *Node) Histogram(namespace, histType string) (*Histogram, error) {
+	if !ValidHistogramType(histType) {
+		return nil, common.Errorf(common.KindInvalid, "Histogram type `%s` is not supported", histType)
+	}
+
+	if n.NamespaceByName(namespace) == nil {
+		return nil, common.Errorf(common.KindNamespaceNotFound, "Namespace %s not found", namespace)
+	}
+
+	cmd := fmt.Sprintf("histogram:namespace=%s;type=%s", namespace, histType)
+	res, err := n.RequestInfo(3, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	v := res[cmd]
+	if v == "" || strings.HasPrefix(strings.ToLower(v), "error") {
+		return nil, fmt.Errorf("Histogram not available: %s", v)
+	}
+
+	return parseHistogram(v)
+}
+
+// Histogram - dump a histogram of the namespace on all nodes and aggregate them.
+// Buckets of nodes with different bucket widths are re-binned to the widest one.
+func (c *Cluster) Histogram(namespace, histType string) ([]HistogramBucket, map[string]*Histogram, error) {
+	nodes := c.Nodes()
+
+	var mutex sync.Mutex
+	perNode := make(map[string]*Histogram, len(nodes))
+	errs := []string{}
+
+	wg := new(sync.WaitGroup)
+	wg.Add(len(nodes))
+	for _, node := range nodes {
+		go func(node *Node) {
+			defer wg.Done()
+
+			h, err := node.Histogram(namespace, histType)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %s", node.Address(), err.Error()))
+				return
+			}
+			perNode[node.Address()] = h
+		}(node)
+	}
+	wg.Wait()
+
+	if len(perNode) == 0 {
+		if len(errs) > 0 {
+			return nil, nil, errors.New(strings.Join(errs, ", "))
+		}
+		return nil, nil, common.Errorf(common.KindNotFound, "No histogram data")
+	}
+
+	width := int64(0)
+	for _, h := range perNode {
+		if h.BucketWidth > width {
+			width = h.BucketWidth
+		}
+	}
+
+	counts := map[int64]int64{}
+	for _, h := range perNode {
+		for i, v := range h.Buckets {
+			from := int64(i) * h.BucketWidth
+			counts[from/width*width] += v
+		}
+	}
+
+	starts := make([]int64, 0, len(counts))
+	maxStart := int64(-1)
+	for from, count := range counts {
+		starts = append(starts, from)
+		if count > 0 && from > maxStart {
+			maxStart = from
+		}
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i] < starts[j] })
+
+	// trailing empty buckets are not interesting for charting
+	res := []HistogramBucket{}
+	for _, from := range starts {
+		if from > maxStart {
+			break
+		}
+		res = append(res, HistogramBucket{From: from, To: from + width, Count: counts[from]})
+	}
+
+	return res, perNode, nil
+}
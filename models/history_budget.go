@@ -0,0 +1,176 @@
+package models
+
+import (
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/aerospike-community/amc/rrd"
+)
+
+const (
+	// estimated memory used by a node latency report in the history
+	_latencyPointBytes = 2048
+
+	// the history retention is never reduced below this fraction of the default
+	_maxHistoryShrinkFactor = 32
+
+	// how often the history budget is checked
+	_historyBudgetInterval = 30 * time.Second
+
+	_defaultStatsHistoryCapacity   = 1800
+	_defaultLatencyHistoryCapacity = 3600
+)
+
+// HistoryMemoryUsage - the estimated memory used by the history of a cluster
+type HistoryMemoryUsage struct {
+	ClusterID string `json:"cluster_id"`
+	Bytes     int64  `json:"bytes"`
+	Limit     int64  `json:"limit"`
+
+	// the retention of the history is divided by this factor to stay within the limits
+	ShrinkFactor int `json:"shrink_factor"`
+}
+
+func (c *Cluster) historyBuckets() ([]*rrd.Bucket, []*rrd.SimpleBucket) {
+	var buckets []*rrd.Bucket
+	var simpleBuckets []*rrd.SimpleBucket
+
+	for _, node := range c.Nodes() {
+		for _, b := range node.statsHistory {
+			buckets = append(buckets, b)
+		}
+		simpleBuckets = append(simpleBuckets, node.latencyHistory)
+
+		for _, ns := range node.Namespaces() {
+			for _, b := range ns.statsHistory {
+				buckets = append(buckets, b)
+			}
+			for _, b := range ns.migrationHistory {
+				buckets = append(buckets, b)
+			}
+			for _, b := range ns.gaugeHistory {
+				buckets = append(buckets, b)
+			}
+			simpleBuckets = append(simpleBuckets, ns.latencyHistory)
+		}
+	}
+
+	return buckets, simpleBuckets
+}
+
+// HistoryMemoryUsage - get the estimated memory used by the throughput and latency history of the cluster
+func (c *Cluster) HistoryMemoryUsage() HistoryMemoryUsage {
+	buckets, simpleBuckets := c.historyBuckets()
+
+	res := HistoryMemoryUsage{
+		ClusterID:    c.ID(),
+		Limit:        c.observer.config.HistoryMemoryLimitPerCluster(),
+		ShrinkFactor: c.historyShrinkFactor.Get().(int),
+	}
+
+	for _, b := range buckets {
+		res.Bytes += b.MemoryUsage()
+	}
+	for _, b := range simpleBuckets {
+		res.Bytes += b.MemoryUsage(_latencyPointBytes)
+	}
+
+	return res
+}
+
+// applyHistoryRetention - resize the history buckets to the current retention of the cluster
+func (c *Cluster) applyHistoryRetention() {
+	factor := c.historyShrinkFactor.Get().(int)
+	buckets, simpleBuckets := c.historyBuckets()
+
+	for _, b := range buckets {
+		b.SetCapacity(_defaultStatsHistoryCapacity / factor)
+	}
+	for _, b := range simpleBuckets {
+		b.SetSize(_defaultLatencyHistoryCapacity / factor)
+	}
+}
+
+// shrinkHistory - halve the retention of the history of the cluster; returns false if it cannot be reduced further
+func (c *Cluster) shrinkHistory() bool {
+	factor := c.historyShrinkFactor.Get().(int)
+	if factor >= _maxHistoryShrinkFactor {
+		return false
+	}
+
+	c.historyShrinkFactor.Set(factor * 2)
+	c.applyHistoryRetention()
+
+	log.Warnf("The history of cluster %s exceeds its memory budget; keeping %s of history", c.ID(), time.Duration(_defaultStatsHistoryCapacity/(factor*2)*c.UpdateInterval())*time.Second)
+	return true
+}
+
+// enforceHistoryBudget - drop the oldest history of the clusters which exceed the per-cluster memory budget,
+// then of the largest clusters until the total fits in the global budget
+func (o *ObserverT) enforceHistoryBudget() {
+	perCluster := o.config.HistoryMemoryLimitPerCluster()
+	global := o.config.HistoryMemoryLimit()
+	if perCluster <= 0 && global <= 0 {
+		return
+	}
+
+	usages := map[*Cluster]int64{}
+	for _, c := range o.Clusters() {
+		// new nodes and namespaces start with the default retention
+		c.applyHistoryRetention()
+
+		usage := c.HistoryMemoryUsage().Bytes
+		for perCluster > 0 && usage > perCluster && c.shrinkHistory() {
+			usage = c.HistoryMemoryUsage().Bytes
+		}
+		usages[c] = usage
+	}
+
+	if global <= 0 {
+		return
+	}
+
+	for {
+		var total int64
+		clusters := make([]*Cluster, 0, len(usages))
+		for c, usage := range usages {
+			total += usage
+			clusters = append(clusters, c)
+		}
+
+		if total <= global {
+			return
+		}
+
+		sort.Slice(clusters, func(i, j int) bool { return usages[clusters[i]] > usages[clusters[j]] })
+
+		shrunk := false
+		for _, c := range clusters {
+			if c.shrinkHistory() {
+				usages[c] = c.HistoryMemoryUsage().Bytes
+				shrunk = true
+				break
+			}
+		}
+
+		if !shrunk {
+			log.Warn("The history of all clusters has been reduced to the minimum, but still exceeds the memory budget")
+			return
+		}
+	}
+}
+
+// HistoryMemoryUsage - get the estimated memory used by the history of all clusters
+func (o *ObserverT) HistoryMemoryUsage() (int64, []HistoryMemoryUsage) {
+	var total int64
+	res := []HistoryMemoryUsage{}
+	for _, c := range o.Clusters() {
+		usage := c.HistoryMemoryUsage()
+		total += usage.Bytes
+		res = append(res, usage)
+	}
+
+	return total, res
+}
@@ -0,0 +1,182 @@
+package models
+
+import (
+	"context"
+	"encoding/hex"
+	"sort"
+	"time"
+
+	as "github.com/aerospike/aerospike-client-go/v5"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// the limits of the hot key sampling; it runs within a request, so the window is kept shorter than the default
+// request timeout
+const (
+	HotKeysDefaultSampleSize = 1000
+	HotKeysMaxSampleSize     = 10000
+	HotKeysDefaultWindow     = 5 * time.Second
+	HotKeysMaxWindow         = 20 * time.Second
+	HotKeysDefaultTop        = 10
+	HotKeysMaxTop            = 100
+
+	// the headers of the sample are read again in batches of this many keys
+	hotKeysBatchSize = 1000
+)
+
+// HotKeySpec - the records sampled for the hot keys: sample_size records of sample_pct of the partitions of the set,
+// all the sets if empty, whose generations are compared window apart
+type HotKeySpec struct {
+	Set        string
+	SampleSize int
+	SamplePct  float64
+	Window     time.Duration
+	Top        int
+}
+
+// HotKey - a sampled record and the writes to it during the window
+type HotKey struct {
+	Digest       string      `json:"digest"`
+	Key          interface{} `json:"key,omitempty"` // only if the key was stored with the record
+	Set          string      `json:"set"`
+	Writes       int64       `json:"writes"`
+	WritesPerSec float64     `json:"writes_per_sec"`
+	Generation   uint32      `json:"generation"`
+}
+
+// HotKeyReport - the most written records of a sample of the namespace, and the transactions the nodes failed
+// during the window because of a hot key
+type HotKeyReport struct {
+	Namespace         string   `json:"namespace"`
+	Set               string   `json:"set"`
+	SampledRecords    int      `json:"sampled_records"`
+	DeletedRecords    int      `json:"deleted_records"` // the sampled records which were deleted during the window
+	WindowSec         float64  `json:"window_sec"`
+	KeyBusyErrors     int64    `json:"key_busy_errors"` // the increase of fail_key_busy on all the nodes
+	KeyBusyErrorsRate float64  `json:"key_busy_errors_per_sec"`
+	Keys              []HotKey `json:"keys"`
+}
+
+// HotKeys - sample the records of the namespace, read their generations again after the window, and return the
+// records written the most in between. The server does not track the hot keys, so only the hot keys in the sample
+// are found, and the reads are not counted; a rise of the key busy errors shows that there are some.
+func (c *Cluster) HotKeys(ctx context.Context, namespace string, spec HotKeySpec) (*HotKeyReport, error) {
+	if spec.SampleSize <= 0 || spec.SampleSize > HotKeysMaxSampleSize {
+		return nil, common.Errorf(common.KindInvalid, "The sample size must be between 1 and %d", HotKeysMaxSampleSize)
+	}
+	if spec.Window <= 0 || spec.Window > HotKeysMaxWindow {
+		return nil, common.Errorf(common.KindInvalid, "The window must be between 1 and %d seconds", int(HotKeysMaxWindow.Seconds()))
+	}
+	if spec.Top <= 0 || spec.Top > HotKeysMaxTop {
+		return nil, common.Errorf(common.KindInvalid, "The number of keys must be between 1 and %d", HotKeysMaxTop)
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < spec.Window+5*time.Second {
+		return nil, common.Errorf(common.KindInvalid, "The window must be shorter than the timeout of the request")
+	}
+
+	rs, err := c.scanSample(ctx, namespace, spec.Set, spec.SampleSize, spec.SamplePct, nil, false)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []*as.Key{}
+	generations := []uint32{}
+	err = readRecords(ctx, rs, spec.SampleSize, func(r *as.Record) error {
+		keys = append(keys, r.Key)
+		generations = append(generations, r.Generation)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	keyBusy := c.keyBusyErrors(namespace)
+	start := time.Now()
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(spec.Window):
+	}
+
+	client := c.origClient()
+	if client == nil {
+		return nil, common.Errorf(common.KindUnavailable, "Cluster %s has been decommissioned", c.ID())
+	}
+
+	res := &HotKeyReport{Namespace: namespace, Set: spec.Set, SampledRecords: len(keys), Keys: []HotKey{}}
+	for i := 0; i < len(keys); i += hotKeysBatchSize {
+		batch := keys[i:]
+		if len(batch) > hotKeysBatchSize {
+			batch = batch[:hotKeysBatchSize]
+		}
+
+		records, err := client.BatchGetHeader(nil, batch)
+		if err != nil {
+			return nil, err
+		}
+
+		for j, r := range records {
+			if r == nil {
+				res.DeletedRecords++
+				continue
+			}
+
+			// the generation wraps around at 2^16
+			writes := int64(r.Generation) - int64(generations[i+j])
+			if writes < 0 {
+				writes += 1 << 16
+			}
+			if writes == 0 {
+				continue
+			}
+
+			key := HotKey{
+				Digest:     hex.EncodeToString(batch[j].Digest()),
+				Set:        batch[j].SetName(),
+				Writes:     writes,
+				Generation: r.Generation,
+			}
+			if v := batch[j].Value(); v != nil {
+				key.Key = jsonValue(v.GetObject())
+			}
+			res.Keys = append(res.Keys, key)
+		}
+	}
+	elapsed := time.Since(start)
+	res.WindowSec = elapsed.Seconds()
+
+	res.KeyBusyErrors = c.keyBusyErrors(namespace) - keyBusy
+	if res.KeyBusyErrors < 0 {
+		// a node restarted during the window
+		res.KeyBusyErrors = 0
+	}
+	res.KeyBusyErrorsRate = float64(res.KeyBusyErrors) / res.WindowSec
+
+	sort.Slice(res.Keys, func(i, j int) bool {
+		if res.Keys[i].Writes != res.Keys[j].Writes {
+			return res.Keys[i].Writes > res.Keys[j].Writes
+		}
+		return res.Keys[i].Digest < res.Keys[j].Digest
+	})
+	if len(res.Keys) > spec.Top {
+		res.Keys = res.Keys[:spec.Top]
+	}
+	for i := range res.Keys {
+		res.Keys[i].WritesPerSec = float64(res.Keys[i].Writes) / res.WindowSec
+	}
+	return res, nil
+}
+
+// keyBusyErrors - the sum of fail_key_busy of the namespace on the nodes, the transactions failed because
+// too many were queued on the same key
+func (c *Cluster) keyBusyErrors(namespace string) int64 {
+	cmd := "namespace/" + namespace
+	res, _ := c.RequestInfoAll(cmd)
+
+	total := int64(0)
+	for _, raw := range res {
+		total += common.Info{cmd: raw}.ToInfo(cmd).ToStats().TryInt("fail_key_busy", 0)
+	}
+	return total
+}
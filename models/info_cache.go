@@ -0,0 +1,102 @@
+package models
+
+import (
+	"time"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// default time the results of read-only info commands are reused for
+const _defaultInfoCacheTTL = 2 * time.Second
+
+// read-only info commands whose results may be cached
+var _cacheableInfoCommands = []string{
+	"statistics", "get-config", "get-stats", "get-dc-config", "dc/*",
+	"namespaces", "namespace/*", "sets", "sets/*", "bins", "bins/*",
+	"sindex", "sindex/*", "sindex-list", "histogram", "latency", "latencies",
+	"build", "build-*", "version", "edition", "node", "features", "feature-key",
+	"service", "services", "services-alumni", "peers-*", "cluster-name",
+	"logs", "log/*", "udf-list", "roster", "racks",
+}
+
+type infoCacheEntry struct {
+	value string
+	at    time.Time
+}
+
+// infoCache keeps the recent results of read-only info commands of a node,
+// so that bursts of requests from the UI do not issue duplicate info calls
+type infoCache struct {
+	entries map[string]infoCacheEntry
+	mutex   common.Mutex
+}
+
+func newInfoCache() *infoCache {
+	return &infoCache{
+		entries: map[string]infoCacheEntry{},
+	}
+}
+
+func isCacheableInfoCommand(cmd string) bool {
+	return matchInfoCommand(infoCommandName(cmd), _cacheableInfoCommands)
+}
+
+// lookup - get the cached results of the commands which are younger than ttl, and the commands which are not cached
+func (ic *infoCache) lookup(ttl time.Duration, cmds []string) (map[string]string, []string) {
+	cached := make(map[string]string, len(cmds))
+	missing := make([]string, 0, len(cmds))
+
+	ic.mutex.Lock()
+	defer ic.mutex.Unlock()
+
+	for _, cmd := range cmds {
+		if entry, exists := ic.entries[cmd]; exists && ttl > 0 && time.Since(entry.at) < ttl {
+			cached[cmd] = entry.value
+		} else {
+			missing = append(missing, cmd)
+		}
+	}
+
+	return cached, missing
+}
+
+// store - cache the results of the cacheable commands and drop the expired entries
+func (ic *infoCache) store(ttl time.Duration, result map[string]string) {
+	if ttl <= 0 {
+		return
+	}
+
+	now := time.Now()
+
+	ic.mutex.Lock()
+	defer ic.mutex.Unlock()
+
+	for cmd, entry := range ic.entries {
+		if now.Sub(entry.at) >= ttl {
+			delete(ic.entries, cmd)
+		}
+	}
+
+	for cmd, value := range result {
+		if isCacheableInfoCommand(cmd) {
+			ic.entries[cmd] = infoCacheEntry{value: value, at: now}
+		}
+	}
+}
+
+func (ic *infoCache) clear() {
+	ic.mutex.Lock()
+	ic.entries = map[string]infoCacheEntry{}
+	ic.mutex.Unlock()
+}
+
+// infoCacheTTL - the configured TTL of the info cache; negative values disable caching
+func (o *ObserverT) infoCacheTTL() time.Duration {
+	ms := o.config.InfoCacheTTL()
+	if ms == 0 {
+		return _defaultInfoCacheTTL
+	} else if ms < 0 {
+		return 0
+	}
+	return time.Duration(ms) * time.Millisecond
+}
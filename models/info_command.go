@@ -0,0 +1,137 @@
+package models
+
+import (
+	"strings"
+
+	as "github.com/aerospike/aerospike-client-go/v5"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// info commands which change the state of the cluster; denied for users without admin privileges
+var _destructiveInfoCommands = []string{
+	"set-config", "log-set", "set-log",
+	"truncate", "truncate-namespace", "truncate-undo", "truncate-namespace-undo",
+	"recluster", "quiesce", "quiesce-undo", "revive", "roster-set",
+	"sindex-create", "sindex-delete", "udf-put", "udf-remove",
+	"tip", "tip-clear", "services-alumni-reset", "dun", "undun",
+	"xdr-set-window", "dump-*", "jem-stats", "mstats",
+}
+
+// infoCommandName - the name of an info command without its parameters, e.g. `set-config` for `set-config:context=service;...`
+func infoCommandName(cmd string) string {
+	cmd = strings.TrimSpace(cmd)
+	if i := strings.Index(cmd, ":"); i >= 0 {
+		return cmd[:i]
+	}
+	return cmd
+}
+
+// matchInfoCommand - match the command name against the patterns; a trailing `*` matches any suffix
+func matchInfoCommand(name string, patterns []string) bool {
+	for _, p := range patterns {
+		p = strings.TrimSpace(p)
+		if strings.HasSuffix(p, "*") {
+			if strings.HasPrefix(name, strings.TrimSuffix(p, "*")) {
+				return true
+			}
+		} else if name == p {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAdmin - check if the current user has admin privileges.
+// Without security there are no roles, so everyone is considered an admin.
+func (c *Cluster) IsAdmin() bool {
+	if user := c.User(); user == nil || *user == "" {
+		return true
+	}
+
+	privileges, _ := c.currentUserPrivileges.Get().([]string)
+	for _, priv := range privileges {
+		if priv == string(as.SysAdmin) || priv == string(as.UserAdmin) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CheckInfoCommand - check whether the info command may be sent to the cluster,
+// using the configured allowlist and denylist, and the default denylist for non-admin users.
+// The servers run every line of a request as a command, so only a single line is allowed.
+func (c *Cluster) CheckInfoCommand(cmd string) error {
+	if strings.ContainsAny(cmd, "\r\n") {
+		return common.Errorf(common.KindInvalid, "Invalid command: only one command can be sent at a time")
+	}
+
+	name := infoCommandName(cmd)
+	if name == "" {
+		return common.Errorf(common.KindInvalid, "Invalid command")
+	}
+
+	rules := c.observer.Config().FireCmdRules()
+	if len(rules.Allow) > 0 && !matchInfoCommand(name, rules.Allow) {
+		return common.Errorf(common.KindNotAllowed, "Command `%s` is not in the allowed list of commands", name)
+	}
+
+	if matchInfoCommand(name, rules.Deny) {
+		return common.Errorf(common.KindNotAllowed, "Command `%s` is not allowed", name)
+	}
+
+	if !c.IsAdmin() && matchInfoCommand(name, _destructiveInfoCommands) {
+		return common.Errorf(common.KindNotAllowed, "Command `%s` requires admin privileges", name)
+	}
+
+	return nil
+}
+
+// ParseInfoResult - parse the raw output of an info command where possible:
+// `k1=v1;k2=v2` into a map, `k1=v1:k2=v2;...` records into a list of maps
+// and `a;b;c` into a list. Other outputs are returned unchanged.
+func ParseInfoResult(raw string) interface{} {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return raw
+	}
+
+	entries := strings.Split(strings.TrimSuffix(raw, ";"), ";")
+
+	isRecord := func(e string) bool {
+		return strings.Contains(e, ":") && strings.Count(e, "=") > 1
+	}
+
+	if isRecord(entries[0]) {
+		records := make([]map[string]string, 0, len(entries))
+		for _, e := range entries {
+			records = append(records, parseInfoPairs(strings.Split(e, ":")))
+		}
+		return records
+	}
+
+	for _, e := range entries {
+		if !strings.Contains(e, "=") {
+			if len(entries) == 1 {
+				return raw
+			}
+			return entries
+		}
+	}
+
+	return parseInfoPairs(entries)
+}
+
+func parseInfoPairs(pairs []string) map[string]string {
+	res := make(map[string]string, len(pairs))
+	for _, p := range pairs {
+		kv := strings.SplitN(p, "=", 2)
+		if len(kv) == 2 {
+			res[kv[0]] = kv[1]
+		} else if kv[0] != "" {
+			res[kv[0]] = ""
+		}
+	}
+	return res
+}
@@ -0,0 +1,125 @@
+package models
+
+import (
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// NodeState is the connection and polling state of a node
+type NodeState struct {
+	ID           string `json:"id"`
+	Address      string `json:"address"`
+	Status       string `json:"status"`
+	Connected    bool   `json:"connected"`
+	StatusReason string `json:"status_reason,omitempty"`
+	RetryAt      int64  `json:"retry_at,omitempty"`
+	Namespaces   int    `json:"namespaces"`
+}
+
+// ClusterState is the internal state of a monitored cluster
+type ClusterState struct {
+	ID        string `json:"id"`
+	Alias     string `json:"alias,omitempty"`
+	Status    string `json:"status"`
+	Permanent bool   `json:"permanent"`
+	Connected bool   `json:"connected"`
+	Sleeping  bool   `json:"sleeping"`
+	Sessions  int    `json:"sessions"` // the sessions holding the cluster
+
+	// times are unix timestamps in seconds, durations are in milliseconds
+	LastPing           int64  `json:"last_ping,omitempty"`
+	Updating           bool   `json:"updating"`
+	UpdateStarted      int64  `json:"update_started,omitempty"`
+	LastUpdate         int64  `json:"last_update,omitempty"`
+	LastUpdateDuration int64  `json:"last_update_duration"`
+	UpdateInterval     int    `json:"update_interval"`
+	PollBackoff        int    `json:"poll_backoff"`
+	StatProfile        string `json:"stat_profile"`
+
+	Nodes   []NodeState        `json:"nodes"`
+	History HistoryMemoryUsage `json:"history"`
+}
+
+// ObserverState is a dump of the internals of the observer, to find out why clusters stop updating
+type ObserverState struct {
+	Sessions int             `json:"sessions"`
+	Clusters []ClusterState  `json:"clusters"`
+	Workers  WorkerPoolState `json:"workers"`
+}
+
+// State - dump the internal state of the observer and its clusters
+func (o *ObserverT) State() ObserverState {
+	sessions := o.sessions.Clone()
+
+	clusterSessions := map[*Cluster]int{}
+	for _, clusters := range sessions {
+		for _, c := range clusters.([]*Cluster) {
+			clusterSessions[c]++
+		}
+	}
+
+	res := ObserverState{
+		Sessions: len(sessions),
+		Clusters: []ClusterState{},
+		Workers:  o.workers.state(),
+	}
+
+	for _, c := range o.Clusters() {
+		state := c.state()
+		state.Sessions = clusterSessions[c]
+		res.Clusters = append(res.Clusters, state)
+	}
+
+	return res
+}
+
+func (c *Cluster) state() ClusterState {
+	res := ClusterState{
+		ID:             c.ID(),
+		Status:         c.Status(),
+		Permanent:      c.IsPermanent(),
+		Connected:      c.IsSet(),
+		Sleeping:       c.Sleeping(),
+		Updating:       atomic.LoadInt32(&c.updating) == 1,
+		UpdateInterval: c.UpdateInterval(),
+		PollBackoff:    c.PollBackoff(),
+		StatProfile:    c.StatProfile().Name,
+		Nodes:          []NodeState{},
+		History:        c.HistoryMemoryUsage(),
+	}
+
+	if alias := c.Alias(); alias != nil {
+		res.Alias = *alias
+	}
+	if lastPing, _ := c.lastPing.Get().(time.Time); !lastPing.IsZero() {
+		res.LastPing = lastPing.Unix()
+	}
+	if started, _ := c.updateStarted.Get().(time.Time); !started.IsZero() {
+		res.UpdateStarted = started.Unix()
+	}
+	if lastUpdate := c.LastUpdated(); !lastUpdate.IsZero() {
+		res.LastUpdate = lastUpdate.Unix()
+	}
+	if took, ok := c.lastUpdateDuration.Get().(time.Duration); ok {
+		res.LastUpdateDuration = int64(took / time.Millisecond)
+	}
+
+	for _, node := range c.Nodes() {
+		ns := NodeState{
+			ID:           node.ID(),
+			Address:      node.Address(),
+			Status:       string(node.Status()),
+			Connected:    node.valid(),
+			StatusReason: node.StatusReason(),
+			Namespaces:   len(node.Namespaces()),
+		}
+		if retryAt := node.RetryAt(); !retryAt.IsZero() {
+			ns.RetryAt = retryAt.Unix()
+		}
+		res.Nodes = append(res.Nodes, ns)
+	}
+	sort.Slice(res.Nodes, func(i, j int) bool { return res.Nodes[i].Address < res.Nodes[j].Address })
+
+	return res
+}
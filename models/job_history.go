@@ -0,0 +1,91 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// maximum number of completed jobs kept per cluster
+const _maxJobHistorySize = 1000
+
+func jobHistoryKey(job common.Stats) string {
+	return fmt.Sprintf("%v/%v/%v", job["address"], job["module"], job["trid"])
+}
+
+// jobResult - extract the result from a job status, e.g. done(ok) => ok
+func jobResult(status string) string {
+	if i := strings.Index(status, "("); i >= 0 {
+		return strings.TrimSuffix(status[i+1:], ")")
+	}
+	return status
+}
+
+// recordCompletedJobs - add the newly completed jobs to the rolling history,
+// so they remain available after the server expires them
+func (c *Cluster) recordCompletedJobs(jobs []common.Stats) {
+	history := c.JobHistory()
+	seen := make(map[string]struct{}, len(history))
+	for _, job := range history {
+		seen[jobHistoryKey(job)] = struct{}{}
+	}
+
+	now := time.Now()
+	newHistory := history
+	for _, job := range jobs {
+		status := job.TryString("status", "")
+		if !strings.HasPrefix(status, "done") {
+			continue
+		}
+
+		node, _ := job["node"].(common.Stats)
+		entry := common.Stats{
+			"address":  node.TryString("address", ""),
+			"trid":     job["trid"],
+			"module":   job.TryString("module", ""),
+			"job-type": job.TryString("job-type", ""),
+			"ns":       job.TryString("ns", ""),
+			"set":      job.TryString("set", ""),
+			"status":   status,
+			"result":   jobResult(status),
+			// run-time and time-since-done are in milliseconds
+			"duration":     job.TryInt("run-time", 0),
+			"recs-read":    job.TryInt("recs-read", job.TryInt("recs-succeeded", 0)),
+			"net-io-bytes": job.TryInt("net-io-bytes", 0),
+			"completed":    now.Add(-time.Duration(job.TryInt("time-since-done", 0))*time.Millisecond).UnixNano() / 1e6,
+		}
+
+		key := jobHistoryKey(entry)
+		if _, exists := seen[key]; exists {
+			continue
+		}
+		seen[key] = struct{}{}
+
+		newHistory = append(newHistory, entry)
+	}
+
+	if len(newHistory) == len(history) {
+		return
+	}
+
+	if len(newHistory) > _maxJobHistorySize {
+		newHistory = newHistory[len(newHistory)-_maxJobHistorySize:]
+	}
+
+	c.jobHistory.Set(newHistory)
+}
+
+// JobHistory - get a copy of the completed jobs, oldest first
+func (c *Cluster) JobHistory() []common.Stats {
+	res := c.jobHistory.Get()
+	if res == nil {
+		return []common.Stats{}
+	}
+
+	history := res.([]common.Stats)
+	jobs := make([]common.Stats, len(history))
+	copy(jobs, history)
+	return jobs
+}
@@ -0,0 +1,171 @@
+package models
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	as "github.com/aerospike/aerospike-client-go/v5"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+const (
+	_kubernetesServiceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+	// the port of the endpoints used if the service has several and none is specified
+	_kubernetesDefaultPortName = "service"
+)
+
+// kubernetesClient reads the endpoints of services from the Kubernetes API,
+// using the service account of the pod AMC runs in
+type kubernetesClient struct {
+	apiURL     string
+	token      string
+	httpClient *http.Client
+}
+
+var (
+	_kubernetesClient      *kubernetesClient
+	_kubernetesClientErr   error
+	_kubernetesClientMutex sync.Mutex
+)
+
+// inClusterKubernetesClient - get the client for the Kubernetes API of the cluster AMC runs in
+func inClusterKubernetesClient() (*kubernetesClient, error) {
+	_kubernetesClientMutex.Lock()
+	defer _kubernetesClientMutex.Unlock()
+
+	if _kubernetesClient != nil || _kubernetesClientErr != nil {
+		return _kubernetesClient, _kubernetesClientErr
+	}
+
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		_kubernetesClientErr = errors.New("AMC is not running in a Kubernetes cluster")
+		return nil, _kubernetesClientErr
+	}
+
+	token, err := ioutil.ReadFile(_kubernetesServiceAccountDir + "/token")
+	if err != nil {
+		_kubernetesClientErr = fmt.Errorf("Error reading the Kubernetes service account token: %s", err.Error())
+		return nil, _kubernetesClientErr
+	}
+
+	ca, err := ioutil.ReadFile(_kubernetesServiceAccountDir + "/ca.crt")
+	if err != nil {
+		_kubernetesClientErr = fmt.Errorf("Error reading the Kubernetes CA certificate: %s", err.Error())
+		return nil, _kubernetesClientErr
+	}
+
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM(ca)
+
+	_kubernetesClient = &kubernetesClient{
+		apiURL: "https://" + net.JoinHostPort(host, port),
+		token:  strings.TrimSpace(string(token)),
+		httpClient: &http.Client{
+			Timeout:   10 * time.Second,
+			Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}},
+		},
+	}
+	return _kubernetesClient, nil
+}
+
+type kubernetesEndpoints struct {
+	Subsets []struct {
+		Addresses []struct {
+			IP string `json:"ip"`
+		} `json:"addresses"`
+		Ports []struct {
+			Name string `json:"name"`
+			Port int    `json:"port"`
+		} `json:"ports"`
+	} `json:"subsets"`
+}
+
+// endpoints - get the ready endpoints of the service
+func (k *kubernetesClient) endpoints(namespace, service string) (*kubernetesEndpoints, error) {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/namespaces/%s/endpoints/%s", k.apiURL, namespace, service), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+k.token)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := k.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Error reading the endpoints of %s/%s: %s", namespace, service, resp.Status)
+	}
+
+	res := &kubernetesEndpoints{}
+	if err := json.NewDecoder(resp.Body).Decode(res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ResolveKubernetesService - get the hosts of the ready pods behind the service, given in the namespace/service form.
+// If port is 0, the port named "service" is used, or the only port of the service.
+func (o *ObserverT) ResolveKubernetesService(name string, port int, tlsName string) ([]*as.Host, error) {
+	parts := strings.SplitN(name, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, common.Errorf(common.KindInvalid, "Invalid Kubernetes service %q; expected <namespace>/<service>", name)
+	}
+
+	k, err := inClusterKubernetesClient()
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints, err := k.endpoints(parts[0], parts[1])
+	if err != nil {
+		return nil, err
+	}
+
+	hosts := []*as.Host{}
+	for _, subset := range endpoints.Subsets {
+		subsetPort := port
+		if subsetPort == 0 {
+			for _, p := range subset.Ports {
+				if p.Name == _kubernetesDefaultPortName || len(subset.Ports) == 1 {
+					subsetPort = p.Port
+				}
+			}
+		}
+
+		if subsetPort == 0 {
+			continue
+		}
+
+		for _, addr := range subset.Addresses {
+			host := as.NewHost(addr.IP, subsetPort)
+			host.TLSName = tlsName
+			hosts = append(hosts, host)
+		}
+	}
+
+	if len(hosts) == 0 {
+		return nil, fmt.Errorf("Kubernetes service %s has no ready endpoints", name)
+	}
+	return hosts, nil
+}
+
+// SetKubernetesSeed - seed the cluster from the endpoints of the Kubernetes service from now on
+func (c *Cluster) SetKubernetesSeed(name string, port int, tlsName string) {
+	c.discoverySeed.Set(&discoverySeed{kind: seedKindKubernetes, name: name, port: port, tlsName: tlsName})
+}
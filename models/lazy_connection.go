@@ -0,0 +1,85 @@
+package models
+
+import (
+	"time"
+
+	as "github.com/aerospike/aerospike-client-go/v5"
+	log "github.com/sirupsen/logrus"
+)
+
+// Sleeping - check if the connections of the cluster have been closed because it has not been viewed for a while
+func (c *Cluster) Sleeping() bool {
+	return c.sleeping.Get().(bool)
+}
+
+func (c *Cluster) shouldSleep() bool {
+	lastPing := c.lastPing.Get().(time.Time)
+
+	// clusters which have never been viewed are monitored for their alerts
+	if lastPing.IsZero() || c.permanent.Get().(bool) || c.Sleeping() || c.policy == nil {
+		return false
+	}
+
+	// cluster_inactive_before_disconnect <= 0 means never disconnect
+	idle := c.observer.config.InactiveDurBeforeDisconnect()
+	return idle > 0 && time.Since(lastPing) > idle
+}
+
+// sleep - close the connections to the cluster, keeping the nodes and their history
+func (c *Cluster) sleep() {
+	c.connMutex.Lock()
+	defer c.connMutex.Unlock()
+
+	if c.Sleeping() {
+		return
+	}
+
+	log.Info("Closing the connections to idle cluster ", c.ID())
+
+	c.sleeping.Set(true)
+	for _, node := range c.Nodes() {
+		node.setOrigNode(nil)
+		node.setStatus(nodeStatus.Off)
+	}
+	c.close()
+}
+
+// wake - reconnect to the cluster if its connections have been closed while it was idle
+func (c *Cluster) wake() {
+	if !c.Sleeping() {
+		return
+	}
+
+	c.connMutex.Lock()
+	defer c.connMutex.Unlock()
+
+	// another request may have already reconnected
+	if !c.Sleeping() {
+		return
+	}
+
+	log.Info("Reconnecting to cluster ", c.ID())
+
+	client, err := as.NewClientWithPolicyAndHost(c.policy, c.connectHosts()...)
+	if err != nil {
+		log.Errorf("Error reconnecting to cluster %s: %s", c.ID(), err.Error())
+		return
+	}
+
+	c.client.Set(client)
+	c.sleeping.Set(false)
+	c.updateLastestPing()
+
+	// update right away instead of waiting for the update interval
+	c.setUpdatedAt(time.Time{})
+	c.update(nil)
+}
+
+// disconnectIdleClusters - close the connections of the clusters which have not been viewed for a while
+func (o *ObserverT) disconnectIdleClusters() {
+	for _, c := range o.Clusters() {
+		if c.shouldSleep() {
+			c.sleep()
+		}
+	}
+}
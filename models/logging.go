@@ -0,0 +1,121 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// valid server log levels, from the least to the most verbose
+var _logLevels = []string{"critical", "warning", "info", "debug", "detail"}
+
+// LogSink - a logging sink of the server (file or console) with its context levels
+type LogSink struct {
+	ID       int               `json:"id"`
+	Path     string            `json:"path"`
+	Contexts map[string]string `json:"contexts"`
+}
+
+// ValidLogLevel - check if the level is a valid server log level
+func ValidLogLevel(level string) bool {
+	for _, l := range _logLevels {
+		if strings.EqualFold(l, level) {
+			return true
+		}
+	}
+	return false
+}
+
+// LogSinks - get the logging sinks of the node and the level of each context
+func (n *Node) LogSinks() ([]LogSink, error) {
+	res, err := n.RequestInfo(3, "logs")
+	if err != nil {
+		return nil, err
+	}
+
+	// format: <id>:<path>;<id>:<path>...
+	sinks := []LogSink{}
+	for _, s := range strings.Split(strings.TrimSpace(res["logs"]), ";") {
+		parts := strings.SplitN(s, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		id, err := strconv.Atoi(parts[0])
+		if err != nil {
+			continue
+		}
+
+		sinks = append(sinks, LogSink{ID: id, Path: parts[1], Contexts: map[string]string{}})
+	}
+
+	if len(sinks) == 0 {
+		return sinks, nil
+	}
+
+	cmds := make([]string, 0, len(sinks))
+	for _, s := range sinks {
+		cmds = append(cmds, "log/"+strconv.Itoa(s.ID))
+	}
+
+	res, err = n.RequestInfo(3, cmds...)
+	if err != nil {
+		return nil, err
+	}
+
+	// format: <context>:<level>;<context>:<level>...
+	for i := range sinks {
+		for _, c := range strings.Split(res["log/"+strconv.Itoa(sinks[i].ID)], ";") {
+			if parts := strings.SplitN(c, ":", 2); len(parts) == 2 {
+				sinks[i].Contexts[parts[0]] = strings.ToLower(parts[1])
+			}
+		}
+	}
+
+	sort.Slice(sinks, func(i, j int) bool { return sinks[i].ID < sinks[j].ID })
+	return sinks, nil
+}
+
+// SetLogLevel - set the level of a logging context of a sink; context `any` sets all the contexts
+func (n *Node) SetLogLevel(sinkID int, context, level string) error {
+	if !ValidLogLevel(level) {
+		return common.Errorf(common.KindInvalid, "Invalid log level `%s`. Valid levels are: %s", level, strings.Join(_logLevels, ", "))
+	}
+
+	sinks, err := n.LogSinks()
+	if err != nil {
+		return err
+	}
+
+	var sink *LogSink
+	for i := range sinks {
+		if sinks[i].ID == sinkID {
+			sink = &sinks[i]
+			break
+		}
+	}
+
+	if sink == nil {
+		return common.Errorf(common.KindNotFound, "Log sink %d not found", sinkID)
+	}
+
+	if _, exists := sink.Contexts[context]; !exists && context != "any" {
+		return common.Errorf(common.KindInvalid, "Invalid log context `%s`", context)
+	}
+
+	cmd := fmt.Sprintf("log-set:id=%d;%s=%s", sinkID, context, strings.ToLower(level))
+	res, err := n.RequestInfo(1, cmd)
+	if err != nil {
+		return err
+	}
+
+	if r := strings.ToLower(strings.TrimSpace(res[cmd])); r != "ok" {
+		return errors.New("Server rejected the log level change: " + res[cmd])
+	}
+
+	return nil
+}
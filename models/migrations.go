@@ -0,0 +1,73 @@
+package models
+
+import (
+	"time"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// gauges recorded on every update cycle to track rebalance progress
+var _recordedMigrationStats = []string{
+	"migrate_tx_partitions_remaining",
+	"migrate_rx_partitions_remaining",
+}
+
+// Migrations - get the latest migration progress of the namespace on the node
+func (ns *Namespace) Migrations() common.Stats {
+	res := ns.latestStats.GetMulti(
+		"migrate_tx_partitions_initial", "migrate_tx_partitions_remaining",
+		"migrate_rx_partitions_initial", "migrate_rx_partitions_remaining",
+	)
+
+	for _, stat := range _recordedMigrationStats {
+		if res[stat] == nil {
+			res[stat] = int64(0)
+		}
+	}
+
+	return res
+}
+
+// MigrationsSince - get the migration history of the namespace on the node since time
+func (ns *Namespace) MigrationsSince(tm time.Time) map[string][]*common.SinglePointValue {
+	// migrationHistory is not written to, so it doesn't need synchronization
+	res := make(map[string][]*common.SinglePointValue, len(ns.migrationHistory))
+	for name, bucket := range ns.migrationHistory {
+		res[name] = bucket.ValuesSince(tm)
+	}
+
+	return res
+}
+
+// Migrations - get the latest migration progress; node => namespace => stats
+func (c *Cluster) Migrations() map[string]map[string]common.Stats {
+	res := map[string]map[string]common.Stats{}
+	for _, node := range c.Nodes() {
+		nodeRes := map[string]common.Stats{}
+		for name, ns := range node.Namespaces() {
+			nodeRes[name] = ns.Migrations()
+		}
+		res[node.Address()] = nodeRes
+	}
+
+	return res
+}
+
+// MigrationsSince - get the migration history since time; node => namespace => stat => values
+func (c *Cluster) MigrationsSince(tm time.Time) map[string]map[string]map[string][]*common.SinglePointValue {
+	// if no tm specified, return for the last 30 mins
+	if tm.IsZero() {
+		tm = c.ServerTime().Add(-time.Minute * 30)
+	}
+
+	res := map[string]map[string]map[string][]*common.SinglePointValue{}
+	for _, node := range c.Nodes() {
+		nodeRes := map[string]map[string][]*common.SinglePointValue{}
+		for name, ns := range node.Namespaces() {
+			nodeRes[name] = ns.MigrationsSince(tm)
+		}
+		res[node.Address()] = nodeRes
+	}
+
+	return res
+}
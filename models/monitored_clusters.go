@@ -0,0 +1,207 @@
+package models
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	as "github.com/aerospike/aerospike-client-go/v5"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// clusters restored from the database on startup belong to this session until they are
+// deleted by a user or removed after being idle; logging out of them does not release them
+const _restoredSessionID = "restored"
+
+// PersistCluster - save a cluster registered from the UI so that it will be monitored again after a restart
+func (o *ObserverT) PersistCluster(cluster *Cluster, policy *as.ClientPolicy, tlsOpts ClusterTLSOptions) {
+	if !o.persistClusters || cluster.permanent.Get().(bool) {
+		return
+	}
+
+	seeds := cluster.connectHosts()
+	mc := &common.MonitoredCluster{
+		Id:                   cluster.ID(),
+		Seeds:                make([]string, 0, len(seeds)),
+		Username:             policy.User,
+		Password:             policy.Password,
+		EncryptOnly:          tlsOpts.EncryptOnly,
+		UseServicesAlternate: policy.UseServicesAlternate,
+		Created:              time.Now(),
+		AuthMode:             AuthModeName(policy.AuthMode),
+		TLSCACert:            tlsOpts.CACert,
+		TLSClientCert:        tlsOpts.ClientCert,
+		TLSClientKey:         tlsOpts.ClientKey,
+	}
+
+	opts := clientPolicyOptions(policy)
+	mc.ConnectionQueueSize = opts.ConnectionQueueSize
+	mc.Timeout = opts.Timeout
+	mc.IdleTimeout = opts.IdleTimeout
+	mc.LoginTimeout = opts.LoginTimeout
+	mc.AlternateAddresses = FormatAlternateAddresses(policy.IpMap)
+	mc.SRVRecord = cluster.SRVSeed()
+
+	if alias := cluster.Alias(); alias != nil {
+		mc.Alias = *alias
+	}
+
+	for _, host := range seeds {
+		mc.Seeds = append(mc.Seeds, net.JoinHostPort(host.Name, strconv.Itoa(host.Port)))
+		if host.TLSName != "" {
+			mc.TLSName = host.TLSName
+		}
+	}
+
+	// the cluster may have been persisted before it went idle or was logged out of
+	if persisted, err := common.MonitoredClusters(); err == nil {
+		for _, p := range persisted {
+			if p.Username == mc.Username && strings.Join(p.Seeds, ",") == strings.Join(mc.Seeds, ",") {
+				common.DeleteMonitoredCluster(p.Id)
+			}
+		}
+	}
+
+	if err := mc.Save(); err != nil {
+		log.Errorf("Error persisting cluster %s: %s", cluster.ID(), err.Error())
+	}
+}
+
+// UpdateClusterAlias - set the alias of the cluster and persist it
+func (o *ObserverT) UpdateClusterAlias(cluster *Cluster, alias string) {
+	cluster.SetAlias(alias)
+
+	if !o.persistClusters {
+		return
+	}
+
+	if a := cluster.Alias(); a != nil {
+		alias = *a
+	}
+
+	if err := common.UpdateMonitoredClusterAlias(cluster.ID(), alias); err != nil {
+		log.Errorf("Error persisting the alias of cluster %s: %s", cluster.ID(), err.Error())
+	}
+}
+
+// UpdateClusterClientPolicy - change the connection settings of the cluster and persist them
+func (o *ObserverT) UpdateClusterClientPolicy(cluster *Cluster, opts ClientPolicyOptions) error {
+	if err := cluster.SetClientPolicy(opts); err != nil {
+		return err
+	}
+
+	if !o.persistClusters || cluster.permanent.Get().(bool) {
+		return nil
+	}
+
+	opts = cluster.ClientPolicy()
+	if err := common.UpdateMonitoredClusterClientPolicy(cluster.ID(), opts.ConnectionQueueSize, opts.Timeout, opts.IdleTimeout, opts.LoginTimeout); err != nil {
+		log.Errorf("Error persisting the connection settings of cluster %s: %s", cluster.ID(), err.Error())
+	}
+
+	return nil
+}
+
+func (o *ObserverT) forgetCluster(cluster *Cluster) {
+	if !o.persistClusters {
+		return
+	}
+
+	if err := common.DeleteMonitoredCluster(cluster.ID()); err != nil {
+		log.Errorf("Error removing persisted cluster %s: %s", cluster.ID(), err.Error())
+	}
+}
+
+// restoreClusters - register the clusters persisted from the UI before the last restart
+func (o *ObserverT) restoreClusters() {
+	clusters, err := common.MonitoredClusters()
+	if err != nil {
+		log.Error("Error while trying to restore persisted clusters: ", err.Error())
+		return
+	}
+
+	for _, mc := range clusters {
+		hosts := make([]*as.Host, 0, len(mc.Seeds))
+		for _, seed := range mc.Seeds {
+			host, port, err := common.SplitHostPort(seed)
+			if err != nil {
+				log.Warnf("Invalid seed %s for persisted cluster %s: %s", seed, mc.Id, err.Error())
+				continue
+			}
+
+			h := as.NewHost(host, port)
+			h.TLSName = mc.TLSName
+			hosts = append(hosts, h)
+		}
+
+		if mc.SRVRecord != "" {
+			if srvHosts, err := o.ResolveSRV(mc.SRVRecord, mc.TLSName); err != nil {
+				log.Warnf("Error resolving SRV record %s for persisted cluster %s: %s", mc.SRVRecord, mc.Id, err.Error())
+			} else {
+				hosts = append(srvHosts, hosts...)
+			}
+		}
+
+		if len(hosts) == 0 {
+			continue
+		}
+
+		cp := as.NewClientPolicy()
+		cp.Timeout = time.Duration(o.config.AMC.Timeout) * time.Second
+		if cp.Timeout <= 0 {
+			cp.Timeout = 30 * time.Second
+		}
+		cp.UseServicesAlternate = mc.UseServicesAlternate
+		if cp.IpMap, err = ParseAlternateAddresses(mc.AlternateAddresses); err != nil {
+			log.Warnf("Invalid alternate addresses for persisted cluster %s: %s", mc.Id, err.Error())
+		}
+		ClientPolicyOptions{
+			ConnectionQueueSize: mc.ConnectionQueueSize,
+			Timeout:             mc.Timeout,
+			IdleTimeout:         mc.IdleTimeout,
+			LoginTimeout:        mc.LoginTimeout,
+		}.Apply(cp)
+		cp.User = mc.Username
+		cp.Password = mc.Password
+		if cp.AuthMode, err = ParseAuthMode(mc.AuthMode); err != nil {
+			log.Warnf("Invalid auth mode for persisted cluster %s: %s", mc.Id, err.Error())
+		}
+
+		if mc.TLSName != "" || mc.EncryptOnly {
+			tlsOpts := ClusterTLSOptions{EncryptOnly: mc.EncryptOnly, CACert: mc.TLSCACert, ClientCert: mc.TLSClientCert, ClientKey: mc.TLSClientKey}
+			if cp.TlsConfig, err = tlsOpts.Config(o.config); err != nil {
+				log.Warnf("Invalid TLS settings for persisted cluster %s: %s", mc.Id, err.Error())
+				continue
+			}
+		}
+
+		if o.FindClusterBySeed(_restoredSessionID, hosts[0], mc.Username, mc.Password) != nil {
+			continue
+		}
+
+		log.Info("Restoring persisted cluster ", mc.Seeds, " user: ", mc.Username)
+		cluster, err := o.Register(context.Background(), _restoredSessionID, cp, mc.Alias, hosts...)
+		if err != nil {
+			// keep the record; the cluster may be reachable after the next restart
+			log.Error("Error while trying to restore persisted cluster ", mc.Seeds, ": ", err.Error())
+			continue
+		}
+
+		if mc.SRVRecord != "" {
+			cluster.SetSRVSeed(mc.SRVRecord, mc.TLSName)
+		}
+
+		// clusters get a new id on every registration
+		if err := common.DeleteMonitoredCluster(mc.Id); err != nil {
+			continue
+		}
+		mc.Id = cluster.ID()
+		if err := mc.Save(); err != nil {
+			log.Errorf("Error persisting cluster %s: %s", cluster.ID(), err.Error())
+		}
+	}
+}
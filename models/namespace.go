@@ -7,7 +7,6 @@ import (
 	"strings"
 	"time"
 
-	// "github.com/sasha-s/go-deadlock"
 	// log "github.com/sirupsen/logrus"
 
 	ast "github.com/aerospike/aerospike-client-go/v5/types"
@@ -29,6 +28,18 @@ var _recordedNamespaceStats = []string{
 	"xdr_write_success", "xdr_write_reqs",
 
 	"udf_success", "udf_reqs",
+
+	"evicted-objects", "expired-objects",
+
+	"fail-generation", "dup-res-ask", "dup-res-respond-read", "dup-res-respond-no-read", "retransmits",
+
+	"defrag-reads", "defrag-writes",
+}
+
+// gauges recorded on every update cycle as they are, not as rates
+var _recordedGaugeStats = []string{
+	"used-bytes-memory", "used-bytes-disk",
+	"defrag-q", "write-q",
 }
 
 // Namespace type struct
@@ -43,23 +54,40 @@ type Namespace struct {
 	calcStats    common.SyncStats
 	latencystats common.SyncStats
 
-	statsHistory   map[string]*rrd.Bucket
-	latencyHistory *rrd.SimpleBucket
+	statsHistory     map[string]*rrd.Bucket
+	migrationHistory map[string]*rrd.Bucket
+	gaugeHistory     map[string]*rrd.Bucket
+	latencyHistory   *rrd.SimpleBucket
+
+	// the raw info of the last update, and how many times the sets have changed
+	rawInfo        map[string]string
+	setsGeneration uint64
 }
 
 // NewNamespace - create new namespace strunct
 func NewNamespace(node *Node, name string) *Namespace {
 	ns := &Namespace{
-		node:           node,
-		name:           name,
-		statsHistory:   map[string]*rrd.Bucket{},
-		latencyHistory: rrd.NewSimpleBucket(5, 3600),
+		node:             node,
+		name:             name,
+		statsHistory:     map[string]*rrd.Bucket{},
+		migrationHistory: map[string]*rrd.Bucket{},
+		gaugeHistory:     map[string]*rrd.Bucket{},
+		latencyHistory:   rrd.NewSimpleBucket(5, 3600),
+		rawInfo:          map[string]string{},
 	}
 
 	for _, stat := range _recordedNamespaceStats {
 		ns.statsHistory[stat] = rrd.NewBucket(ns.node.cluster.UpdateInterval(), 3600, true)
 	}
 
+	for _, stat := range _recordedMigrationStats {
+		ns.migrationHistory[stat] = rrd.NewBucket(ns.node.cluster.UpdateInterval(), 3600, false)
+	}
+
+	for _, stat := range _recordedGaugeStats {
+		ns.gaugeHistory[stat] = rrd.NewBucket(ns.node.cluster.UpdateInterval(), 3600, false)
+	}
+
 	return ns
 }
 
@@ -67,6 +95,12 @@ func (ns *Namespace) setUpdateInterval(val int) {
 	for _, b := range ns.statsHistory {
 		b.SetResolution(val)
 	}
+	for _, b := range ns.migrationHistory {
+		b.SetResolution(val)
+	}
+	for _, b := range ns.gaugeHistory {
+		b.SetResolution(val)
+	}
 }
 
 // ServerTime - return server time
@@ -80,13 +114,28 @@ func (ns *Namespace) ServerTime() time.Time {
 func (ns *Namespace) update(info common.Info) error {
 	defer ns.notifyAboutChanges()
 
-	ns.setInfo(info)
-	ns.setAliases()
+	// the stats are parsed again only if they or the config have changed
+	if rawInfoChanged(ns.rawInfo, info, "namespace/"+ns.name, "get-config:context=namespace;id="+ns.name) {
+		ns.setInfo(info)
+		ns.setAliases()
+	} else {
+		ns.latestInfo.SetInfo(info)
+	}
+
+	if rawInfoChanged(ns.rawInfo, info, "sets/"+ns.name) {
+		ns.setsGeneration++
+	}
+
 	ns.updateHistory()
 	return nil
 }
 
 func (ns *Namespace) updateIndexInfo(indexes map[string]common.Info) error {
+	if !ns.node.cluster.collectsStats(StatGroupSindex) {
+		ns.indexInfo.SetInfo(common.Info{})
+		return nil
+	}
+
 	cmdList := make([]string, 0, len(indexes))
 	for idxName, idxMap := range indexes {
 		cmdList = append(cmdList, fmt.Sprintf("sindex/%s/%s", idxMap["ns"], idxName))
@@ -98,6 +147,13 @@ func (ns *Namespace) updateIndexInfo(indexes map[string]common.Info) error {
 		return err
 	}
 
+	for _, res := range info {
+		if sindexStatFailed(res) {
+			ns.node.invalidateSindexList()
+			break
+		}
+	}
+
 	ns.indexInfo.SetInfo(common.Info(info))
 	return nil
 }
@@ -207,11 +263,19 @@ func (ns *Namespace) updateHistory() {
 		bucket := ns.statsHistory[stat]
 		bucket.Add(tm, ns.calcStats.TryFloat(stat, 0))
 	}
+
+	for _, stat := range _recordedMigrationStats {
+		ns.migrationHistory[stat].Add(tm, ns.latestStats.TryFloat(stat, 0))
+	}
+
+	for _, stat := range _recordedGaugeStats {
+		ns.gaugeHistory[stat].Add(tm, ns.calcStats.TryFloat(stat, 0))
+	}
 }
 
 // setAliases - set calcStats
 func (ns *Namespace) setAliases() {
-	stats := ns.latestStats
+	stats := ns.latestStats.Clone()
 	calcStats := common.Stats{}
 
 	calcStats["stat_read_success"] = stats.TryInt("client_read_success", 0)
@@ -303,6 +367,16 @@ func (ns *Namespace) setAliases() {
 	calcStats["current-time"] = stats.TryInt("current_time", 0)
 	calcStats["evicted-objects"] = stats.TryInt("evicted_objects", 0)
 	calcStats["expired-objects"] = stats.TryInt("expired_objects", 0)
+	calcStats["fail-generation"] = stats.TryInt("fail_generation", 0)
+	calcStats["dup-res-ask"] = stats.TryInt("dup_res_ask", 0)
+	calcStats["dup-res-respond-read"] = stats.TryInt("dup_res_respond_read", 0)
+	calcStats["dup-res-respond-no-read"] = stats.TryInt("dup_res_respond_no_read", 0)
+	calcStats["retransmits"] = retransmits(stats.Clone())
+
+	defrag := ns.devicesDefragStats(stats.Clone())
+	for _, stat := range []string{"defrag-q", "write-q", "defrag-reads", "defrag-writes"} {
+		calcStats[stat] = defrag.TryInt(stat, 0)
+	}
 	calcStats["hwm-breached"] = stats.TryString("hwm_breached", "false")
 	calcStats["master-objects"] = stats.TryInt("master_objects", 0)
 	calcStats["master-sub-objects"] = stats.TryInt("master_sub_objects", 0)
@@ -409,7 +483,9 @@ func (ns *Namespace) ConfigAttrs(names ...string) common.Stats {
 }
 
 // SetConfig - set config attribution
-func (ns *Namespace) SetConfig(config common.Info) ([]string, error) {
+func (ns *Namespace) SetConfig(config common.Info, user string) ([]string, error) {
+	oldConfig := ns.ConfigAttrs()
+
 	cmd := "set-config:context=namespace;id=" + ns.name
 	cmds := make([]string, 0, len(config))
 	cmdMap := make(map[string]string, len(config))
@@ -432,6 +508,8 @@ func (ns *Namespace) SetConfig(config common.Info) ([]string, error) {
 		}
 	}
 
+	ns.node.recordConfigChanges("namespace", ns.name, oldConfig, config, unsetParams, user)
+
 	if len(errMsg) == 0 {
 		return unsetParams, ns.node.update()
 	}
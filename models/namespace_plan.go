@@ -0,0 +1,222 @@
+package models
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// maximum number of namespaces supported by the server
+const _maxNamespaces = 32
+
+var namespaceNameRegexp = regexp.MustCompile(`^[A-Za-z0-9_-]{1,31}$`)
+var sizeRegexp = regexp.MustCompile(`^(\d+)([KMGTkmgt]?)$`)
+
+// NamespacePlanRequest - desired parameters of a new namespace
+type NamespacePlanRequest struct {
+	Name              string   `json:"name" form:"name"`
+	ReplicationFactor int      `json:"replication_factor" form:"replication_factor"`
+	StorageEngine     string   `json:"storage_engine" form:"storage_engine"`
+	Devices           []string `json:"devices" form:"devices"`
+	FileSize          string   `json:"file_size" form:"file_size"`
+	MemorySize        string   `json:"memory_size" form:"memory_size"`
+	DefaultTTL        string   `json:"default_ttl" form:"default_ttl"`
+	DataInMemory      bool     `json:"data_in_memory" form:"data_in_memory"`
+}
+
+// NamespacePlan - the generated config stanza and the steps to apply it on every node
+type NamespacePlan struct {
+	Config   string              `json:"config"`
+	Errors   []string            `json:"errors"`
+	Warnings []string            `json:"warnings"`
+	Steps    map[string][]string `json:"steps"`
+}
+
+// parseSize - parse a config size value (e.g. 4G) into bytes
+func parseSize(s string) (int64, error) {
+	m := sizeRegexp.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return 0, common.Errorf(common.KindInvalid, "Invalid size `%s`", s)
+	}
+
+	v, err := strconv.ParseInt(m[1], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	switch strings.ToUpper(m[2]) {
+	case "K":
+		v <<= 10
+	case "M":
+		v <<= 20
+	case "G":
+		v <<= 30
+	case "T":
+		v <<= 40
+	}
+
+	return v, nil
+}
+
+// usedStorageDevices - devices and files used by the existing namespaces of the node; path => namespace
+func (n *Node) usedStorageDevices() map[string]string {
+	res := map[string]string{}
+	for name, ns := range n.Namespaces() {
+		for k, v := range ns.ConfigAttrs() {
+			if strings.HasPrefix(k, "storage-engine.device") || strings.HasPrefix(k, "storage-engine.file") {
+				if path, ok := v.(string); ok && !strings.HasPrefix(k, "storage-engine.filesize") {
+					res[path] = name
+				}
+			}
+		}
+	}
+
+	return res
+}
+
+// PlanNamespace - validate the desired namespace against the resources of the nodes
+// and generate the config stanza. Namespaces cannot be added dynamically, so the plan
+// includes the rolling restart steps for every node.
+func (c *Cluster) PlanNamespace(req *NamespacePlanRequest) *NamespacePlan {
+	plan := &NamespacePlan{Errors: []string{}, Warnings: []string{}, Steps: map[string][]string{}}
+	nodes := c.Nodes()
+
+	if !namespaceNameRegexp.MatchString(req.Name) {
+		plan.Errors = append(plan.Errors, "Namespace name must be 1-31 characters of letters, digits, `_` or `-`")
+	}
+
+	for _, ns := range c.NamespaceList() {
+		if ns == req.Name {
+			plan.Errors = append(plan.Errors, fmt.Sprintf("Namespace %s already exists", req.Name))
+		}
+	}
+
+	if len(c.NamespaceList()) >= _maxNamespaces {
+		plan.Errors = append(plan.Errors, fmt.Sprintf("The server supports at most %d namespaces", _maxNamespaces))
+	}
+
+	if req.ReplicationFactor < 1 {
+		plan.Errors = append(plan.Errors, "Replication factor must be at least 1")
+	} else if req.ReplicationFactor > len(nodes) {
+		plan.Warnings = append(plan.Warnings, fmt.Sprintf("Replication factor %d is larger than the cluster size %d", req.ReplicationFactor, len(nodes)))
+	} else if req.ReplicationFactor == 1 {
+		plan.Warnings = append(plan.Warnings, "Replication factor 1 keeps no replica copies; data is lost if a node fails")
+	}
+
+	memorySize, err := parseSize(req.MemorySize)
+	if err != nil || memorySize == 0 {
+		plan.Errors = append(plan.Errors, "Invalid memory size")
+	}
+
+	if req.DefaultTTL != "" {
+		if _, err := strconv.ParseUint(req.DefaultTTL, 10, 32); err != nil {
+			plan.Errors = append(plan.Errors, "Default TTL must be a number of seconds")
+		}
+	}
+
+	switch req.StorageEngine {
+	case "memory":
+		if len(req.Devices) > 0 {
+			plan.Warnings = append(plan.Warnings, "Devices are ignored for the memory storage engine")
+		}
+	case "device":
+		if len(req.Devices) == 0 {
+			plan.Errors = append(plan.Errors, "At least one device or file is required for the device storage engine")
+		}
+		for _, d := range req.Devices {
+			if !strings.HasPrefix(d, "/") {
+				plan.Errors = append(plan.Errors, fmt.Sprintf("Device path %s must be absolute", d))
+			} else if !strings.HasPrefix(d, "/dev/") && req.FileSize == "" {
+				plan.Errors = append(plan.Errors, "File size is required when storing data in files")
+			}
+		}
+		if req.FileSize != "" {
+			if _, err := parseSize(req.FileSize); err != nil {
+				plan.Errors = append(plan.Errors, "Invalid file size")
+			}
+		}
+	default:
+		plan.Errors = append(plan.Errors, "Storage engine must be either memory or device")
+	}
+
+	for _, node := range nodes {
+		used := node.usedStorageDevices()
+		for _, d := range req.Devices {
+			if ns, exists := used[d]; exists {
+				plan.Errors = append(plan.Errors, fmt.Sprintf("%s is already used by namespace %s on node %s", d, ns, node.Address()))
+			}
+		}
+
+		// system_free_mem_kbytes is only reported by newer servers
+		if freeKB := node.StatsAttr("system_free_mem_kbytes"); freeKB != nil && memorySize > 0 {
+			if kb, ok := freeKB.(int64); ok && memorySize > kb*1024 {
+				plan.Warnings = append(plan.Warnings, fmt.Sprintf("Node %s has less free memory than %s", node.Address(), req.MemorySize))
+			}
+		}
+	}
+
+	if len(plan.Errors) > 0 {
+		return plan
+	}
+
+	plan.Config = req.stanza()
+
+	addrs := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		addrs = append(addrs, node.Address())
+	}
+	sort.Strings(addrs)
+
+	for i, addr := range addrs {
+		steps := []string{
+			fmt.Sprintf("Add the namespace stanza to aerospike.conf on %s", addr),
+			fmt.Sprintf("Restart the Aerospike service on %s", addr),
+			"Wait for the node to rejoin the cluster and for migrations to complete",
+		}
+		if i == len(addrs)-1 {
+			steps = append(steps, fmt.Sprintf("Verify namespace %s is available on all nodes", req.Name))
+		}
+		plan.Steps[addr] = steps
+	}
+
+	return plan
+}
+
+// stanza - render the namespace in aerospike.conf syntax
+func (req *NamespacePlanRequest) stanza() string {
+	buf := new(bytes.Buffer)
+	fmt.Fprintf(buf, "namespace %s {\n", req.Name)
+	fmt.Fprintf(buf, "\treplication-factor %d\n", req.ReplicationFactor)
+	fmt.Fprintf(buf, "\tmemory-size %s\n", req.MemorySize)
+	if req.DefaultTTL != "" {
+		fmt.Fprintf(buf, "\tdefault-ttl %s\n", req.DefaultTTL)
+	}
+
+	if req.StorageEngine == "memory" {
+		buf.WriteString("\tstorage-engine memory\n")
+	} else {
+		buf.WriteString("\tstorage-engine device {\n")
+		for _, d := range req.Devices {
+			if strings.HasPrefix(d, "/dev/") {
+				fmt.Fprintf(buf, "\t\tdevice %s\n", d)
+			} else {
+				fmt.Fprintf(buf, "\t\tfile %s\n", d)
+			}
+		}
+		if req.FileSize != "" {
+			fmt.Fprintf(buf, "\t\tfilesize %s\n", req.FileSize)
+		}
+		if req.DataInMemory {
+			buf.WriteString("\t\tdata-in-memory true\n")
+		}
+		buf.WriteString("\t}\n")
+	}
+
+	buf.WriteString("}\n")
+	return buf.String()
+}
@@ -5,9 +5,12 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"net"
 	"regexp"
+	"runtime/debug"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	as "github.com/aerospike/aerospike-client-go/v5"
@@ -70,6 +73,15 @@ type Node struct {
 
 	serverTimeDelta common.SyncValue //time.Duration
 
+	// the sindex list, requested only when it is stale or its generation has changed
+	sindexList common.SyncValue //*sindexList
+	sindexGen  int64            // accessed atomically
+
+	// the backoff of a node failing its info requests
+	backoff common.SyncValue //*nodeBackoff
+
+	infoCache *infoCache
+
 	_alertStates common.SyncStats
 }
 
@@ -105,6 +117,7 @@ func newNode(cluster *Cluster, origNode *as.Node) *Node {
 		latencyHistory:  lh,
 		_alertStates:    *common.NewSyncStats(common.Stats{}),
 		serverTimeDelta: common.NewSyncValue(time.Duration(0)),
+		infoCache:       newInfoCache(),
 	}
 
 	statsHistory := make(map[string]*rrd.Bucket, len(_recordedNodeStats))
@@ -131,32 +144,67 @@ func (n *Node) setUpdateInterval(val int) {
 	}
 }
 
+// the number of namespaces of a node updated at the same time
+const _namespaceUpdateConcurrency = 4
+
 func (n *Node) update() error {
 	defer n.notifyAboutChanges()
 	defer n.updateHistory() // always update the stats; when node is down, the stats will be zero
 
 	if !n.valid() {
 		n.setStatus(nodeStatus.Off)
+		n.updateFailed("Node is not active")
 		log.Warningf("Node %s is not active.", n.origHost)
 		return nil
 	}
+
+	// a failing node is not polled again before its backoff expires, so that it doesn't hold up the update
+	if n.backingOff() {
+		n.setStatus(nodeStatus.Off)
+		return nil
+	}
 	n.setStatus(nodeStatus.On)
 
 	tm := time.Now()
 
-	if err := n.updateNamespaceNames(); err != nil {
-		n.setStatus(nodeStatus.Off)
-		log.Warningf("Node %s is not active.", n.origHost)
-		return err
+	// the namespace list and the latencies are requested along with the rest of the info,
+	// so that an update takes a single round trip unless namespaces have been added
+	latencyKeys := n.infoLatencyKeys()
+	sindexGen := n.sindexGeneration()
+	cmds := append(append([]string{"namespaces"}, n.infoKeys()...), latencyKeys...)
+
+	// retry 3 times, unless the node is already failing
+	retries := 3
+	if n.failing() {
+		retries = 1
 	}
 
-	// retry 3 times
-	info, err := n.RequestInfo(3, n.infoKeys()...)
+	info, err := n.requestInfo(retries, 0, false, cmds...)
 	if err != nil {
 		n.setStatus(nodeStatus.Off)
+		n.updateFailed(err.Error())
+		log.Warningf("Node %s is not active.", n.origHost)
 		return err
 	}
 
+	if added := n.setNamespaceNames(info["namespaces"]); len(added) > 0 {
+		keys := []string{}
+		for _, ns := range added {
+			keys = append(keys, n.namespaceInfoKeys(ns)...)
+		}
+
+		if err := n.requestMoreInfo(info, keys); err != nil {
+			n.setStatus(nodeStatus.Off)
+			n.updateFailed(err.Error())
+			return err
+		}
+	}
+
+	if sindexes, exists := info["sindex"]; exists {
+		n.setSindexList(sindexes, sindexGen)
+	}
+	n.updateSindexGeneration(common.Info(info))
+
 	n.setInfo(common.Info(info))
 	n.setConfig(n.InfoAttrs("get-config:").ToInfo("get-config:"))
 
@@ -165,10 +213,14 @@ func (n *Node) update() error {
 	var latencyMap map[string]common.Stats
 	var nodeLatency map[string]common.Stats
 
-	infoLatency, err := n.RequestInfo(3, n.infoLatencyKeys()...)
-	if err != nil {
-		n.setStatus(nodeStatus.Off)
-		return err
+	// the build is not known before the first update
+	infoLatency := info
+	if keys := n.infoLatencyKeys(); strings.Join(keys, ";") != strings.Join(latencyKeys, ";") {
+		if infoLatency, err = n.requestInfo(retries, 0, false, keys...); err != nil {
+			n.setStatus(nodeStatus.Off)
+			n.updateFailed(err.Error())
+			return err
+		}
 	}
 
 	build := n.Build()
@@ -179,12 +231,33 @@ func (n *Node) update() error {
 	}
 	n.setNodeLatency(nodeLatency)
 
+	// the namespaces are updated concurrently, since the sindex stats need an info call per namespace
+	namespaces := n.Namespaces()
+	sem := make(chan struct{}, _namespaceUpdateConcurrency)
+	wg := sync.WaitGroup{}
+	wg.Add(len(namespaces))
+	for _, ns := range namespaces {
+		sem <- struct{}{}
+		go func(ns *Namespace) {
+			defer func() { <-sem; wg.Done() }()
+
+			// make sure panics do not bring AMC down
+			defer func() {
+				if err := recover(); err != nil {
+					log.Error(string(debug.Stack()))
+				}
+			}()
+
+			ns.update(n.InfoAttrs("namespace/"+ns.name, "sets/"+ns.name, "get-config:context=namespace;id="+ns.name))
+			ns.updateIndexInfo(n.Indexes(ns.name))
+			ns.updateLatencyInfo(latencyMap[ns.name])
+		}(ns)
+	}
+	wg.Wait()
+
 	nsAggStats := common.Stats{}
 	nsAggCalcStats := common.Stats{}
-	for _, ns := range n.Namespaces() {
-		ns.update(n.InfoAttrs("namespace/"+ns.name, "sets/"+ns.name, "get-config:context=namespace;id="+ns.name))
-		ns.updateIndexInfo(n.Indexes(ns.name))
-		ns.updateLatencyInfo(latencyMap[ns.name])
+	for _, ns := range namespaces {
 		ns.aggStats(nsAggStats, nsAggCalcStats)
 
 		// update node's server time
@@ -193,6 +266,7 @@ func (n *Node) update() error {
 
 	stats := common.Info(info).ToInfo("statistics").ToStats()
 	n.setStats(stats, nsAggStats, nsAggCalcStats)
+	n.updateSucceeded()
 
 	log.Debugf("Updating Node: %v, build: %s, objects: %v, took: %s", n.ID(), n.Build(), stats.TryInt("objects", 0), time.Since(tm))
 
@@ -221,76 +295,108 @@ func (n *Node) applyNsStatsToAggregate(stats, calcStats map[string]common.Stats)
 	}
 }
 
-func (n *Node) applyNsSetStatsToAggregate(stats map[string]map[string]common.Stats) map[string]map[string]common.Stats {
-	for nsName, ns := range n.Namespaces() {
-		setsInfo := ns.SetsInfo()
-
-		if stats[nsName] == nil {
-			stats[nsName] = setsInfo
-			continue
-		}
-
-		for setName, setInfo := range setsInfo {
-			if stats[nsName][setName] == nil {
-				stats[nsName][setName] = setInfo
-				continue
-			}
-
-			stats[nsName][setName].AggregateStats(setInfo)
-		}
-	}
+// RequestInfo get node info
+func (n *Node) RequestInfo(reties int, cmd ...string) (result map[string]string, err error) {
+	return n.RequestInfoWithTimeout(reties, 0, cmd...)
+}
 
-	return stats
+// RequestInfoWithTimeout get node info; if timeout is 0, the client policy timeout is used.
+// Results of read-only commands may be served from the node's info cache.
+func (n *Node) RequestInfoWithTimeout(reties int, timeout time.Duration, cmd ...string) (result map[string]string, err error) {
+	return n.requestInfo(reties, timeout, true, cmd...)
 }
 
-// RequestInfo get node info
-func (n *Node) RequestInfo(reties int, cmd ...string) (result map[string]string, err error) {
+// requestInfo - send the info commands to the node; the results of read-only commands are always cached,
+// but are only read from the cache if useCache is set. Polling does not use the cache to keep the stats
+// rates accurate.
+func (n *Node) requestInfo(reties int, timeout time.Duration, useCache bool, cmd ...string) (result map[string]string, err error) {
 	if len(cmd) == 0 {
 		return map[string]string{}, nil
 	}
 
 	origNode := n.origNode()
 	if origNode == nil {
-		return map[string]string{}, fmt.Errorf("Failed to request info. Node %q is not active", *n.origHost)
+		return map[string]string{}, common.Errorf(common.KindNodeOffline, "Failed to request info. Node %q is not active", *n.origHost)
 	}
 
-	for i := 0; i < reties; i++ {
+	ttl := n.cluster.observer.infoCacheTTL()
+	cached, missing := map[string]string{}, cmd
+	if useCache {
+		cached, missing = n.infoCache.lookup(ttl, cmd)
+		if len(missing) == 0 {
+			return cached, nil
+		}
+	}
+
+	if timeout <= 0 {
 		client := n.cluster.origClient()
-		timeout := client.Cluster().ClientPolicy().Timeout
+		if client == nil {
+			return map[string]string{}, common.Errorf(common.KindUnavailable, "Cluster %s has been decommissioned", n.cluster.ID())
+		}
+		timeout = client.Cluster().ClientPolicy().Timeout
+	}
 
+	for i := 0; i < reties; i++ {
 		infoPolicy := &as.InfoPolicy{Timeout: timeout}
-		result, err = origNode.RequestInfo(infoPolicy, cmd...)
+		result, err = origNode.RequestInfo(infoPolicy, missing...)
 		if err == nil {
-			return result, nil
+			break
 		}
 		// TODO: only retry for EOF or Timeout errors
 	}
 
-	return result, err
+	if err != nil {
+		return result, err
+	}
+
+	n.infoCache.store(ttl, result)
+
+	// commands which may change the state of the node invalidate the cache
+	for _, c := range missing {
+		if !isCacheableInfoCommand(c) {
+			n.infoCache.clear()
+			break
+		}
+	}
+
+	for k, v := range cached {
+		result[k] = v
+	}
+
+	return result, nil
 }
 
-// updateNamespaceNames update namsespaces names
-func (n *Node) updateNamespaceNames() error {
-	namespaceListMap, err := n.RequestInfo(3, "namespaces")
+// requestMoreInfo - request the commands and add their results to info
+func (n *Node) requestMoreInfo(info map[string]string, cmds []string) error {
+	res, err := n.requestInfo(3, 0, false, cmds...)
 	if err != nil {
 		return err
 	}
 
-	namespacesStr := namespaceListMap["namespaces"]
+	for k, v := range res {
+		info[k] = v
+	}
+	return nil
+}
+
+// setNamespaceNames - update the namespaces from the namespace list; returns the names of the added namespaces
+func (n *Node) setNamespaceNames(namespacesStr string) []string {
 	namespaces := strings.Split(namespacesStr, ";")
 
+	added := []string{}
 	namespaceMap := make(map[string]*Namespace, len(namespaces))
 	currentNamespaces := n.Namespaces()
 	for _, nsName := range namespaces {
 		if namespace := currentNamespaces[nsName]; namespace == nil {
 			namespaceMap[nsName] = NewNamespace(n, nsName)
+			added = append(added, nsName)
 		} else {
 			namespaceMap[nsName] = namespace
 		}
 	}
 	n.namespaces.Set(namespaceMap)
 
-	return nil
+	return added
 }
 
 // LatestLatency get latest latency
@@ -459,6 +565,9 @@ func (n *Node) updateHistory() {
 
 // XdrEnabled - is XDR enabled?
 func (n *Node) XdrEnabled() bool {
+	if n.Xdr5() {
+		return len(n.XdrDCs()) > 0
+	}
 	return n.StatsAttr("xdr_uptime") != nil
 }
 
@@ -474,6 +583,13 @@ func (n *Node) XdrStats() common.Stats {
 
 // XdrStatus - get XDR status
 func (n *Node) XdrStatus() XDRStatus {
+	if n.Xdr5() {
+		if len(n.XdrDCs()) == 0 {
+			return xdrStatus.Off
+		}
+		return xdrStatus.On
+	}
+
 	if n.latestConfig.TryString("enable-xdr", "") != "true" {
 		return xdrStatus.Off
 	}
@@ -482,6 +598,9 @@ func (n *Node) XdrStatus() XDRStatus {
 
 // SwitchXDR - switch XDR on for a node
 func (n *Node) SwitchXDR(on bool) error {
+	if n.Xdr5() {
+		return errors.New("XDR cannot be switched on/off in server 5.0+; add or remove datacenters instead")
+	}
 	return n.SetXDRConfig("enable-xdr", on)
 }
 
@@ -507,6 +626,10 @@ func (n *Node) infoLatencyKeys() []string {
 	build := n.Build()
 	res := []string{}
 
+	if !n.cluster.collectsStats(StatGroupLatency) {
+		return res
+	}
+
 	if strings.Compare(build, "5.1") > 0 {
 		res = append(res, "latencies:")
 	} else {
@@ -520,16 +643,23 @@ func (n *Node) infoKeys() []string {
 	res := []string{"node", "statistics", "features",
 		"cluster-generation", "partition-generation", "build_time",
 		"edition", "version", "build", "build_os", "bins", "jobs:",
-		"sindex", "udf-list" /*"latency:", "latencies:",*/, "get-config:", "cluster-name",
+		"udf-list" /*"latency:", "latencies:",*/, "get-config:", "cluster-name",
 		"service", "service-clear-std", "service-tls-std",
+		"service-clear-alt", "service-tls-alt",
+	}
+
+	// the sindex list is cached, since it rarely changes and can be large
+	if n.sindexListStale() {
+		res = append(res, "sindex")
 	}
 
 	build := n.Build()
 
-	if build != common.NOT_AVAILABLE {
+	if build != common.NOT_AVAILABLE && n.cluster.collectsStats(StatGroupXDR) {
 		if strings.Compare(build, "5.0") > 0 {
 			if n.Enterprise() {
-				res = append(res, "get-config:context=xdr")
+				res = append(res, "get-config:context=xdr", "feature-key")
+				res = append(res, n.xdrInfoKeys()...)
 			}
 		} else {
 			if n.Enterprise() {
@@ -540,14 +670,20 @@ func (n *Node) infoKeys() []string {
 
 	// add namespace stat requests
 	for ns := range n.Namespaces() {
-		res = append(res, "namespace/"+ns)
-		res = append(res, "sets/"+ns)
-		res = append(res, "get-config:context=namespace;id="+ns)
+		res = append(res, n.namespaceInfoKeys(ns)...)
 	}
 
 	return res
 }
 
+func (n *Node) namespaceInfoKeys(ns string) []string {
+	res := []string{"namespace/" + ns, "get-config:context=namespace;id=" + ns}
+	if n.cluster.collectsStats(StatGroupSets) {
+		res = append(res, "sets/"+ns)
+	}
+	return res
+}
+
 // NamespaceByName get namespace id by name
 func (n *Node) NamespaceByName(ns string) *Namespace {
 	res := n.Namespaces()
@@ -642,7 +778,9 @@ func (n *Node) ConfigAttr(name string) interface{} {
 }
 
 // SetServerConfig - set server config for node
-func (n *Node) SetServerConfig(context string, config map[string]string) ([]string, error) {
+func (n *Node) SetServerConfig(context string, config map[string]string, user string) ([]string, error) {
+	oldConfig := n.contextConfig(context)
+
 	cmd := "set-config:context=" + context
 	cmds := make([]string, 0, len(config))
 	cmdMap := make(map[string]string, len(config))
@@ -666,6 +804,8 @@ func (n *Node) SetServerConfig(context string, config map[string]string) ([]stri
 		}
 	}
 
+	n.recordConfigChanges(context, "", oldConfig, config, unsetParams, user)
+
 	if len(errMsg) == 0 {
 		return unsetParams, n.update()
 	}
@@ -673,6 +813,56 @@ func (n *Node) SetServerConfig(context string, config map[string]string) ([]stri
 	return unsetParams, errors.New(errMsg)
 }
 
+// ValidateServerConfig - check the parameters against the node's running config.
+// Parameters not present in the running config are not supported by the server version,
+// and new values should be of the same type as the current ones.
+// Returns a map of invalid parameters to the reason
+func (n *Node) ValidateServerConfig(context string, config map[string]string) map[string]string {
+	current := n.contextConfig(context)
+	invalid := map[string]string{}
+	for parameter, value := range config {
+		v := current.Get(parameter)
+		if v == nil {
+			invalid[parameter] = fmt.Sprintf("Parameter is not supported in context `%s` by server version %s", context, n.Build())
+			continue
+		}
+
+		switch cv := v.(type) {
+		case int64:
+			if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+				invalid[parameter] = "Value must be an integer"
+			}
+		case float64:
+			if _, err := strconv.ParseFloat(value, 64); err != nil {
+				invalid[parameter] = "Value must be a number"
+			}
+		case string:
+			if cv == "true" || cv == "false" {
+				if value != "true" && value != "false" {
+					invalid[parameter] = "Value must be either true or false"
+				}
+			}
+		}
+	}
+
+	return invalid
+}
+
+// VerifyServerConfig - check that the node's running config reflects the new values.
+// Node config should have been re-read before calling this method.
+// Returns the list of parameters whose value was not accepted by the server
+func (n *Node) VerifyServerConfig(context string, config map[string]string) []string {
+	current := n.contextConfig(context).ToStringValues()
+	unverified := []string{}
+	for parameter, value := range config {
+		if v, exists := current[parameter]; !exists || v != value {
+			unverified = append(unverified, parameter)
+		}
+	}
+
+	return unverified
+}
+
 func (n *Node) setInfo(stats common.Info) {
 	// n.oldInfo = n.latestInfo
 	n.latestInfo.SetInfo(stats)
@@ -815,6 +1005,10 @@ func (n *Node) Memory() common.Stats {
 
 // DataCenters - build DC list
 func (n *Node) DataCenters() map[string]common.Stats {
+	if n.Xdr5() {
+		return n.xdr5DataCenters()
+	}
+
 	if exists := n.latestInfo.Get("get-dc-config"); exists == nil {
 		return nil
 	}
@@ -823,7 +1017,10 @@ func (n *Node) DataCenters() map[string]common.Stats {
 	for k, stats := range dcs {
 		nodes := strings.Split(stats.TryString("Nodes", ""), ",")
 		for i := range nodes {
-			nodes[i] = strings.Replace(nodes[i], "+", ":", -1)
+			// host+port
+			if idx := strings.LastIndex(nodes[i], "+"); idx >= 0 {
+				nodes[i] = net.JoinHostPort(nodes[i][:idx], nodes[i][idx+1:])
+			}
 		}
 		stats["Nodes"] = common.DeleteEmpty(nodes)
 		stats["namespaces"] = common.DeleteEmpty(strings.Split(stats.TryString("namespaces", ""), ","))
@@ -863,7 +1060,7 @@ func (n *Node) Jobs() map[string]common.Stats {
 
 // Indexes - get sindex stat
 func (n *Node) Indexes(namespace string) map[string]common.Info {
-	indexes := n.latestInfo.ToInfoMap("sindex", "indexname", ":")
+	indexes := n.sindexes()
 	if namespace == "" {
 		return indexes
 	}
@@ -880,7 +1077,7 @@ func (n *Node) Indexes(namespace string) map[string]common.Info {
 
 // NamespaceIndexes - get sindex by namespace
 func (n *Node) NamespaceIndexes() map[string][]string {
-	indexes := n.latestInfo.ToInfoMap("sindex", "indexname", ":")
+	indexes := n.sindexes()
 	result := map[string][]string{}
 	for idxName, idxInfo := range indexes {
 		if idxInfo != nil && idxInfo["ns"] != "" {
@@ -902,16 +1099,16 @@ func (n *Node) UDFs() map[string]common.Stats {
 
 // Address - get node ip address
 func (n *Node) Address() string {
-	if s := n.InfoAttrFirstValidValueAmong("service-tls-std", "service-clear-std", "service"); s != common.NOT_AVAILABLE {
+	if s := n.serviceAddress(); s != common.NOT_AVAILABLE {
 		return s
 	}
 	h := *n.origHost
-	return h.Name + ":" + strconv.Itoa(h.Port)
+	return net.JoinHostPort(h.Name, strconv.Itoa(h.Port))
 }
 
 // Host - get node hostname
 func (n *Node) Host() string {
-	if s := n.InfoAttrFirstValidValueAmong("service-tls-std", "service-clear-std", "service"); s != common.NOT_AVAILABLE {
+	if s := n.serviceAddress(); s != common.NOT_AVAILABLE {
 		host, _, err := common.SplitHostPort(s)
 		if err == nil && len(host) > 0 {
 			return host
@@ -924,7 +1121,7 @@ func (n *Node) Host() string {
 
 // Port - get node port
 func (n *Node) Port() uint16 {
-	if s := n.InfoAttrFirstValidValueAmong("service-tls-std", "service-clear-std", "service"); s != common.NOT_AVAILABLE {
+	if s := n.serviceAddress(); s != common.NOT_AVAILABLE {
 		_, port, err := common.SplitHostPort(s)
 		if err == nil {
 			return uint16(port)
@@ -1328,6 +1525,7 @@ func (n *Node) updateNotifications() error {
 	n.CheckFileDescriptors(latestState)
 	n.CheckDiskSpace(latestState)
 	n.CheckMemory(latestState)
+	n.CheckFeatureKey(latestState)
 
 	return nil
 }
@@ -0,0 +1,81 @@
+package models
+
+import (
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// a node failing info requests is polled again after this many update intervals, doubled on every failure
+const _maxNodeBackoff = 32
+
+// nodeBackoff is the state of a node which fails its info requests; it is replaced, never modified
+type nodeBackoff struct {
+	failures int
+	retryAt  time.Time
+	reason   string
+}
+
+func (n *Node) backoffState() *nodeBackoff {
+	state, _ := n.backoff.Get().(*nodeBackoff)
+	return state
+}
+
+// StatusReason - get why the last update of the node failed; empty if it succeeded
+func (n *Node) StatusReason() string {
+	if state := n.backoffState(); state != nil {
+		return state.reason
+	}
+	return ""
+}
+
+// RetryAt - get when the node is polled again after failing; zero if it is polled every update
+func (n *Node) RetryAt() time.Time {
+	if state := n.backoffState(); state != nil {
+		return state.retryAt
+	}
+	return time.Time{}
+}
+
+// failing - check whether the last update of the node failed
+func (n *Node) failing() bool {
+	return n.backoffState() != nil
+}
+
+// backingOff - check whether the node is skipped in this update because it has been failing
+func (n *Node) backingOff() bool {
+	state := n.backoffState()
+	return state != nil && time.Now().Before(state.retryAt)
+}
+
+// updateFailed - record a failed update and back off polling the node exponentially
+func (n *Node) updateFailed(reason string) {
+	failures := 1
+	if state := n.backoffState(); state != nil {
+		failures = state.failures + 1
+	}
+
+	// doubled up to the cap only, since the failures of a node which stays down keep adding up
+	intervals := 1
+	for i := 1; i < failures && intervals < _maxNodeBackoff; i++ {
+		intervals *= 2
+	}
+	if intervals > _maxNodeBackoff {
+		intervals = _maxNodeBackoff
+	}
+
+	retryAt := time.Now().Add(time.Duration(intervals*n.cluster.UpdateInterval()) * time.Second)
+	n.backoff.Set(&nodeBackoff{failures: failures, retryAt: retryAt, reason: reason})
+
+	if failures > 1 {
+		log.Warnf("Node %s failed %d updates in a row: %s; retrying at %s", n.origHost, failures, reason, retryAt.Format(time.RFC3339))
+	}
+}
+
+// updateSucceeded - reset the backoff after a successful update
+func (n *Node) updateSucceeded() {
+	if state := n.backoffState(); state != nil {
+		log.Infof("Node %s is responding again after %d failed updates", n.origHost, state.failures)
+		n.backoff.Set((*nodeBackoff)(nil))
+	}
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"math"
+	"sort"
+)
+
+// the sizes of the units of the object size histograms; the linear histogram of the servers before 5.2
+// counts 128 byte blocks
+var _histogramUnitBytes = map[string]int64{
+	"":        1,
+	"bytes":   1,
+	"rblocks": 128,
+}
+
+// ObjectSizeDistribution - the object sizes of a namespace aggregated across the nodes, in bytes; the percentiles
+// are the upper bounds of the buckets of the nearest-rank percentiles
+type ObjectSizeDistribution struct {
+	Namespace      string            `json:"namespace"`
+	Objects        int64             `json:"objects"`
+	P50Bytes       int64             `json:"p50_bytes"`
+	P95Bytes       int64             `json:"p95_bytes"`
+	P99Bytes       int64             `json:"p99_bytes"`
+	MaxBytes       int64             `json:"max_bytes"`
+	WriteBlockSize int64             `json:"write_block_size,omitempty"` // of the device storage engine
+	Buckets        []HistogramBucket `json:"buckets"`
+	Error          string            `json:"error,omitempty"`
+}
+
+// ObjectSizeDistribution - the object size histogram of the namespace on all nodes and its percentiles; the linear
+// histogram is used if the servers do not have the object-size one
+func (c *Cluster) ObjectSizeDistribution(namespace string) (*ObjectSizeDistribution, error) {
+	buckets, perNode, err := c.Histogram(namespace, "object-size")
+	if err != nil {
+		if buckets, perNode, err = c.Histogram(namespace, "object-size-linear"); err != nil {
+			return nil, err
+		}
+	}
+
+	unit := int64(1)
+	for _, h := range perNode {
+		if u, exists := _histogramUnitBytes[h.Units]; exists {
+			unit = u
+		}
+		break
+	}
+
+	res := &ObjectSizeDistribution{Namespace: namespace, Buckets: make([]HistogramBucket, len(buckets))}
+	for i, b := range buckets {
+		res.Buckets[i] = HistogramBucket{From: b.From * unit, To: b.To * unit, Count: b.Count}
+		res.Objects += b.Count
+	}
+
+	percentile := func(p float64) int64 {
+		rank := int64(math.Ceil(p * float64(res.Objects)))
+		seen := int64(0)
+		for _, b := range res.Buckets {
+			if seen += b.Count; seen >= rank && b.Count > 0 {
+				return b.To
+			}
+		}
+		return 0
+	}
+	if res.Objects > 0 {
+		res.P50Bytes, res.P95Bytes, res.P99Bytes = percentile(.5), percentile(.95), percentile(.99)
+		res.MaxBytes = res.Buckets[len(res.Buckets)-1].To
+	}
+
+	for _, node := range c.Nodes() {
+		if ns := node.NamespaceByName(namespace); ns != nil {
+			if wbs := ns.latestStats.TryInt("storage-engine.write-block-size", 0); wbs > res.WriteBlockSize {
+				res.WriteBlockSize = wbs
+			}
+		}
+	}
+	return res, nil
+}
+
+// ObjectSizeDistributions - the object size distributions of all the namespaces, sorted by their name;
+// the namespaces whose histograms could not be read have their error
+func (c *Cluster) ObjectSizeDistributions() []*ObjectSizeDistribution {
+	namespaces := c.NamespaceList()
+	sort.Strings(namespaces)
+
+	res := make([]*ObjectSizeDistribution, 0, len(namespaces))
+	for _, namespace := range namespaces {
+		d, err := c.ObjectSizeDistribution(namespace)
+		if err != nil {
+			d = &ObjectSizeDistribution{Namespace: namespace, Buckets: []HistogramBucket{}, Error: err.Error()}
+		}
+		res = append(res, d)
+	}
+	return res
+}
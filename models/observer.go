@@ -1,21 +1,20 @@
 package models
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
 	"net"
 	"runtime/debug"
+	"sort"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	as "github.com/aerospike/aerospike-client-go/v5"
 	asl "github.com/aerospike/aerospike-client-go/v5/logger"
 	log "github.com/sirupsen/logrus"
 
-	// "github.com/sasha-s/go-deadlock"
-
 	"github.com/aerospike-community/amc/common"
 )
 
@@ -32,33 +31,79 @@ type ObserverT struct {
 	sessions common.SyncStats // map[string][]*Cluster
 	config   *common.Config
 
+	// the last request of every session; see sessions.go
+	sessionActivity common.SyncStats // map[string]sessionActivity
+
 	debug common.SyncValue //DebugStatus
 
 	clusters common.SyncValue //[]*Cluster
-	mutex    sync.RWMutex
+	mutex    common.RWMutex
 
 	notifyCloseChan chan struct{}
 
 	xdrSeeds chan string
+
+	// bounds the number of concurrent info calls to the nodes while polling
+	workers *workerPool
+
+	// clusters registered from the UI are persisted if the secret key could be loaded
+	persistClusters bool
 }
 
 // New - add monitoring server to the cluster
 func New(config *common.Config) *ObserverT {
-	var err error
 	o := &ObserverT{
-		sessions: *common.NewSyncStats(common.Stats{}),
-		clusters: common.NewSyncValue([]*Cluster{}),
-		config:   config,
-		debug:    common.NewSyncValue(DebugStatus{}),
-		xdrSeeds: make(chan string, 128),
+		sessions:        *common.NewSyncStats(common.Stats{}),
+		sessionActivity: *common.NewSyncStats(common.Stats{}),
+		clusters:        common.NewSyncValue([]*Cluster{}),
+		config:          config,
+		debug:           common.NewSyncValue(DebugStatus{}),
+		xdrSeeds:        make(chan string, 128),
+		workers:         newWorkerPool(config.AMC.PollConcurrency),
 	}
 	go o.observe(config)
 
+	o.registerConfigClusters()
+
+	if err := common.LoadSecretKey(config.SecretKeyFile()); err != nil {
+		log.Error("Clusters added from the UI will not be persisted: ", err.Error())
+	} else {
+		o.persistClusters = true
+		o.restoreClusters()
+	}
+
+	return o
+}
+
+// registerConfigClusters - register the clusters in the config file
+// which are not monitored yet, and update the settings of the existing ones
+func (o *ObserverT) registerConfigClusters() {
 	// Add Monitoring servers to the cluster
 	// These clusters do not belong to any sessions, but will
-	for _, server := range config.AMC.Clusters {
+	for _, server := range o.config.ClusterConfigs() {
 		cp := as.NewClientPolicy()
 		cp.UseServicesAlternate = server.UseServicesAlternate
+		if len(server.AlternateAddresses) > 0 {
+			cp.IpMap = server.AlternateAddresses
+		}
+
+		policyOpts := ClientPolicyOptions{
+			ConnectionQueueSize: server.ConnectionQueueSize,
+			Timeout:             server.Timeout,
+			IdleTimeout:         server.IdleTimeout,
+			LoginTimeout:        server.LoginTimeout,
+		}
+		if err := policyOpts.Validate(); err != nil {
+			log.Errorf("Invalid connection settings for host %s:%d: %s", server.Host, server.Port, err.Error())
+			continue
+		}
+		policyOpts.Apply(cp)
+
+		statProfile, err := ParseStatProfile(server.StatProfile)
+		if err != nil {
+			log.Errorf("Invalid stat profile for host %s:%d: %s", server.Host, server.Port, err.Error())
+			continue
+		}
 
 		host := as.NewHost(server.Host, int(server.Port))
 		if common.AMCIsEnterprise() {
@@ -70,8 +115,8 @@ func New(config *common.Config) *ObserverT {
 				host.TLSName = tlsName
 
 				tc := &tls.Config{
-					Certificates:             config.ClientPool(),
-					RootCAs:                  config.ServerPool(),
+					Certificates:             o.config.ClientPool(),
+					RootCAs:                  o.config.ServerPool(),
 					PreferServerCipherSuites: true,
 				}
 				tc.BuildNameToCertificate()
@@ -79,21 +124,87 @@ func New(config *common.Config) *ObserverT {
 			}
 		}
 
-		cluster := o.FindClusterBySeed("automatic", host, server.User, server.Password)
+		hosts := []*as.Host{host}
+		if server.SRVRecord != "" {
+			var err error
+			if hosts, err = o.ResolveSRV(server.SRVRecord, host.TLSName); err != nil {
+				log.Errorf("Error resolving SRV record %s: %s", server.SRVRecord, err.Error())
+				continue
+			}
+		} else if server.KubernetesService != "" {
+			var err error
+			if hosts, err = o.ResolveKubernetesService(server.KubernetesService, int(server.Port), host.TLSName); err != nil {
+				log.Errorf("Error reading the endpoints of Kubernetes service %s: %s", server.KubernetesService, err.Error())
+				continue
+			}
+		}
+
+		cluster := o.FindClusterBySeed("automatic", hosts[0], server.User, server.Password)
 		if cluster == nil {
-			log.Warn("Adding host ", server.Host, ":", server.Port, " user: ", server.User)
-			cluster, err = o.Register("automatic", cp, server.Alias, host)
+			log.Warn("Adding host ", hosts[0], " user: ", server.User)
+			var err error
+			cluster, err = o.Register(context.Background(), "automatic", cp, server.Alias, hosts...)
 			if err != nil {
 				log.Error("Error while trying to add database from config file for monitoring: ", err.Error())
 				continue
 			}
+		} else {
+			// the cluster may have been added by a user before it was added to the config file
+			cluster.SetAlias(server.Alias)
+			o.AppendCluster(context.Background(), "automatic", cluster)
+
+			if opts := clientPolicyOptions(cp); cluster.ClientPolicy() != opts {
+				if err := cluster.SetClientPolicy(opts); err != nil {
+					log.Errorf("Error changing the connection settings of cluster %s: %s", cluster.ID(), err.Error())
+				}
+			}
+		}
+		if server.SRVRecord != "" {
+			cluster.SetSRVSeed(server.SRVRecord, host.TLSName)
+		} else if server.KubernetesService != "" {
+			cluster.SetKubernetesSeed(server.KubernetesService, int(server.Port), host.TLSName)
 		}
+
+		cluster.SetStatProfile(statProfile)
+		cluster.SetReportSchedule(server.ReportSchedule)
+
 		// mark it so it won't be removed automatically
 		cluster.setPermanent(true)
 		cluster.showInUI.Set(server.ShowInUI)
 	}
+}
 
-	return o
+// ReloadConfig - reload the config file and apply it to the observer without dropping the sessions
+// or the history of the clusters. Clusters removed from the config file are no longer kept permanently;
+// they stop being monitored unless a user session is monitoring them.
+func (o *ObserverT) ReloadConfig() error {
+	oldClusters := o.sessionClusters("automatic")
+
+	if err := o.config.Reload(); err != nil {
+		return err
+	}
+
+	o.registerConfigClusters()
+
+	configured := map[*Cluster]bool{}
+	for _, server := range o.config.ClusterConfigs() {
+		host := as.NewHost(server.Host, int(server.Port))
+		host.TLSName = strings.TrimSpace(server.TLSName)
+		if cluster := o.FindClusterBySeed("automatic", host, server.User, server.Password); cluster != nil {
+			configured[cluster] = true
+		}
+	}
+
+	for _, cluster := range oldClusters {
+		if !configured[cluster] {
+			log.Info("Cluster ", cluster.ID(), " has been removed from the config file")
+			cluster.setPermanent(false)
+			cluster.showInUI.Set(false)
+			o.RemoveCluster(context.Background(), "automatic", cluster)
+		}
+	}
+
+	return nil
 }
 
 func (o *ObserverT) stop() {
@@ -101,15 +212,20 @@ func (o *ObserverT) stop() {
 	close(o.notifyCloseChan)
 }
 
+// updateClusters - start updating the clusters which are due; slow clusters do not hold up the others,
+// and a cluster is not updated again while its previous update is running
 func (o *ObserverT) updateClusters() {
-	clusters := o.Clusters()
+	for _, c := range o.Clusters() {
+		go c.update(nil)
+	}
+}
 
-	wg := new(sync.WaitGroup)
-	wg.Add(len(clusters))
-	for _, c := range clusters {
-		go c.update(wg)
+// sendSummaryReports - send the summary reports of the clusters which are due
+func (o *ObserverT) sendSummaryReports() {
+	now := time.Now()
+	for _, c := range o.Clusters() {
+		c.sendSummaryReport(now)
 	}
-	wg.Wait()
 }
 
 func (o *ObserverT) removeIdleClusters() {
@@ -117,7 +233,9 @@ func (o *ObserverT) removeIdleClusters() {
 
 	for _, c := range clusters {
 		if c.shouldAutoRemove() {
+			log.Info("Removing idle cluster " + c.ID())
 			c.close()
+			// persisted clusters are kept until a user deletes them
 			o.removeClusterFromAllSessions(c)
 		}
 	}
@@ -132,6 +250,11 @@ func (o *ObserverT) Clusters() []*Cluster {
 	return clusters
 }
 
+// ConfigClusters - the clusters in the config file
+func (o *ObserverT) ConfigClusters() []*Cluster {
+	return o.sessionClusters("automatic")
+}
+
 func (o *ObserverT) clustersRef() []*Cluster {
 	return o.clusters.Get().([]*Cluster)
 }
@@ -148,6 +271,7 @@ func (o *ObserverT) observe(config *common.Config) {
 	// update as soon as initiated once
 	o.updateClusters()
 
+	var lastBudgetCheck, lastDNSRefresh time.Time
 	for {
 		select {
 
@@ -157,7 +281,19 @@ func (o *ObserverT) observe(config *common.Config) {
 			}
 
 			o.removeIdleClusters()
+			o.disconnectIdleClusters()
 			o.updateClusters()
+			o.sendSummaryReports()
+
+			if time.Since(lastBudgetCheck) >= _historyBudgetInterval {
+				o.enforceHistoryBudget()
+				lastBudgetCheck = time.Now()
+			}
+
+			if interval := o.dnsRefreshInterval(); interval > 0 && time.Since(lastDNSRefresh) >= interval {
+				go o.refreshDiscoverySeeds()
+				lastDNSRefresh = time.Now()
+			}
 
 		case <-o.notifyCloseChan:
 			clusters := o.Clusters()
@@ -185,7 +321,7 @@ func (o *ObserverT) sessionClusters(sessionID string) []*Cluster {
 }
 
 // AppendCluster add cluster for monitoring
-func (o *ObserverT) AppendCluster(sessionID string, cluster *Cluster) {
+func (o *ObserverT) AppendCluster(ctx context.Context, sessionID string, cluster *Cluster) {
 	o.mutex.Lock()
 	defer o.mutex.Unlock()
 
@@ -199,8 +335,9 @@ func (o *ObserverT) AppendCluster(sessionID string, cluster *Cluster) {
 		}
 	}
 
+	logger := common.Logger(ctx).WithField("cluster_id", cluster.ID())
 	if !cExists {
-		log.Info("Appending cluster " + cluster.ID() + " to the models...")
+		logger.Info("Appending cluster " + cluster.ID() + " to the models...")
 		clusters = append(clusters, cluster)
 		o.clusters.Set(clusters)
 	}
@@ -214,7 +351,7 @@ func (o *ObserverT) AppendCluster(sessionID string, cluster *Cluster) {
 		}
 	}
 
-	log.Info("Appending cluster " + cluster.ID() + " to session " + sessionID)
+	logger.WithField("session_id", sessionID).Info("Appending cluster " + cluster.ID() + " to session " + sessionID)
 
 	sessionClusters = append(sessionClusters, cluster)
 	o.sessions.Set(sessionID, sessionClusters)
@@ -246,11 +383,31 @@ func (o *ObserverT) removeClusterFromAllSessions(cluster *Cluster) {
 	}
 	o.clusters.Set(newClusters)
 
-	log.Info("Automatically removed idle cluster " + cluster.ID() + " from session all sessions")
+	log.Info("Removed cluster " + cluster.ID() + " from all sessions")
+}
+
+// DeleteCluster - stop monitoring the cluster for all sessions, close its connections, drop its history
+// and remove it from the persisted clusters. Clusters in the config file are monitored again after
+// the next reload or restart unless they are removed from it.
+func (o *ObserverT) DeleteCluster(ctx context.Context, cluster *Cluster) {
+	common.Logger(ctx).WithField("cluster_id", cluster.ID()).Info("Deleting cluster " + cluster.ID())
+	o.dropCluster(cluster)
+	o.forgetCluster(cluster)
 }
 
-// RemoveCluster - remove cluster from observer
-func (o *ObserverT) RemoveCluster(sessionID string, cluster *Cluster) int {
+// dropCluster - stop monitoring the cluster for all sessions, close its connections and drop its history;
+// it stays persisted
+func (o *ObserverT) dropCluster(cluster *Cluster) {
+	cluster.setPermanent(false)
+	o.removeClusterFromAllSessions(cluster)
+
+	cluster.close()
+	cluster.nodes.Set(map[as.Host]*Node{})
+}
+
+// RemoveCluster - remove cluster from the session; it is no longer monitored if no other session
+// holds it, but stays persisted until it is deleted
+func (o *ObserverT) RemoveCluster(ctx context.Context, sessionID string, cluster *Cluster) int {
 	o.mutex.Lock()
 	defer o.mutex.Unlock()
 
@@ -291,23 +448,38 @@ func (o *ObserverT) RemoveCluster(sessionID string, cluster *Cluster) int {
 		}
 	}
 
-	log.Info("Removing cluster " + cluster.ID() + " from session " + sessionID)
+	common.Logger(ctx).WithFields(log.Fields{"cluster_id": cluster.ID(), "session_id": sessionID}).Info("Removing cluster " + cluster.ID() + " from session " + sessionID)
 	remainingClusters := o.sessionClusters(sessionID)
 	if len(remainingClusters) == 0 {
 		// remove session
 		o.sessions.Del(sessionID)
+		o.sessionActivity.Del(sessionID)
 	}
 	return len(remainingClusters)
 }
 
 // Register - register cluster to observer
-func (o *ObserverT) Register(sessionID string, policy *as.ClientPolicy, alias string, hosts ...*as.Host) (*Cluster, error) {
+func (o *ObserverT) Register(ctx context.Context, sessionID string, policy *as.ClientPolicy, alias string, hosts ...*as.Host) (*Cluster, error) {
+	if err := o.makeRoomForCluster(ctx, sessionID); err != nil {
+		return nil, err
+	}
+
 	client, err := as.NewClientWithPolicyAndHost(policy, hosts...)
 	if err != nil {
 		return nil, err
 	}
 
 	cluster := newCluster(o, client, alias, policy.User, policy.Password, hosts)
+	cp := *policy
+	cluster.policy = &cp
+
+	// host names are re-resolved periodically to pick up nodes whose addresses change
+	for _, host := range hosts {
+		if net.ParseIP(host.Name) == nil {
+			cluster.discoverySeed.Set(&discoverySeed{kind: seedKindDNS, name: host.Name, port: host.Port, tlsName: host.TLSName})
+			break
+		}
+	}
 	if cluster.IsSet() {
 		cluster.update(nil)
 
@@ -317,7 +489,7 @@ func (o *ObserverT) Register(sessionID string, policy *as.ClientPolicy, alias st
 		}
 	}
 
-	o.AppendCluster(sessionID, cluster)
+	o.AppendCluster(ctx, sessionID, cluster)
 
 	return cluster, nil
 }
@@ -355,9 +527,11 @@ func (o *ObserverT) AutoClusters() []*Cluster {
 }
 
 // FindClusterByID - get cluster by id
+// Clusters whose connections were closed while idle are reconnected
 func (o *ObserverT) FindClusterByID(id string) *Cluster {
 	for _, cluster := range o.clustersRef() {
 		if cluster.ID() == id {
+			cluster.wake()
 			return cluster
 		}
 	}
@@ -373,7 +547,7 @@ func (o *ObserverT) NodeHasBeenDiscovered(sessionID string, alias string) *Clust
 		}
 		for _, node := range client.Cluster().GetNodes() {
 			for _, host := range node.GetAliases() {
-				if strings.ToLower(host.Name+":"+strconv.Itoa(host.Port)) == strings.ToLower(alias) {
+				if strings.ToLower(net.JoinHostPort(host.Name, strconv.Itoa(host.Port))) == strings.ToLower(alias) {
 					return cluster
 				}
 			}
@@ -387,7 +561,7 @@ func (o *ObserverT) NodeHasBeenDiscovered(sessionID string, alias string) *Clust
 func (o *ObserverT) FindClusterBySeed(sid string, host *as.Host, user, password string) *Cluster {
 	hostAddrs := strings.Split(host.Name, ",")
 	for _, hostAddr := range hostAddrs {
-		aliases := findAliases(hostAddr, host.TLSName, host.Port)
+		aliases := o.findAliases(hostAddr, host.TLSName, host.Port)
 
 		// try to find the cluster in current session by seed
 		clusters, sessionExists := o.MonitoringClusters(sid)
@@ -432,9 +606,12 @@ func (o *ObserverT) findClusterBySeedOnly(seed as.Host) *Cluster {
 	return nil
 }
 
-// DatacenterInfo -
+// DatacenterInfo - the multicluster view of the old UI, keyed by the cluster ids and by the seed addresses
+// of the remote datacenters, which collide when the datacenters share addresses.
 // Add auto clusters to the mix
 // DO NOT add auto-clusters which are already included in the cluster.
+//
+// Deprecated: use DatacenterTopology, which is keyed by the cluster identities.
 func (o *ObserverT) DatacenterInfo(sessionID string) common.Stats {
 	res := map[string]common.Stats{}
 	sClusters := o.sessionClusters(sessionID)
@@ -497,11 +674,11 @@ L:
 		if nodesIfc, exists := cluster["nodes"]; exists {
 			for _, nodeStatsIfc := range nodesIfc.(common.Stats) {
 				nodeStats := nodeStatsIfc.(common.Stats)
-				nodeAddr1 := fmt.Sprintf("%s:%v", nodeStats.TryString("ip", ""), nodeStats.Get("port"))
+				nodeAddr1 := net.JoinHostPort(nodeStats.TryString("ip", ""), fmt.Sprint(nodeStats.Get("port")))
 				if nodeAddr1 != addr {
 					delete(res, nodeAddr1)
 				}
-				nodeAddr2 := fmt.Sprintf("%s:%v", nodeStats.TryString("access_ip", ""), nodeStats.Get("access_port"))
+				nodeAddr2 := net.JoinHostPort(nodeStats.TryString("access_ip", ""), fmt.Sprint(nodeStats.Get("access_port")))
 				if nodeAddr2 != addr {
 					delete(res, nodeAddr2)
 				}
@@ -524,8 +701,8 @@ L:
 		if nodesIfc, exists := cluster["nodes"]; exists {
 			for _, nodeStatsIfc := range nodesIfc.(common.Stats) {
 				nodeStats := nodeStatsIfc.(common.Stats)
-				nodeAddr1 := fmt.Sprintf("%s:%v", nodeStats.TryString("ip", ""), nodeStats.Get("port"))
-				nodeAddr2 := fmt.Sprintf("%s:%v", nodeStats.TryString("access_ip", ""), nodeStats.Get("access_port"))
+				nodeAddr1 := net.JoinHostPort(nodeStats.TryString("ip", ""), fmt.Sprint(nodeStats.Get("port")))
+				nodeAddr2 := net.JoinHostPort(nodeStats.TryString("access_ip", ""), fmt.Sprint(nodeStats.Get("access_port")))
 
 				// see if there are other clusters with similar nodes
 				for id, otherCluster := range res {
@@ -538,7 +715,7 @@ L:
 						nodes2 := nodesIfc2.(common.Stats)
 						for _, nodeStatsIfc2 := range nodes2 {
 							nodeStats2 := nodeStatsIfc2.(common.Stats)
-							if nodeAddr1 == fmt.Sprintf("%s:%v", nodeStats2.TryString("ip", ""), nodeStats2.Get("port")) || nodeAddr2 == fmt.Sprintf("%s:%v", nodeStats2.TryString("access_ip", ""), nodeStats2.Get("access_port")) {
+							if nodeAddr1 == net.JoinHostPort(nodeStats2.TryString("ip", ""), fmt.Sprint(nodeStats2.Get("port"))) || nodeAddr2 == net.JoinHostPort(nodeStats2.TryString("access_ip", ""), fmt.Sprint(nodeStats2.Get("access_port"))) {
 								nodes2[addr] = common.Stats{
 									"status":         "off",
 									"access_ip":      host,
@@ -594,14 +771,14 @@ L:
 				clientPolicy.LimitConnectionsToQueueSize = true
 				clientPolicy.ConnectionQueueSize = 1
 
-				_, err = o.Register(sessionID, clientPolicy, "", seedHost)
+				_, err = o.Register(context.Background(), sessionID, clientPolicy, "", seedHost)
 				if err == nil {
 					// c.update(nil)
 					continue
 				}
 
 				clientPolicy.UseServicesAlternate = true
-				_, err = o.Register(sessionID, clientPolicy, "", seedHost)
+				_, err = o.Register(context.Background(), sessionID, clientPolicy, "", seedHost)
 				if err == nil {
 					// c.update(nil)
 					continue
@@ -660,14 +837,14 @@ func (o *ObserverT) debugExpired() bool {
 	return debug.On && time.Now().After(debug.StartTime.Add(debug.Duration))
 }
 
-func findAliases(address, tlsName string, port int) []as.Host {
+func (o *ObserverT) findAliases(address, tlsName string, port int) []as.Host {
 	// IP addresses do not need a lookup
 	ip := net.ParseIP(address)
 	if ip != nil {
 		return []as.Host{{Name: ip.String(), Port: port, TLSName: tlsName}}
 	}
 
-	addresses, err := net.LookupHost(address)
+	addresses, err := o.resolveHost(address)
 	if err != nil {
 		return nil
 	}
@@ -678,14 +855,29 @@ func findAliases(address, tlsName string, port int) []as.Host {
 	return aliases
 }
 
-func resolveHost(address string) ([]string, error) {
+// resolveHost - get the IP addresses of the host, ordered by the preferred IP version
+func (o *ObserverT) resolveHost(address string) ([]string, error) {
 	// IP addresses do not need a lookup
 	ip := net.ParseIP(address)
 	if ip != nil {
-		return []string{address}, nil
+		return []string{ip.String()}, nil
+	}
+
+	addresses, err := net.LookupHost(address)
+	if err != nil {
+		return nil, err
+	}
+
+	preferIPVersion := o.config.PreferIPVersion()
+	preferV6 := preferIPVersion == 6
+	if preferIPVersion == 4 || preferV6 {
+		sort.SliceStable(addresses, func(i, j int) bool {
+			isV4 := func(addr string) bool { return net.ParseIP(addr).To4() != nil }
+			return isV4(addresses[i]) != preferV6 && isV4(addresses[j]) == preferV6
+		})
 	}
 
-	return net.LookupHost(address)
+	return addresses, nil
 }
 
 // func externalIPs() []string {
@@ -0,0 +1,107 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+const (
+	// number of consecutive updates taking longer than the interval before polling backs off
+	_slowUpdatesBeforeBackoff = 3
+
+	// number of consecutive fast updates before the backoff is reduced
+	_fastUpdatesBeforeRecovery = 5
+
+	// the polling interval is multiplied at most by this factor
+	_maxPollBackoff = 8
+)
+
+// PollBackoff - get the factor by which the update interval of the cluster is multiplied
+// because its updates take longer than the interval
+func (c *Cluster) PollBackoff() int {
+	return c.pollBackoff.Get().(int)
+}
+
+// EffectiveUpdateInterval - get the update interval in seconds after the backoff has been applied
+func (c *Cluster) EffectiveUpdateInterval() int {
+	return c.UpdateInterval() * c.PollBackoff()
+}
+
+// adaptPolling - back off polling the cluster when its updates consistently take longer than
+// the update interval, and recover once they become fast again.
+// Only called from update, which never runs concurrently for the same cluster.
+func (c *Cluster) adaptPolling(took time.Duration) {
+	interval := time.Duration(c.UpdateInterval()) * time.Second
+	backoff := c.PollBackoff()
+
+	switch {
+	case took > interval*time.Duration(backoff):
+		c.fastUpdates = 0
+		c.slowUpdates++
+		if c.slowUpdates < _slowUpdatesBeforeBackoff || backoff >= _maxPollBackoff {
+			return
+		}
+
+		c.slowUpdates = 0
+		c.setPollBackoff(backoff*2, took)
+
+	case backoff > 1 && took < interval*time.Duration(backoff)/2:
+		c.slowUpdates = 0
+		c.fastUpdates++
+		if c.fastUpdates < _fastUpdatesBeforeRecovery {
+			return
+		}
+
+		c.fastUpdates = 0
+		c.setPollBackoff(backoff/2, took)
+
+	default:
+		c.slowUpdates = 0
+		c.fastUpdates = 0
+	}
+}
+
+func (c *Cluster) setPollBackoff(backoff int, took time.Duration) {
+	if backoff < 1 {
+		backoff = 1
+	} else if backoff > _maxPollBackoff {
+		backoff = _maxPollBackoff
+	}
+
+	old := c.PollBackoff()
+	c.pollBackoff.Set(backoff)
+
+	if backoff > old {
+		log.Warnf("Updating cluster %s took %s, longer than its update interval of %ds; polling every %ds", c.ID(), took, c.UpdateInterval(), c.EffectiveUpdateInterval())
+	} else {
+		log.Infof("Updating cluster %s took %s; polling every %ds", c.ID(), took, c.EffectiveUpdateInterval())
+	}
+
+	if old == 1 && backoff > 1 {
+		c.alerts.Register(&common.Alert{
+			ID:          time.Now().UnixNano(),
+			ClusterID:   c.ID(),
+			Type:        common.AlertTypeClusterSlowPolling,
+			NodeAddress: c.SeedAddress(),
+			Desc:        fmt.Sprintf("Updates of cluster <strong>%s</strong> take %s, longer than the update interval; polling every %ds", c.SeedAddress(), took.Round(time.Millisecond), c.EffectiveUpdateInterval()),
+			Created:     time.Now(),
+			LastOccured: time.Now(),
+			Status:      common.AlertStatusYellow,
+		})
+	} else if old > 1 && backoff == 1 {
+		c.alerts.Register(&common.Alert{
+			ID:          time.Now().UnixNano(),
+			ClusterID:   c.ID(),
+			Type:        common.AlertTypeClusterSlowPolling,
+			NodeAddress: c.SeedAddress(),
+			Desc:        fmt.Sprintf("Updates of cluster <strong>%s</strong> are back within the update interval", c.SeedAddress()),
+			Created:     time.Now(),
+			LastOccured: time.Now(),
+			Status:      common.AlertStatusGreen,
+		})
+	}
+}
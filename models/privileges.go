@@ -0,0 +1,64 @@
+package models
+
+import (
+	"sort"
+
+	as "github.com/aerospike/aerospike-client-go/v5"
+)
+
+// PrivilegeInfo - a privilege and the scopes it can be granted at
+type PrivilegeInfo struct {
+	Code       string   `json:"code"`
+	Scopes     []string `json:"scopes"`
+	MinVersion string   `json:"min_version"`
+	Forms      []string `json:"forms"`
+}
+
+// privileges known to the client; global-only privileges cannot be scoped to a namespace or set
+var _privilegeCatalog = []PrivilegeInfo{
+	{Code: string(as.UserAdmin), Scopes: []string{"global"}, MinVersion: "3.0"},
+	{Code: string(as.SysAdmin), Scopes: []string{"global"}, MinVersion: "3.0"},
+	{Code: string(as.DataAdmin), Scopes: []string{"global"}, MinVersion: "3.0"},
+	{Code: string(as.Read), Scopes: []string{"global", "namespace", "set"}, MinVersion: "3.0"},
+	{Code: string(as.ReadWrite), Scopes: []string{"global", "namespace", "set"}, MinVersion: "3.0"},
+	{Code: string(as.ReadWriteUDF), Scopes: []string{"global", "namespace", "set"}, MinVersion: "3.0"},
+	{Code: string(as.Write), Scopes: []string{"global", "namespace", "set"}, MinVersion: "4.6"},
+}
+
+// PrivilegeCatalog - get the privileges supported by all nodes of the cluster,
+// with the concrete forms (e.g. `read.test.demo`) for the current namespaces and sets
+func (c *Cluster) PrivilegeCatalog() []PrivilegeInfo {
+	namespaces := c.NamespaceList()
+	sort.Strings(namespaces)
+
+	sets := make(map[string][]string, len(namespaces))
+	for _, ns := range namespaces {
+		for _, set := range c.NamespaceSetsInfo(ns) {
+			if name, ok := set["set_name"].(string); ok && name != "" {
+				sets[ns] = append(sets[ns], name)
+			}
+		}
+		sort.Strings(sets[ns])
+	}
+
+	res := make([]PrivilegeInfo, 0, len(_privilegeCatalog))
+	for _, p := range _privilegeCatalog {
+		if c.versionSupported(p.MinVersion) != nil {
+			continue
+		}
+
+		p.Forms = []string{p.Code}
+		if len(p.Scopes) > 1 {
+			for _, ns := range namespaces {
+				p.Forms = append(p.Forms, p.Code+"."+ns)
+				for _, set := range sets[ns] {
+					p.Forms = append(p.Forms, p.Code+"."+ns+"."+set)
+				}
+			}
+		}
+
+		res = append(res, p)
+	}
+
+	return res
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// RackID - get the configured rack-id of the namespace on the node
+func (ns *Namespace) RackID() int64 {
+	return ns.ConfigAttrs().TryInt("rack-id", 0)
+}
+
+// NamespaceRacks - get the rack topology of a namespace; rack-id => node addresses
+func (c *Cluster) NamespaceRacks(namespace string) map[string][]string {
+	res := map[string][]string{}
+	for _, node := range c.Nodes() {
+		ns := node.NamespaceByName(namespace)
+		if ns == nil {
+			continue
+		}
+
+		rackID := strconv.FormatInt(ns.RackID(), 10)
+		res[rackID] = append(res[rackID], node.Address())
+	}
+
+	for _, addrs := range res {
+		sort.Strings(addrs)
+	}
+
+	return res
+}
+
+// RackTopology - get the rack topology of all namespaces; namespace => rack-id => node addresses
+func (c *Cluster) RackTopology() map[string]map[string][]string {
+	namespaces := c.NamespaceList()
+	res := make(map[string]map[string][]string, len(namespaces))
+	for _, ns := range namespaces {
+		res[ns] = c.NamespaceRacks(ns)
+	}
+
+	return res
+}
+
+// SetRackID - set rack-id of the namespace on the nodes.
+// The new rack-id takes effect after the cluster is reclustered.
+func (c *Cluster) SetRackID(namespace string, rackID int64, nodes []*Node, user string) map[*Node]error {
+	res := make(map[*Node]error, len(nodes))
+	for _, node := range nodes {
+		ns := node.NamespaceByName(namespace)
+		if ns == nil {
+			res[node] = common.Errorf(common.KindNamespaceNotFound, "Namespace not found on node")
+			continue
+		}
+
+		_, res[node] = ns.SetConfig(map[string]string{"rack-id": strconv.FormatInt(rackID, 10)}, user)
+	}
+
+	return res
+}
+
+// Recluster - issue a recluster command to the cluster; only the principal node acts on it
+func (c *Cluster) Recluster() error {
+	res, err := c.RequestInfoAll("recluster:")
+	if err != nil {
+		return err
+	}
+
+	for _, r := range res {
+		if strings.ToLower(r) == "ok" {
+			return nil
+		}
+	}
+
+	return errors.New("Recluster was not accepted by the principal node")
+}
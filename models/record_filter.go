@@ -0,0 +1,523 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	as "github.com/aerospike/aerospike-client-go/v5"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// RecordFilter - a filter of the records of the scans and the queries, evaluated by the servers: a boolean
+// expression of comparisons of the bins or the metadata of the records to values, e.g.
+//
+//	age >= 21 and (country = 'US' or not exists(country)) and ttl() < 86400
+//
+// The type of a bin is that of the value it is compared to, so the records whose bin has another type do not
+// match. The comparisons are =, !=, <, <=, > and >=, and =~ for the POSIX extended regular expressions.
+type RecordFilter struct {
+	text string
+	root *filterNode
+}
+
+// the metadata of the records which can be compared, and whether it is a string
+var _filterMetadata = map[string]bool{
+	"ttl":           false, // seconds until the record expires
+	"void_time":     false, // nanoseconds since the epoch when the record expires
+	"last_update":   false, // nanoseconds since the epoch
+	"since_update":  false, // milliseconds
+	"device_size":   false, // bytes
+	"memory_size":   false, // bytes
+	"digest_modulo": false, // digest_modulo(n), to match 1/n of the records
+	"set_name":      true,
+}
+
+// the metadata which servers before 5.2 can compare, with the predicate expressions
+var _filterPredExpMetadata = map[string]func(arg int64) as.PredExp{
+	"void_time":     func(int64) as.PredExp { return as.NewPredExpRecVoidTime() },
+	"last_update":   func(int64) as.PredExp { return as.NewPredExpRecLastUpdate() },
+	"device_size":   func(int64) as.PredExp { return as.NewPredExpRecDeviceSize() },
+	"digest_modulo": func(arg int64) as.PredExp { return as.NewPredExpRecDigestModulo(int32(arg)) },
+}
+
+var _filterExpComparisons = map[string]func(left, right *as.Expression) *as.Expression{
+	"=":  as.ExpEq,
+	"!=": as.ExpNotEq,
+	"<":  as.ExpLess,
+	"<=": as.ExpLessEq,
+	">":  as.ExpGreater,
+	">=": as.ExpGreaterEq,
+}
+
+var _filterPredExpIntComparisons = map[string]func() as.PredExp{
+	"=":  as.NewPredExpIntegerEqual,
+	"!=": as.NewPredExpIntegerUnequal,
+	"<":  as.NewPredExpIntegerLess,
+	"<=": as.NewPredExpIntegerLessEq,
+	">":  as.NewPredExpIntegerGreater,
+	">=": as.NewPredExpIntegerGreaterEq,
+}
+
+// the POSIX extended syntax of the regular expressions, REG_EXTENDED
+const _filterRegexExtended = 1
+
+// filterNode - a node of a parsed filter: and, or and not of its children, a comparison of the operand
+// to the value, or exists and key_exists
+type filterNode struct {
+	op       string
+	children []*filterNode
+
+	bin   string // the bin compared, or tested by exists
+	fn    string // the metadata compared, if not a bin
+	arg   int64  // the argument of digest_modulo
+	value interface{}
+}
+
+// ParseRecordFilter - parse the text of a filter
+func ParseRecordFilter(text string) (*RecordFilter, error) {
+	tokens, err := tokenizeFilter(text)
+	if err != nil {
+		return nil, common.Errorf(common.KindInvalid, "Invalid filter: %s", err)
+	}
+	if len(tokens) == 0 {
+		return nil, common.Errorf(common.KindInvalid, "Invalid filter: it is empty")
+	}
+
+	p := &filterParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err == nil && p.pos < len(p.tokens) {
+		err = fmt.Errorf("unexpected %s", p.tokens[p.pos].text)
+	}
+	if err != nil {
+		return nil, common.Errorf(common.KindInvalid, "Invalid filter: %s", err)
+	}
+	return &RecordFilter{text: text, root: root}, nil
+}
+
+func (f *RecordFilter) String() string {
+	return f.text
+}
+
+// Expression - the filter as an expression, for the servers 5.2 and later
+func (f *RecordFilter) Expression() *as.Expression {
+	return f.root.expression()
+}
+
+// PredExp - the filter as predicate expressions, for the servers before 5.2; these only compare the integer
+// and the string bins and some of the metadata
+func (f *RecordFilter) PredExp() ([]as.PredExp, error) {
+	return f.root.predExp(nil)
+}
+
+// applyFilter - set the filter on the policy, as an expression or as predicate expressions depending on the
+// version of the servers; nothing to do if the filter is nil
+func (c *Cluster) applyFilter(policy *as.BasePolicy, f *RecordFilter) error {
+	if f == nil {
+		return nil
+	}
+	if c.versionSupported("5.2") == nil {
+		policy.FilterExpression = f.Expression()
+		return nil
+	}
+
+	predExp, err := f.PredExp()
+	if err != nil {
+		return err
+	}
+	policy.PredExp = predExp
+	return nil
+}
+
+func (n *filterNode) expression() *as.Expression {
+	switch n.op {
+	case "and", "or":
+		exps := make([]*as.Expression, len(n.children))
+		for i, child := range n.children {
+			exps[i] = child.expression()
+		}
+		if n.op == "and" {
+			return as.ExpAnd(exps...)
+		}
+		return as.ExpOr(exps...)
+	case "not":
+		return as.ExpNot(n.children[0].expression())
+	case "exists":
+		return as.ExpBinExists(n.bin)
+	case "key_exists":
+		return as.ExpKeyExists()
+	case "=~":
+		return as.ExpRegexCompare(n.value.(string), as.ExpRegexFlagEXTENDED, n.operandExpression())
+	}
+
+	var value *as.Expression
+	switch v := n.value.(type) {
+	case int64:
+		value = as.ExpIntVal(v)
+	case float64:
+		value = as.ExpFloatVal(v)
+	case string:
+		value = as.ExpStringVal(v)
+	case bool:
+		value = as.ExpBoolVal(v)
+	}
+	return _filterExpComparisons[n.op](n.operandExpression(), value)
+}
+
+func (n *filterNode) operandExpression() *as.Expression {
+	switch n.fn {
+	case "":
+	case "ttl":
+		return as.ExpTTL()
+	case "void_time":
+		return as.ExpVoidTime()
+	case "last_update":
+		return as.ExpLastUpdate()
+	case "since_update":
+		return as.ExpSinceUpdate()
+	case "device_size":
+		return as.ExpDeviceSize()
+	case "memory_size":
+		return as.ExpMemorySize()
+	case "digest_modulo":
+		return as.ExpDigestModulo(n.arg)
+	case "set_name":
+		return as.ExpSetName()
+	}
+
+	switch n.value.(type) {
+	case int64:
+		return as.ExpIntBin(n.bin)
+	case float64:
+		return as.ExpFloatBin(n.bin)
+	case bool:
+		return as.ExpBoolBin(n.bin)
+	}
+	return as.ExpStringBin(n.bin)
+}
+
+// predExp - append the predicate expressions of the node to res, in postfix order
+func (n *filterNode) predExp(res []as.PredExp) ([]as.PredExp, error) {
+	switch n.op {
+	case "and", "or", "not":
+		var err error
+		for _, child := range n.children {
+			if res, err = child.predExp(res); err != nil {
+				return nil, err
+			}
+		}
+		switch n.op {
+		case "and":
+			return append(res, as.NewPredExpAnd(uint16(len(n.children)))), nil
+		case "or":
+			return append(res, as.NewPredExpOr(uint16(len(n.children)))), nil
+		}
+		return append(res, as.NewPredExpNot()), nil
+	case "exists", "key_exists":
+		return nil, common.Errorf(common.KindInvalid, "%s() filters are not supported on servers before 5.2", n.op)
+	}
+
+	if n.fn != "" {
+		operand, exists := _filterPredExpMetadata[n.fn]
+		if !exists {
+			return nil, common.Errorf(common.KindInvalid, "%s() filters are not supported on servers before 5.2", n.fn)
+		}
+		return append(res, operand(n.arg), as.NewPredExpIntegerValue(n.value.(int64)), _filterPredExpIntComparisons[n.op]()), nil
+	}
+
+	switch v := n.value.(type) {
+	case int64:
+		return append(res, as.NewPredExpIntegerBin(n.bin), as.NewPredExpIntegerValue(v), _filterPredExpIntComparisons[n.op]()), nil
+	case string:
+		res = append(res, as.NewPredExpStringBin(n.bin), as.NewPredExpStringValue(v))
+		switch n.op {
+		case "=":
+			return append(res, as.NewPredExpStringEqual()), nil
+		case "!=":
+			return append(res, as.NewPredExpStringUnequal()), nil
+		case "=~":
+			return append(res, as.NewPredExpStringRegex(_filterRegexExtended)), nil
+		}
+		return nil, common.Errorf(common.KindInvalid, "String comparisons with %s are not supported on servers before 5.2", n.op)
+	}
+	return nil, common.Errorf(common.KindInvalid, "Filters on float and boolean bins are not supported on servers before 5.2")
+}
+
+// filterToken - a token of a filter; kind is one of ident, bin (a quoted bin name), string, number or op
+type filterToken struct {
+	kind, text string
+}
+
+// tokenizeFilter - split the filter into identifiers, `quoted` bin names, 'quoted' or "quoted" strings,
+// numbers and operators
+func tokenizeFilter(text string) ([]filterToken, error) {
+	res := []filterToken{}
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '\'' || r == '"' || r == '`':
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(runes) && runes[j] != r; j++ {
+				if runes[j] == '\\' && j+1 < len(runes) {
+					j++
+				}
+				b.WriteRune(runes[j])
+			}
+			if j == len(runes) {
+				return nil, fmt.Errorf("unterminated quote at %d", i+1)
+			}
+			kind := "string"
+			if r == '`' {
+				kind = "bin"
+			}
+			res = append(res, filterToken{kind: kind, text: b.String()})
+			i = j + 1
+		case unicode.IsLetter(r) || r == '_':
+			j := i + 1
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_') {
+				j++
+			}
+			res = append(res, filterToken{kind: "ident", text: string(runes[i:j])})
+			i = j
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			j := i + 1
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || strings.ContainsRune(".eE", runes[j]) ||
+				(strings.ContainsRune("+-", runes[j]) && strings.ContainsRune("eE", runes[j-1]))) {
+				j++
+			}
+			res = append(res, filterToken{kind: "number", text: string(runes[i:j])})
+			i = j
+		default:
+			op := ""
+			for _, candidate := range []string{"!=", "<>", "<=", ">=", "==", "=~", "=", "<", ">", "(", ")"} {
+				if strings.HasPrefix(string(runes[i:]), candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected %q at %d", r, i+1)
+			}
+			i += len(op)
+			switch op {
+			case "<>":
+				op = "!="
+			case "==":
+				op = "="
+			}
+			res = append(res, filterToken{kind: "op", text: op})
+		}
+	}
+	return res, nil
+}
+
+type filterParser struct {
+	tokens []filterToken
+	pos    int
+}
+
+func (p *filterParser) peek() *filterToken {
+	if p.pos < len(p.tokens) {
+		return &p.tokens[p.pos]
+	}
+	return nil
+}
+
+// keyword - consume the next token if it is the keyword, in any case
+func (p *filterParser) keyword(word string) bool {
+	if t := p.peek(); t != nil && t.kind == "ident" && strings.EqualFold(t.text, word) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// op - consume the next token if it is the operator
+func (p *filterParser) op(op string) bool {
+	if t := p.peek(); t != nil && t.kind == "op" && t.text == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *filterParser) expect(op string) error {
+	if !p.op(op) {
+		return p.unexpected(op)
+	}
+	return nil
+}
+
+func (p *filterParser) unexpected(expected string) error {
+	if t := p.peek(); t != nil {
+		return fmt.Errorf("expected %s instead of %s", expected, t.text)
+	}
+	return fmt.Errorf("expected %s at the end", expected)
+}
+
+func (p *filterParser) parseOr() (*filterNode, error) {
+	return p.parseList("or", p.parseAnd)
+}
+
+func (p *filterParser) parseAnd() (*filterNode, error) {
+	return p.parseList("and", p.parseUnary)
+}
+
+// parseList - parse the operands of the and or the or
+func (p *filterParser) parseList(op string, parse func() (*filterNode, error)) (*filterNode, error) {
+	first, err := parse()
+	if err != nil {
+		return nil, err
+	}
+
+	res := &filterNode{op: op, children: []*filterNode{first}}
+	for p.keyword(op) {
+		child, err := parse()
+		if err != nil {
+			return nil, err
+		}
+		res.children = append(res.children, child)
+	}
+	if len(res.children) == 1 {
+		return first, nil
+	}
+	return res, nil
+}
+
+func (p *filterParser) parseUnary() (*filterNode, error) {
+	if p.keyword("not") {
+		child, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &filterNode{op: "not", children: []*filterNode{child}}, nil
+	}
+
+	if p.op("(") {
+		res, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return res, p.expect(")")
+	}
+	return p.parseComparison()
+}
+
+// parseComparison - parse a comparison of a bin or a metadata function to a value, or exists(bin)
+// or key_exists()
+func (p *filterParser) parseComparison() (*filterNode, error) {
+	t := p.peek()
+	if t == nil || (t.kind != "ident" && t.kind != "bin") {
+		return nil, p.unexpected("a bin or a function")
+	}
+	p.pos++
+
+	n := &filterNode{bin: t.text}
+	if t.kind == "ident" && p.op("(") {
+		n.bin = ""
+		fn := strings.ToLower(t.text)
+		switch _, isMetadata := _filterMetadata[fn]; {
+		case fn == "exists":
+			bin := p.peek()
+			if bin == nil || (bin.kind != "ident" && bin.kind != "bin") {
+				return nil, p.unexpected("a bin")
+			}
+			p.pos++
+			return &filterNode{op: "exists", bin: bin.text}, p.expect(")")
+		case fn == "key_exists":
+			return &filterNode{op: "key_exists"}, p.expect(")")
+		case fn == "digest_modulo":
+			arg := p.peek()
+			if arg == nil || arg.kind != "number" {
+				return nil, p.unexpected("the modulo")
+			}
+			p.pos++
+			modulo, err := strconv.ParseInt(arg.text, 10, 32)
+			if err != nil || modulo <= 0 {
+				return nil, fmt.Errorf("invalid modulo %s", arg.text)
+			}
+			n.arg = modulo
+		case !isMetadata:
+			return nil, fmt.Errorf("unknown function %s()", t.text)
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		n.fn = fn
+	}
+
+	op := p.peek()
+	if op == nil || op.kind != "op" || op.text == "(" || op.text == ")" {
+		return nil, p.unexpected("a comparison")
+	}
+	p.pos++
+	n.op = op.text
+
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	n.value = value
+	return n, n.check()
+}
+
+// parseValue - parse an integer, a float, a string or a boolean
+func (p *filterParser) parseValue() (interface{}, error) {
+	t := p.peek()
+	if t == nil {
+		return nil, p.unexpected("a value")
+	}
+	p.pos++
+
+	switch {
+	case t.kind == "string":
+		return t.text, nil
+	case t.kind == "number":
+		if n, err := strconv.ParseInt(t.text, 10, 64); err == nil {
+			return n, nil
+		}
+		f, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %s", t.text)
+		}
+		return f, nil
+	case t.kind == "ident" && (strings.EqualFold(t.text, "true") || strings.EqualFold(t.text, "false")):
+		return strings.EqualFold(t.text, "true"), nil
+	}
+	return nil, fmt.Errorf("expected a value instead of %s; quote the strings", t.text)
+}
+
+// check - fail if the value cannot be compared to the operand with the operator
+func (n *filterNode) check() error {
+	operand := n.bin
+	if n.fn != "" {
+		operand = n.fn + "()"
+		_, isString := n.value.(string)
+		_, isInt := n.value.(int64)
+		if _filterMetadata[n.fn] && !isString {
+			return fmt.Errorf("%s can only be compared to strings", operand)
+		}
+		if !_filterMetadata[n.fn] && !isInt {
+			return fmt.Errorf("%s can only be compared to integers", operand)
+		}
+	}
+
+	switch n.value.(type) {
+	case string:
+	case bool:
+		if n.op != "=" && n.op != "!=" {
+			return fmt.Errorf("%s can only be compared to a boolean with = or !=", operand)
+		}
+	default:
+		if n.op == "=~" {
+			return fmt.Errorf("%s can only be matched to a string regular expression", operand)
+		}
+	}
+	return nil
+}
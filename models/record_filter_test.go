@@ -0,0 +1,123 @@
+package models
+
+import (
+	as "github.com/aerospike/aerospike-client-go/v5"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Record Filter", func() {
+
+	DescribeTable("tokenizing the filters",
+		func(text string, tokens []filterToken) {
+			res, err := tokenizeFilter(text)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal(tokens))
+		},
+		Entry("identifiers, operators and numbers", "age>=21", []filterToken{{"ident", "age"}, {"op", ">="}, {"number", "21"}}),
+		Entry("the alternate operators", "a <> 1 and b == 2", []filterToken{
+			{"ident", "a"}, {"op", "!="}, {"number", "1"}, {"ident", "and"}, {"ident", "b"}, {"op", "="}, {"number", "2"},
+		}),
+		Entry("quoted strings with escapes", `'it\'s' "x"`, []filterToken{{"string", "it's"}, {"string", "x"}}),
+		Entry("quoted bin names", "`my bin`", []filterToken{{"bin", "my bin"}}),
+		Entry("negative and float numbers", "-1.5e+3 -2", []filterToken{{"number", "-1.5e+3"}, {"number", "-2"}}),
+		Entry("nothing but spaces", " \t", []filterToken{}),
+	)
+
+	DescribeTable("parsing the valid filters",
+		func(text string, root *filterNode) {
+			f, err := ParseRecordFilter(text)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(f.root).To(Equal(root))
+			Expect(f.String()).To(Equal(text))
+		},
+		Entry("an integer comparison", "age >= 21", &filterNode{op: ">=", bin: "age", value: int64(21)}),
+		Entry("a string comparison of a quoted bin", "`first name` = 'Joe'", &filterNode{op: "=", bin: "first name", value: "Joe"}),
+		Entry("a float comparison", "score != 1.5", &filterNode{op: "!=", bin: "score", value: 1.5}),
+		Entry("a boolean comparison", "active = TRUE", &filterNode{op: "=", bin: "active", value: true}),
+		Entry("a regular expression", "name =~ '^J'", &filterNode{op: "=~", bin: "name", value: "^J"}),
+		Entry("the metadata", "ttl() < 86400", &filterNode{op: "<", fn: "ttl", value: int64(86400)}),
+		Entry("digest_modulo", "DIGEST_MODULO(3) = 0", &filterNode{op: "=", fn: "digest_modulo", arg: 3, value: int64(0)}),
+		Entry("the set name", "set_name() = 'demo'", &filterNode{op: "=", fn: "set_name", value: "demo"}),
+		Entry("exists and not", "not exists(country)", &filterNode{op: "not", children: []*filterNode{{op: "exists", bin: "country"}}}),
+		Entry("key_exists", "key_exists()", &filterNode{op: "key_exists"}),
+		Entry("and before or", "a = 1 or b = 2 and c = 3", &filterNode{op: "or", children: []*filterNode{
+			{op: "=", bin: "a", value: int64(1)},
+			{op: "and", children: []*filterNode{{op: "=", bin: "b", value: int64(2)}, {op: "=", bin: "c", value: int64(3)}}},
+		}}),
+		Entry("parentheses", "(a = 1 or b = 2) and c = 3", &filterNode{op: "and", children: []*filterNode{
+			{op: "or", children: []*filterNode{{op: "=", bin: "a", value: int64(1)}, {op: "=", bin: "b", value: int64(2)}}},
+			{op: "=", bin: "c", value: int64(3)},
+		}}),
+	)
+
+	DescribeTable("rejecting the malformed filters",
+		func(text, msg string) {
+			_, err := ParseRecordFilter(text)
+			Expect(err).To(MatchError("Invalid filter: " + msg))
+		},
+		Entry("an empty filter", "", "it is empty"),
+		Entry("an unterminated quote", "name = 'Joe", "unterminated quote at 8"),
+		Entry("an unknown character", "age # 21", `unexpected '#' at 5`),
+		Entry("a missing value", "age >=", "expected a value at the end"),
+		Entry("a missing comparison", "age 21", "expected a comparison instead of 21"),
+		Entry("an unquoted string", "name = Joe", "expected a value instead of Joe; quote the strings"),
+		Entry("a dangling and", "a = 1 and", "expected a bin or a function at the end"),
+		Entry("an unclosed parenthesis", "(a = 1", "expected ) at the end"),
+		Entry("an extra parenthesis", "a = 1)", "unexpected )"),
+		Entry("an unknown function", "size() > 1", "unknown function size()"),
+		Entry("an invalid modulo", "digest_modulo(0) = 1", "invalid modulo 0"),
+		Entry("a string compared to the ttl", "ttl() = '1'", "ttl() can only be compared to integers"),
+		Entry("an integer compared to the set name", "set_name() = 1", "set_name() can only be compared to strings"),
+		Entry("an ordered boolean comparison", "active > true", "active can only be compared to a boolean with = or !="),
+		Entry("a regular expression on an integer", "age =~ 1", "age can only be matched to a string regular expression"),
+	)
+
+	DescribeTable("falling back to the predicate expressions",
+		func(text string, predExp []as.PredExp) {
+			f, err := ParseRecordFilter(text)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(f.PredExp()).To(Equal(predExp))
+		},
+		Entry("an integer comparison", "age >= 21", []as.PredExp{
+			as.NewPredExpIntegerBin("age"), as.NewPredExpIntegerValue(21), as.NewPredExpIntegerGreaterEq(),
+		}),
+		Entry("a string comparison", "name != 'Joe'", []as.PredExp{
+			as.NewPredExpStringBin("name"), as.NewPredExpStringValue("Joe"), as.NewPredExpStringUnequal(),
+		}),
+		Entry("a regular expression", "name =~ '^J'", []as.PredExp{
+			as.NewPredExpStringBin("name"), as.NewPredExpStringValue("^J"), as.NewPredExpStringRegex(_filterRegexExtended),
+		}),
+		Entry("the metadata", "last_update() > 100", []as.PredExp{
+			as.NewPredExpRecLastUpdate(), as.NewPredExpIntegerValue(100), as.NewPredExpIntegerGreater(),
+		}),
+		Entry("digest_modulo", "digest_modulo(3) = 0", []as.PredExp{
+			as.NewPredExpRecDigestModulo(3), as.NewPredExpIntegerValue(0), as.NewPredExpIntegerEqual(),
+		}),
+		Entry("and, or and not in postfix order", "not (a = 1 or b = 2) and c = 3", []as.PredExp{
+			as.NewPredExpIntegerBin("a"), as.NewPredExpIntegerValue(1), as.NewPredExpIntegerEqual(),
+			as.NewPredExpIntegerBin("b"), as.NewPredExpIntegerValue(2), as.NewPredExpIntegerEqual(),
+			as.NewPredExpOr(2), as.NewPredExpNot(),
+			as.NewPredExpIntegerBin("c"), as.NewPredExpIntegerValue(3), as.NewPredExpIntegerEqual(),
+			as.NewPredExpAnd(2),
+		}),
+	)
+
+	DescribeTable("rejecting the filters the predicate expressions do not support",
+		func(text, msg string) {
+			f, err := ParseRecordFilter(text)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(f.Expression()).NotTo(BeNil())
+
+			_, err = f.PredExp()
+			Expect(err).To(MatchError(msg))
+		},
+		Entry("exists", "a = 1 and exists(b)", "exists() filters are not supported on servers before 5.2"),
+		Entry("key_exists", "key_exists()", "key_exists() filters are not supported on servers before 5.2"),
+		Entry("the ttl", "ttl() > 0", "ttl() filters are not supported on servers before 5.2"),
+		Entry("an ordered string comparison", "name < 'J'", "String comparisons with < are not supported on servers before 5.2"),
+		Entry("a float bin", "score > 1.5", "Filters on float and boolean bins are not supported on servers before 5.2"),
+		Entry("a boolean bin", "active = true", "Filters on float and boolean bins are not supported on servers before 5.2"),
+	)
+})
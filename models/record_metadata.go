@@ -0,0 +1,91 @@
+package models
+
+import (
+	"encoding/hex"
+	"errors"
+	"time"
+
+	as "github.com/aerospike/aerospike-client-go/v5"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// the metadata expressions were introduced in server 5.6; older servers only return the header
+const _recordMetadataExpVersion = "5.6"
+
+// RecordMetadata - the metadata of a record, without its bins; the sizes and the last update time
+// are only known on server 5.6+
+type RecordMetadata struct {
+	Exists     bool       `json:"exists"`
+	Digest     string     `json:"digest"`
+	Generation uint32     `json:"generation,omitempty"`
+	TTL        uint32     `json:"ttl,omitempty"` // seconds until the record expires
+	LastUpdate *time.Time `json:"last_update_time,omitempty"`
+	DeviceSize *int64     `json:"device_size,omitempty"`
+	MemorySize *int64     `json:"memory_size,omitempty"`
+}
+
+// RecordKey - the key of a record: its user key, or its digest in hex
+func RecordKey(namespace, set string, key interface{}, digest string) (*as.Key, error) {
+	if digest == "" {
+		k, err := as.NewKey(namespace, set, key)
+		if err != nil {
+			return nil, err
+		}
+		return k, nil
+	}
+
+	b, err := hex.DecodeString(digest)
+	if err != nil || len(b) != 20 {
+		return nil, common.Errorf(common.KindInvalid, "The digest must be 20 bytes in hex")
+	}
+	k, aerr := as.NewKeyWithDigest(namespace, set, nil, b)
+	if aerr != nil {
+		return nil, aerr
+	}
+	return k, nil
+}
+
+// RecordMetadata - read the metadata of the record without transferring its bins
+func (c *Cluster) RecordMetadata(key *as.Key) (*RecordMetadata, error) {
+	client := c.origClient()
+	if client == nil {
+		return nil, common.Errorf(common.KindUnavailable, "Cluster %s has been decommissioned", c.ID())
+	}
+
+	res := &RecordMetadata{Digest: hex.EncodeToString(key.Digest())}
+
+	var r *as.Record
+	var err as.Error
+	withExps := c.versionSupported(_recordMetadataExpVersion) == nil
+	if withExps {
+		r, err = client.Operate(nil, key,
+			as.ExpReadOp("last_update", as.ExpLastUpdate(), as.ExpReadFlagDefault),
+			as.ExpReadOp("device_size", as.ExpDeviceSize(), as.ExpReadFlagDefault),
+			as.ExpReadOp("memory_size", as.ExpMemorySize(), as.ExpReadFlagDefault),
+		)
+	} else {
+		r, err = client.GetHeader(nil, key)
+	}
+
+	if errors.Is(err, as.ErrKeyNotFound) {
+		return res, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	res.Exists, res.Generation, res.TTL = true, r.Generation, r.Expiration
+	if withExps {
+		if ns, ok := r.Bins["last_update"].(int); ok {
+			tm := time.Unix(0, int64(ns))
+			res.LastUpdate = &tm
+		}
+		for name, size := range map[string]**int64{"device_size": &res.DeviceSize, "memory_size": &res.MemorySize} {
+			if v, ok := r.Bins[name].(int); ok {
+				n := int64(v)
+				*size = &n
+			}
+		}
+	}
+	return res, nil
+}
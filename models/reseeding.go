@@ -0,0 +1,92 @@
+package models
+
+import (
+	"net"
+	"sort"
+	"strconv"
+
+	as "github.com/aerospike/aerospike-client-go/v5"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// liveHosts - get the addresses of the nodes which were active in the latest update of the cluster
+func (c *Cluster) liveHosts() []*as.Host {
+	hosts, _ := c.knownHosts.Get().([]*as.Host)
+	return hosts
+}
+
+// connectHosts - get the hosts used to connect to the cluster; the addresses of the live nodes
+// come first, so that reconnecting does not depend on the original seeds still being part of the cluster
+func (c *Cluster) connectHosts() []*as.Host {
+	seeds := c.seeds.Get().([]*as.Host)
+	live := c.liveHosts()
+
+	res := make([]*as.Host, 0, len(live)+len(seeds))
+	added := map[string]bool{}
+	for _, hosts := range [][]*as.Host{live, seeds} {
+		for _, host := range hosts {
+			if !added[host.String()] {
+				added[host.String()] = true
+				res = append(res, host)
+			}
+		}
+	}
+
+	return res
+}
+
+// reseed - track the addresses of the active nodes of the cluster and persist them as its seeds
+func (c *Cluster) reseed() {
+	live := []*as.Host{}
+	for _, node := range c.Nodes() {
+		if node.Status() == nodeStatus.On && node.origHost != nil {
+			live = append(live, node.origHost)
+		}
+	}
+
+	// keep the last known addresses while the cluster is unreachable
+	if len(live) == 0 {
+		return
+	}
+
+	sort.Slice(live, func(i, j int) bool { return live[i].String() < live[j].String() })
+
+	old := c.liveHosts()
+	if len(old) == len(live) {
+		changed := false
+		for i := range old {
+			if old[i].String() != live[i].String() {
+				changed = true
+				break
+			}
+		}
+
+		if !changed {
+			return
+		}
+	}
+
+	c.knownHosts.Set(live)
+	c.observer.persistSeeds(c)
+}
+
+// persistSeeds - update the seeds of a persisted cluster with the addresses of its live nodes
+func (o *ObserverT) persistSeeds(cluster *Cluster) {
+	if !o.persistClusters || cluster.permanent.Get().(bool) {
+		return
+	}
+
+	if err := common.UpdateMonitoredClusterSeeds(cluster.ID(), seedAddresses(cluster.connectHosts())); err != nil {
+		log.Errorf("Error persisting the seeds of cluster %s: %s", cluster.ID(), err.Error())
+	}
+}
+
+func seedAddresses(hosts []*as.Host) []string {
+	res := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		res = append(res, net.JoinHostPort(host.Name, strconv.Itoa(host.Port)))
+	}
+	return res
+}
@@ -2,7 +2,6 @@ package models
 
 import (
 	"bufio"
-	"errors"
 	"fmt"
 	"io"
 	"regexp"
@@ -78,7 +77,7 @@ func (r *Restore) Execute() error {
 	node := r.cluster.RandomActiveNode()
 	if node == nil {
 		r.UpdateStatus(common.BackupStatusFailed)
-		return errors.New("No active nodes found in the cluster")
+		return common.Errorf(common.KindUnavailable, "No active nodes found in the cluster")
 	}
 
 	// try to connect to the remote address and run the command
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// StrongConsistency - check if the namespace is configured for strong consistency
+func (ns *Namespace) StrongConsistency() bool {
+	return ns.ConfigAttrs().TryString("strong-consistency", "false") == "true"
+}
+
+// PartitionState - get the unavailable and dead partition counts of the namespace on the node
+func (ns *Namespace) PartitionState() common.Stats {
+	return common.Stats{
+		"strong-consistency":     ns.StrongConsistency(),
+		"unavailable_partitions": ns.latestStats.TryInt("unavailable_partitions", 0),
+		"dead_partitions":        ns.latestStats.TryInt("dead_partitions", 0),
+	}
+}
+
+// NamespacePartitionState - get the partition state of the namespace on every node; node address => state
+func (c *Cluster) NamespacePartitionState(namespace string) map[string]common.Stats {
+	res := map[string]common.Stats{}
+	for _, node := range c.Nodes() {
+		if ns := node.NamespaceByName(namespace); ns != nil {
+			state := ns.PartitionState()
+			state["node_status"] = node.Status()
+			res[node.Address()] = state
+		}
+	}
+	return res
+}
+
+// ReviveNamespace - revive the dead partitions of a strong consistency namespace
+// on all nodes and recluster. Reviving may bring back stale data, so it must only be
+// done after the operator has confirmed that the lost data is acceptable.
+func (c *Cluster) ReviveNamespace(namespace string) (map[*Node]error, error) {
+	if err := c.CheckInfoCommand("revive"); err != nil {
+		return nil, err
+	}
+
+	deadPartitions := int64(0)
+	found := false
+	for _, node := range c.Nodes() {
+		ns := node.NamespaceByName(namespace)
+		if ns == nil {
+			continue
+		}
+
+		found = true
+		if !ns.StrongConsistency() {
+			return nil, fmt.Errorf("Namespace %s is not a strong consistency namespace", namespace)
+		}
+		deadPartitions += ns.latestStats.TryInt("dead_partitions", 0)
+	}
+
+	if !found {
+		return nil, common.Errorf(common.KindNamespaceNotFound, "Namespace not found")
+	}
+
+	if deadPartitions == 0 {
+		return nil, fmt.Errorf("Namespace %s has no dead partitions", namespace)
+	}
+
+	cmd := "revive:namespace=" + namespace
+	res, nodeErrs := c.RequestInfoNodes(c.Nodes(), 0, cmd)
+
+	// the result of every node; nil means success
+	errs := make(map[*Node]error, len(res))
+	failed := false
+	for node, r := range res {
+		errs[node] = nodeErrs[node]
+		if errs[node] == nil && strings.ToLower(strings.TrimSpace(r)) != "ok" {
+			errs[node] = errors.New("Server rejected the revive: " + r)
+		}
+		if errs[node] != nil {
+			failed = true
+		}
+	}
+
+	// the revive only takes effect after a recluster
+	if failed {
+		return errs, errors.New("Revive failed on some nodes; the cluster was not reclustered")
+	}
+
+	if err := c.Recluster(); err != nil {
+		return errs, errors.New("Partitions were revived but recluster failed: " + err.Error())
+	}
+
+	return errs, nil
+}
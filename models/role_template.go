@@ -0,0 +1,102 @@
+package models
+
+import (
+	"fmt"
+
+	as "github.com/aerospike/aerospike-client-go/v5"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// role whitelists are supported from server version 5.6
+const _roleWhitelistMinVersion = "5.6"
+
+func privilegeIndex(privileges []as.Privilege, p as.Privilege) int {
+	for i := range privileges {
+		if privileges[i].Code == p.Code && privileges[i].Namespace == p.Namespace && privileges[i].SetName == p.SetName {
+			return i
+		}
+	}
+	return -1
+}
+
+// ApplyRole - make the role on the cluster match the given privileges and whitelist;
+// the role is created if it does not exist, otherwise only the differences are applied.
+// Returns the action taken: created, updated or unchanged.
+func (c *Cluster) ApplyRole(role string, privileges []as.Privilege, whitelist []string) (string, error) {
+	if len(whitelist) > 0 {
+		if err := c.versionSupported(_roleWhitelistMinVersion); err != nil {
+			return "", fmt.Errorf("Role whitelists require server version %s or later: %s", _roleWhitelistMinVersion, err.Error())
+		}
+	}
+
+	var existing *as.Role
+	for _, r := range c.Roles() {
+		if r.Name == role {
+			existing = r
+			break
+		}
+	}
+
+	client := c.origClient()
+	if client == nil {
+		return "", common.Errorf(common.KindUnavailable, "Cluster %s has been decommissioned", c.ID())
+	}
+
+	if existing == nil {
+		if err := client.CreateRole(nil, role, privileges, whitelist, 0, 0); err != nil {
+			return "", err
+		}
+		return "created", nil
+	}
+
+	var added, removed []as.Privilege
+	for _, p := range privileges {
+		if privilegeIndex(existing.Privileges, p) < 0 {
+			added = append(added, p)
+		}
+	}
+	for _, p := range existing.Privileges {
+		if privilegeIndex(privileges, p) < 0 {
+			removed = append(removed, p)
+		}
+	}
+
+	whitelistChanged := len(whitelist) != len(existing.Whitelist)
+	if !whitelistChanged {
+		current := make(map[string]bool, len(existing.Whitelist))
+		for _, addr := range existing.Whitelist {
+			current[addr] = true
+		}
+		for _, addr := range whitelist {
+			if !current[addr] {
+				whitelistChanged = true
+				break
+			}
+		}
+	}
+
+	if len(added) == 0 && len(removed) == 0 && !whitelistChanged {
+		return "unchanged", nil
+	}
+
+	if len(added) > 0 {
+		if err := client.GrantPrivileges(nil, role, added); err != nil {
+			return "", err
+		}
+	}
+
+	if len(removed) > 0 {
+		if err := client.RevokePrivileges(nil, role, removed); err != nil {
+			return "", err
+		}
+	}
+
+	if whitelistChanged {
+		if err := client.SetWhitelist(nil, role, whitelist); err != nil {
+			return "", err
+		}
+	}
+
+	return "updated", nil
+}
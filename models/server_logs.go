@@ -0,0 +1,191 @@
+package models
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	_defaultServerLogTimeout  = 5 * time.Second
+	_defaultServerLogMaxLines = 1000
+
+	// log lines within this window around an alert are considered related to it
+	_alertLogCorrelationWindow = 2 * time.Minute
+)
+
+// e.g. `May 04 2021 10:11:12 GMT: WARNING (hb): (hb.c:123) message`
+var serverLogLineRegexp = regexp.MustCompile(`^(\w{3} \d{2} \d{4} \d{2}:\d{2}:\d{2}(?:\.\d+)? \w+): (\w+) \(([^)]*)\): (.*)$`)
+
+// the host placeholder of the endpoint, with the port which follows it if any
+var serverLogHostRegexp = regexp.MustCompile(`\{host\}(?::(\d+))?`)
+
+var serverLogTimeLayouts = []string{"Jan 02 2006 15:04:05 MST", "Jan 02 2006 15:04:05.000 MST"}
+
+// ServerLogLine - a parsed line of the server log
+type ServerLogLine struct {
+	Time     *time.Time `json:"time,omitempty"`
+	Severity string     `json:"severity,omitempty"`
+	Context  string     `json:"context,omitempty"`
+	Message  string     `json:"message"`
+}
+
+// IsProblem - check if the line is a warning or an error
+func (l *ServerLogLine) IsProblem() bool {
+	switch l.Severity {
+	case "WARNING", "CRITICAL", "ERROR", "FAILED":
+		return true
+	}
+	return false
+}
+
+func parseServerLogLine(line string) ServerLogLine {
+	m := serverLogLineRegexp.FindStringSubmatch(line)
+	if m == nil {
+		return ServerLogLine{Message: line}
+	}
+
+	res := ServerLogLine{Severity: strings.ToUpper(m[2]), Context: m[3], Message: m[4]}
+	for _, layout := range serverLogTimeLayouts {
+		if tm, err := time.Parse(layout, m[1]); err == nil {
+			res.Time = &tm
+			break
+		}
+	}
+
+	return res
+}
+
+// ServerLogMaxLines - the maximum number of log lines which can be requested
+func (c *Cluster) ServerLogMaxLines() int {
+	if max := c.observer.Config().ServerLogSettings().MaxLines; max > 0 {
+		return max
+	}
+	return _defaultServerLogMaxLines
+}
+
+// serverLogEndpoint - the endpoint with the host of the node; IPv6 addresses are bracketed
+func serverLogEndpoint(endpoint, host string) string {
+	return serverLogHostRegexp.ReplaceAllStringFunc(endpoint, func(s string) string {
+		if port := serverLogHostRegexp.FindStringSubmatch(s)[1]; port != "" {
+			return net.JoinHostPort(host, port)
+		}
+		if strings.Contains(host, ":") {
+			return "[" + host + "]"
+		}
+		return host
+	})
+}
+
+// serverLogTail - a ring buffer of the last lines of the log
+type serverLogTail struct {
+	lines []string
+	next  int
+	full  bool
+}
+
+func newServerLogTail(size int) *serverLogTail {
+	return &serverLogTail{lines: make([]string, size)}
+}
+
+func (t *serverLogTail) add(line string) {
+	t.lines[t.next] = line
+	t.next = (t.next + 1) % len(t.lines)
+	if t.next == 0 {
+		t.full = true
+	}
+}
+
+// tail - the lines in the order they were added
+func (t *serverLogTail) tail() []string {
+	if !t.full {
+		return t.lines[:t.next]
+	}
+	return append(t.lines[t.next:], t.lines[:t.next]...)
+}
+
+// TailServerLog - fetch the last lines of the server log of the node from the configured log endpoint
+func (n *Node) TailServerLog(lines int) ([]ServerLogLine, error) {
+	config := n.cluster.observer.Config().ServerLogSettings()
+	if config.Endpoint == "" {
+		return nil, errors.New("Server log endpoint is not configured")
+	}
+
+	if max := n.cluster.ServerLogMaxLines(); lines <= 0 || lines > max {
+		lines = max
+	}
+
+	u, err := url.Parse(serverLogEndpoint(config.Endpoint, n.Host()))
+	if err != nil {
+		return nil, fmt.Errorf("Invalid server log endpoint: %s", err.Error())
+	}
+
+	q := u.Query()
+	q.Set("lines", strconv.Itoa(lines))
+	u.RawQuery = q.Encode()
+
+	timeout := _defaultServerLogTimeout
+	if config.Timeout > 0 {
+		timeout = time.Duration(config.Timeout) * time.Second
+	}
+
+	client := http.Client{Timeout: timeout}
+	resp, err := client.Get(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Server log endpoint returned %s", resp.Status)
+	}
+
+	// the endpoint may ignore the lines parameter and return the whole log;
+	// only the last lines are kept while the response is read
+	tail := newServerLogTail(lines)
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if line := strings.TrimRight(scanner.Text(), "\r"); line != "" {
+			tail.add(line)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	res := []ServerLogLine{}
+	for _, line := range tail.tail() {
+		res = append(res, parseServerLogLine(line))
+	}
+
+	return res, nil
+}
+
+// AlertLogLines - map the node's alerts to the problem lines of the log logged around the time of each alert; alert id => lines
+func (n *Node) AlertLogLines(lines []ServerLogLine) map[int64][]ServerLogLine {
+	res := map[int64][]ServerLogLine{}
+	for _, alert := range n.AlertsFrom(0) {
+		from := alert.Created.Add(-_alertLogCorrelationWindow)
+		to := alert.LastOccured.Add(_alertLogCorrelationWindow)
+
+		for _, l := range lines {
+			if l.Time == nil || !l.IsProblem() {
+				continue
+			}
+			if !l.Time.Before(from) && !l.Time.After(to) {
+				res[alert.ID] = append(res[alert.ID], l)
+			}
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"strconv"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Server logs", func() {
+	table.DescribeTable("the endpoint of a node",
+		func(endpoint, host, expected string) {
+			Expect(serverLogEndpoint(endpoint, host)).To(Equal(expected))
+		},
+		table.Entry("an IPv4 address with a port", "http://{host}:8090/aerospike.log", "10.0.0.1", "http://10.0.0.1:8090/aerospike.log"),
+		table.Entry("an IPv6 address with a port", "http://{host}:8090/aerospike.log", "fe80::1", "http://[fe80::1]:8090/aerospike.log"),
+		table.Entry("an IPv6 address without a port", "http://{host}/aerospike.log", "fe80::1", "http://[fe80::1]/aerospike.log"),
+		table.Entry("a host name", "https://{host}/logs?node={host}", "node1", "https://node1/logs?node=node1"),
+		table.Entry("no placeholder", "http://logs/aerospike.log", "node1", "http://logs/aerospike.log"),
+	)
+
+	Describe("the tail of a log", func() {
+		lines := func(n int) []string {
+			res := []string{}
+			for i := 0; i < n; i++ {
+				res = append(res, strconv.Itoa(i))
+			}
+			return res
+		}
+
+		It("keeps every line when there are fewer lines than its size", func() {
+			tail := newServerLogTail(5)
+			for _, l := range lines(3) {
+				tail.add(l)
+			}
+			Expect(tail.tail()).To(Equal([]string{"0", "1", "2"}))
+		})
+
+		It("keeps every line when there are as many lines as its size", func() {
+			tail := newServerLogTail(3)
+			for _, l := range lines(3) {
+				tail.add(l)
+			}
+			Expect(tail.tail()).To(Equal([]string{"0", "1", "2"}))
+		})
+
+		It("keeps the last lines in order", func() {
+			tail := newServerLogTail(3)
+			for _, l := range lines(10) {
+				tail.add(l)
+			}
+			Expect(tail.tail()).To(Equal([]string{"7", "8", "9"}))
+		})
+
+		It("is empty before a line is added", func() {
+			Expect(newServerLogTail(3).tail()).To(BeEmpty())
+		})
+	})
+})
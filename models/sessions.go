@@ -0,0 +1,103 @@
+package models
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// SessionCluster - a cluster attached to a session, and the user it was logged in with
+type SessionCluster struct {
+	ID   string `json:"cluster_id"`
+	Name string `json:"cluster_name"`
+	User string `json:"user"`
+}
+
+// SessionInfo - an active session of AMC: the users it logged in to its clusters with,
+// the address of its last request and when it was made
+type SessionInfo struct {
+	ID           string           `json:"session_id"`
+	Users        []string         `json:"users"`
+	RemoteIP     string           `json:"remote_ip"`
+	Created      time.Time        `json:"created"`
+	LastActivity time.Time        `json:"last_activity"`
+	Clusters     []SessionCluster `json:"clusters"`
+}
+
+// sessionActivity - the requests of a session; stored by value so that it can be shared between the requests
+type sessionActivity struct {
+	remoteIP string
+	created  time.Time
+	last     time.Time
+}
+
+// internalSession - the session is not a user's, but holds the clusters of the config file or the restored ones
+func internalSession(sessionID string) bool {
+	return sessionID == "automatic" || sessionID == _restoredSessionID
+}
+
+// TouchSession - record a request of the session from the address
+func (o *ObserverT) TouchSession(sessionID, remoteIP string) {
+	if !o.SessionExists(sessionID) {
+		return
+	}
+
+	now := time.Now()
+	activity := sessionActivity{remoteIP: remoteIP, created: now, last: now}
+	if prev, exists := o.sessionActivity.Get(sessionID).(sessionActivity); exists {
+		activity.created = prev.created
+	}
+	o.sessionActivity.Set(sessionID, activity)
+}
+
+// Sessions - the active sessions of the users, sorted by their last activity, the latest first
+func (o *ObserverT) Sessions() []SessionInfo {
+	res := []SessionInfo{}
+	for sessionID, clusters := range o.sessions.Clone() {
+		if internalSession(sessionID) {
+			continue
+		}
+
+		info := SessionInfo{ID: sessionID, Users: []string{}, Clusters: []SessionCluster{}}
+		if activity, exists := o.sessionActivity.Get(sessionID).(sessionActivity); exists {
+			info.RemoteIP, info.Created, info.LastActivity = activity.remoteIP, activity.created, activity.last
+		}
+
+		for _, cluster := range clusters.([]*Cluster) {
+			sc := SessionCluster{ID: cluster.ID()}
+			if alias := cluster.Alias(); alias != nil {
+				sc.Name = *alias
+			}
+			if user := cluster.User(); user != nil {
+				sc.User = *user
+				info.Users = append(info.Users, *user)
+			}
+			info.Clusters = append(info.Clusters, sc)
+		}
+		info.Users = common.SortStrings(common.StrUniq(info.Users))
+
+		res = append(res, info)
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].LastActivity.After(res[j].LastActivity) })
+	return res
+}
+
+// RevokeSession - end the session: its clusters are removed from it, and no longer monitored if no other
+// session monitors them, and its next request is rejected; false if the session does not exist
+func (o *ObserverT) RevokeSession(ctx context.Context, sessionID string) bool {
+	if internalSession(sessionID) || !o.SessionExists(sessionID) {
+		return false
+	}
+
+	for _, cluster := range o.sessionClusters(sessionID) {
+		o.RemoveCluster(ctx, sessionID, cluster)
+	}
+	o.sessions.Del(sessionID)
+	o.sessionActivity.Del(sessionID)
+
+	common.Logger(ctx).WithField("session_id", sessionID).Info("Revoked session " + sessionID)
+	return true
+}
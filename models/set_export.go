@@ -0,0 +1,44 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	as "github.com/aerospike/aerospike-client-go/v5"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// ExportSet - scan the whole set, or its first limit records if limit > 0, and pass the records to f as they
+// are read; only the records matching the filter are read if it is not nil, and only the bins if any are given.
+// The scan stops at the first error of f, or when the context is done.
+func (c *Cluster) ExportSet(ctx context.Context, namespace, set string, limit int, bins []string, filter *RecordFilter, f func(SampleRecord) error) error {
+	if limit < 0 {
+		return common.Errorf(common.KindInvalid, "Invalid limit")
+	}
+	if err := c.checkNamespace(namespace); err != nil {
+		return err
+	}
+
+	client := c.origClient()
+	if client == nil {
+		return common.Errorf(common.KindUnavailable, "Cluster %s has been decommissioned", c.ID())
+	}
+
+	policy := as.NewScanPolicy()
+	policy.MaxRecords = int64(limit)
+	if deadline, ok := ctx.Deadline(); ok {
+		policy.TotalTimeout = time.Until(deadline)
+	}
+	if err := c.applyFilter(&policy.BasePolicy, filter); err != nil {
+		return err
+	}
+
+	rs, err := client.ScanAll(policy, namespace, set, bins...)
+	if err != nil {
+		return err
+	}
+	return readRecords(ctx, rs, limit, func(r *as.Record) error {
+		return f(sampleRecord(r))
+	})
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// set indexes were introduced in server 5.6
+const _setIndexMinVersion = "5.6"
+
+// SetIndexes - get the set index state of the namespace sets on the node; set => info
+func (ns *Namespace) SetIndexes() map[string]common.Stats {
+	res := map[string]common.Stats{}
+	for name, set := range ns.SetsInfo() {
+		res[name] = common.Stats{
+			"enabled":    set.TryString("enable-index", "false") == "true",
+			"populating": set.TryString("index_populating", "false") == "true",
+			"objects":    set.TryInt("objects", 0),
+		}
+	}
+
+	return res
+}
+
+// SetIndexMemory - get the memory used by the set indexes of the namespace on the node
+func (ns *Namespace) SetIndexMemory() int64 {
+	return ns.latestStats.TryInt("memory_used_set_index_bytes", 0)
+}
+
+// SetIndexes - get the set indexes of the namespace on all nodes; node address => set => info
+func (c *Cluster) SetIndexes(namespace string) map[string]common.Stats {
+	res := map[string]common.Stats{}
+	for _, node := range c.Nodes() {
+		ns := node.NamespaceByName(namespace)
+		if ns == nil {
+			continue
+		}
+
+		res[node.Address()] = common.Stats{
+			"node_status":  node.Status(),
+			"memory_bytes": ns.SetIndexMemory(),
+			"sets":         ns.SetIndexes(),
+		}
+	}
+
+	return res
+}
+
+// SetSetIndex - enable or disable the set index of a set on all nodes
+func (c *Cluster) SetSetIndex(namespace, set string, enable bool) error {
+	if err := c.versionSupported(_setIndexMinVersion); err != nil {
+		return fmt.Errorf("Set indexes require server %s+: %s", _setIndexMinVersion, err.Error())
+	}
+
+	if set == "" || strings.ContainsAny(set, ";:=") {
+		return common.Errorf(common.KindInvalid, "Invalid set name `%s`", set)
+	}
+
+	cmd := fmt.Sprintf("set-config:context=namespace;id=%s;set=%s;enable-index=%t", namespace, set, enable)
+	res, err := c.RequestInfoAll(cmd)
+	if err != nil {
+		return err
+	}
+
+	errs := []string{}
+	for node, r := range res {
+		if strings.ToLower(r) != "ok" {
+			errs = append(errs, fmt.Sprintf("%s: %s", node.Address(), r))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("Setting the set index failed on nodes: %s", strings.Join(errs, ", "))
+	}
+
+	return nil
+}
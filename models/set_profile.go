@@ -0,0 +1,152 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	as "github.com/aerospike/aerospike-client-go/v5"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// the limits of the records a set profile is computed from; only the aggregates are returned
+const (
+	SetProfileDefaultLimit = 1000
+	SetProfileMaxLimit     = 10000
+)
+
+// BinProfile - the records of a set profile which have the bin, the types of its values and their average size
+type BinProfile struct {
+	Name         string         `json:"name"`
+	Count        int            `json:"count"`
+	Pct          float64        `json:"pct"` // of the sampled records
+	Types        map[string]int `json:"types"`
+	AvgSizeBytes float64        `json:"avg_size_bytes"`
+
+	totalSize int
+}
+
+// SetProfile - the bins of the sampled records of a set, the most common first
+type SetProfile struct {
+	SampledRecords int           `json:"sampled_records"`
+	AvgBinCount    float64       `json:"avg_bin_count"`
+	Bins           []*BinProfile `json:"bins"`
+}
+
+// ProfileSet - scan the set for the first limit records matching the filter, as SampleSet, and return the names,
+// the types and the average sizes of their bins
+func (c *Cluster) ProfileSet(ctx context.Context, namespace, set string, limit int, samplePct float64, filter *RecordFilter) (*SetProfile, error) {
+	if limit <= 0 || limit > SetProfileMaxLimit {
+		return nil, common.Errorf(common.KindInvalid, "The limit must be between 1 and %d", SetProfileMaxLimit)
+	}
+
+	rs, err := c.scanSample(ctx, namespace, set, limit, samplePct, filter, true)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &SetProfile{Bins: []*BinProfile{}}
+	bins := map[string]*BinProfile{}
+	binCount := 0
+	err = readRecords(ctx, rs, limit, func(r *as.Record) error {
+		res.SampledRecords++
+		binCount += len(r.Bins)
+		for name, v := range r.Bins {
+			bin := bins[name]
+			if bin == nil {
+				bin = &BinProfile{Name: name, Types: map[string]int{}}
+				bins[name] = bin
+				res.Bins = append(res.Bins, bin)
+			}
+			bin.Count++
+			bin.Types[binType(v)]++
+			bin.totalSize += binSize(v)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, bin := range res.Bins {
+		bin.Pct = 100 * float64(bin.Count) / float64(res.SampledRecords)
+		bin.AvgSizeBytes = float64(bin.totalSize) / float64(bin.Count)
+	}
+	if res.SampledRecords > 0 {
+		res.AvgBinCount = float64(binCount) / float64(res.SampledRecords)
+	}
+
+	sort.Slice(res.Bins, func(i, j int) bool {
+		if res.Bins[i].Count != res.Bins[j].Count {
+			return res.Bins[i].Count > res.Bins[j].Count
+		}
+		return res.Bins[i].Name < res.Bins[j].Name
+	})
+	return res, nil
+}
+
+// binType - the name of the server particle type of the value of a bin
+func binType(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "nil"
+	case bool:
+		return "boolean"
+	case int, int64, int32, int16, int8, uint, uint64, uint32, uint16, uint8:
+		return "integer"
+	case float64, float32:
+		return "float"
+	case string:
+		return "string"
+	case []byte:
+		return "bytes"
+	case as.GeoJSONValue:
+		return "geojson"
+	case as.HLLValue:
+		return "hll"
+	case []interface{}:
+		return "list"
+	case map[interface{}]interface{}, []as.MapPair:
+		return "map"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// binSize - an estimate of the size of the value of a bin: 8 bytes for the numbers, the length of the strings
+// and the blobs, and the sum of the elements of the lists and the maps
+func binSize(v interface{}) int {
+	switch v := v.(type) {
+	case nil:
+		return 0
+	case bool:
+		return 1
+	case string:
+		return len(v)
+	case []byte:
+		return len(v)
+	case as.GeoJSONValue:
+		return len(v)
+	case as.HLLValue:
+		return len(v)
+	case []interface{}:
+		size := 0
+		for _, e := range v {
+			size += binSize(e)
+		}
+		return size
+	case map[interface{}]interface{}:
+		size := 0
+		for k, e := range v {
+			size += binSize(k) + binSize(e)
+		}
+		return size
+	case []as.MapPair:
+		size := 0
+		for _, p := range v {
+			size += binSize(p.Key) + binSize(p.Value)
+		}
+		return size
+	}
+	return 8
+}
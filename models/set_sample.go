@@ -0,0 +1,191 @@
+package models
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	as "github.com/aerospike/aerospike-client-go/v5"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// the partitions of a namespace; a sample scans a random range of them
+const _partitionCount = 4096
+
+// the limits of the set samples and the sindex queries, whose records are returned whole in a response
+const (
+	SetSampleDefaultLimit = 10
+	SetSampleMaxLimit     = 1000
+)
+
+// SampleRecord - a record of a set sample or of a sindex query, with its bins converted to values which can be
+// encoded as JSON
+type SampleRecord struct {
+	Digest     string                 `json:"digest"`
+	Key        interface{}            `json:"key,omitempty"` // only if the key was stored with the record
+	Node       string                 `json:"node"`
+	Generation uint32                 `json:"generation"`
+	TTL        uint32                 `json:"ttl"` // seconds until the record expires
+	Bins       map[string]interface{} `json:"bins"`
+}
+
+// SampleSet - scan the set for the first limit records and return them; samplePct < 100 scans a random range
+// of that percent of the partitions, so that the sample does not always start with the same records.
+// Only the records matching the filter, if not nil, are returned, and only the bins if any are given.
+func (c *Cluster) SampleSet(ctx context.Context, namespace, set string, limit int, samplePct float64, filter *RecordFilter, bins ...string) ([]SampleRecord, error) {
+	if limit <= 0 || limit > SetSampleMaxLimit {
+		return nil, common.Errorf(common.KindInvalid, "The limit must be between 1 and %d", SetSampleMaxLimit)
+	}
+
+	rs, err := c.scanSample(ctx, namespace, set, limit, samplePct, filter, true, bins...)
+	if err != nil {
+		return nil, err
+	}
+	return readSampleRecords(ctx, rs, limit)
+}
+
+// scanSample - start a scan of the set for at most limit records, of samplePct of the partitions
+// starting at a random one, matching the filter if not nil; only the metadata of the records is read
+// unless includeBinData
+func (c *Cluster) scanSample(ctx context.Context, namespace, set string, limit int, samplePct float64, filter *RecordFilter, includeBinData bool, bins ...string) (*as.Recordset, error) {
+	if samplePct <= 0 || samplePct > 100 {
+		return nil, common.Errorf(common.KindInvalid, "The sample percentage must be greater than 0 and at most 100")
+	}
+	if err := c.checkNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	client := c.origClient()
+	if client == nil {
+		return nil, common.Errorf(common.KindUnavailable, "Cluster %s has been decommissioned", c.ID())
+	}
+
+	policy := as.NewScanPolicy()
+	policy.MaxRecords = int64(limit)
+	policy.IncludeBinData = includeBinData
+	policy.TotalTimeout = 30 * time.Second
+	if deadline, ok := ctx.Deadline(); ok {
+		policy.TotalTimeout = time.Until(deadline)
+	}
+	if err := c.applyFilter(&policy.BasePolicy, filter); err != nil {
+		return nil, err
+	}
+
+	count := int(math.Ceil(_partitionCount * samplePct / 100))
+	begin := 0
+	if count < _partitionCount {
+		begin = rand.Intn(_partitionCount - count + 1)
+	}
+
+	rs, err := client.ScanPartitions(policy, as.NewPartitionFilterByRange(begin, count), namespace, set, bins...)
+	if err != nil {
+		return nil, err
+	}
+	return rs, nil
+}
+
+// checkNamespace - fail if the namespace is not one of the cluster
+func (c *Cluster) checkNamespace(namespace string) error {
+	for _, ns := range c.NamespaceList() {
+		if ns == namespace {
+			return nil
+		}
+	}
+	return common.Errorf(common.KindNamespaceNotFound, "Namespace %s not found", namespace)
+}
+
+// readRecords - pass the first limit records of the scan or the query to f, all if limit <= 0, and close it;
+// stops at the first error of f
+func readRecords(ctx context.Context, rs *as.Recordset, limit int, f func(*as.Record) error) error {
+	defer rs.Close()
+
+	for read := 0; limit <= 0 || read < limit; read++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case r, open := <-rs.Results():
+			if !open {
+				return nil
+			}
+			if r.Err != nil {
+				return r.Err
+			}
+			if err := f(r.Record); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// readSampleRecords - read the first limit records of the scan or the query, and close it
+func readSampleRecords(ctx context.Context, rs *as.Recordset, limit int) ([]SampleRecord, error) {
+	res := []SampleRecord{}
+	err := readRecords(ctx, rs, limit, func(r *as.Record) error {
+		res = append(res, sampleRecord(r))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func sampleRecord(r *as.Record) SampleRecord {
+	res := SampleRecord{
+		Digest:     hex.EncodeToString(r.Key.Digest()),
+		Generation: r.Generation,
+		TTL:        r.Expiration,
+		Bins:       make(map[string]interface{}, len(r.Bins)),
+	}
+	if v := r.Key.Value(); v != nil {
+		res.Key = jsonValue(v.GetObject())
+	}
+	if r.Node != nil {
+		res.Node = r.Node.GetHost().String()
+	}
+	for name, v := range r.Bins {
+		res.Bins[name] = jsonValue(v)
+	}
+	return res
+}
+
+// jsonValue - the value of a bin as one which can be encoded as JSON: the keys of the maps are formatted
+// as strings, and the geo JSON values are returned as their JSON string
+func jsonValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		res := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			res[fmt.Sprint(jsonValue(k))] = jsonValue(e)
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(v))
+		for i, e := range v {
+			res[i] = jsonValue(e)
+		}
+		return res
+	case []as.MapPair:
+		res := make(map[string]interface{}, len(v))
+		for _, p := range v {
+			res[fmt.Sprint(jsonValue(p.Key))] = jsonValue(p.Value)
+		}
+		return res
+	case as.GeoJSONValue:
+		return string(v)
+	case as.HLLValue:
+		return []byte(v)
+	case float64:
+		// JSON has no infinities or NaN
+		if math.IsInf(v, 0) || math.IsNaN(v) {
+			return fmt.Sprint(v)
+		}
+		return v
+	}
+	return v
+}
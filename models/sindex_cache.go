@@ -0,0 +1,82 @@
+package models
+
+import (
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// the sindex list of a node is requested again when its sindex generation changes; it is also requested after
+// this long, for the indexes created by other clients while the cluster did not change
+const _sindexListMaxAge = 10 * time.Minute
+
+// sindexList is the parsed sindex list of a node; it is replaced, never modified
+type sindexList struct {
+	fetched    time.Time
+	generation int64                  // the sindex generation of the node when the list was requested
+	indexes    map[string]common.Info // map[indexname]info
+}
+
+// sindexGeneration - the generation of the indexes of the node; it changes when an index is created or dropped
+// through AMC, when the stats of an index cannot be read and when the cluster generation of the node changes,
+// since the indexes are synchronized to the nodes joining the cluster
+func (n *Node) sindexGeneration() int64 {
+	return atomic.LoadInt64(&n.sindexGen)
+}
+
+// updateSindexGeneration - bump the sindex generation if the cluster generation of the node has changed
+func (n *Node) updateSindexGeneration(info common.Info) {
+	last := n.InfoAttr("cluster-generation")
+	if gen, exists := info["cluster-generation"]; exists && last != common.NOT_AVAILABLE && gen != last {
+		atomic.AddInt64(&n.sindexGen, 1)
+	}
+}
+
+func (n *Node) cachedSindexList() *sindexList {
+	list, _ := n.sindexList.Get().(*sindexList)
+	return list
+}
+
+// sindexListStale - check whether the sindex list needs to be requested in the next update
+func (n *Node) sindexListStale() bool {
+	list := n.cachedSindexList()
+	return list == nil || list.generation != n.sindexGeneration() || time.Since(list.fetched) > _sindexListMaxAge
+}
+
+// setSindexList - cache the sindex list requested at the given sindex generation; a list requested before an
+// invalidation stays stale
+func (n *Node) setSindexList(raw string, generation int64) {
+	n.sindexList.Set(&sindexList{
+		fetched:    time.Now(),
+		generation: generation,
+		indexes:    common.Info{"sindex": raw}.ToInfoMap("sindex", "indexname", ":"),
+	})
+}
+
+// invalidateSindexList - request the sindex list again in the next update
+func (n *Node) invalidateSindexList() {
+	atomic.AddInt64(&n.sindexGen, 1)
+}
+
+// invalidateSindexLists - request the sindex lists of all nodes again after an index was created or dropped
+func (c *Cluster) invalidateSindexLists() {
+	for _, node := range c.Nodes() {
+		node.invalidateSindexList()
+	}
+}
+
+// sindexes - get the cached sindexes of the node; the result must not be modified
+func (n *Node) sindexes() map[string]common.Info {
+	if list := n.cachedSindexList(); list != nil {
+		return list.indexes
+	}
+	return map[string]common.Info{}
+}
+
+// sindexStatFailed - check whether the stats of an index could not be read, usually because it was dropped
+func sindexStatFailed(res string) bool {
+	res = strings.ToUpper(res)
+	return strings.HasPrefix(res, "FAIL") || strings.HasPrefix(res, "ERROR")
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"github.com/aerospike-community/amc/common"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Sindex cache", func() {
+	var n *Node
+
+	BeforeEach(func() {
+		n = &Node{}
+	})
+
+	It("is stale until the list is set", func() {
+		Expect(n.sindexListStale()).To(BeTrue())
+		Expect(n.sindexes()).To(BeEmpty())
+
+		n.setSindexList("ns=test:set=demo:indexname=idx_a:bin=a:type=NUMERIC", n.sindexGeneration())
+		Expect(n.sindexListStale()).To(BeFalse())
+		Expect(n.sindexes()).To(HaveKey("idx_a"))
+	})
+
+	It("is stale after an invalidation", func() {
+		n.setSindexList("ns=test:set=demo:indexname=idx_a:bin=a:type=NUMERIC", n.sindexGeneration())
+		n.invalidateSindexList()
+		Expect(n.sindexListStale()).To(BeTrue())
+
+		// the cached indexes are still served until the list is requested again
+		Expect(n.sindexes()).To(HaveKey("idx_a"))
+	})
+
+	It("stays stale when the list was requested before an invalidation", func() {
+		gen := n.sindexGeneration()
+		n.invalidateSindexList()
+		n.setSindexList("", gen)
+		Expect(n.sindexListStale()).To(BeTrue())
+	})
+
+	It("is stale when the cluster generation of the node changes", func() {
+		n.latestInfo.SetInfo(common.Info{"cluster-generation": "4"})
+		n.setSindexList("", n.sindexGeneration())
+
+		n.updateSindexGeneration(common.Info{"cluster-generation": "4"})
+		Expect(n.sindexListStale()).To(BeFalse())
+
+		n.updateSindexGeneration(common.Info{"cluster-generation": "5"})
+		Expect(n.sindexListStale()).To(BeTrue())
+	})
+
+	It("is not invalidated by the first cluster generation of the node", func() {
+		n.setSindexList("", n.sindexGeneration())
+		n.updateSindexGeneration(common.Info{"cluster-generation": "1"})
+		Expect(n.sindexListStale()).To(BeFalse())
+	})
+})
@@ -0,0 +1,124 @@
+package models
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	as "github.com/aerospike/aerospike-client-go/v5"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// SindexQuery - a query on a secondary index: the records whose indexed bin is equal to Value, or for
+// the numeric indexes between Begin and End inclusive, which also match the Filter if not nil
+type SindexQuery struct {
+	Value      string
+	Begin, End *int64
+	Filter     *RecordFilter
+	Limit      int
+	Timeout    time.Duration
+	Bins       []string // the bins returned; all if empty
+}
+
+// the collection types of the indexes by their indextype
+var _indexCollectionTypes = map[string]as.IndexCollectionType{
+	"":          as.ICT_DEFAULT,
+	"none":      as.ICT_DEFAULT,
+	"default":   as.ICT_DEFAULT,
+	"list":      as.ICT_LIST,
+	"mapkeys":   as.ICT_MAPKEYS,
+	"mapvalues": as.ICT_MAPVALUES,
+}
+
+// QuerySindex - run the query on the index and return the first Limit matching records; the bin, the type
+// and the collection type of the index are read from the sindex list collected from the nodes
+func (c *Cluster) QuerySindex(ctx context.Context, namespace, indexName string, q SindexQuery) ([]SampleRecord, error) {
+	if q.Limit <= 0 || q.Limit > SetSampleMaxLimit {
+		return nil, common.Errorf(common.KindInvalid, "The limit must be between 1 and %d", SetSampleMaxLimit)
+	}
+
+	index, exists := c.NamespaceIndexInfo(namespace)[indexName]
+	if !exists {
+		return nil, common.Errorf(common.KindNotFound, "Index %s not found", indexName)
+	}
+
+	// servers before 6.0 list the bins of the index and the upper case type
+	bin := index.TryString("bin", index.TryString("bins", ""))
+	indexType := strings.ToLower(index.TryString("type", ""))
+	collectionType, exists := _indexCollectionTypes[strings.ToLower(index.TryString("indextype", ""))]
+	if bin == "" || !exists {
+		return nil, common.Errorf(common.KindInvalid, "Index %s is not supported", indexName)
+	}
+
+	var filter *as.Filter
+	switch {
+	case q.Begin != nil || q.End != nil:
+		if q.Begin == nil || q.End == nil || q.Value != "" {
+			return nil, common.Errorf(common.KindInvalid, "A range query needs begin and end, and no value")
+		}
+		if indexType != "numeric" {
+			return nil, common.Errorf(common.KindInvalid, "Range queries are only supported on the numeric indexes")
+		}
+		if collectionType == as.ICT_DEFAULT {
+			filter = as.NewRangeFilter(bin, *q.Begin, *q.End)
+		} else {
+			filter = as.NewContainsRangeFilter(bin, collectionType, *q.Begin, *q.End)
+		}
+	case indexType == "numeric":
+		value, err := strconv.ParseInt(q.Value, 10, 64)
+		if err != nil {
+			return nil, common.Errorf(common.KindInvalid, "The value of a query on a numeric index must be an integer")
+		}
+		filter = equalFilter(bin, collectionType, value)
+	case indexType == "string":
+		filter = equalFilter(bin, collectionType, q.Value)
+	default:
+		return nil, common.Errorf(common.KindInvalid, "Queries on %s indexes are not supported", indexType)
+	}
+
+	client := c.origClient()
+	if client == nil {
+		return nil, common.Errorf(common.KindUnavailable, "Cluster %s has been decommissioned", c.ID())
+	}
+
+	// the indexes on all the records of the namespace are listed with set NULL
+	set := index.TryString("set", "")
+	if set == "NULL" {
+		set = ""
+	}
+
+	stmt := as.NewStatement(namespace, set, q.Bins...)
+	if err := stmt.SetFilter(filter); err != nil {
+		return nil, err
+	}
+
+	policy := as.NewQueryPolicy()
+	policy.MaxRecords = int64(q.Limit)
+	policy.TotalTimeout = q.Timeout
+	if deadline, ok := ctx.Deadline(); ok && (q.Timeout <= 0 || time.Until(deadline) < q.Timeout) {
+		policy.TotalTimeout = time.Until(deadline)
+	}
+	if policy.TotalTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, policy.TotalTimeout)
+		defer cancel()
+	}
+	if err := c.applyFilter(&policy.BasePolicy, q.Filter); err != nil {
+		return nil, err
+	}
+
+	rs, err := client.Query(policy, stmt)
+	if err != nil {
+		return nil, err
+	}
+	return readSampleRecords(ctx, rs, q.Limit)
+}
+
+func equalFilter(bin string, collectionType as.IndexCollectionType, value interface{}) *as.Filter {
+	if collectionType == as.ICT_DEFAULT {
+		return as.NewEqualFilter(bin, value)
+	}
+	return as.NewContainsFilter(bin, collectionType, value)
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"sort"
+	"time"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// the node stats included in the snapshots
+var _snapshotNodeStats = []string{"objects", "tombstones", "client_connections", "uptime", "cluster_size", "migrate_partitions_remaining"}
+
+// SnapshotNode is the state of a node in a cluster snapshot
+type SnapshotNode struct {
+	ID           string       `json:"id"`
+	Address      string       `json:"address"`
+	Status       string       `json:"status"`
+	StatusReason string       `json:"status_reason,omitempty"`
+	Build        string       `json:"build"`
+	Memory       common.Stats `json:"memory"`
+	Disk         common.Stats `json:"disk"`
+	Stats        common.Stats `json:"stats"`
+}
+
+// ClusterSnapshot is the state of the cluster captured at the end of an update cycle,
+// so that the data of the nodes, namespaces, throughput and alerts belong to the same cycle
+type ClusterSnapshot struct {
+	ClusterID     string `json:"cluster_id"`
+	ClusterStatus string `json:"cluster_status"`
+	Timestamp     int64  `json:"timestamp"`
+
+	Nodes         []SnapshotNode                                 `json:"nodes"`
+	Namespaces    map[string]common.Stats                        `json:"namespaces"`
+	Throughput    map[string]map[string]*common.SinglePointValue `json:"throughput"`
+	Alerts        []*common.Alert                                `json:"alerts"`
+	RedAlertCount int                                            `json:"red_alert_count"`
+}
+
+// Snapshot - get the state of the cluster captured at the end of the last update;
+// nil if the cluster has not been updated yet
+func (c *Cluster) Snapshot() *ClusterSnapshot {
+	snapshot, _ := c.snapshot.Get().(*ClusterSnapshot)
+	return snapshot
+}
+
+// takeSnapshot - capture the state of the cluster after an update.
+// Only called from update, so the nodes and namespaces do not change while they are read.
+func (c *Cluster) takeSnapshot() {
+	res := &ClusterSnapshot{
+		ClusterID:     c.ID(),
+		ClusterStatus: c.Status(),
+		Timestamp:     time.Now().Unix(),
+		Nodes:         []SnapshotNode{},
+		Namespaces:    c.NamespaceInfo(c.NamespaceList()),
+		Throughput:    c.LatestThroughput(),
+		Alerts:        c.AlertsFrom(0),
+		RedAlertCount: c.RedAlertCount(),
+	}
+
+	for _, node := range c.Nodes() {
+		res.Nodes = append(res.Nodes, SnapshotNode{
+			ID:           node.ID(),
+			Address:      node.Address(),
+			Status:       string(node.Status()),
+			StatusReason: node.StatusReason(),
+			Build:        node.Build(),
+			Memory:       node.Memory(),
+			Disk:         node.Disk(),
+			Stats:        node.AnyAttrs(_snapshotNodeStats...),
+		})
+	}
+	sort.Slice(res.Nodes, func(i, j int) bool { return res.Nodes[i].Address < res.Nodes[j].Address })
+
+	c.snapshot.Set(res)
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+)
+
+// StabilityCheck - result of the cluster stability check
+type StabilityCheck struct {
+	Stable  bool     `json:"stable"`
+	Reasons []string `json:"reasons"`
+
+	ClusterKeys map[string][]string `json:"cluster_keys"`
+	ClusterSize map[string]int64    `json:"cluster_size"`
+	Migrations  map[string]int64    `json:"migrations"`
+}
+
+// CheckStability - verify all nodes are up, share the same cluster key and size,
+// and have no pending migrations. If expectedSize is 0, the number of known nodes is expected.
+func (c *Cluster) CheckStability(expectedSize int) *StabilityCheck {
+	res := &StabilityCheck{
+		Reasons:     []string{},
+		ClusterKeys: map[string][]string{},
+		ClusterSize: map[string]int64{},
+		Migrations:  map[string]int64{},
+	}
+
+	nodes := c.Nodes()
+	if expectedSize <= 0 {
+		expectedSize = len(nodes)
+	}
+
+	for _, node := range nodes {
+		addr := node.Address()
+		if node.Status() != nodeStatus.On {
+			res.Reasons = append(res.Reasons, fmt.Sprintf("Node %s is not active", addr))
+			continue
+		}
+
+		key := fmt.Sprintf("%v", node.StatsAttr("cluster_key"))
+		res.ClusterKeys[key] = append(res.ClusterKeys[key], addr)
+
+		size := node.StatsAttrs("cluster_size").TryInt("cluster_size", 0)
+		res.ClusterSize[addr] = size
+		if size != int64(expectedSize) {
+			res.Reasons = append(res.Reasons, fmt.Sprintf("Node %s reports cluster size %d, expected %d", addr, size, expectedSize))
+		}
+
+		if integrity := node.StatsAttr("cluster_integrity"); integrity != nil && fmt.Sprintf("%v", integrity) != "true" {
+			res.Reasons = append(res.Reasons, fmt.Sprintf("Node %s reports cluster integrity fault", addr))
+		}
+
+		migrations := node.StatsAttrs("migrate_partitions_remaining").TryInt("migrate_partitions_remaining", 0)
+		res.Migrations[addr] = migrations
+		if migrations > 0 {
+			res.Reasons = append(res.Reasons, fmt.Sprintf("Node %s has %d partitions remaining to migrate", addr, migrations))
+		}
+	}
+
+	if len(nodes) != expectedSize {
+		res.Reasons = append(res.Reasons, fmt.Sprintf("%d nodes are visible, expected %d", len(nodes), expectedSize))
+	}
+
+	if len(res.ClusterKeys) > 1 {
+		keys := make([]string, 0, len(res.ClusterKeys))
+		for k := range res.ClusterKeys {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		res.Reasons = append(res.Reasons, fmt.Sprintf("Nodes do not share the same cluster key: %v", keys))
+	}
+
+	res.Stable = len(res.Reasons) == 0
+	return res
+}
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// Optional stat groups; the node and namespace stats are always collected
+const (
+	StatGroupSets    = "sets"
+	StatGroupSindex  = "sindex"
+	StatGroupLatency = "latency"
+	StatGroupXDR     = "xdr"
+)
+
+// DefaultStatProfile collects every stat group
+const DefaultStatProfile = "full"
+
+var _statGroups = []string{StatGroupSets, StatGroupSindex, StatGroupLatency, StatGroupXDR}
+
+// the stat groups collected by the predefined profiles
+var _statProfiles = map[string][]string{
+	"full":    _statGroups,
+	"basic":   {StatGroupLatency, StatGroupXDR},
+	"minimal": {},
+}
+
+// StatProfile determines which of the optional stat groups are collected for a cluster
+type StatProfile struct {
+	Name   string   `json:"name"`
+	Groups []string `json:"groups"`
+}
+
+// ParseStatProfile - parse a profile name, or a comma delimited list of the stat groups to collect
+func ParseStatProfile(profile string) (*StatProfile, error) {
+	profile = strings.TrimSpace(profile)
+	if profile == "" {
+		profile = DefaultStatProfile
+	}
+
+	if groups, exists := _statProfiles[profile]; exists {
+		return &StatProfile{Name: profile, Groups: groups}, nil
+	}
+
+	groups := []string{}
+	for _, group := range strings.Split(profile, ",") {
+		group = strings.TrimSpace(group)
+		if group == "" {
+			continue
+		}
+
+		valid := false
+		for _, g := range _statGroups {
+			valid = valid || g == group
+		}
+		if !valid {
+			return nil, common.Errorf(common.KindInvalid, "Invalid stat profile or group `%s`; valid profiles are full, basic and minimal, valid groups are %s", group, strings.Join(_statGroups, ", "))
+		}
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+
+	return &StatProfile{Name: strings.Join(groups, ","), Groups: groups}, nil
+}
+
+// Collects - check whether the stat group is collected
+func (p *StatProfile) Collects(group string) bool {
+	for _, g := range p.Groups {
+		if g == group {
+			return true
+		}
+	}
+	return false
+}
+
+// StatProfile - get the stat profile of the cluster
+func (c *Cluster) StatProfile() *StatProfile {
+	if profile, _ := c.statProfile.Get().(*StatProfile); profile != nil {
+		return profile
+	}
+
+	profile, _ := ParseStatProfile(DefaultStatProfile)
+	return profile
+}
+
+// SetStatProfile - change the stat groups collected for the cluster from the next update;
+// the stats of the groups no longer collected are dropped
+func (c *Cluster) SetStatProfile(profile *StatProfile) {
+	c.statProfile.Set(profile)
+}
+
+// collectsStats - check whether the stat group is collected for the cluster
+func (c *Cluster) collectsStats(group string) bool {
+	return c.StatProfile().Collects(group)
+}
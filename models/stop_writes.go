@@ -0,0 +1,185 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+// ThresholdPrediction - when a usage is predicted to reach a threshold
+type ThresholdPrediction struct {
+	Threshold     string   `json:"threshold"`
+	ThresholdPct  float64  `json:"threshold_pct"`
+	ThresholdByte int64    `json:"threshold_bytes"`
+	Breached      bool     `json:"breached"`
+	SecondsLeft   *int64   `json:"seconds_left"`
+	At            *int64   `json:"at"`
+	GrowthRate    *float64 `json:"growth_bytes_per_sec"`
+}
+
+// StorageUsagePrediction - the usage of a storage (memory or disk) and its predicted thresholds
+type StorageUsagePrediction struct {
+	UsedBytes   int64                  `json:"used_bytes"`
+	TotalBytes  int64                  `json:"total_bytes"`
+	UsedPct     float64                `json:"used_pct"`
+	Predictions []*ThresholdPrediction `json:"predictions"`
+}
+
+// StopWritesPrediction - the predictions of a namespace on a node
+type StopWritesPrediction struct {
+	Memory *StorageUsagePrediction `json:"memory"`
+	Disk   *StorageUsagePrediction `json:"disk,omitempty"`
+
+	// the earliest predicted stop-writes; nil if usage is not growing
+	StopWritesAt *int64 `json:"stop_writes_at"`
+	StopWrites   bool   `json:"stop_writes"`
+}
+
+// growthRate - least squares slope of the values in units per second; nil if there are not enough samples
+func (ns *Namespace) growthRate(stat string, since time.Time) *float64 {
+	bucket := ns.gaugeHistory[stat]
+	if bucket == nil {
+		return nil
+	}
+
+	var n, sumX, sumY, sumXY, sumXX float64
+	var x0 int64
+	for _, v := range bucket.ValuesSince(since) {
+		ts := v.Timestamp(1)
+		val := v.Value(nil)
+		if ts == nil || val == nil {
+			continue
+		}
+
+		// shift the timestamps to keep the sums small
+		if n == 0 {
+			x0 = *ts
+		}
+
+		x := float64(*ts - x0)
+		n++
+		sumX += x
+		sumY += *val
+		sumXY += x * *val
+		sumXX += x * x
+	}
+
+	denom := n*sumXX - sumX*sumX
+	if n < 2 || denom == 0 {
+		return nil
+	}
+
+	slope := (n*sumXY - sumX*sumY) / denom
+	return &slope
+}
+
+func predictThreshold(name string, pct float64, used, total int64, rate *float64, now time.Time) *ThresholdPrediction {
+	p := &ThresholdPrediction{
+		Threshold:     name,
+		ThresholdPct:  pct,
+		ThresholdByte: int64(float64(total) * pct / 100),
+		GrowthRate:    rate,
+	}
+
+	if used >= p.ThresholdByte {
+		p.Breached = true
+		zero := int64(0)
+		p.SecondsLeft = &zero
+		at := now.Unix() * 1000
+		p.At = &at
+		return p
+	}
+
+	if rate != nil && *rate > 0 {
+		secs := int64(math.Ceil(float64(p.ThresholdByte-used) / *rate))
+		p.SecondsLeft = &secs
+		at := now.Add(time.Duration(secs)*time.Second).Unix() * 1000
+		p.At = &at
+	}
+
+	return p
+}
+
+// PredictStopWrites - predict when the namespace on the node reaches its eviction (high water mark)
+// and stop-writes thresholds, using the growth rate of the usage over the window
+func (ns *Namespace) PredictStopWrites(window time.Duration) *StopWritesPrediction {
+	stats := ns.latestStats.Clone()
+	now := ns.ServerTime()
+	if now.IsZero() {
+		now = time.Now()
+	}
+	since := now.Add(-window)
+
+	res := &StopWritesPrediction{}
+
+	usedMem := ns.calcStats.TryInt("used-bytes-memory", 0)
+	totalMem := ns.calcStats.TryInt("total-bytes-memory", 0)
+	if totalMem > 0 {
+		rate := ns.growthRate("used-bytes-memory", since)
+		res.Memory = &StorageUsagePrediction{
+			UsedBytes:  usedMem,
+			TotalBytes: totalMem,
+			UsedPct:    float64(usedMem) * 100 / float64(totalMem),
+		}
+
+		if hwm := stats.TryFloat("high-water-memory-pct", 0); hwm > 0 {
+			res.Memory.Predictions = append(res.Memory.Predictions, predictThreshold("high-water-memory-pct", hwm, usedMem, totalMem, rate, now))
+		}
+
+		// stop-writes-pct defaults to 90
+		stopWrites := predictThreshold("stop-writes-pct", stats.TryFloat("stop-writes-pct", 90), usedMem, totalMem, rate, now)
+		res.Memory.Predictions = append(res.Memory.Predictions, stopWrites)
+		res.updateStopWrites(stopWrites)
+	}
+
+	if totalDisk := ns.calcStats.TryInt("total-bytes-disk", 0); totalDisk > 0 {
+		usedDisk := ns.calcStats.TryInt("used-bytes-disk", 0)
+		rate := ns.growthRate("used-bytes-disk", since)
+		res.Disk = &StorageUsagePrediction{
+			UsedBytes:  usedDisk,
+			TotalBytes: totalDisk,
+			UsedPct:    float64(usedDisk) * 100 / float64(totalDisk),
+		}
+
+		if hwm := stats.TryFloat("high-water-disk-pct", 0); hwm > 0 {
+			res.Disk.Predictions = append(res.Disk.Predictions, predictThreshold("high-water-disk-pct", hwm, usedDisk, totalDisk, rate, now))
+		}
+
+		// writes stop when the available space drops below min-avail-pct (5 by default).
+		// Available space also depends on defragmentation, so this is a best case estimate.
+		minAvail := stats.TryFloat("storage-engine.min-avail-pct", 5)
+		stopWrites := predictThreshold("min-avail-pct", 100-minAvail, usedDisk, totalDisk, rate, now)
+		res.Disk.Predictions = append(res.Disk.Predictions, stopWrites)
+		res.updateStopWrites(stopWrites)
+	}
+
+	if stats.TryString("stop_writes", "false") == "true" {
+		res.StopWrites = true
+	}
+
+	return res
+}
+
+func (p *StopWritesPrediction) updateStopWrites(t *ThresholdPrediction) {
+	if t.Breached {
+		p.StopWrites = true
+	}
+
+	if t.At != nil && (p.StopWritesAt == nil || *t.At < *p.StopWritesAt) {
+		p.StopWritesAt = t.At
+	}
+}
+
+// PredictStopWrites - predict stop-writes for every namespace on every node; namespace => node => prediction
+func (c *Cluster) PredictStopWrites(window time.Duration) map[string]map[string]*StopWritesPrediction {
+	res := map[string]map[string]*StopWritesPrediction{}
+	for _, node := range c.Nodes() {
+		for name, ns := range node.Namespaces() {
+			if res[name] == nil {
+				res[name] = map[string]*StopWritesPrediction{}
+			}
+			res[name][node.Address()] = ns.PredictStopWrites(window)
+		}
+	}
+
+	return res
+}
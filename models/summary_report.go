@@ -0,0 +1,326 @@
+package models
+
+import (
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/aerospike-community/amc/common"
+	"github.com/aerospike-community/amc/mailer"
+)
+
+// the throughput stats whose peaks are reported, and their names in the reports
+var _summaryThroughputStats = []struct{ stat, name string }{
+	{"stat_read_reqs", "Reads"},
+	{"stat_write_reqs", "Writes"},
+	{"batch_read_reqs", "Batch reads"},
+	{"query_reqs", "Queries"},
+	{"scan_reqs", "Scans"},
+	{"udf_reqs", "UDFs"},
+}
+
+// the number of the alerts listed in a summary report; they are all counted
+const _summaryReportAlerts = 50
+
+// SummaryPeak - the highest throughput of the cluster over the period of a summary report
+type SummaryPeak struct {
+	Name string    `json:"name"`
+	TPS  float64   `json:"tps"`
+	At   time.Time `json:"at"`
+}
+
+// SummaryNamespace - the growth and the capacity headroom of a namespace over the period of a summary report;
+// the headroom is the difference between the thresholds and the usage of the fullest node, in percent
+type SummaryNamespace struct {
+	Name             string  `json:"name"`
+	ObjectsStart     int64   `json:"objects_start"`
+	ObjectsEnd       int64   `json:"objects_end"`
+	ObjectGrowth     int64   `json:"object_growth"`
+	ObjectGrowthPct  float64 `json:"object_growth_pct"`
+	MemoryUsedPct    float64 `json:"memory_used_pct"`
+	DiskUsedPct      float64 `json:"disk_used_pct"`
+	MemoryHWMRoomPct float64 `json:"memory_hwm_headroom_pct"`
+	StopWritesRoom   float64 `json:"stop_writes_headroom_pct"`
+	DiskHWMRoomPct   float64 `json:"disk_hwm_headroom_pct"`
+}
+
+// SummaryReport - the summary of a cluster over a period: the growth of the objects, the peak throughput,
+// the alerts raised and the capacity headroom
+type SummaryReport struct {
+	ClusterID   string             `json:"cluster_id"`
+	Cluster     string             `json:"cluster"`
+	Period      string             `json:"period,omitempty"` // daily or weekly; empty if the reports are not sent
+	From        time.Time          `json:"from"`
+	To          time.Time          `json:"to"`
+	Namespaces  []SummaryNamespace `json:"namespaces"`
+	PeakTPS     []SummaryPeak      `json:"peak_tps"`
+	AlertCounts map[string]int     `json:"alert_counts"`
+	Alerts      []ReportAlert      `json:"alerts"`
+}
+
+// summaryPeriod collects the stats of the current period of the summary reports on every update,
+// since the history of the cluster is shorter than the period
+type summaryPeriod struct {
+	mutex sync.Mutex
+
+	start   time.Time
+	due     time.Time // zero until the reports are scheduled
+	period  string    // the period the report is due for
+	objects map[string]int64
+	peaks   map[string]SummaryPeak
+}
+
+// SetReportSchedule - set the period of the summary reports of the cluster, overriding the one of the mailer:
+// daily, weekly or off; empty for the one of the mailer
+func (c *Cluster) SetReportSchedule(period string) {
+	c.reportSchedule.Set(period)
+}
+
+// reportScheduleOf - the schedule of the summary reports of the cluster; nil if they are not sent
+func (c *Cluster) reportScheduleOf() *common.ReportSchedule {
+	period, _ := c.reportSchedule.Get().(string)
+	return c.observer.Config().ReportSchedule(period)
+}
+
+// namespaceObjects - the master objects of the namespaces, summed over the nodes
+func (c *Cluster) namespaceObjects() map[string]int64 {
+	res := map[string]int64{}
+	for _, node := range c.Nodes() {
+		for name, ns := range node.Namespaces() {
+			res[name] += ns.StatsAttrs("master-objects").TryInt("master-objects", 0)
+		}
+	}
+	return res
+}
+
+// recordSummaryStats - record the peak throughput of the cluster for the summary report; called after every update
+func (c *Cluster) recordSummaryStats() {
+	throughput := c.LatestThroughput()
+
+	p := c.summary
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.start.IsZero() {
+		p.start = time.Now()
+		p.objects = c.namespaceObjects()
+		p.peaks = map[string]SummaryPeak{}
+	}
+
+	zero := float64(0)
+	for _, s := range _summaryThroughputStats {
+		total := float64(0)
+		for _, v := range throughput[s.stat] {
+			total += *v.Value(&zero)
+		}
+		if total > p.peaks[s.stat].TPS {
+			p.peaks[s.stat] = SummaryPeak{Name: s.name, TPS: total, At: time.Now()}
+		}
+	}
+}
+
+// SummaryReport - the summary report of the current period, up to now
+func (c *Cluster) SummaryReport() *SummaryReport {
+	p := c.summary
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	report := c.summaryReport(p, time.Now())
+	if schedule := c.reportScheduleOf(); schedule != nil {
+		report.Period = schedule.Period
+	}
+	return report
+}
+
+// summaryReport - build the report of the period up to the time; the period must be locked
+func (c *Cluster) summaryReport(p *summaryPeriod, to time.Time) *SummaryReport {
+	report := &SummaryReport{
+		ClusterID:   c.ID(),
+		Cluster:     c.ID(),
+		From:        p.start,
+		To:          to,
+		Namespaces:  []SummaryNamespace{},
+		PeakTPS:     []SummaryPeak{},
+		AlertCounts: map[string]int{},
+		Alerts:      []ReportAlert{},
+	}
+	if alias := c.Alias(); alias != nil && *alias != "" {
+		report.Cluster = *alias
+	}
+	if report.From.IsZero() {
+		report.From = to
+	}
+
+	for _, s := range _summaryThroughputStats {
+		if peak, exists := p.peaks[s.stat]; exists {
+			report.PeakTPS = append(report.PeakTPS, peak)
+		} else {
+			report.PeakTPS = append(report.PeakTPS, SummaryPeak{Name: s.name})
+		}
+	}
+
+	nodes := c.Nodes()
+	objects := c.namespaceObjects()
+	names := make([]string, 0, len(objects))
+	for name := range objects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		ns := SummaryNamespace{Name: name, ObjectsStart: p.objects[name], ObjectsEnd: objects[name]}
+		ns.ObjectGrowth = ns.ObjectsEnd - ns.ObjectsStart
+		if ns.ObjectsStart > 0 {
+			ns.ObjectGrowthPct = common.Round(float64(ns.ObjectGrowth)*100/float64(ns.ObjectsStart), .5, 2)
+		}
+
+		// the headroom is that of the fullest node
+		ns.MemoryHWMRoomPct, ns.StopWritesRoom, ns.DiskHWMRoomPct = 100, 100, 100
+		for _, node := range nodes {
+			nodeNs := node.NamespaceByName(name)
+			if nodeNs == nil {
+				continue
+			}
+
+			memory, disk, config := nodeNs.Memory(), nodeNs.Disk(), nodeNs.ConfigAttrs()
+			memPct := usedPct(memory.TryInt("used-bytes-memory", 0), memory.TryInt("total-bytes-memory", 0))
+			diskPct := usedPct(disk.TryInt("used-bytes-disk", 0), disk.TryInt("total-bytes-disk", 0))
+			if memPct > ns.MemoryUsedPct {
+				ns.MemoryUsedPct = memPct
+			}
+			if diskPct > ns.DiskUsedPct {
+				ns.DiskUsedPct = diskPct
+			}
+
+			for _, room := range []struct {
+				headroom  *float64
+				threshold string
+				used      float64
+			}{
+				{&ns.MemoryHWMRoomPct, "high-water-memory-pct", memPct},
+				{&ns.StopWritesRoom, "stop-writes-pct", memPct},
+				{&ns.DiskHWMRoomPct, "high-water-disk-pct", diskPct},
+			} {
+				if threshold := config.TryFloat(room.threshold, 0); threshold > 0 && threshold-room.used < *room.headroom {
+					*room.headroom = common.Round(threshold-room.used, .5, 2)
+				}
+			}
+		}
+		report.Namespaces = append(report.Namespaces, ns)
+	}
+
+	addrs := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		addrs = append(addrs, node.Address())
+	}
+	alerts, err := common.AlertsCreatedSince(addrs, report.From)
+	if err != nil {
+		log.Errorf("Error reading the alerts of cluster %s for its summary report: %s", c.ID(), err.Error())
+	}
+	for _, alert := range alerts {
+		report.AlertCounts[string(alert.Status)]++
+	}
+	// the latest alerts are listed
+	if len(alerts) > _summaryReportAlerts {
+		alerts = alerts[len(alerts)-_summaryReportAlerts:]
+	}
+	for i := len(alerts) - 1; i >= 0; i-- {
+		alert := alerts[i]
+		report.Alerts = append(report.Alerts, ReportAlert{
+			Status:      string(alert.Status),
+			Node:        alert.NodeAddress,
+			Namespace:   alert.Namespace.String,
+			Description: alert.Desc,
+			Created:     alert.Created,
+			LastOccured: alert.LastOccured,
+			Resolved:    alert.Resolved.Valid(),
+		})
+	}
+
+	return report
+}
+
+func usedPct(used, total int64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return common.Round(float64(used)*100/float64(total), .5, 2)
+}
+
+// sendSummaryReport - send the summary report of the period if it is due, and start the next period.
+// Reports are not sent for the periods which ended while AMC was not running.
+func (c *Cluster) sendSummaryReport(now time.Time) {
+	schedule := c.reportScheduleOf()
+
+	p := c.summary
+	p.mutex.Lock()
+	if schedule == nil || p.start.IsZero() {
+		p.due = time.Time{}
+		p.mutex.Unlock()
+		return
+	}
+
+	// the schedule may have been changed by reloading the config
+	if p.due.IsZero() || p.period != schedule.Period || p.due.After(schedule.Next(now)) {
+		p.due, p.period = schedule.Next(now), schedule.Period
+	}
+	if now.Before(p.due) {
+		p.mutex.Unlock()
+		return
+	}
+
+	report := c.summaryReport(p, now)
+	report.Period = schedule.Period
+	p.start, p.due = now, schedule.Next(now)
+	p.objects = c.namespaceObjects()
+	p.peaks = map[string]SummaryPeak{}
+	p.mutex.Unlock()
+
+	go report.send(c.observer.Config())
+}
+
+// send - mail the report to the recipients of every locale in their language
+func (r *SummaryReport) send(config *common.Config) {
+	for locale, to := range mailer.RecipientsByLocale(config, config.ReportRecipients()) {
+		title := config.Translate(locale, "Daily summary")
+		if r.Period == common.ReportWeekly {
+			title = config.Translate(locale, "Weekly summary")
+		}
+
+		// the descriptions of the alerts are HTML, in the language of the recipients
+		alerts := make([]map[string]interface{}, 0, len(r.Alerts))
+		for _, alert := range r.Alerts {
+			alerts = append(alerts, map[string]interface{}{
+				"Created": alert.Created.Format("2006-01-02 15:04 MST"),
+				"Status":  config.Translate(locale, strings.ToUpper(alert.Status)),
+				"Node":    alert.Node,
+				"Message": template.HTML(config.Translate(locale, alert.Description)),
+			})
+		}
+
+		ctx := map[string]interface{}{
+			"Title":   template.HTML(title),
+			"Cluster": r.Cluster,
+			"From":    r.From.Format("2006-01-02 15:04 MST"),
+			"To":      r.To.Format("2006-01-02 15:04 MST"),
+			"Report":  r,
+			"Alerts":  alerts,
+		}
+		subject := fmt.Sprintf(config.Translate(locale, "AMC %s: %s"), title, r.Cluster)
+
+		for i := 0; i < 5; i++ {
+			err := mailer.SendLocalizedMailTo(config, locale, to, "reports/summary.html", subject, ctx)
+			if err == nil {
+				break
+			}
+
+			log.Errorf("Failed to send the summary report of cluster %s: %s", r.ClusterID, err.Error())
+			time.Sleep(5 * time.Second)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package models
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// TopologyCluster is a cluster in the XDR topology; either a monitored cluster,
+// or a remote datacenter known only from the addresses its shippers are configured with
+type TopologyCluster struct {
+	// the cluster name and the ids of its nodes; the addresses of the remote datacenter if not monitored
+	Key string `json:"key"`
+
+	ClusterID   string   `json:"cluster_id,omitempty"`
+	ClusterName string   `json:"cluster_name,omitempty"`
+	Alias       string   `json:"alias,omitempty"`
+	NodeIDs     []string `json:"node_ids"`
+	Addresses   []string `json:"addresses"`
+	Namespaces  []string `json:"namespaces"`
+	Monitored   bool     `json:"monitored"`
+
+	// the state of a monitored cluster: its nodes by their ids, its datacenters and its throughput
+	Stats common.Stats `json:"stats,omitempty"`
+}
+
+// TopologyLink is an XDR datacenter of a cluster shipping to another cluster
+type TopologyLink struct {
+	Source     string   `json:"source"`
+	Target     string   `json:"target"`
+	DC         string   `json:"dc"`
+	Namespaces []string `json:"namespaces"`
+}
+
+// Topology is the graph of the XDR replication between the clusters
+type Topology struct {
+	Clusters []*TopologyCluster `json:"clusters"`
+	Links    []*TopologyLink    `json:"links"`
+}
+
+// identity - the cluster name followed by the sorted ids of the nodes, which is unique
+// even when clusters in different datacenters share names or addresses
+func (c *Cluster) identity() string {
+	ids := []string{}
+	for _, node := range c.Nodes() {
+		if id := node.ID(); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	name := ""
+	if n := c.Name(); n != nil {
+		name = *n
+	}
+	return name + "/" + strings.Join(ids, ",")
+}
+
+func (c *Cluster) topologyCluster(sessionID string) *TopologyCluster {
+	info := c.DatacenterInfo(sessionID)
+	tc := &TopologyCluster{
+		Key:        c.identity(),
+		ClusterID:  c.ID(),
+		NodeIDs:    []string{},
+		Addresses:  c.NodeList(),
+		Namespaces: c.NamespaceList(),
+		Monitored:  true,
+		Stats: common.Stats{
+			"nodes":     info["nodes"],
+			"dc_name":   info["dc_name"],
+			"read_tps":  info["read_tps"],
+			"write_tps": info["write_tps"],
+		},
+	}
+
+	if name := c.Name(); name != nil {
+		tc.ClusterName = *name
+	}
+	if alias := c.Alias(); alias != nil {
+		tc.Alias = *alias
+	}
+
+	for _, node := range c.Nodes() {
+		if id := node.ID(); id != "" {
+			tc.NodeIDs = append(tc.NodeIDs, id)
+		}
+	}
+	sort.Strings(tc.NodeIDs)
+
+	return tc
+}
+
+// DatacenterTopology - get the XDR topology of the clusters monitored by the session and the automatic clusters,
+// keyed by the cluster identities; this is the multicluster view. Remote datacenters which are not monitored are
+// merged when they share addresses.
+func (o *ObserverT) DatacenterTopology(sessionID string) *Topology {
+	clusters := o.sessionClusters(sessionID)
+L:
+	for _, cluster := range o.AutoClusters() {
+		for _, c := range clusters {
+			if c == cluster || c.SameAs(cluster) {
+				continue L
+			}
+		}
+		clusters = append(clusters, cluster)
+	}
+
+	topology := &Topology{Clusters: []*TopologyCluster{}, Links: []*TopologyLink{}}
+	byKey := map[string]*TopologyCluster{}
+	byAddress := map[string]*TopologyCluster{}
+
+	for _, c := range clusters {
+		tc := c.topologyCluster(sessionID)
+		byKey[tc.Key] = tc
+		topology.Clusters = append(topology.Clusters, tc)
+		for _, addr := range tc.Addresses {
+			byAddress[addr] = tc
+		}
+	}
+
+	// links are reported by every node of the source cluster; keep one per datacenter
+	links := map[string]*TopologyLink{}
+	for _, c := range clusters {
+		source := c.identity()
+		for _, node := range c.Nodes() {
+			for dcName, dc := range node.DataCenters() {
+				addresses, _ := dc["Nodes"].([]string)
+				namespaces, _ := dc["namespaces"].([]string)
+				if len(addresses) == 0 {
+					continue
+				}
+
+				target := o.topologyTarget(sessionID, addresses, byKey, byAddress, topology)
+				linkKey := source + "|" + dcName
+				link := links[linkKey]
+				if link == nil {
+					link = &TopologyLink{Source: source, Target: target.Key, DC: dcName, Namespaces: []string{}}
+					links[linkKey] = link
+					topology.Links = append(topology.Links, link)
+				}
+				link.Namespaces = mergeSorted(link.Namespaces, namespaces)
+			}
+		}
+	}
+
+	return topology
+}
+
+// topologyTarget - find the cluster the addresses of a datacenter belong to, or add it as a remote datacenter
+func (o *ObserverT) topologyTarget(sessionID string, addresses []string, byKey, byAddress map[string]*TopologyCluster, topology *Topology) *TopologyCluster {
+	for _, addr := range addresses {
+		if tc := byAddress[addr]; tc != nil {
+			for _, a := range addresses {
+				if byAddress[a] == nil {
+					byAddress[a] = tc
+					tc.Addresses = mergeSorted(tc.Addresses, []string{a})
+				}
+			}
+			return tc
+		}
+
+		// the datacenter may be monitored through a different address
+		if c := o.NodeHasBeenDiscovered(sessionID, addr); c != nil {
+			if tc := byKey[c.identity()]; tc != nil {
+				byAddress[addr] = tc
+				return tc
+			}
+		}
+	}
+
+	sorted := mergeSorted(nil, addresses)
+	tc := &TopologyCluster{
+		Key:        strings.Join(sorted, ","),
+		NodeIDs:    []string{},
+		Addresses:  sorted,
+		Namespaces: []string{},
+	}
+	byKey[tc.Key] = tc
+	for _, addr := range addresses {
+		byAddress[addr] = tc
+	}
+	topology.Clusters = append(topology.Clusters, tc)
+
+	return tc
+}
+
+// mergeSorted - get the sorted union of the lists
+func mergeSorted(a, b []string) []string {
+	set := map[string]bool{}
+	for _, v := range append(append([]string{}, a...), b...) {
+		set[v] = true
+	}
+
+	res := make([]string, 0, len(set))
+	for v := range set {
+		res = append(res, v)
+	}
+	sort.Strings(res)
+	return res
+}
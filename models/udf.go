@@ -0,0 +1,34 @@
+package models
+
+import (
+	"strings"
+
+	"github.com/yuin/gopher-lua/parse"
+)
+
+// UDFSyntaxError - a syntax error in a UDF module
+type UDFSyntaxError struct {
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Message string `json:"message"`
+	Token   string `json:"token,omitempty"`
+}
+
+// ValidateLuaUDF - parse the Lua module and return the syntax errors, if any
+func ValidateLuaUDF(name, body string) []UDFSyntaxError {
+	_, err := parse.Parse(strings.NewReader(body), name)
+	if err == nil {
+		return nil
+	}
+
+	if perr, ok := err.(*parse.Error); ok {
+		return []UDFSyntaxError{{
+			Line:    perr.Pos.Line,
+			Column:  perr.Pos.Column,
+			Message: perr.Message,
+			Token:   perr.Token,
+		}}
+	}
+
+	return []UDFSyntaxError{{Message: err.Error()}}
+}
@@ -0,0 +1,169 @@
+package models
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	as "github.com/aerospike/aerospike-client-go/v5"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// the limits of the records a UDF is executed on when no key is given
+const (
+	UDFSampleDefaultLimit = 10
+	UDFSampleMaxLimit     = 100
+)
+
+// UDFCall - a function of a registered UDF module and its arguments, decoded from JSON with UseNumber
+type UDFCall struct {
+	Module   string // the name of the module, with or without .lua
+	Function string
+	Args     []interface{}
+	Timeout  time.Duration
+}
+
+// UDFResult - the result of the execution of a UDF on a record, or the error it failed with
+type UDFResult struct {
+	Digest string      `json:"digest"`
+	Key    interface{} `json:"key,omitempty"`
+	Result interface{} `json:"result"`
+	Error  string      `json:"error,omitempty"`
+}
+
+// ExecuteUDF - execute the UDF on the record; the errors of the UDF are returned in the result
+func (c *Cluster) ExecuteUDF(key *as.Key, call UDFCall) (*UDFResult, error) {
+	client, args, err := c.udfCall(&call)
+	if err != nil {
+		return nil, err
+	}
+
+	res := executeUDF(client, key, call, args)
+	return &res, nil
+}
+
+// ExecuteUDFOnSample - execute the UDF on the first limit records of a scan of the set, to smoke test it
+// without knowing the keys of the records; see SampleSet
+func (c *Cluster) ExecuteUDFOnSample(ctx context.Context, namespace, set string, limit int, call UDFCall) ([]UDFResult, error) {
+	if limit <= 0 || limit > UDFSampleMaxLimit {
+		return nil, common.Errorf(common.KindInvalid, "The limit must be between 1 and %d", UDFSampleMaxLimit)
+	}
+
+	client, args, err := c.udfCall(&call)
+	if err != nil {
+		return nil, err
+	}
+
+	rs, err := c.scanSample(ctx, namespace, set, limit, 100, nil, false)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []*as.Key{}
+	err = readRecords(ctx, rs, limit, func(r *as.Record) error {
+		keys = append(keys, r.Key)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]UDFResult, 0, len(keys))
+	for _, key := range keys {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		res = append(res, executeUDF(client, key, call, args))
+	}
+	return res, nil
+}
+
+// udfCall - check that the module of the call is registered, and convert its arguments
+func (c *Cluster) udfCall(call *UDFCall) (*as.Client, []as.Value, error) {
+	call.Module = strings.TrimSuffix(call.Module, ".lua")
+	if call.Module == "" || call.Function == "" {
+		return nil, nil, common.Errorf(common.KindInvalid, "Invalid parameters: module and function are required")
+	}
+
+	registered := false
+	for _, node := range c.Nodes() {
+		_, exists := node.UDFs()[call.Module+".lua"]
+		registered = registered || exists
+	}
+	if !registered {
+		return nil, nil, common.Errorf(common.KindNotFound, "UDF module %s not found", call.Module)
+	}
+
+	client := c.origClient()
+	if client == nil {
+		return nil, nil, common.Errorf(common.KindUnavailable, "Cluster %s has been decommissioned", c.ID())
+	}
+
+	args := make([]as.Value, len(call.Args))
+	for i, arg := range call.Args {
+		v, err := udfArg(arg)
+		if err != nil {
+			return nil, nil, err
+		}
+		args[i] = as.NewValue(v)
+	}
+	return client, args, nil
+}
+
+func executeUDF(client *as.Client, key *as.Key, call UDFCall, args []as.Value) UDFResult {
+	res := UDFResult{Digest: hex.EncodeToString(key.Digest())}
+	if v := key.Value(); v != nil {
+		res.Key = jsonValue(v.GetObject())
+	}
+
+	policy := as.NewWritePolicy(0, 0)
+	policy.TotalTimeout = call.Timeout
+
+	result, err := client.Execute(policy, key, call.Module, call.Function, args...)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	res.Result = jsonValue(result)
+	return res
+}
+
+// udfArg - the value of an argument decoded from JSON: the numbers are passed as integers unless they
+// have a fraction or an exponent
+func udfArg(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n, nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return nil, common.Errorf(common.KindInvalid, "Invalid argument %s", v)
+		}
+		return f, nil
+	case []interface{}:
+		res := make([]interface{}, len(v))
+		for i, e := range v {
+			var err error
+			if res[i], err = udfArg(e); err != nil {
+				return nil, err
+			}
+		}
+		return res, nil
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			var err error
+			if res[k], err = udfArg(e); err != nil {
+				return nil, err
+			}
+		}
+		return res, nil
+	case nil, bool, string:
+		return v, nil
+	}
+	return nil, common.Errorf(common.KindInvalid, "Invalid argument %v", v)
+}
@@ -0,0 +1,175 @@
+package models
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/aerospike-community/amc/common"
+	"github.com/aerospike-community/amc/mailer"
+)
+
+const _generatedPasswordLength = 16
+
+const _passwordChars = "abcdefghijkmnopqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789!@#%^*-_"
+
+// BulkUser - a user to be created in a bulk import
+type BulkUser struct {
+	User     string   `json:"user"`
+	Password string   `json:"password,omitempty"`
+	Roles    []string `json:"roles"`
+	Email    string   `json:"email,omitempty"`
+}
+
+// BulkUserResult - the outcome of creating a single user in a bulk import
+type BulkUserResult struct {
+	User     string `json:"user"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Password string `json:"password,omitempty"`
+	Emailed  bool   `json:"emailed"`
+}
+
+// generatePassword - generate a random password from a readable alphabet
+func generatePassword() (string, error) {
+	max := big.NewInt(int64(len(_passwordChars)))
+	res := make([]byte, _generatedPasswordLength)
+	for i := range res {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		res[i] = _passwordChars[n.Int64()]
+	}
+	return string(res), nil
+}
+
+// ExportUsers - get all users sorted by name
+func (c *Cluster) ExportUsers() []BulkUser {
+	users := c.Users()
+	res := make([]BulkUser, 0, len(users))
+	for _, u := range users {
+		roles := make([]string, len(u.Roles))
+		copy(roles, u.Roles)
+		sort.Strings(roles)
+		res = append(res, BulkUser{User: u.User, Roles: roles})
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].User < res[j].User })
+	return res
+}
+
+// ImportUsers - create the users one by one. Users without a password get a generated one,
+// which is returned in the result; if emailPasswords is set and the user has an email address,
+// the password is sent to the user instead.
+func (c *Cluster) ImportUsers(users []BulkUser, emailPasswords bool) []BulkUserResult {
+	existing := map[string]bool{}
+	for _, u := range c.Users() {
+		existing[u.User] = true
+	}
+
+	validRoles := map[string]bool{}
+	for _, r := range c.RoleNames() {
+		validRoles[r] = true
+	}
+
+	clusterName := c.ID()
+	if alias := c.Alias(); alias != nil {
+		clusterName = *alias
+	}
+
+	res := make([]BulkUserResult, 0, len(users))
+	for _, u := range users {
+		r := BulkUserResult{User: u.User}
+		generated, err := c.importUser(&u, existing, validRoles)
+		if err != nil {
+			r.Status = "failure"
+			r.Error = err.Error()
+			res = append(res, r)
+			continue
+		}
+
+		existing[u.User] = true
+		r.Status = "success"
+		if emailPasswords && u.Email != "" {
+			if err := c.emailCredentials(clusterName, &u); err != nil {
+				log.Errorf("Failed to email the credentials of user %s: %s", u.User, err.Error())
+				r.Error = "User created, but sending the email failed: " + err.Error()
+			} else {
+				r.Emailed = true
+			}
+		}
+
+		if generated && !r.Emailed {
+			r.Password = u.Password
+		}
+
+		res = append(res, r)
+	}
+
+	return res
+}
+
+// importUser - validate and create the user; returns true if the password was generated
+func (c *Cluster) importUser(u *BulkUser, existing, validRoles map[string]bool) (bool, error) {
+	u.User = strings.TrimSpace(u.User)
+	if u.User == "" {
+		return false, common.Errorf(common.KindInvalid, "Invalid user name")
+	}
+
+	if existing[u.User] {
+		return false, common.Errorf(common.KindAlreadyExists, "User already exists")
+	}
+
+	roles := make([]string, 0, len(u.Roles))
+	for _, role := range u.Roles {
+		role = strings.TrimSpace(role)
+		if role == "" {
+			continue
+		}
+		if len(validRoles) > 0 && !validRoles[role] {
+			return false, common.Errorf(common.KindInvalid, "Invalid role %s", role)
+		}
+		roles = append(roles, role)
+	}
+	u.Roles = roles
+
+	generated := false
+	if u.Password == "" {
+		pass, err := generatePassword()
+		if err != nil {
+			return false, err
+		}
+		u.Password = pass
+		generated = true
+	}
+
+	return generated, c.CreateUser(u.User, u.Password, u.Roles)
+}
+
+func (c *Cluster) emailCredentials(clusterName string, u *BulkUser) error {
+	config := c.observer.config
+	locale := config.Locale(u.Email)
+	context := map[string]string{
+		"Cluster":  clusterName,
+		"User":     u.User,
+		"Password": u.Password,
+		"Roles":    strings.Join(u.Roles, ", "),
+	}
+
+	var err error
+	for i := 0; i < 3; i++ {
+		subject := fmt.Sprintf(config.Translate(locale, "AMC: Your Aerospike account on %s"), clusterName)
+		if err = mailer.SendLocalizedMailTo(config, locale, []string{u.Email}, "users/credentials.html", subject, context); err == nil {
+			return nil
+		}
+		time.Sleep(time.Second)
+	}
+
+	return err
+}
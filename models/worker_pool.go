@@ -0,0 +1,77 @@
+package models
+
+import (
+	"runtime/debug"
+	"sync/atomic"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// default number of info calls to the nodes which are run concurrently while polling
+const _defaultPollConcurrency = 64
+
+// workerPool runs jobs on a fixed number of goroutines
+type workerPool struct {
+	jobs chan func()
+
+	size int
+	busy int32
+}
+
+func newWorkerPool(size int) *workerPool {
+	if size <= 0 {
+		size = _defaultPollConcurrency
+	}
+
+	p := &workerPool{
+		jobs: make(chan func(), size),
+		size: size,
+	}
+
+	for i := 0; i < size; i++ {
+		go p.work()
+	}
+
+	return p
+}
+
+func (p *workerPool) work() {
+	for job := range p.jobs {
+		p.run(job)
+	}
+}
+
+func (p *workerPool) run(job func()) {
+	atomic.AddInt32(&p.busy, 1)
+	defer atomic.AddInt32(&p.busy, -1)
+
+	// make sure panics do not bring the worker down
+	defer func() {
+		if err := recover(); err != nil {
+			log.Error(string(debug.Stack()))
+		}
+	}()
+
+	job()
+}
+
+// Submit - queue the job; blocks while all workers are busy and the queue is full.
+// Jobs must not submit other jobs and wait for them, otherwise the pool may deadlock.
+func (p *workerPool) Submit(job func()) {
+	p.jobs <- job
+}
+
+// WorkerPoolState is the load of the worker pool
+type WorkerPoolState struct {
+	Size   int `json:"size"`
+	Busy   int `json:"busy"`
+	Queued int `json:"queued"`
+}
+
+func (p *workerPool) state() WorkerPoolState {
+	return WorkerPoolState{
+		Size:   p.size,
+		Busy:   int(atomic.LoadInt32(&p.busy)),
+		Queued: len(p.jobs),
+	}
+}
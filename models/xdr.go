@@ -0,0 +1,277 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	version "github.com/mcuadros/go-version"
+
+	"github.com/aerospike-community/amc/common"
+)
+
+// Xdr5 - does the node use the 5.0+ XDR model (per-DC and per-namespace config, no xdr port)
+func (n *Node) Xdr5() bool {
+	build := n.Build()
+	return build != common.NOT_AVAILABLE && version.Compare(build, "5.0", ">=")
+}
+
+// XdrDCs - get the names of the XDR datacenters configured on the node (5.0+)
+func (n *Node) XdrDCs() []string {
+	dcs := common.DeleteEmpty(strings.Split(n.XdrConfig().TryString("dcs", ""), ","))
+	sort.Strings(dcs)
+	return dcs
+}
+
+// XdrDCConfig - get the config of an XDR datacenter (5.0+)
+func (n *Node) XdrDCConfig(dc string) common.Stats {
+	cmd := "get-config:context=xdr;dc=" + dc
+	return n.InfoAttrs(cmd).ToInfo(cmd).ToStats()
+}
+
+// XdrDCNamespaces - get the namespaces shipped to an XDR datacenter (5.0+)
+func (n *Node) XdrDCNamespaces(dc string) []string {
+	return common.DeleteEmpty(strings.Split(n.XdrDCConfig(dc).TryString("namespaces", ""), ","))
+}
+
+// XdrDCNamespaceConfig - get the per-namespace config of an XDR datacenter (5.0+)
+func (n *Node) XdrDCNamespaceConfig(dc, namespace string) common.Stats {
+	cmd := "get-config:context=xdr;dc=" + dc + ";namespace=" + namespace
+	return n.InfoAttrs(cmd).ToInfo(cmd).ToStats()
+}
+
+// XdrDCStats - get the statistics of an XDR datacenter, including lag and throughput (5.0+)
+func (n *Node) XdrDCStats(dc string) common.Stats {
+	cmd := "get-stats:context=xdr;dc=" + dc
+	return n.InfoAttrs(cmd).ToInfo(cmd).ToStats()
+}
+
+// SetXdrDCConfig - set the config of an XDR datacenter, or of a namespace in the datacenter if namespace is not empty (5.0+)
+func (n *Node) SetXdrDCConfig(dc, namespace string, config map[string]string, user string) ([]string, error) {
+	context := "xdr;dc=" + dc
+	if namespace != "" {
+		context += ";namespace=" + namespace
+	}
+
+	return n.SetServerConfig(context, config, user)
+}
+
+// xdrNodeAddress - get the host:port of a node-address-port entry, which is the address, the port
+// and an optional tls name; IPv6 addresses are enclosed in brackets
+func xdrNodeAddress(addr string) (string, bool) {
+	rest := addr
+	host := ""
+	if strings.HasPrefix(addr, "[") {
+		end := strings.Index(addr, "]")
+		if end < 0 {
+			return "", false
+		}
+		host, rest = addr[1:end], strings.TrimPrefix(addr[end+1:], ":")
+	} else {
+		parts := strings.SplitN(addr, ":", 2)
+		if len(parts) < 2 {
+			return "", false
+		}
+		host, rest = parts[0], parts[1]
+	}
+
+	port := strings.SplitN(rest, ":", 2)[0]
+	if host == "" || port == "" {
+		return "", false
+	}
+	return net.JoinHostPort(host, port), true
+}
+
+// xdr5DataCenters - get the datacenters in the same format as the legacy get-dc-config (5.0+)
+func (n *Node) xdr5DataCenters() map[string]common.Stats {
+	dcs := map[string]common.Stats{}
+	for _, dc := range n.XdrDCs() {
+		config := n.XdrDCConfig(dc)
+
+		nodes := []string{}
+		for _, addr := range strings.Split(config.TryString("node-address-port", ""), ",") {
+			if address, ok := xdrNodeAddress(addr); ok {
+				nodes = append(nodes, address)
+			}
+		}
+
+		if len(nodes) == 0 {
+			continue
+		}
+
+		config["DC_Name"] = dc
+		config["Nodes"] = nodes
+		config["namespaces"] = n.XdrDCNamespaces(dc)
+		dcs[dc] = config
+	}
+
+	return dcs
+}
+
+// xdrInfoKeys - info commands for the XDR datacenters known from the last update (5.0+)
+func (n *Node) xdrInfoKeys() []string {
+	res := []string{}
+	for _, dc := range n.XdrDCs() {
+		res = append(res, "get-config:context=xdr;dc="+dc, "get-stats:context=xdr;dc="+dc)
+		for _, ns := range n.XdrDCNamespaces(dc) {
+			res = append(res, "get-config:context=xdr;dc="+dc+";namespace="+ns)
+		}
+	}
+
+	return res
+}
+
+// XdrDCs - get the per-node config and statistics of the XDR datacenters of the cluster (5.0+);
+// dc => node address => info
+func (c *Cluster) XdrDCs() map[string]map[string]common.Stats {
+	res := map[string]map[string]common.Stats{}
+	for _, node := range c.Nodes() {
+		if !node.Xdr5() {
+			continue
+		}
+
+		for _, dc := range node.XdrDCs() {
+			if res[dc] == nil {
+				res[dc] = map[string]common.Stats{}
+			}
+
+			stats := node.XdrDCStats(dc)
+			res[dc][node.Address()] = common.Stats{
+				"node_status": node.Status(),
+				"config":      node.XdrDCConfig(dc),
+				"stats":       stats,
+				"lag":         stats.Get("lag"),
+				"throughput":  stats.Get("throughput"),
+			}
+		}
+	}
+
+	return res
+}
+
+// xdrDCAction - run a dynamic XDR datacenter action on the node (5.0+)
+func (n *Node) xdrDCAction(dc string, params ...string) error {
+	if !n.Xdr5() {
+		return errors.New("Dynamic XDR datacenter management requires server 5.0+")
+	}
+
+	if dc == "" || strings.ContainsAny(dc, ";=:,") {
+		return common.Errorf(common.KindInvalid, "Invalid datacenter name `%s`", dc)
+	}
+
+	cmd := "set-config:context=xdr;dc=" + dc + ";" + strings.Join(params, ";")
+	res, err := n.RequestInfo(3, cmd)
+	if err != nil {
+		return err
+	}
+
+	if resp := res[cmd]; strings.ToLower(resp) != "ok" {
+		return fmt.Errorf("%s resulted in error '%s'", cmd, resp)
+	}
+
+	return n.update()
+}
+
+// CreateXdrDC - create an XDR datacenter on the node (5.0+)
+func (n *Node) CreateXdrDC(dc string) error {
+	return n.xdrDCAction(dc, "action=create")
+}
+
+// DeleteXdrDC - delete an XDR datacenter from the node (5.0+)
+func (n *Node) DeleteXdrDC(dc string) error {
+	return n.xdrDCAction(dc, "action=delete")
+}
+
+// AddXdrDCNode - add a seed node to an XDR datacenter; address is host:port[:tls-name] (5.0+)
+func (n *Node) AddXdrDCNode(dc, address string) error {
+	if err := validateXdrNodeAddress(address); err != nil {
+		return err
+	}
+	return n.xdrDCAction(dc, "node-address-port="+address, "action=add")
+}
+
+// RemoveXdrDCNode - remove a seed node from an XDR datacenter; address is host:port[:tls-name] (5.0+)
+func (n *Node) RemoveXdrDCNode(dc, address string) error {
+	if err := validateXdrNodeAddress(address); err != nil {
+		return err
+	}
+	return n.xdrDCAction(dc, "node-address-port="+address, "action=remove")
+}
+
+func validateXdrNodeAddress(address string) error {
+	parts := strings.Split(address, ":")
+	if strings.ContainsAny(address, ";=,") || len(parts) < 2 || len(parts) > 3 || parts[0] == "" {
+		return common.Errorf(common.KindInvalid, "Invalid node address `%s`; expected host:port[:tls-name]", address)
+	}
+
+	if _, err := strconv.ParseUint(parts[1], 10, 16); err != nil {
+		return common.Errorf(common.KindInvalid, "Invalid port in node address `%s`", address)
+	}
+
+	return nil
+}
+
+// AddXdrDCNamespace - start shipping a namespace to an XDR datacenter (5.0+).
+// rewind is either empty, `all` or the number of seconds to rewind
+func (n *Node) AddXdrDCNamespace(dc, namespace, rewind string) error {
+	if n.NamespaceByName(namespace) == nil {
+		return common.Errorf(common.KindNamespaceNotFound, "Namespace %s not found", namespace)
+	}
+
+	params := []string{"namespace=" + namespace, "action=add"}
+	if rewind != "" {
+		if err := checkXdrRewind(rewind); err != nil {
+			return err
+		}
+		params = append(params, "rewind="+rewind)
+	}
+
+	return n.xdrDCAction(dc, params...)
+}
+
+// checkXdrRewind - the rewind of a namespace is either `all` or the number of seconds
+func checkXdrRewind(rewind string) error {
+	if _, err := strconv.ParseUint(rewind, 10, 32); rewind != "all" && err != nil {
+		return common.Errorf(common.KindInvalid, "Invalid rewind value `%s`; expected `all` or the number of seconds", rewind)
+	}
+	return nil
+}
+
+// RemoveXdrDCNamespace - stop shipping a namespace to an XDR datacenter (5.0+)
+func (n *Node) RemoveXdrDCNamespace(dc, namespace string) error {
+	if n.NamespaceByName(namespace) == nil {
+		return common.Errorf(common.KindNamespaceNotFound, "Namespace %s not found", namespace)
+	}
+
+	return n.xdrDCAction(dc, "namespace="+namespace, "action=remove")
+}
+
+// RewindXdrDCNamespace - reship the namespace's records to an XDR datacenter,
+// e.g. to recover a destination after an outage (5.0+).
+// The server only accepts rewind when adding a namespace, so it is removed and re-added;
+// the arguments are checked first, so that the shipping is not stopped by an invalid rewind.
+func (n *Node) RewindXdrDCNamespace(dc, namespace, rewind string) error {
+	if rewind == "" {
+		return common.Errorf(common.KindInvalid, "Rewind value is required")
+	}
+	if err := checkXdrRewind(rewind); err != nil {
+		return err
+	}
+	if n.NamespaceByName(namespace) == nil {
+		return common.Errorf(common.KindNamespaceNotFound, "Namespace %s not found", namespace)
+	}
+
+	for _, ns := range n.XdrDCNamespaces(dc) {
+		if ns == namespace {
+			if err := n.RemoveXdrDCNamespace(dc, namespace); err != nil {
+				return err
+			}
+			break
+		}
+	}
+
+	return n.AddXdrDCNamespace(dc, namespace, rewind)
+}
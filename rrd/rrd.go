@@ -2,16 +2,20 @@ package rrd
 
 import (
 	"sort"
-	"sync"
 	"time"
 
-	// "github.com/sasha-s/go-deadlock"
 	// log "github.com/sirupsen/logrus"
 
 	"github.com/aerospike-community/amc/common"
 	timeseries "github.com/aerospike-community/amc/go-time-series"
 )
 
+// number of points kept by a bucket by default
+const defaultBucketCapacity = 1800
+
+// estimated memory used by a point in a bucket: the value, its pointer and the sample count
+const bytesPerPoint = 8 + 8 + 1
+
 // Bucket type struct
 type Bucket struct {
 	// this flag determines if the values passed to the bucket are total counts,
@@ -25,10 +29,14 @@ type Bucket struct {
 
 	resolution float64
 
+	// the number of points kept, and how the points of the current time series are aggregated
+	capacity int
+	tsType   timeseries.TSType
+
 	ts      *timeseries.TimeSeries
 	tsOlder map[int64]*timeseries.TimeSeries // map[last_update]time_series
 
-	mutex sync.RWMutex
+	mutex common.RWMutex
 }
 
 // NewBucket - new RDD bucket
@@ -36,7 +44,7 @@ func NewBucket(resolution, size int, rollingTotal bool) *Bucket {
 	ts, err := timeseries.NewTimeSeries(timeseries.TSTypeAvg,
 		timeseries.WithGranularities(
 			[]timeseries.Granularity{
-				{Granularity: time.Second * time.Duration(resolution), Count: 1800}, // half an hour every second
+				{Granularity: time.Second * time.Duration(resolution), Count: defaultBucketCapacity}, // half an hour every second
 				// {Granularity: time.Minute, Count: 60 * 24},                          // then 60 mins summed on minute
 				// {Granularity: time.Hour, Count: 24 * 90},                            // 24 hours grouped by hour
 				// {Granularity: time.Hour * 24, Count: 31},                            // 31 days hourly average
@@ -48,6 +56,8 @@ func NewBucket(resolution, size int, rollingTotal bool) *Bucket {
 	return &Bucket{
 		rollingTotal: rollingTotal,
 		resolution:   float64(resolution),
+		capacity:     defaultBucketCapacity,
+		tsType:       timeseries.TSTypeAvg,
 		ts:           ts,
 		tsOlder:      map[int64]*timeseries.TimeSeries{},
 	}
@@ -79,19 +89,36 @@ func (b *Bucket) Add(timestamp int64, val float64) {
 		}
 	}
 
+	lastTimestamp := b.lastTimestamp
 	b.lastTimestamp = &timestamp
 
 	if b.rollingTotal {
+		// the delta is spread over the ticks since the last value when some were missed, e.g. by a slow update
+		elapsed := b.resolution
+		if lastTimestamp != nil && float64(timestamp-*lastTimestamp) > elapsed {
+			elapsed = float64(timestamp - *lastTimestamp)
+		}
+
+		v := 0.0
 		if val >= *b.lastValue {
-			v := (val - *b.lastValue) / b.resolution
-			b.ts.IncreaseAtTime(v, time.Unix(timestamp, 0))
-			*b.lastRollingValue = v
-		} else {
-			// the count on the server side must have been reset; add the value and substitute
-			v := val / b.resolution
-			b.ts.IncreaseAtTime(v, time.Unix(timestamp, 0))
-			*b.lastRollingValue = v
+			v = (val - *b.lastValue) / elapsed
 		}
+		// otherwise the counter on the server side has been reset by a restart, or has wrapped;
+		// the amount counted since is unknown, so a zero point is added instead of a negative or huge rate
+		// and the following points are calculated from the new value
+
+		if step := int64(b.resolution); lastTimestamp != nil && step > 0 {
+			// only the ticks the bucket keeps are filled
+			start := *lastTimestamp + step
+			if oldest := timestamp - step*int64(b.capacity); start < oldest {
+				start = oldest
+			}
+			for tm := start; tm < timestamp; tm += step {
+				b.ts.IncreaseAtTime(v, time.Unix(tm, 0))
+			}
+		}
+		b.ts.IncreaseAtTime(v, time.Unix(timestamp, 0))
+		*b.lastRollingValue = v
 	} else {
 		// otherwise add the value to the timeseries bucket
 		b.ts.IncreaseAtTime(val/b.resolution, time.Unix(timestamp, 0))
@@ -118,7 +145,7 @@ func (b *Bucket) SetResolution(resolution int) {
 	ts, err := timeseries.NewTimeSeries(timeseries.TSTypeSum,
 		timeseries.WithGranularities(
 			[]timeseries.Granularity{
-				{Granularity: time.Second * time.Duration(resolution), Count: 1800}, // half an hour every second
+				{Granularity: time.Second * time.Duration(resolution), Count: b.capacity}, // half an hour every second
 				// {Granularity: time.Minute, Count: 60 * 24},                          // then 60 mins summed on minute
 				// {Granularity: time.Hour, Count: 24 * 90},                            // 24 hours grouped by hour
 				// {Granularity: time.Hour * 24, Count: 31},                            // 31 days hourly average
@@ -132,10 +159,63 @@ func (b *Bucket) SetResolution(resolution int) {
 	}
 
 	b.ts = ts
+	b.tsType = timeseries.TSTypeSum
 
 	b.resolution = float64(resolution)
 }
 
+// Capacity - get the number of points kept by the bucket
+func (b *Bucket) Capacity() int {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	return b.capacity
+}
+
+// SetCapacity - change the number of points kept by the bucket;
+// when shrinking, the oldest points and the points kept from previous resolutions are dropped
+func (b *Bucket) SetCapacity(capacity int) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if capacity <= 0 || capacity == b.capacity {
+		return
+	}
+
+	ts, err := timeseries.NewTimeSeries(b.tsType,
+		timeseries.WithGranularities(
+			[]timeseries.Granularity{
+				{Granularity: time.Second * time.Duration(b.resolution), Count: capacity},
+			}))
+	if err != nil {
+		return
+	}
+
+	// copy the points which fit in the new capacity
+	now := time.Now()
+	since := now.Add(-time.Duration(capacity) * time.Second * time.Duration(b.resolution))
+	if values, err := b.ts.RangeValues(since, now); err == nil {
+		for _, v := range values {
+			ts.IncreaseAtTime(v.Value, v.Time)
+		}
+	}
+
+	if capacity < b.capacity {
+		b.tsOlder = map[int64]*timeseries.TimeSeries{}
+	}
+
+	b.ts = ts
+	b.capacity = capacity
+}
+
+// MemoryUsage - get the estimated memory used by the points of the bucket in bytes
+func (b *Bucket) MemoryUsage() int64 {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	return int64(b.capacity*(1+len(b.tsOlder))) * bytesPerPoint
+}
+
 // ValuesSince - get bucket values simce time
 func (b *Bucket) ValuesSince(tm time.Time) []*common.SinglePointValue {
 	b.mutex.RLock()
@@ -36,7 +36,8 @@ var _ = Describe("Stats Bucket", func() {
 		bucket := NewBucket(5, 10, true)
 		Expect(bucket.LastValue()).To(BeNil())
 
-		tm1 := time.Now().Unix()
+		// the values are read up to now, at the ticks of the resolution
+		tm1 := time.Now().Add(-time.Minute).Truncate(5 * time.Second).Unix()
 		val1 := float64(1)
 		bucket.Add(tm1, val1)
 		// fmt.Printf("%#v\n", *bucket)
@@ -84,4 +85,29 @@ var _ = Describe("Stats Bucket", func() {
 			common.NewSinglePointValue(&expectedTm4, &expectedVal4),
 		}))
 	})
+
+	It("must not add negative rates when the counter is reset", func() {
+		bucket := NewBucket(5, 10, true)
+
+		tm1 := time.Now().Unix()
+		bucket.Add(tm1, float64(100))
+
+		tm2 := tm1 + 5
+		bucket.Add(tm2, float64(110))
+
+		// the node restarted
+		tm3 := tm2 + 5
+		bucket.Add(tm3, float64(3))
+
+		expectedTm3 := tm3
+		expectedVal3 := float64(0)
+		Expect(*bucket.LastValue()).To(Equal(*common.NewSinglePointValue(&expectedTm3, &expectedVal3)))
+
+		tm4 := tm3 + 5
+		bucket.Add(tm4, float64(13))
+
+		expectedTm4 := tm4
+		expectedVal4 := float64(10) / 5 // delta since the reset / resolution
+		Expect(*bucket.LastValue()).To(Equal(*common.NewSinglePointValue(&expectedTm4, &expectedVal4)))
+	})
 })
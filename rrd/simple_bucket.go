@@ -2,10 +2,8 @@ package rrd
 
 import (
 	"math"
-	"sync"
 	"time"
 
-	// "github.com/sasha-s/go-deadlock"
 	// log "github.com/sirupsen/logrus"
 
 	"github.com/aerospike-community/amc/common"
@@ -22,7 +20,7 @@ type SimpleBucket struct {
 	lastValue     interface{}
 	lastTimestamp *int64
 
-	mutex sync.RWMutex
+	mutex common.RWMutex
 }
 
 // NewSimpleBucket - create simple bucket
@@ -116,6 +114,41 @@ func (b *SimpleBucket) ValuesSince(tm time.Time) []interface{} {
 	return res
 }
 
+// SetSize - change the number of values kept by the bucket; when shrinking, the oldest values are dropped
+func (b *SimpleBucket) SetSize(size int) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if size <= 0 || size == len(b.values) {
+		return
+	}
+
+	values := make([]interface{}, size)
+	for i := b.offset - size + 1; i <= b.offset; i++ {
+		if i >= 0 && b.offset-i < len(b.values) {
+			values[i%size] = b.values[i%len(b.values)]
+		}
+	}
+
+	b.values = values
+}
+
+// MemoryUsage - get the estimated memory used by the bucket in bytes;
+// each slot holds an interface, and each value a pointer to its own interface
+func (b *SimpleBucket) MemoryUsage(bytesPerValue int) int64 {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	var res int64 = int64(len(b.values)) * 16
+	for _, v := range b.values {
+		if v != nil {
+			res += int64(8 + 16 + bytesPerValue)
+		}
+	}
+
+	return res
+}
+
 // LastValue - get last value
 func (b *SimpleBucket) LastValue() interface{} {
 	b.mutex.RLock()
@@ -13,8 +13,15 @@ version=`git describe --tags $(git rev-list --tags --max-count=1)`
 # tag=`git rev-parse --short HEAD`
 version_build="$edition-$version"
 
+# DEADLOCK=1 swaps in go-deadlock's mutexes to detect deadlocks;
+# requires github.com/sasha-s/go-deadlock in go.mod
+tags=$edition
+if [ -n "$DEADLOCK" ]; then
+	tags="$edition deadlock"
+fi
+
 # build binary
 #godep go build -race -a -tags $edition -ldflags "-X github.com/aerospike-community/amc/common.AMCEdition=$edition -X github.com/aerospike-community/amc/common.AMCBuild=$build -X github.com/aerospike-community/amc/common.AMCVersion=$version -X github.com/aerospike-community/amc/common.AMCEnv=dev" -o amc .
-go build -race -a -tags $edition -ldflags "-X github.com/aerospike-community/amc/common.AMCEdition=$edition -X github.com/aerospike-community/amc/common.AMCBuild=$build -X github.com/aerospike-community/amc/common.AMCVersion=$version -X github.com/aerospike-community/amc/common.AMCEnv=dev" -o amc .
+go build -race -a -tags "$tags" -ldflags "-X github.com/aerospike-community/amc/common.AMCEdition=$edition -X github.com/aerospike-community/amc/common.AMCBuild=$build -X github.com/aerospike-community/amc/common.AMCVersion=$version -X github.com/aerospike-community/amc/common.AMCEnv=dev" -o amc .
 
 ./amc -config-file=$GOPATH/src/github.com/aerospike-community/amc/amc.dev.conf -profile